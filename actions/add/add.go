@@ -5,21 +5,33 @@ import (
 
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
 // AddResult is the structured result of chassis:add.
 type AddResult struct {
 	Chassis string `json:"chassis"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+
+	message.Log
 }
 
 // Add implements the chassis:add command
 type Add struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
 
 	Dir     string
 	Chassis string
 	Force   bool
+	After   string // sibling to insert the new path after
+	Before  string // sibling to insert the new path before
+	Limits  pkgchassis.Limits
 
 	result *AddResult
 }
@@ -31,26 +43,59 @@ func (a *Add) Result() any {
 
 // Execute runs the add action
 func (a *Add) Execute() error {
+	if a.After != "" && a.Before != "" {
+		return fmt.Errorf("--after and --before are mutually exclusive")
+	}
+
+	endPhase := a.Phase("load chassis")
 	c, err := chassis.Load(a.Dir)
+	endPhase()
 	if err != nil {
 		return err
 	}
 
 	if a.Force && c.Exists(a.Chassis) {
 		a.result = &AddResult{Chassis: a.Chassis}
-		a.Term().Info().Printfln("Already exists: %s", a.Chassis)
+		a.Report(message.ChassisExists, a.Chassis)
 		return nil
 	}
 
-	if err := c.Add(a.Chassis); err != nil {
+	if err := pkgchassis.ValidatePath(a.Chassis); err != nil {
+		return err
+	}
+
+	if err := a.Limits.CheckAdd(c.Chassis, a.Chassis); err != nil {
+		return err
+	}
+
+	switch {
+	case a.After != "":
+		err = c.AddNextTo(a.Chassis, a.After, false)
+	case a.Before != "":
+		err = c.AddNextTo(a.Chassis, a.Before, true)
+	default:
+		err = c.Add(a.Chassis)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to add chassis path: %w", err)
 	}
 
-	if err := c.Save(a.Dir); err != nil {
+	if a.DryRun() {
+		a.result = &AddResult{Chassis: a.Chassis, DryRun: true}
+		a.Report(message.DryRun)
+		a.Term().Printfln("  chassis.yaml: + %s", a.Chassis)
+		return nil
+	}
+
+	a.WarnAliasExpansion(c)
+	endPhase = a.Phase("save chassis")
+	err = c.Save(a.Dir)
+	endPhase()
+	if err != nil {
 		return err
 	}
 
 	a.result = &AddResult{Chassis: a.Chassis}
-	a.Term().Success().Printfln("Added: %s", a.Chassis)
+	a.Report(message.ChassisAdded, a.Chassis)
 	return nil
 }
@@ -0,0 +1,31 @@
+package add
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestAddGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Add
+		dryRun bool
+	}{
+		{"leaf", &Add{Chassis: "platform.foundation.network.egress"}, false},
+		{"before", &Add{Chassis: "platform.foundation.network.egress", Before: "ingress"}, false},
+		{"dry-run", &Add{Chassis: "platform.foundation.network.egress"}, true},
+		{"exists", &Add{Chassis: "platform.foundation.cluster"}, false},
+		{"force", &Add{Chassis: "platform.foundation.cluster", Force: true}, false},
+		{"invalid", &Add{Chassis: "platform.Foundation"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package adopt
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// AdoptResult is the structured result of chassis:adopt.
+type AdoptResult struct {
+	Paths   []chassis.Reference `json:"paths"`
+	Applied bool                `json:"applied,omitempty"`
+	DryRun  bool                `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Adopt implements the chassis:adopt command
+type Adopt struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir    string
+	Apply  bool
+	Limits pkgchassis.Limits
+
+	result *AdoptResult
+}
+
+// Result returns the structured result for JSON output.
+func (a *Adopt) Result() any {
+	return a.result
+}
+
+// Mutates reports whether the action writes chassis.yaml.
+func (a *Adopt) Mutates() bool {
+	return a.Apply && !a.DryRun()
+}
+
+// Execute runs the adopt action
+func (a *Adopt) Execute() error {
+	endPhase := a.Phase("load chassis")
+	c, err := chassis.Load(a.Dir)
+	endPhase()
+	if errors.Is(err, fs.ErrNotExist) {
+		c = chassis.New()
+	} else if err != nil {
+		return err
+	}
+
+	endPhase = a.Phase("scan references")
+	refs, err := chassis.UndeclaredReferences(a.Dir, c)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	a.result = &AdoptResult{Paths: refs, DryRun: a.DryRun()}
+	if len(refs) == 0 {
+		a.Report(message.NothingToAdopt)
+		return nil
+	}
+
+	for _, ref := range refs {
+		a.Term().Printfln("  + %s (%s)", ref.Chassis, strings.Join(ref.Files, ", "))
+	}
+	if !a.Mutates() {
+		a.Report(message.AdoptSuggested, len(refs))
+		return nil
+	}
+
+	// Parents sort first, so each path is checked against the ones added before
+	for _, ref := range refs {
+		if c.Exists(ref.Chassis) {
+			continue
+		}
+		if err := a.Limits.CheckAdd(c.Chassis, ref.Chassis); err != nil {
+			return err
+		}
+		if err := c.Add(ref.Chassis); err != nil {
+			return fmt.Errorf("failed to add %s: %w", ref.Chassis, err)
+		}
+	}
+
+	a.WarnAliasExpansion(c)
+	endPhase = a.Phase("save chassis")
+	err = c.Save(a.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	a.result.Applied = true
+	a.Report(message.Adopted, len(refs))
+	return nil
+}
@@ -0,0 +1,33 @@
+package adopt
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestAdoptGolden(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     *Adopt
+		dryRun     bool
+		undeclared bool
+	}{
+		{"nothing", &Adopt{}, false, false},
+		{"suggest", &Adopt{}, false, true},
+		{"dry-run", &Adopt{Apply: true}, true, true},
+		{"apply", &Adopt{Apply: true}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			if tt.undeclared {
+				golden.WriteFile(t, dir, "inst/prod/nodes/prod-5.yaml", "hostname: prod-5\nchassis:\n  - platform.foundation.network.egress\n")
+			}
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
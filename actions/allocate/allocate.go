@@ -0,0 +1,146 @@
+package allocate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// AllocateResult is the structured result of chassis:allocate.
+type AllocateResult struct {
+	Node    string `json:"node"` // hostname@platform
+	Chassis string `json:"chassis"`
+	File    string `json:"file"`
+	// Changed is false when the node was already allocated to the path.
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Allocate implements the chassis:allocate command
+type Allocate struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir     string
+	Node    string // hostname or hostname@platform
+	Chassis string
+	Policy  chassis.Policy
+
+	result *AllocateResult
+}
+
+// Result returns the structured result for JSON output.
+func (a *Allocate) Result() any {
+	return a.result
+}
+
+// Execute runs the allocate action
+func (a *Allocate) Execute() error {
+	endPhase := a.Phase("load chassis")
+	c, err := chassis.Load(a.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(a.Chassis) {
+		if canonical, ok := c.Canonical(a.Chassis); ok {
+			return fmt.Errorf("chassis %q not found in chassis.yaml, did you mean %q?", a.Chassis, canonical)
+		}
+		return fmt.Errorf("chassis %q not found in chassis.yaml", a.Chassis)
+	}
+
+	endPhase = a.Phase("find node")
+	node, err := chassis.FindNode(a.Dir, a.Node)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	a.result = &AllocateResult{
+		Node:    node.Hostname + "@" + node.Platform,
+		Chassis: a.Chassis,
+		File:    node.File,
+		DryRun:  a.DryRun(),
+	}
+	for _, entry := range node.Chassis {
+		if strings.TrimSpace(entry) == a.Chassis {
+			a.Report(message.NodeAlreadyAllocated, chassis.NodeName(node.Hostname, node.Platform), a.Chassis)
+			return nil
+		}
+	}
+
+	if err := a.checkCapacity(c, node); err != nil {
+		return err
+	}
+
+	if a.DryRun() {
+		a.Report(message.DryRun)
+		a.Term().Printfln("  %s: + %s", node.File, a.Chassis)
+		a.result.Changed = true
+		return nil
+	}
+
+	endPhase = a.Phase("update node")
+	a.result.Changed, err = chassis.AddAllocation(node.File, a.Chassis)
+	endPhase()
+	if err != nil {
+		return fmt.Errorf("failed to allocate %s: %w", a.result.Node, err)
+	}
+	a.Report(message.NodeAllocated, chassis.NodeName(node.Hostname, node.Platform), a.Chassis)
+	return nil
+}
+
+// checkCapacity verifies that the allocation keeps the path and its
+// ancestors within their max_nodes annotations on the node's platform,
+// as enforced by policy.capacity.
+func (a *Allocate) checkCapacity(c *chassis.Chassis, node chassis.Node) error {
+	if a.Policy.Capacity.Severity == chassis.PolicyOff {
+		return nil
+	}
+	meta, err := pkgchassis.LoadMeta(a.Dir)
+	if err != nil {
+		return err
+	}
+	nodes, err := chassis.LoadNodes(a.Dir, node.Platform)
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		a.Log().Debug("Failed to load some node files", "error", skipped)
+		a.Degrade("failed to load some node files", skipped)
+	}
+	for i, n := range nodes {
+		if n.File == node.File {
+			nodes[i].Chassis = append(append([]string(nil), n.Chassis...), a.Chassis)
+		}
+	}
+
+	for p := a.Chassis; p != ""; p = pkgchassis.Parent(p) {
+		limit := meta[p].MaxNodes
+		if limit <= 0 {
+			continue
+		}
+		count := chassis.NodeCounts(c, nodes, p)[node.Platform]
+		if count <= limit {
+			continue
+		}
+		if a.Policy.Capacity.Severity == chassis.PolicyWarning {
+			a.Report(message.CapacityExceeded, p, count, node.Platform, limit)
+			continue
+		}
+		return fmt.Errorf("allocating %s would give %s %d node(s) of platform %s, max_nodes is %d",
+			chassis.NodeName(node.Hostname, node.Platform), p, count, node.Platform, limit)
+	}
+	return nil
+}
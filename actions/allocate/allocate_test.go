@@ -0,0 +1,36 @@
+package allocate
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestAllocateGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Allocate
+		dryRun bool
+		node   string // replaces prod-1.yaml of the fixture
+	}{
+		{"allocate", &Allocate{Node: "prod-1@prod", Chassis: "platform.foundation.storage.kv"}, false, ""},
+		{"dry-run", &Allocate{Node: "prod-1", Chassis: "platform.foundation.storage.kv"}, true, ""},
+		{"already", &Allocate{Node: "prod-1", Chassis: "platform.foundation.cluster.control"}, false, ""},
+		{"already-padded", &Allocate{Node: "prod-1", Chassis: "platform.foundation.cluster.control"}, false,
+			"hostname: prod-1\nchassis:\n  - \" platform.foundation.cluster.control \"\n"},
+		{"unknown-chassis", &Allocate{Node: "prod-1", Chassis: "platform.foundation.storage.sql"}, false, ""},
+		{"unknown-node", &Allocate{Node: "prod-9", Chassis: "platform.foundation.storage.kv"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			if tt.node != "" {
+				golden.WriteFile(t, dir, "inst/prod/nodes/prod-1.yaml", tt.node)
+			}
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".prod-1.yaml", dir, "inst/prod/nodes/prod-1.yaml")
+		})
+	}
+}
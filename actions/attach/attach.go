@@ -0,0 +1,120 @@
+package attach
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// AttachResult is the structured result of chassis:attach.
+type AttachResult struct {
+	Component string `json:"component"`
+	Chassis   string `json:"chassis"`
+	Version   string `json:"version,omitempty"`
+	Playbook  string `json:"playbook"`
+	// Changed is false when a play of the path already had the role.
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Attach implements the chassis:attach command
+type Attach struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir       string
+	Component string
+	Chassis   string
+	Version   string
+	Policy    chassis.Policy
+
+	result *AttachResult
+}
+
+// Result returns the structured result for JSON output.
+func (a *Attach) Result() any {
+	return a.result
+}
+
+// Execute runs the attach action
+func (a *Attach) Execute() error {
+	endPhase := a.Phase("load chassis")
+	c, err := chassis.Load(a.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(a.Chassis) {
+		if canonical, ok := c.Canonical(a.Chassis); ok {
+			return fmt.Errorf("chassis %q not found in chassis.yaml, did you mean %q?", a.Chassis, canonical)
+		}
+		return fmt.Errorf("chassis %q not found in chassis.yaml", a.Chassis)
+	}
+	playbook, err := chassis.LayerPlaybook(a.Dir, a.Chassis)
+	if err != nil {
+		return err
+	}
+	if err := a.checkOwnership(); err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(a.Dir, playbook)
+	if err != nil {
+		rel = playbook
+	}
+	a.result = &AttachResult{
+		Component: a.Component,
+		Chassis:   a.Chassis,
+		Version:   a.Version,
+		Playbook:  rel,
+		DryRun:    a.DryRun(),
+	}
+
+	endPhase = a.Phase("update playbook")
+	a.result.Changed, err = chassis.AttachRole(playbook, a.Chassis, a.Component, a.Version, a.DryRun())
+	endPhase()
+	if err != nil {
+		return fmt.Errorf("failed to attach %s: %w", a.Component, err)
+	}
+	if !a.result.Changed {
+		a.Report(message.ComponentAlreadyAttached, a.Component, a.Chassis)
+		return nil
+	}
+	if a.DryRun() {
+		a.Report(message.DryRun)
+		a.Term().Printfln("  %s: %s + %s", rel, a.Chassis, a.Component)
+		return nil
+	}
+	a.Report(message.ComponentAttached, a.Component, a.Chassis, rel)
+	return nil
+}
+
+// checkOwnership verifies that the role belongs under the chassis path, as
+// enforced by policy.layer_ownership. Without a severity a mismatch is
+// reported but the role is attached, matching chassis:validate.
+func (a *Attach) checkOwnership() error {
+	ownership := a.Policy.LayerOwnership
+	if ownership.Severity == chassis.PolicyOff {
+		return nil
+	}
+	ok, allowed := ownership.Owns(a.Component, a.Chassis)
+	if ok {
+		return nil
+	}
+	if ownership.Severity == chassis.PolicyError {
+		return fmt.Errorf("role %s can't be attached outside its layer (expected under %s)",
+			a.Component, strings.Join(allowed, ", "))
+	}
+	a.Report(message.ComponentOutsideLayer, a.Component, strings.Join(allowed, ", "))
+	return nil
+}
@@ -0,0 +1,30 @@
+package attach
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestAttachGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Attach
+		dryRun bool
+	}{
+		{"attach", &Attach{Component: "foundation.network.traefik", Chassis: "platform.foundation.network.ingress"}, false},
+		{"constraint", &Attach{Component: "foundation.network.traefik", Chassis: "platform.foundation.network.ingress", Version: "~2.10"}, false},
+		{"dry-run", &Attach{Component: "foundation.network.traefik", Chassis: "platform.foundation.network.ingress"}, true},
+		{"already", &Attach{Component: "foundation.storage.redis", Chassis: "platform.foundation.storage.kv"}, false},
+		{"unknown-chassis", &Attach{Component: "foundation.network.traefik", Chassis: "platform.foundation.network.egress"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".foundation.yaml", dir, "src/foundation/foundation.yaml")
+		})
+	}
+}
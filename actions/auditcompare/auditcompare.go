@@ -0,0 +1,339 @@
+package auditcompare
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Export formats.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// HostDiff lists the inventory groups of a host known to one side only.
+type HostDiff struct {
+	Hostname string   `json:"hostname"`
+	Missing  []string `json:"missing,omitempty"` // groups from the chassis, not in the export
+	Extra    []string `json:"extra,omitempty"`   // groups from the export, not from the chassis
+}
+
+// AuditResult is the structured result of chassis:audit-compare.
+type AuditResult struct {
+	File          string     `json:"file"`
+	Format        string     `json:"format"`
+	Matching      int        `json:"matching"`
+	OnlyChassis   []string   `json:"only_chassis"`
+	OnlyExternal  []string   `json:"only_external"`
+	Mismatches    []HostDiff `json:"mismatches"`
+	UnknownGroups []string   `json:"unknown_groups"`
+
+	message.Log
+}
+
+// AuditCompare implements the chassis:audit-compare command
+type AuditCompare struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir          string
+	File         string
+	Format       string
+	Platform     string
+	AllGroups    bool // compare groups unknown to the chassis too
+	Distribution pkgchassis.Strategy
+
+	result *AuditResult
+}
+
+// Result returns the structured result for JSON output.
+func (a *AuditCompare) Result() any {
+	return a.result
+}
+
+// Execute runs the audit-compare action
+func (a *AuditCompare) Execute() error {
+	format := a.Format
+	if format == "" {
+		format = FormatCSV
+		if strings.EqualFold(filepath.Ext(a.File), ".json") {
+			format = FormatJSON
+		}
+	}
+	if format != FormatCSV && format != FormatJSON {
+		return fmt.Errorf("unknown export format %q (supported: %s, %s)", a.Format, FormatCSV, FormatJSON)
+	}
+
+	endPhase := a.Phase("read " + a.File)
+	data, err := os.ReadFile(a.File)
+	if err != nil {
+		endPhase()
+		return err
+	}
+	var external map[string][]string
+	if format == FormatJSON {
+		external, err = readJSON(data)
+	} else {
+		external, err = readCSV(bytes.NewReader(data))
+	}
+	endPhase()
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", a.File, err)
+	}
+
+	endPhase = a.Phase("load chassis")
+	c, err := chassis.Load(a.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	distributor, err := pkgchassis.NewDistributor(a.Distribution)
+	if err != nil {
+		return err
+	}
+
+	endPhase = a.Phase("load nodes")
+	var nodes []chassis.Node
+	err = chassis.ForEachNode(a.Dir, a.Platform, func(n chassis.Node) error {
+		nodes = append(nodes, n)
+		return nil
+	})
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		a.Log().Debug("Failed to load some node files", "error", skipped)
+		a.Degrade("failed to load some node files", skipped)
+	}
+
+	a.result = &AuditResult{
+		File:          a.File,
+		Format:        format,
+		OnlyChassis:   []string{},
+		OnlyExternal:  []string{},
+		Mismatches:    []HostDiff{},
+		UnknownGroups: []string{},
+	}
+	a.compare(chassisGroups(c, distributor, nodes), external, knownGroups(c))
+	a.print()
+	return nil
+}
+
+// chassisGroups returns the inventory groups of each host, as exported by
+// chassis:export: hosts of several platforms are merged.
+func chassisGroups(c *chassis.Chassis, d pkgchassis.Distributor, nodes []chassis.Node) map[string][]string {
+	paths := make(map[string][]string)
+	for _, n := range pkgchassis.Allocate(c.Chassis, d, chassis.DeclaredNodes(nodes)) {
+		for _, p := range n.Paths() {
+			if c.Exists(p) {
+				paths[n.Hostname] = append(paths[n.Hostname], p)
+			}
+		}
+		if _, ok := paths[n.Hostname]; !ok {
+			paths[n.Hostname] = nil
+		}
+	}
+	groups := make(map[string][]string, len(paths))
+	for hostname, p := range paths {
+		groups[hostname] = chassis.InventoryGroups(p)
+	}
+	for _, n := range nodes {
+		if n.Quarantined {
+			groups[n.Hostname] = addGroups(groups[n.Hostname], []string{export.QuarantinedGroup})
+		}
+	}
+	return groups
+}
+
+// knownGroups returns the inventory groups derived from the chassis.
+func knownGroups(c *chassis.Chassis) map[string]bool {
+	known := map[string]bool{export.QuarantinedGroup: true}
+	for _, p := range c.Flatten() {
+		known[pkgchassis.GroupName(p)] = true
+	}
+	return known
+}
+
+// compare fills the result with the differences between both sides.
+func (a *AuditCompare) compare(local, external map[string][]string, known map[string]bool) {
+	r := a.result
+	unknown := make(map[string]bool)
+	for hostname, groups := range external {
+		if _, ok := local[hostname]; !ok {
+			r.OnlyExternal = append(r.OnlyExternal, hostname)
+			continue
+		}
+		var kept []string
+		for _, g := range groups {
+			if !known[g] {
+				unknown[g] = true
+				if !a.AllGroups {
+					continue
+				}
+			}
+			kept = append(kept, g)
+		}
+		d := HostDiff{
+			Hostname: hostname,
+			Missing:  chassis.Subtract(local[hostname], kept),
+			Extra:    chassis.Subtract(kept, local[hostname]),
+		}
+		if len(d.Missing) > 0 || len(d.Extra) > 0 {
+			r.Mismatches = append(r.Mismatches, d)
+		} else {
+			r.Matching++
+		}
+	}
+	for hostname := range local {
+		if _, ok := external[hostname]; !ok {
+			r.OnlyChassis = append(r.OnlyChassis, hostname)
+		}
+	}
+	for g := range unknown {
+		r.UnknownGroups = append(r.UnknownGroups, g)
+	}
+	sort.Strings(r.OnlyChassis)
+	sort.Strings(r.OnlyExternal)
+	sort.Strings(r.UnknownGroups)
+	sort.Slice(r.Mismatches, func(i, j int) bool { return r.Mismatches[i].Hostname < r.Mismatches[j].Hostname })
+}
+
+// print reports the differences.
+func (a *AuditCompare) print() {
+	r := a.result
+	if len(r.OnlyChassis) == 0 && len(r.OnlyExternal) == 0 && len(r.Mismatches) == 0 {
+		a.Report(message.AuditMatches, r.Matching, a.File)
+	}
+	if len(r.OnlyChassis) > 0 {
+		a.Term().Info().Printfln("Missing from %s (%d hosts)", a.File, len(r.OnlyChassis))
+		for _, h := range r.OnlyChassis {
+			a.Term().Printfln("  - %s", h)
+		}
+	}
+	if len(r.OnlyExternal) > 0 {
+		a.Term().Info().Printfln("Missing from the chassis (%d hosts)", len(r.OnlyExternal))
+		for _, h := range r.OnlyExternal {
+			a.Term().Printfln("  + %s", h)
+		}
+	}
+	if len(r.Mismatches) > 0 {
+		a.Term().Info().Printfln("Group differences (%d hosts)", len(r.Mismatches))
+		for _, d := range r.Mismatches {
+			a.Term().Printfln("  %s", d.Hostname)
+			for _, g := range d.Missing {
+				a.Term().Printfln("    - %s", g)
+			}
+			for _, g := range d.Extra {
+				a.Term().Printfln("    + %s", g)
+			}
+		}
+	}
+	if len(r.UnknownGroups) > 0 && !a.AllGroups {
+		a.Report(message.AuditUnknownGroups, len(r.UnknownGroups), strings.Join(r.UnknownGroups, ", "))
+	}
+}
+
+// readCSV parses rows of hostname and inventory groups. Groups may be
+// spread over the remaining columns or separated by ";" within a cell.
+// A header row starting with "hostname" is ignored, and rows of the same
+// host are merged.
+func readCSV(r io.Reader) (map[string][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	hosts := make(map[string][]string)
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		hostname := strings.TrimSpace(record[0])
+		if first && strings.EqualFold(hostname, "hostname") {
+			first = false
+			continue
+		}
+		first = false
+		if hostname == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: hostname is required", line)
+		}
+		var groups []string
+		for _, cell := range record[1:] {
+			groups = append(groups, strings.Split(cell, ";")...)
+		}
+		hosts[hostname] = addGroups(hosts[hostname], groups)
+	}
+	return hosts, nil
+}
+
+// readJSON parses an object of hostname → groups, or an array of objects
+// with hostname and groups fields.
+func readJSON(data []byte) (map[string][]string, error) {
+	hosts := make(map[string][]string)
+	var byHost map[string][]string
+	if err := json.Unmarshal(data, &byHost); err == nil {
+		for hostname, groups := range byHost {
+			hosts[hostname] = addGroups(hosts[hostname], groups)
+		}
+		return hosts, nil
+	}
+
+	var entries []struct {
+		Hostname string   `json:"hostname"`
+		Groups   []string `json:"groups"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("expected an object of hostname to groups or an array of {hostname, groups}: %w", err)
+	}
+	for i, e := range entries {
+		if e.Hostname == "" {
+			return nil, fmt.Errorf("entry %d: hostname is required", i+1)
+		}
+		hosts[e.Hostname] = addGroups(hosts[e.Hostname], e.Groups)
+	}
+	return hosts, nil
+}
+
+// addGroups adds groups to a sorted set of inventory group names. Chassis
+// paths are accepted for their group name.
+func addGroups(set, groups []string) []string {
+	for _, g := range groups {
+		g = pkgchassis.GroupName(strings.TrimSpace(g))
+		if g == "" {
+			continue
+		}
+		if i := sort.SearchStrings(set, g); i == len(set) || set[i] != g {
+			set = append(set[:i], append([]string{g}, set[i:]...)...)
+		}
+	}
+	if set == nil {
+		set = []string{}
+	}
+	return set
+}
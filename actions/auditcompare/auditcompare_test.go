@@ -0,0 +1,33 @@
+package auditcompare
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestAuditCompareGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	golden.WriteFile(t, dir, "cmdb.csv", `hostname,groups
+prod-1,platform;platform_foundation;platform_foundation_cluster;platform_foundation_cluster_control;linux
+prod-2,platform.foundation.cluster.nodes;platform.foundation.storage.kv
+prod-9,platform
+`)
+	golden.WriteFile(t, dir, "cmdb.json", `{"prod-4": ["platform", "platform.interaction", "platform.interaction.observability", "platform.cognition", "platform.cognition.data"]}`)
+
+	tests := []struct {
+		name   string
+		action *AuditCompare
+	}{
+		{"csv", &AuditCompare{File: filepath.Join(dir, "cmdb.csv"), Platform: "prod"}},
+		{"all-groups", &AuditCompare{File: filepath.Join(dir, "cmdb.csv"), Platform: "prod", AllGroups: true}},
+		{"json", &AuditCompare{File: filepath.Join(dir, "cmdb.json"), Platform: "prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
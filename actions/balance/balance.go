@@ -0,0 +1,239 @@
+package balance
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ChildCount is the node count of a child path within a platform.
+type ChildCount struct {
+	Platform string `json:"platform"`
+	Chassis  string `json:"chassis"`
+	Current  int    `json:"current"`
+	Proposed int    `json:"proposed"`
+	// Target is the desired count: --per-child, lowered to the max_nodes
+	// annotation of the child.
+	Target int `json:"target"`
+}
+
+// Move is a suggested allocation of an unallocated node.
+type Move struct {
+	Hostname string `json:"hostname"`
+	Platform string `json:"platform"`
+	File     string `json:"file"`
+	Chassis  string `json:"chassis"`
+}
+
+// BalanceResult is the structured result of chassis:balance.
+type BalanceResult struct {
+	Chassis  string       `json:"chassis"`
+	PerChild int          `json:"per_child"`
+	Children []ChildCount `json:"children"`
+	Moves    []Move       `json:"moves"`
+	Applied  bool         `json:"applied,omitempty"`
+	DryRun   bool         `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Balance implements the chassis:balance command
+type Balance struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Chassis  string
+	Platform string
+	PerChild int
+	Apply    bool
+
+	// Distribution selects how allocations are counted, as in chassis:show.
+	Distribution pkgchassis.Strategy
+
+	result *BalanceResult
+}
+
+// Result returns the structured result for JSON output.
+func (b *Balance) Result() any {
+	return b.result
+}
+
+// Mutates reports whether the action writes node files.
+func (b *Balance) Mutates() bool {
+	return b.Apply && !b.DryRun()
+}
+
+// Execute runs the balance action
+func (b *Balance) Execute() error {
+	if b.PerChild < 1 {
+		return fmt.Errorf("--per-child must be at least 1")
+	}
+
+	endPhase := b.Phase("load chassis")
+	c, err := chassis.Load(b.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(b.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", b.Chassis)
+	}
+	children := c.Children(b.Chassis)
+	if len(children) == 0 {
+		return fmt.Errorf("chassis %q has no children to balance", b.Chassis)
+	}
+	distributor, err := pkgchassis.NewDistributor(b.Distribution)
+	if err != nil {
+		return err
+	}
+
+	meta, err := pkgchassis.LoadMeta(b.Dir)
+	if err != nil {
+		return err
+	}
+
+	endPhase = b.Phase("load nodes")
+	nodes, err := chassis.LoadNodes(b.Dir, b.Platform)
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		b.Log().Debug("Failed to load some node files", "error", skipped)
+		b.Degrade("failed to load some node files", skipped)
+	}
+
+	byPlatform := make(map[string][]chassis.Node)
+	for _, n := range nodes {
+		byPlatform[n.Platform] = append(byPlatform[n.Platform], n)
+	}
+	platforms := make([]string, 0, len(byPlatform))
+	for platform := range byPlatform {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	b.result = &BalanceResult{
+		Chassis:  b.Chassis,
+		PerChild: b.PerChild,
+		Children: []ChildCount{},
+		Moves:    []Move{},
+		DryRun:   b.DryRun(),
+	}
+	for _, platform := range platforms {
+		counts, moves := b.plan(c, distributor, meta, children, byPlatform[platform])
+		b.result.Children = append(b.result.Children, counts...)
+		b.result.Moves = append(b.result.Moves, moves...)
+	}
+
+	if b.Mutates() && len(b.result.Moves) > 0 {
+		endPhase = b.Phase("update nodes")
+		for _, m := range b.result.Moves {
+			if _, err := chassis.AddAllocation(m.File, m.Chassis); err != nil {
+				endPhase()
+				return fmt.Errorf("failed to allocate %s@%s: %w", m.Hostname, m.Platform, err)
+			}
+		}
+		endPhase()
+		b.result.Applied = true
+	}
+
+	b.print()
+	return nil
+}
+
+// plan counts the nodes of a platform effectively allocated to each child
+// and assigns unallocated nodes to the child furthest below the desired
+// count, in hostname order. Children never get more nodes than their
+// max_nodes annotation.
+func (b *Balance) plan(c *chassis.Chassis, d pkgchassis.Distributor, meta pkgchassis.Meta, children []string, nodes []chassis.Node) ([]ChildCount, []Move) {
+	platform := nodes[0].Platform
+	counts := make([]ChildCount, len(children))
+	for i, child := range children {
+		counts[i] = ChildCount{Platform: platform, Chassis: child, Target: b.PerChild}
+		if limit := meta[child].MaxNodes; limit > 0 && limit < b.PerChild {
+			counts[i].Target = limit
+		}
+	}
+	for _, n := range pkgchassis.Allocate(c.Chassis, d, chassis.DeclaredNodes(nodes)) {
+		for i, child := range children {
+			if n.AllocatedTo(child) {
+				counts[i].Current++
+			}
+		}
+	}
+	for i := range counts {
+		counts[i].Proposed = counts[i].Current
+	}
+
+	unallocated := validate.Unallocated(nodes)
+	sort.Slice(unallocated, func(i, j int) bool { return unallocated[i].Hostname < unallocated[j].Hostname })
+
+	var moves []Move
+	for _, n := range unallocated {
+		best := -1
+		for i := range counts {
+			if counts[i].Proposed >= counts[i].Target {
+				continue
+			}
+			if best == -1 || counts[i].Target-counts[i].Proposed > counts[best].Target-counts[best].Proposed {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		counts[best].Proposed++
+		moves = append(moves, Move{Hostname: n.Hostname, Platform: n.Platform, File: n.File, Chassis: counts[best].Chassis})
+	}
+	return counts, moves
+}
+
+// print reports counts and suggested moves.
+func (b *Balance) print() {
+	r := b.result
+	for _, cc := range r.Children {
+		line := fmt.Sprintf("  %s@%s: %d/%d", cc.Chassis, cc.Platform, cc.Current, cc.Target)
+		if cc.Proposed != cc.Current {
+			line += fmt.Sprintf(" -> %d", cc.Proposed)
+		}
+		b.Term().Printfln("%s", line)
+	}
+
+	short := 0
+	for _, cc := range r.Children {
+		if cc.Proposed < cc.Target {
+			short++
+		}
+	}
+
+	if len(r.Moves) == 0 {
+		if short == 0 {
+			b.Report(message.Balanced, r.Chassis)
+		}
+	} else {
+		for _, m := range r.Moves {
+			b.Term().Printfln("  %s: + %s", m.File, m.Chassis)
+		}
+		if r.Applied {
+			b.Report(message.BalanceApplied, len(r.Moves), r.Chassis)
+		} else {
+			b.Report(message.BalanceSuggested, len(r.Moves))
+		}
+	}
+	if short > 0 {
+		b.Report(message.BalanceShort, short, r.PerChild)
+	}
+}
@@ -0,0 +1,32 @@
+package balance
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+func TestBalanceGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Balance
+		dryRun bool
+	}{
+		{"report", &Balance{Chassis: "platform.foundation.cluster", PerChild: 2}, false},
+		// Only nodes allocated to a child or below count for it
+		{"explicit-only", &Balance{Chassis: "platform.foundation.cluster", PerChild: 2,
+			Distribution: pkgchassis.StrategyExplicitOnly}, false},
+		{"platform", &Balance{Chassis: "platform.foundation.cluster", Platform: "prod", PerChild: 1}, false},
+		{"dry-run", &Balance{Chassis: "platform.foundation.cluster", Platform: "prod", PerChild: 2, Apply: true}, true},
+		{"apply", &Balance{Chassis: "platform.foundation.cluster", Platform: "prod", PerChild: 2, Apply: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
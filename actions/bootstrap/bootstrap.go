@@ -0,0 +1,219 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// DefaultPattern names nodes after the last segment of their chassis path.
+const DefaultPattern = "{{.Name}}-{{.Index}}"
+
+// hostnamePattern matches hostnames usable as node file names.
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+
+// Count is the number of nodes to generate for a chassis path.
+type Count struct {
+	Chassis string `json:"chassis"`
+	Nodes   int    `json:"nodes"`
+}
+
+// GeneratedNode is a skeleton node file.
+type GeneratedNode struct {
+	Hostname string `json:"hostname"`
+	File     string `json:"file"`
+	Chassis  string `json:"chassis"`
+	Existing bool   `json:"existing,omitempty"` // the file already exists and is kept
+}
+
+// BootstrapResult is the structured result of chassis:bootstrap.
+type BootstrapResult struct {
+	Platform string          `json:"platform"`
+	Counts   []Count         `json:"counts"`
+	Nodes    []GeneratedNode `json:"nodes"`
+	DryRun   bool            `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Bootstrap implements the chassis:bootstrap command
+type Bootstrap struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir      string
+	Platform string
+	Count    string // comma-separated <chassis>=<n>, empty for min_nodes annotations
+	Pattern  string // hostname template
+
+	result *BootstrapResult
+}
+
+// Result returns the structured result for JSON output.
+func (b *Bootstrap) Result() any {
+	return b.result
+}
+
+// Execute runs the bootstrap action
+func (b *Bootstrap) Execute() error {
+	if b.Platform == "" {
+		return fmt.Errorf("--platform is required")
+	}
+	pattern := b.Pattern
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	tmpl, err := template.New("hostname").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid hostname pattern: %w", err)
+	}
+
+	endPhase := b.Phase("load chassis")
+	c, err := chassis.Load(b.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	counts, err := parseCounts(b.Count)
+	if err != nil {
+		return err
+	}
+	meta, err := pkgchassis.LoadMeta(b.Dir)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		for _, p := range meta.Paths() {
+			if n := meta[p].MinNodes; n > 0 {
+				counts = append(counts, Count{Chassis: p, Nodes: n})
+			}
+		}
+		if len(counts) == 0 {
+			return fmt.Errorf("nothing to bootstrap: pass --count <chassis>=<n>, or annotate paths with min_nodes in %s", pkgchassis.MetaFile)
+		}
+	}
+	for _, cnt := range counts {
+		if !c.Exists(cnt.Chassis) {
+			return fmt.Errorf("chassis %q not found in chassis.yaml", cnt.Chassis)
+		}
+		if limit := meta[cnt.Chassis].MaxNodes; limit > 0 && cnt.Nodes > limit {
+			return fmt.Errorf("%d node(s) requested for %s, max_nodes is %d", cnt.Nodes, cnt.Chassis, limit)
+		}
+	}
+
+	b.result = &BootstrapResult{
+		Platform: b.Platform,
+		Counts:   counts,
+		Nodes:    []GeneratedNode{},
+		DryRun:   b.DryRun(),
+	}
+	seen := make(map[string]string)
+	for _, cnt := range counts {
+		for i := 1; i <= cnt.Nodes; i++ {
+			hostname, err := render(tmpl, b.Platform, cnt.Chassis, i)
+			if err != nil {
+				return err
+			}
+			if other, ok := seen[hostname]; ok {
+				return fmt.Errorf("hostname %q is generated for both %s and %s; use a --pattern including {{.Chassis}} or another distinct field", hostname, other, cnt.Chassis)
+			}
+			seen[hostname] = cnt.Chassis
+
+			file := chassis.NodeFile(b.Dir, b.Platform, hostname)
+			_, statErr := os.Stat(file)
+			b.result.Nodes = append(b.result.Nodes, GeneratedNode{
+				Hostname: hostname,
+				File:     file,
+				Chassis:  cnt.Chassis,
+				Existing: statErr == nil,
+			})
+		}
+	}
+
+	if !b.DryRun() {
+		endPhase = b.Phase("write nodes")
+		for _, n := range b.result.Nodes {
+			if n.Existing {
+				continue
+			}
+			if _, err := chassis.SetAllocations(n.File, n.Hostname, []string{n.Chassis}); err != nil {
+				endPhase()
+				return fmt.Errorf("failed to create %s: %w", n.File, err)
+			}
+		}
+		endPhase()
+	}
+
+	b.print()
+	return nil
+}
+
+// print lists the node files created and kept.
+func (b *Bootstrap) print() {
+	r := b.result
+	if r.DryRun {
+		b.Report(message.DryRun)
+	}
+	created, existing := 0, 0
+	for _, n := range r.Nodes {
+		if n.Existing {
+			existing++
+			continue
+		}
+		created++
+		b.Term().Printfln("  + %s: %s", n.File, n.Chassis)
+	}
+	if existing > 0 {
+		b.Report(message.BootstrapKept, existing)
+	}
+	b.Report(message.NodesBootstrapped, created, r.Platform)
+}
+
+// parseCounts parses a comma-separated list of <chassis>=<n>.
+func parseCounts(spec string) ([]Count, error) {
+	var counts []Count
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		path, value, ok := strings.Cut(item, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid count %q (expected <chassis>=<n> with n at least 1)", item)
+		}
+		counts = append(counts, Count{Chassis: strings.TrimSpace(path), Nodes: n})
+	}
+	return counts, nil
+}
+
+// render executes the hostname pattern for the index-th node of a chassis path.
+func render(tmpl *template.Template, platform, chassisPath string, index int) (string, error) {
+	data := struct {
+		Platform, Chassis, Name string
+		Index                   int
+	}{platform, chassisPath, chassisPath[strings.LastIndex(chassisPath, ".")+1:], index}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("invalid hostname pattern: %w", err)
+	}
+	hostname := sb.String()
+	if !hostnamePattern.MatchString(hostname) {
+		return "", fmt.Errorf("hostname pattern renders %q for %s, which isn't a valid hostname", hostname, chassisPath)
+	}
+	return hostname, nil
+}
@@ -0,0 +1,29 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestBootstrapGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Bootstrap
+		dryRun bool
+	}{
+		{"count", &Bootstrap{Platform: "staging", Count: "platform.foundation.cluster.control=1,platform.foundation.cluster.nodes=2"}, false},
+		{"dry-run", &Bootstrap{Platform: "staging", Count: "platform.foundation.cluster.nodes=2"}, true},
+		{"pattern", &Bootstrap{Platform: "staging", Count: "platform.foundation.cluster.nodes=2", Pattern: "{{.Platform}}-worker-{{.Index}}"}, false},
+		{"existing", &Bootstrap{Platform: "prod", Count: "platform.foundation.cluster.nodes=3", Pattern: "prod-{{.Index}}"}, false},
+		{"unknown-chassis", &Bootstrap{Platform: "staging", Count: "platform.foundation.compute=1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
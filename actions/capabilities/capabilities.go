@@ -0,0 +1,140 @@
+package capabilities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ResultSchemaVersion is the version of the JSON results of all actions.
+// It is increased on incompatible changes, e.g. renamed or removed fields.
+const ResultSchemaVersion = 1
+
+// Flag describes an option of an action.
+type Flag struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   any    `json:"default,omitempty"`
+	Enum      []any  `json:"enum,omitempty"`
+}
+
+// ActionInfo describes an action of the plugin.
+type ActionInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Arguments   []string `json:"arguments"`
+	Flags       []Flag   `json:"flags"`
+	// Formats lists the values of the action's format option, if any.
+	Formats []string `json:"formats,omitempty"`
+}
+
+// CapabilitiesResult is the structured result of chassis:capabilities.
+type CapabilitiesResult struct {
+	Version string       `json:"version"`
+	Actions []ActionInfo `json:"actions"`
+	// OutputFormats are the output modes every action supports.
+	OutputFormats []string `json:"output_formats"`
+	// Schemas are the versions of the file formats and results the plugin reads and writes.
+	Schemas map[string]int `json:"schemas"`
+}
+
+// Capabilities implements the chassis:capabilities command
+type Capabilities struct {
+	action.WithLogger
+	action.WithTerm
+
+	Version     string
+	Definitions map[string][]byte // action name → action YAML, including global options
+
+	result *CapabilitiesResult
+}
+
+// Result returns the structured result for JSON output.
+func (c *Capabilities) Result() any {
+	return c.result
+}
+
+// definition is the part of an action YAML describing its interface.
+type definition struct {
+	Action struct {
+		Description string `yaml:"description"`
+		Arguments   []struct {
+			Name string `yaml:"name"`
+		} `yaml:"arguments"`
+		Options []struct {
+			Name      string `yaml:"name"`
+			Shorthand string `yaml:"shorthand"`
+			Type      string `yaml:"type"`
+			Default   any    `yaml:"default"`
+			Enum      []any  `yaml:"enum"`
+		} `yaml:"options"`
+	} `yaml:"action"`
+}
+
+// Execute runs the capabilities action
+func (c *Capabilities) Execute() error {
+	names := make([]string, 0, len(c.Definitions))
+	for name := range c.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.result = &CapabilitiesResult{
+		Version:       c.Version,
+		Actions:       make([]ActionInfo, 0, len(names)),
+		OutputFormats: []string{"text", "json"},
+		Schemas: map[string]int{
+			"chassis":  chassis.FormatVersion,
+			"results":  ResultSchemaVersion,
+			"snapshot": export.SnapshotVersion,
+		},
+	}
+
+	for _, name := range names {
+		var def definition
+		if err := yaml.Unmarshal(c.Definitions[name], &def); err != nil {
+			return fmt.Errorf("invalid definition of %s: %w", name, err)
+		}
+		info := ActionInfo{
+			Name:        name,
+			Description: def.Action.Description,
+			Arguments:   []string{},
+			Flags:       []Flag{},
+		}
+		for _, a := range def.Action.Arguments {
+			info.Arguments = append(info.Arguments, a.Name)
+		}
+		for _, o := range def.Action.Options {
+			info.Flags = append(info.Flags, Flag{
+				Name:      o.Name,
+				Shorthand: o.Shorthand,
+				Type:      o.Type,
+				Default:   o.Default,
+				Enum:      o.Enum,
+			})
+			if o.Name == "format" {
+				for _, v := range o.Enum {
+					info.Formats = append(info.Formats, fmt.Sprint(v))
+				}
+			}
+		}
+		c.result.Actions = append(c.result.Actions, info)
+	}
+
+	c.Term().Info().Printfln("plasmactl-chassis %s", c.result.Version)
+	for _, a := range c.result.Actions {
+		flags := make([]string, 0, len(a.Flags))
+		for _, f := range a.Flags {
+			flags = append(flags, "--"+f.Name)
+		}
+		c.Term().Printfln("  %s  %s", a.Name, strings.Join(flags, " "))
+	}
+	return nil
+}
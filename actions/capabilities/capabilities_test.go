@@ -0,0 +1,24 @@
+package capabilities
+
+import (
+	"os"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestCapabilitiesGolden(t *testing.T) {
+	definitions := make(map[string][]byte)
+	for name, file := range map[string]string{
+		"chassis:exists": "../exists/exists.yaml",
+		"chassis:parent": "../parent/parent.yaml",
+		"chassis:add":    "../add/add.yaml",
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		definitions[name] = data
+	}
+	golden.Run(t, "capabilities", "", &Capabilities{Version: "v1.0.0", Definitions: definitions})
+}
@@ -0,0 +1,67 @@
+package children
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ChildrenResult is the structured result of chassis:children.
+type ChildrenResult struct {
+	Chassis  string   `json:"chassis"`
+	Children []string `json:"children"`
+
+	message.Log
+}
+
+// Children implements the chassis:children command
+type Children struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir       string
+	Chassis   string
+	Recursive bool // all descendants instead of direct children
+
+	result *ChildrenResult
+}
+
+// Result returns the structured result for JSON output.
+func (ch *Children) Result() any {
+	return ch.result
+}
+
+// Execute runs the children action. Paths are printed one per line in
+// chassis.yaml order, for scripting.
+func (ch *Children) Execute() error {
+	endPhase := ch.Phase("load chassis")
+	c, err := pkgchassis.Load(ch.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(ch.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", ch.Chassis)
+	}
+
+	ch.result = &ChildrenResult{Chassis: ch.Chassis, Children: []string{}}
+	if ch.Recursive {
+		for _, p := range c.FlattenWithPrefix(ch.Chassis) {
+			if p != ch.Chassis {
+				ch.result.Children = append(ch.result.Children, p)
+			}
+		}
+	} else {
+		ch.result.Children = append(ch.result.Children, c.Children(ch.Chassis)...)
+	}
+
+	for _, p := range ch.result.Children {
+		ch.Term().Printfln("%s", p)
+	}
+	return nil
+}
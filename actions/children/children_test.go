@@ -0,0 +1,25 @@
+package children
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestChildrenGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Children
+	}{
+		{"direct", &Children{Chassis: "platform.foundation"}},
+		{"recursive", &Children{Chassis: "platform.foundation", Recursive: true}},
+		{"leaf", &Children{Chassis: "platform.cognition.data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
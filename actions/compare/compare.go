@@ -0,0 +1,152 @@
+package compare
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// AttachmentDiff lists chassis paths a component is attached to on one side only.
+type AttachmentDiff struct {
+	Component string   `json:"component"`
+	Missing   []string `json:"missing,omitempty"`
+	Extra     []string `json:"extra,omitempty"`
+}
+
+// CompareResult is the structured output for chassis:compare
+type CompareResult struct {
+	Other       string           `json:"other"`
+	Missing     []string         `json:"missing"`
+	Extra       []string         `json:"extra"`
+	Attachments []AttachmentDiff `json:"attachments"`
+
+	message.Log
+}
+
+// Compare implements the chassis:compare command
+type Compare struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir   string
+	Other string
+
+	result *CompareResult
+}
+
+// Result returns the structured result for JSON output
+func (c *Compare) Result() any {
+	return c.result
+}
+
+// Execute runs the compare action
+func (c *Compare) Execute() error {
+	if c.Other == "" {
+		return fmt.Errorf("--other is required")
+	}
+
+	endPhase := c.Phase("load chassis")
+	local, err := pkgchassis.Load(c.Dir)
+	if err != nil {
+		endPhase()
+		return err
+	}
+	other, err := pkgchassis.Load(c.Other)
+	endPhase()
+	if err != nil {
+		return fmt.Errorf("other repository: %w", err)
+	}
+
+	endPhase = c.Phase("load components")
+	localAttachments := c.loadAttachments(c.Dir)
+	otherAttachments := c.loadAttachments(c.Other)
+	endPhase()
+
+	diff := pkgchassis.Diff(other, local)
+	c.result = &CompareResult{
+		Other:       c.Other,
+		Missing:     diff.Removed,
+		Extra:       diff.Added,
+		Attachments: diffAttachments(localAttachments, otherAttachments),
+	}
+
+	if diff.IsEmpty() && len(c.result.Attachments) == 0 {
+		c.Report(message.ChassisMatches, c.Other)
+		return nil
+	}
+
+	if len(c.result.Missing) > 0 {
+		c.Term().Info().Printfln("Missing locally (%d paths)", len(c.result.Missing))
+		for _, p := range c.result.Missing {
+			c.Term().Printfln("  - %s", p)
+		}
+	}
+	if len(c.result.Extra) > 0 {
+		c.Term().Info().Printfln("Extra locally (%d paths)", len(c.result.Extra))
+		for _, p := range c.result.Extra {
+			c.Term().Printfln("  + %s", p)
+		}
+	}
+	if len(c.result.Attachments) > 0 {
+		c.Term().Info().Printfln("Attachment differences (%d components)", len(c.result.Attachments))
+		for _, a := range c.result.Attachments {
+			c.Term().Printfln("  %s", a.Component)
+			for _, p := range a.Missing {
+				c.Term().Printfln("    - %s", p)
+			}
+			for _, p := range a.Extra {
+				c.Term().Printfln("    + %s", p)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadAttachments returns component → chassis paths for a repository.
+func (c *Compare) loadAttachments(dir string) map[string][]string {
+	attachments, err := chassis.LoadAttachments(dir, "")
+	if err != nil {
+		c.Log().Debug("Failed to load components", "dir", dir, "error", err)
+		c.Degrade("failed to load components of "+dir, err)
+	}
+	return chassis.AttachmentPaths(attachments)
+}
+
+// diffAttachments compares attachment maps, sorted by component name.
+func diffAttachments(local, other map[string][]string) []AttachmentDiff {
+	names := make(map[string]bool)
+	for name := range local {
+		names[name] = true
+	}
+	for name := range other {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	result := []AttachmentDiff{}
+	for _, name := range sorted {
+		d := AttachmentDiff{
+			Component: name,
+			Missing:   chassis.Subtract(other[name], local[name]),
+			Extra:     chassis.Subtract(local[name], other[name]),
+		}
+		if len(d.Missing) > 0 || len(d.Extra) > 0 {
+			result = append(result, d)
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package compare
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestCompareGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	other := golden.CopyRepo(t, filepath.Join(filepath.Dir(dir), "blueprint"))
+	golden.WriteFile(t, other, "chassis.yaml", `platform:
+  foundation:
+    - cluster:
+      - control
+      - nodes
+    - storage:
+      - kv
+      - object
+  interaction:
+    - observability
+    - management
+  cognition:
+    - data
+`)
+	golden.WriteFile(t, other, "src/cognition/cognition.yaml", `- hosts: platform.cognition
+  roles:
+    - cognition.data.postgres
+`)
+
+	tests := []struct {
+		name   string
+		action *Compare
+	}{
+		{"blueprint", &Compare{Other: other}},
+		{"same", &Compare{Other: dir}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
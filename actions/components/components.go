@@ -0,0 +1,154 @@
+package components
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// ComponentInfo is a component attached to a chassis path.
+type ComponentInfo struct {
+	Component string `json:"component"`
+	Version   string `json:"version,omitempty"`
+	// Constraint is the version constraint recorded with the role, if any.
+	Constraint string `json:"constraint,omitempty"`
+	Playbook   string `json:"playbook"`
+	Chassis    string `json:"chassis"`
+}
+
+// ComponentsResult is the structured result of chassis:components.
+type ComponentsResult struct {
+	Chassis    string          `json:"chassis,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Components []ComponentInfo `json:"components"`
+
+	message.Log
+}
+
+// Components implements the chassis:components command
+type Components struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir     string
+	Chassis string // only components attached to this subtree
+	Name    string // component name glob, e.g. "foundation.*"
+
+	result *ComponentsResult
+}
+
+// Result returns the structured result for JSON output.
+func (c *Components) Result() any {
+	return c.result
+}
+
+// Execute runs the components action
+func (c *Components) Execute() error {
+	if c.Name != "" {
+		if _, err := path.Match(c.Name, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", c.Name, err)
+		}
+	}
+
+	endPhase := c.Phase("load chassis")
+	ch, err := chassis.Load(c.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if c.Chassis != "" && !ch.Exists(c.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", c.Chassis)
+	}
+
+	endPhase = c.Phase("load attachments")
+	attachments, err := chassis.LoadAttachments(c.Dir, c.Chassis)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = c.Phase("load components")
+	versions, err := chassis.ComponentVersions(c.Dir)
+	endPhase()
+	if err != nil {
+		c.Log().Debug("Failed to load components", "error", err)
+		c.Degrade("failed to load components", err)
+	}
+
+	c.result = &ComponentsResult{Chassis: c.Chassis, Name: c.Name, Components: []ComponentInfo{}}
+	for _, a := range attachments {
+		if c.Name != "" {
+			if ok, _ := path.Match(c.Name, a.Component); !ok {
+				continue
+			}
+		}
+		playbook, err := filepath.Rel(c.Dir, a.Playbook)
+		if err != nil {
+			playbook = a.Playbook
+		}
+		c.result.Components = append(c.result.Components, ComponentInfo{
+			Component:  a.Component,
+			Version:    versions[a.Component],
+			Constraint: a.Version,
+			Playbook:   playbook,
+			Chassis:    a.Chassis,
+		})
+	}
+	sort.Slice(c.result.Components, func(i, j int) bool {
+		a, b := c.result.Components[i], c.result.Components[j]
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Chassis < b.Chassis
+	})
+
+	if len(c.result.Components) == 0 {
+		c.Report(message.NoComponents)
+		return nil
+	}
+	c.printTable()
+	return nil
+}
+
+// printTable prints one aligned row per attachment.
+func (c *Components) printTable() {
+	table := [][]string{{"COMPONENT", "VERSION", "PLAYBOOK", "CHASSIS"}}
+	for _, comp := range c.result.Components {
+		version := comp.Version
+		if comp.Constraint != "" {
+			version = strings.TrimSpace(version + " (" + comp.Constraint + ")")
+		}
+		if version == "" {
+			version = "-"
+		}
+		table = append(table, []string{comp.Component, version, comp.Playbook, comp.Chassis})
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, line := range table {
+		for i, cell := range line {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, line := range table {
+		var b strings.Builder
+		for i, cell := range line {
+			if i < len(line)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], cell)
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		c.Term().Printfln("  %s", b.String())
+	}
+}
@@ -0,0 +1,25 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestComponentsGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Components
+	}{
+		{"all", &Components{}},
+		{"subtree", &Components{Chassis: "platform.foundation"}},
+		{"name", &Components{Name: "interaction.*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package deallocate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// DeallocateResult is the structured result of chassis:deallocate.
+type DeallocateResult struct {
+	Node    string `json:"node"` // hostname@platform
+	Chassis string `json:"chassis"`
+	File    string `json:"file"`
+	// Changed is false when the node wasn't directly allocated to the path.
+	Changed bool `json:"changed"`
+	// Unallocated is set when the node is left without any allocation.
+	Unallocated bool `json:"unallocated,omitempty"`
+	DryRun      bool `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Deallocate implements the chassis:deallocate command
+type Deallocate struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir     string
+	Node    string // hostname or hostname@platform
+	Chassis string
+
+	result *DeallocateResult
+}
+
+// Result returns the structured result for JSON output.
+func (d *Deallocate) Result() any {
+	return d.result
+}
+
+// Execute runs the deallocate action. The path doesn't need to exist, so
+// entries left behind by removed paths can be cleaned up.
+func (d *Deallocate) Execute() error {
+	endPhase := d.Phase("find node")
+	node, err := chassis.FindNode(d.Dir, d.Node)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	name := chassis.NodeName(node.Hostname, node.Platform)
+	d.result = &DeallocateResult{
+		Node:    node.Hostname + "@" + node.Platform,
+		Chassis: d.Chassis,
+		File:    node.File,
+		DryRun:  d.DryRun(),
+	}
+
+	remaining := 0
+	for _, entry := range node.Chassis {
+		if strings.TrimSpace(entry) == d.Chassis {
+			d.result.Changed = true
+		} else {
+			remaining++
+		}
+	}
+	if !d.result.Changed {
+		d.Report(message.NodeNotAllocated, name, d.Chassis)
+		return nil
+	}
+	d.result.Unallocated = remaining == 0
+
+	if d.DryRun() {
+		d.Report(message.DryRun)
+		d.Term().Printfln("  %s: - %s", node.File, d.Chassis)
+	} else {
+		endPhase = d.Phase("update node")
+		_, err = chassis.RemoveAllocation(node.File, d.Chassis)
+		endPhase()
+		if err != nil {
+			return fmt.Errorf("failed to deallocate %s: %w", d.result.Node, err)
+		}
+		d.Report(message.NodeDeallocated, name, d.Chassis)
+	}
+	if d.result.Unallocated {
+		d.Report(message.NodeLeftUnallocated, name)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package deallocate
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestDeallocateGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Deallocate
+		dryRun bool
+	}{
+		{"deallocate", &Deallocate{Node: "prod-2@prod", Chassis: "platform.foundation.storage.kv"}, false},
+		{"dry-run", &Deallocate{Node: "prod-2", Chassis: "platform.foundation.storage.kv"}, true},
+		{"not-allocated", &Deallocate{Node: "prod-2", Chassis: "platform.foundation.cluster.control"}, false},
+		{"unknown-node", &Deallocate{Node: "prod-9", Chassis: "platform.foundation.storage.kv"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".prod-2.yaml", dir, "inst/prod/nodes/prod-2.yaml")
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package detach
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// DetachResult is the structured result of chassis:detach.
+type DetachResult struct {
+	Component string `json:"component"`
+	Chassis   string `json:"chassis"`
+	Playbook  string `json:"playbook"`
+	// Changed is false when no play of the path had the role.
+	Changed bool `json:"changed"`
+	// EmptyPlays counts the plays left without roles or tasks, including
+	// the removed ones.
+	EmptyPlays   int  `json:"empty_plays,omitempty"`
+	RemovedPlays int  `json:"removed_plays,omitempty"`
+	DryRun       bool `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Detach implements the chassis:detach command
+type Detach struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir         string
+	Component   string
+	Chassis     string
+	RemoveEmpty bool
+
+	result *DetachResult
+}
+
+// Result returns the structured result for JSON output.
+func (d *Detach) Result() any {
+	return d.result
+}
+
+// Execute runs the detach action
+func (d *Detach) Execute() error {
+	playbook, err := chassis.LayerPlaybook(d.Dir, d.Chassis)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(d.Dir, playbook)
+	if err != nil {
+		rel = playbook
+	}
+	d.result = &DetachResult{
+		Component: d.Component,
+		Chassis:   d.Chassis,
+		Playbook:  rel,
+		DryRun:    d.DryRun(),
+	}
+
+	endPhase := d.Phase("update playbook")
+	detachment, err := chassis.DetachRole(playbook, d.Chassis, d.Component, d.RemoveEmpty, d.DryRun())
+	endPhase()
+	if err != nil {
+		return fmt.Errorf("failed to detach %s: %w", d.Component, err)
+	}
+	d.result.Changed = detachment.Detached
+	d.result.EmptyPlays = detachment.EmptyPlays
+	d.result.RemovedPlays = detachment.RemovedPlays
+	if !detachment.Detached {
+		d.Report(message.ComponentNotAttached, d.Component, d.Chassis)
+		return nil
+	}
+
+	if d.DryRun() {
+		d.Report(message.DryRun)
+		d.Term().Printfln("  %s: %s - %s", rel, d.Chassis, d.Component)
+		if detachment.RemovedPlays > 0 {
+			d.Term().Printfln("  %s: - %d empty play(s) of %s", rel, detachment.RemovedPlays, d.Chassis)
+		}
+		return nil
+	}
+	d.Report(message.ComponentDetached, d.Component, d.Chassis, rel)
+	if detachment.RemovedPlays > 0 {
+		d.Report(message.EmptyPlaysRemoved, detachment.RemovedPlays, d.Chassis)
+	} else if detachment.EmptyPlays > 0 {
+		d.Report(message.PlaysLeftEmpty, detachment.EmptyPlays, d.Chassis, rel)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package detach
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestDetachGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Detach
+		dryRun bool
+	}{
+		{"detach", &Detach{Component: "foundation.cluster.k8s", Chassis: "platform.foundation.cluster"}, false},
+		{"dry-run", &Detach{Component: "foundation.cluster.k8s", Chassis: "platform.foundation.cluster"}, true},
+		{"remove-empty", &Detach{Component: "foundation.storage.redis", Chassis: "platform.foundation.storage.kv", RemoveEmpty: true}, false},
+		{"not-attached", &Detach{Component: "foundation.storage.redis", Chassis: "platform.foundation.cluster"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".foundation.yaml", dir, "src/foundation/foundation.yaml")
+		})
+	}
+}
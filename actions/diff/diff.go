@@ -0,0 +1,188 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// WorkingTree names the working tree as a side of the diff.
+const WorkingTree = "working tree"
+
+// DiffResult is the structured output for chassis:diff
+type DiffResult struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	pkgchassis.TopologyDiff
+
+	message.Log
+}
+
+// Diff implements the chassis:diff command
+type Diff struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir  string
+	From string // directory or git ref
+	To   string // directory or git ref, the working tree if empty
+
+	Distribution pkgchassis.Strategy
+
+	result *DiffResult
+}
+
+// Result returns the structured result for JSON output
+func (d *Diff) Result() any {
+	return d.result
+}
+
+// Execute runs the diff action
+func (d *Diff) Execute() error {
+	distributor, err := pkgchassis.NewDistributor(d.Distribution)
+	if err != nil {
+		return err
+	}
+
+	fromDir, cleanup, err := d.resolve(d.From)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	toDir, cleanup, err := d.resolve(d.To)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	older, err := d.loadTopology(fromDir, d.From, distributor)
+	if err != nil {
+		return err
+	}
+	newer, err := d.loadTopology(toDir, d.label(d.To), distributor)
+	if err != nil {
+		return err
+	}
+
+	d.result = &DiffResult{
+		From:         d.From,
+		To:           d.label(d.To),
+		TopologyDiff: pkgchassis.DiffTopology(older, newer),
+	}
+	if d.result.IsEmpty() {
+		d.Report(message.TopologyUnchanged, d.result.From, d.result.To)
+		return nil
+	}
+	d.print()
+	return nil
+}
+
+// label names a side of the diff for humans.
+func (d *Diff) label(source string) string {
+	if source == "" {
+		return WorkingTree
+	}
+	return source
+}
+
+// resolve returns the directory holding a side of the diff: the working
+// tree if source is empty, source itself if it is a directory, or else the
+// state of the repository at the git ref source, extracted to a temporary
+// directory removed by the returned cleanup function.
+func (d *Diff) resolve(source string) (string, func(), error) {
+	if source == "" {
+		return d.Dir, func() {}, nil
+	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return source, func() {}, nil
+	}
+	endPhase := d.Phase("checkout " + source)
+	dir, cleanup, err := chassis.CheckoutRef(d.Dir, source)
+	endPhase()
+	if err != nil {
+		return "", nil, fmt.Errorf("%s is neither a directory nor a git ref: %w", source, err)
+	}
+	return dir, cleanup, nil
+}
+
+// loadTopology loads the chassis, effective allocations and attachments of
+// a side of the diff.
+func (d *Diff) loadTopology(dir, label string, distributor pkgchassis.Distributor) (pkgchassis.Topology, error) {
+	endPhase := d.Phase("load " + label)
+	defer endPhase()
+
+	c, err := pkgchassis.Load(dir)
+	if err != nil {
+		return pkgchassis.Topology{}, fmt.Errorf("chassis at %s: %w", label, err)
+	}
+	t := pkgchassis.Topology{
+		Chassis:     c,
+		Allocations: make(map[string][]string),
+	}
+
+	nodes, err := chassis.LoadNodes(dir, "")
+	if err != nil {
+		d.Log().Debug("Failed to load nodes", "dir", dir, "error", err)
+		d.Degrade("failed to load nodes of "+label, err)
+	}
+	for _, n := range pkgchassis.Allocate(c, distributor, chassis.DeclaredNodes(nodes)) {
+		name := n.DisplayName()
+		t.Allocations[name] = append(t.Allocations[name], n.Paths()...)
+	}
+
+	attachments, err := chassis.LoadAttachments(dir, "")
+	if err != nil {
+		d.Log().Debug("Failed to load components", "dir", dir, "error", err)
+		d.Degrade("failed to load components of "+label, err)
+	}
+	t.Attachments = chassis.AttachmentPaths(attachments)
+	return t, nil
+}
+
+// print writes the human-readable report.
+func (d *Diff) print() {
+	r := d.result
+	d.Term().Info().Printfln("%s -> %s", r.From, r.To)
+	if n := len(r.Added) + len(r.Removed) + len(r.Renamed); n > 0 {
+		d.Term().Info().Printfln("Paths (%d changes)", n)
+		for _, p := range r.Added {
+			d.Term().Printfln("  + %s", p)
+		}
+		for _, p := range r.Removed {
+			d.Term().Printfln("  - %s", p)
+		}
+		for _, rn := range r.Renamed {
+			d.Term().Printfln("  ~ %s -> %s", rn.Old, rn.New)
+		}
+	}
+	for _, section := range []struct {
+		title  string
+		kind   string
+		deltas []pkgchassis.Delta
+	}{
+		{"Allocations", "nodes", r.Allocations},
+		{"Attachments", "components", r.Attachments},
+	} {
+		if len(section.deltas) == 0 {
+			continue
+		}
+		d.Term().Info().Printfln("%s (%d %s)", section.title, len(section.deltas), section.kind)
+		for _, delta := range section.deltas {
+			d.Term().Printfln("  %s", delta.Name)
+			for _, p := range delta.Added {
+				d.Term().Printfln("    + %s", p)
+			}
+			for _, p := range delta.Removed {
+				d.Term().Printfln("    - %s", p)
+			}
+		}
+	}
+}
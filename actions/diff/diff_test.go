@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestDiffGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	older := golden.CopyRepo(t, filepath.Join(filepath.Dir(dir), "older"))
+	golden.WriteFile(t, dir, "chassis.yaml", `platform:
+  foundation:
+    - k8s:
+      - control
+      - nodes
+    - storage:
+      - kv
+    - network:
+      - ingress
+  interaction:
+    - observability
+    - management
+    - web
+  cognition:
+    - data
+`)
+	golden.WriteFile(t, dir, "inst/dev/nodes/dev-1.yaml", `hostname: dev-1
+chassis:
+  - platform.foundation.k8s
+`)
+	golden.WriteFile(t, dir, "inst/prod/nodes/prod-1.yaml", `hostname: prod-1
+chassis:
+  - platform.foundation.k8s.control
+`)
+	golden.WriteFile(t, dir, "inst/prod/nodes/prod-2.yaml", `hostname: prod-2
+chassis:
+  - platform.foundation.k8s.nodes
+  - platform.foundation.storage.kv
+`)
+	golden.WriteFile(t, dir, "inst/prod/nodes/prod-3.yaml", `hostname: prod-3
+quarantined: true
+chassis:
+  - platform.foundation.k8s.control
+`)
+	golden.WriteFile(t, dir, "src/foundation/foundation.yaml", `- hosts: platform.foundation.k8s
+  roles:
+    - foundation.cluster.k8s
+    - role: foundation.cluster.etcd
+      version: "~3.5"
+- hosts: platform.foundation.storage.kv
+  roles:
+    - foundation.storage.redis
+- hosts: platform.interaction.web
+  roles:
+    - interaction.web.nginx
+`)
+
+	tests := []struct {
+		name   string
+		action *Diff
+	}{
+		{"working-tree", &Diff{From: older}},
+		{"reverse", &Diff{From: dir, To: older}},
+		{"unchanged", &Diff{From: older, To: older}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
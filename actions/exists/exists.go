@@ -0,0 +1,56 @@
+package exists
+
+import (
+	"github.com/launchrctl/launchr"
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ExitMissing is the exit code of chassis:exists for a path not declared in chassis.yaml.
+const ExitMissing = 2
+
+// ExistsResult is the structured result of chassis:exists.
+type ExistsResult struct {
+	Chassis string `json:"chassis"`
+	Exists  bool   `json:"exists"`
+
+	message.Log
+}
+
+// Exists implements the chassis:exists command
+type Exists struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir     string
+	Chassis string
+
+	result *ExistsResult
+}
+
+// Result returns the structured result for JSON output.
+func (e *Exists) Result() any {
+	return e.result
+}
+
+// Execute runs the exists action. It prints nothing: a missing path is
+// reported by exit code [ExitMissing] only, for use in shell conditions.
+func (e *Exists) Execute() error {
+	// Only chassis.yaml is read, no node files or playbooks
+	endPhase := e.Phase("load chassis")
+	c, err := pkgchassis.Load(e.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	e.result = &ExistsResult{Chassis: e.Chassis, Exists: c.Exists(e.Chassis)}
+	if !e.result.Exists {
+		return launchr.NewExitError(ExitMissing, "")
+	}
+	return nil
+}
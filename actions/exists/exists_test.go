@@ -0,0 +1,24 @@
+package exists
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestExistsGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Exists
+	}{
+		{"found", &Exists{Chassis: "platform.cognition.data"}},
+		{"missing", &Exists{Chassis: "platform.cognition.lake"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package explain
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+)
+
+// Kinds of explained codes.
+const (
+	KindRule    = "rule"
+	KindMessage = "message"
+)
+
+// CodeInfo is a known code with its one line summary.
+type CodeInfo struct {
+	Code    string `json:"code"`
+	Kind    string `json:"kind"`
+	Summary string `json:"summary"`
+}
+
+// ExplainResult is the structured result of chassis:explain.
+type ExplainResult struct {
+	Code        string     `json:"code,omitempty"`
+	Kind        string     `json:"kind,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Causes      []string   `json:"causes,omitempty"`
+	Remediation []string   `json:"remediation,omitempty"`
+	Codes       []CodeInfo `json:"codes,omitempty"`
+}
+
+// Explain implements the chassis:explain command
+type Explain struct {
+	action.WithLogger
+	action.WithTerm
+
+	Code string
+
+	result *ExplainResult
+}
+
+// Result returns the structured result for JSON output.
+func (e *Explain) Result() any {
+	return e.result
+}
+
+// Execute runs the explain action
+func (e *Explain) Execute() error {
+	if e.Code == "" {
+		e.result = &ExplainResult{Codes: allCodes()}
+		e.printCodes()
+		return nil
+	}
+
+	if r, err := validate.Lookup(e.Code); err == nil {
+		e.result = &ExplainResult{
+			Code:        r.Name,
+			Kind:        KindRule,
+			Description: r.Description,
+			Causes:      r.Causes,
+			Remediation: r.Remediation,
+		}
+	} else if level, format, ok := message.Lookup(message.Code(e.Code)); ok {
+		e.result = &ExplainResult{Code: e.Code, Kind: KindMessage, Description: format}
+		if x, ok := message.Explain(message.Code(e.Code)); ok {
+			e.result.Description = x.Description
+			e.result.Causes = x.Causes
+			e.result.Remediation = x.Remediation
+		} else if level == message.LevelSuccess || level == message.LevelInfo {
+			e.result.Remediation = []string{"None, this message reports a normal outcome"}
+		}
+	} else {
+		return fmt.Errorf("unknown code %q, run chassis:explain without arguments to list all codes", e.Code)
+	}
+
+	e.print()
+	return nil
+}
+
+// allCodes lists validation rules, then message codes.
+func allCodes() []CodeInfo {
+	var codes []CodeInfo
+	for _, r := range validate.Rules() {
+		codes = append(codes, CodeInfo{Code: r.Name, Kind: KindRule, Summary: r.Description})
+	}
+	for _, code := range message.Codes() {
+		_, format, _ := message.Lookup(code)
+		summary := format
+		if x, ok := message.Explain(code); ok {
+			summary = x.Description
+		}
+		codes = append(codes, CodeInfo{Code: string(code), Kind: KindMessage, Summary: summary})
+	}
+	return codes
+}
+
+// print prints the explanation of a single code.
+func (e *Explain) print() {
+	r := e.result
+	e.Term().Info().Printfln("%s (%s)", r.Code, r.Kind)
+	e.Term().Printfln("%s", r.Description)
+	if len(r.Causes) > 0 {
+		e.Term().Info().Println("Causes")
+		for _, c := range r.Causes {
+			e.Term().Printfln("  - %s", c)
+		}
+	}
+	if len(r.Remediation) > 0 {
+		e.Term().Info().Println("Remediation")
+		for _, s := range r.Remediation {
+			e.Term().Printfln("  - %s", s)
+		}
+	}
+}
+
+// printCodes prints all known codes grouped by kind.
+func (e *Explain) printCodes() {
+	headings := map[string]string{KindRule: "Validation rules", KindMessage: "Messages"}
+	kind := ""
+	for _, c := range e.result.Codes {
+		if c.Kind != kind {
+			kind = c.Kind
+			e.Term().Info().Println(headings[kind])
+		}
+		e.Term().Printfln("  %s", c.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package explain
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestExplainGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Explain
+	}{
+		{"all", &Explain{}},
+		{"rule", &Explain{Code: "node-unallocated"}},
+		{"message", &Explain{Code: "allocation_empty"}},
+		{"unknown", &Explain{Code: "nothing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden.Run(t, tt.name, "", tt.action)
+		})
+	}
+}
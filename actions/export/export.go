@@ -0,0 +1,252 @@
+package export
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// Export formats.
+const (
+	FormatInventory  = "inventory"
+	FormatSnapshot   = "snapshot"
+	FormatPromLabels = "prom-labels"
+	FormatSSHConfig  = "ssh-config"
+	FormatDOT        = "dot"
+)
+
+// ExportResult is the structured result of chassis:export.
+type ExportResult struct {
+	Format    string `json:"format"`
+	Output    string `json:"output,omitempty"`
+	Bytes     int    `json:"bytes"`
+	Checksum  string `json:"checksum,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	// Aliases maps the SSH host aliases of the ssh-config format to their nodes.
+	Aliases []export.SSHAlias `json:"aliases,omitempty"`
+	// Obfuscated tells whether hostnames, or "paths" also path segments, were obfuscated.
+	Obfuscated string `json:"obfuscated,omitempty"`
+
+	message.Log
+}
+
+// Export implements the chassis:export command
+type Export struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Format   string
+	Output   string // file to write, stdout if empty
+	Platform string
+	Checksum bool   // embed a content checksum in snapshots
+	Sign     bool   // write a detached snapshot signature
+	Match    string // regular expression paths must match
+	Exclude  string // regular expression paths must not match
+	Config   chassis.Config
+
+	// ExcludeQuarantined leaves quarantined nodes out of the inventory, labels, SSH config and graph
+	ExcludeQuarantined bool
+	// Obfuscate replaces hostnames, and path segments with ObfuscatePaths,
+	// by keyed digests.
+	Obfuscate      bool
+	ObfuscatePaths bool
+
+	filter     *chassis.PathFilter
+	obfuscator *export.Obfuscator
+	result     *ExportResult
+}
+
+// Result returns the structured result for JSON output.
+func (e *Export) Result() any {
+	return e.result
+}
+
+// Execute runs the export action
+func (e *Export) Execute() error {
+	if e.Sign && e.Output == "" {
+		return fmt.Errorf("--sign requires --output for the detached signature file")
+	}
+	if (e.Checksum || e.Sign) && e.Format != FormatSnapshot {
+		return fmt.Errorf("--checksum and --sign are only supported by the %s format", FormatSnapshot)
+	}
+	if (e.Obfuscate || e.ObfuscatePaths) && e.Format == FormatSSHConfig {
+		return fmt.Errorf("--obfuscate is not supported by the %s format: obfuscated hosts can't be connected to", FormatSSHConfig)
+	}
+	if (e.Match != "" || e.Exclude != "") && e.Format == FormatSnapshot {
+		return fmt.Errorf("--match and --exclude are not supported by the %s format: snapshots restore the whole chassis", FormatSnapshot)
+	}
+	if (e.Match != "" || e.Exclude != "") && e.ObfuscatePaths {
+		return fmt.Errorf("--match and --exclude can't be combined with --obfuscate-paths: paths are filtered before they are obfuscated")
+	}
+	filter, err := chassis.NewPathFilter(e.Match, e.Exclude)
+	if err != nil {
+		return err
+	}
+	e.filter = filter
+
+	endPhase := e.Phase("load chassis")
+	c, err := chassis.Load(e.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = e.Phase("load nodes")
+	nodes, err := chassis.LoadNodes(e.Dir, e.Platform)
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		e.Log().Debug("Failed to load some node files", "error", skipped)
+		e.Degrade("failed to load some node files", skipped)
+	}
+
+	e.result = &ExportResult{
+		Format: e.Format,
+		Output: e.Output,
+	}
+
+	if e.Obfuscate || e.ObfuscatePaths {
+		endPhase = e.Phase("obfuscate")
+		c, nodes, err = e.obfuscate(c, nodes)
+		endPhase()
+		if err != nil {
+			return err
+		}
+	}
+
+	endPhase = e.Phase("render " + e.Format)
+	data, err := e.render(c, nodes)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	e.result.Bytes = len(data)
+
+	if e.Output == "" {
+		e.Term().Printf("%s", data)
+		return nil
+	}
+	if err := os.WriteFile(e.Output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", e.Output, err)
+	}
+	e.Report(message.Exported, e.Format, e.Output)
+
+	if e.Sign {
+		if e.Config.Export.Snapshot.SignCommand == "" {
+			return fmt.Errorf("--sign requires export.snapshot.sign_command in the %s config", chassis.ConfigKey)
+		}
+		endPhase := e.Phase("sign")
+		sig, err := export.Sign(e.Config.Export.Snapshot.SignCommand, data)
+		endPhase()
+		if err != nil {
+			return err
+		}
+		e.result.Signature = export.SignatureFile(e.Output)
+		if err := os.WriteFile(e.result.Signature, sig, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", e.result.Signature, err)
+		}
+		e.Report(message.SnapshotSigned, e.result.Signature)
+	}
+	return nil
+}
+
+// obfuscate replaces hostnames, and path segments if requested, by digests
+// keyed with the key of the environment.
+func (e *Export) obfuscate(c *chassis.Chassis, nodes []chassis.Node) (*chassis.Chassis, []chassis.Node, error) {
+	key, err := e.Config.Export.Obfuscation.Key()
+	if err != nil {
+		return nil, nil, err
+	}
+	o, err := export.NewObfuscator(key, e.ObfuscatePaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.obfuscator = o
+	e.result.Obfuscated = "hostnames"
+	if e.ObfuscatePaths {
+		e.result.Obfuscated = "paths"
+	}
+	return o.Apply(c, nodes)
+}
+
+// render encodes the chassis and nodes in the requested format.
+func (e *Export) render(c *chassis.Chassis, nodes []chassis.Node) ([]byte, error) {
+	excludeQuarantined := e.ExcludeQuarantined || e.Config.Export.Inventory.ExcludeQuarantined
+	switch e.Format {
+	case "", FormatInventory:
+		inv, err := export.BuildInventory(c, nodes, export.InventoryOptions{
+			Distribution: e.Config.Distribution,
+			Limits:       e.Config.Limits,
+			HostVars:     e.Config.Export.Inventory.HostVarMapping(),
+
+			ExcludeQuarantined: excludeQuarantined,
+			Filter:             e.filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return inv.Marshal()
+	case FormatSnapshot:
+		snap := export.BuildSnapshot(c, nodes)
+		if e.Checksum {
+			if err := snap.Seal(); err != nil {
+				return nil, err
+			}
+			e.result.Checksum = snap.Checksum
+		}
+		return snap.Marshal()
+	case FormatPromLabels:
+		groups, err := export.BuildTargetGroups(c, nodes, export.LabelOptions{
+			Distribution:       e.Config.Distribution,
+			ExcludeQuarantined: excludeQuarantined,
+			Filter:             e.filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return groups.Marshal()
+	case FormatSSHConfig:
+		aliases, err := export.BuildSSHAliases(c, nodes, export.SSHOptions{
+			Distribution:       e.Config.Distribution,
+			Config:             e.Config.Export.SSH,
+			ExcludeQuarantined: excludeQuarantined,
+			Filter:             e.filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+		e.result.Aliases = aliases
+		return export.RenderSSHConfig(aliases, e.Config.Export.SSH), nil
+	case FormatDOT:
+		attachments, err := chassis.LoadAttachments(e.Dir, "")
+		if err != nil {
+			e.Log().Debug("Failed to load attachments", "error", err)
+			e.Degrade("failed to load attachments", err)
+		}
+		if e.obfuscator != nil {
+			for i := range attachments {
+				attachments[i].Chassis = e.obfuscator.Path(attachments[i].Chassis)
+			}
+		}
+		return export.RenderDOT(c, nodes, attachments, export.DOTOptions{
+			Config:             e.Config.Export.DOT,
+			ExcludeQuarantined: excludeQuarantined,
+			Filter:             e.filter,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q (supported: %s, %s, %s, %s, %s)",
+			e.Format, FormatInventory, FormatSnapshot, FormatPromLabels, FormatSSHConfig, FormatDOT)
+	}
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+// excludeQuarantined sets export.inventory.exclude_quarantined.
+var excludeQuarantined = chassis.Config{Export: chassis.ExportConfig{Inventory: chassis.InventoryConfig{ExcludeQuarantined: true}}}
+
+func TestExportGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Export
+	}{
+		{"inventory", &Export{Format: FormatInventory}},
+		{"inventory-platform", &Export{Format: FormatInventory, Platform: "prod", ExcludeQuarantined: true}},
+		{"inventory-match", &Export{Format: FormatInventory, Match: `cluster`}},
+		{"snapshot", &Export{Format: FormatSnapshot}},
+		{"prom-labels", &Export{Format: FormatPromLabels}},
+		{"prom-labels-exclude-quarantined", &Export{Format: FormatPromLabels, Config: excludeQuarantined}},
+		{"ssh-config", &Export{Format: FormatSSHConfig}},
+		{"dot", &Export{Format: FormatDOT}},
+		{"obfuscate", &Export{Format: FormatInventory, Obfuscate: true, ObfuscatePaths: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
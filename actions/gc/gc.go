@@ -0,0 +1,199 @@
+package gc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// GCResult is the structured result of chassis:gc.
+type GCResult struct {
+	Removed    []string            `json:"removed"`
+	Plays      []chassis.EmptyPlay `json:"plays"`
+	Kept       []string            `json:"kept,omitempty"` // empty paths kept because they are referenced
+	Aggressive bool                `json:"aggressive,omitempty"`
+	DryRun     bool                `json:"dry_run,omitempty"`
+	Errors     []chassis.FileError `json:"errors,omitempty"`
+
+	message.Log
+}
+
+// GC implements the chassis:gc command
+type GC struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir        string
+	Aggressive bool
+
+	result *GCResult
+}
+
+// Result returns the structured result for JSON output.
+func (g *GC) Result() any {
+	return g.result
+}
+
+// Execute runs the gc action
+func (g *GC) Execute() error {
+	endPhase := g.Phase("load chassis")
+	c, err := chassis.Load(g.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = g.Phase("load references")
+	referenced, err := g.referencedPaths()
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	g.result = &GCResult{
+		Removed:    []string{},
+		Plays:      []chassis.EmptyPlay{},
+		Aggressive: g.Aggressive,
+		DryRun:     g.DryRun(),
+	}
+
+	// An empty path that is still referenced is kept, and so are its ancestors
+	keep := func(p string) bool {
+		for _, r := range referenced {
+			if r == p || pkgchassis.IsDescendantOf(r, p) {
+				if !contains(g.result.Kept, p) {
+					g.result.Kept = append(g.result.Kept, p)
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	removed, err := c.Prune(g.Aggressive, keep)
+	if err != nil {
+		return err
+	}
+	g.result.Removed = append(g.result.Removed, removed...)
+
+	endPhase = g.Phase("prune plays")
+	plays, err := chassis.PruneEmptyPlays(g.Dir, g.DryRun())
+	endPhase()
+	g.result.Plays = append(g.result.Plays, plays...)
+	g.result.Errors = chassis.FileErrors(err)
+
+	if len(removed) > 0 && !g.DryRun() {
+		g.WarnAliasExpansion(c)
+		endPhase = g.Phase("save chassis")
+		err = c.Save(g.Dir)
+		endPhase()
+		if err != nil {
+			return err
+		}
+		g.pruneMeta(removed)
+	}
+
+	g.print()
+	return nil
+}
+
+// referencedPaths returns chassis paths used by node allocations and playbook plays.
+func (g *GC) referencedPaths() ([]string, error) {
+	nodes, err := chassis.LoadNodes(g.Dir, "")
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := chassis.LoadAttachments(g.Dir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, n := range nodes {
+		for _, entry := range n.Chassis {
+			// Only literal paths pin a path; wildcards and exclusions don't
+			for _, t := range pkgchassis.Terms(entry) {
+				if !strings.ContainsAny(t, "*!") {
+					paths = append(paths, t)
+				}
+			}
+		}
+	}
+	for _, a := range attachments {
+		paths = append(paths, a.Chassis)
+	}
+	return paths, nil
+}
+
+// pruneMeta drops annotations of removed paths.
+func (g *GC) pruneMeta(removed []string) {
+	meta, err := pkgchassis.LoadMeta(g.Dir)
+	if err != nil {
+		g.Log().Debug("Failed to load annotations", "error", err)
+		g.Degrade("failed to load annotations", err)
+		return
+	}
+	changed := false
+	for _, p := range removed {
+		if chassis.RemoveMeta(meta, p) {
+			changed = true
+		}
+	}
+	if changed {
+		if err := chassis.SaveMeta(g.Dir, meta); err != nil {
+			g.result.Errors = append(g.result.Errors, chassis.FileErrors(err)...)
+		}
+	}
+}
+
+// print reports what was pruned.
+func (g *GC) print() {
+	r := g.result
+	if g.DryRun() {
+		g.Report(message.DryRun)
+	}
+
+	if len(r.Removed) == 0 && len(r.Plays) == 0 {
+		g.Report(message.NothingToPrune)
+	}
+	for _, p := range r.Removed {
+		g.Term().Printfln("  chassis.yaml: - %s", p)
+	}
+	for _, p := range r.Plays {
+		hosts := p.Hosts
+		if hosts == "" {
+			hosts = "<no hosts>"
+		}
+		g.Term().Printfln("  %s: - play %d (hosts: %s)", p.Playbook, p.Index+1, hosts)
+	}
+	if len(r.Kept) > 0 {
+		g.Report(message.PruneKept, len(r.Kept), strings.Join(r.Kept, ", "))
+	}
+	if len(r.Errors) > 0 {
+		g.Report(message.FilesNotUpdated, len(r.Errors))
+		cli.PrintFileErrors(g.Term(), r.Errors)
+		g.Degrade("prune incomplete", fmt.Errorf("%d file(s) could not be updated", len(r.Errors)))
+	}
+	if !g.DryRun() && (len(r.Removed) > 0 || len(r.Plays) > 0) {
+		g.Report(message.Pruned, len(r.Removed), len(r.Plays))
+	}
+}
+
+// contains checks if slice contains value.
+func contains(slice []string, value string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package gc
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestGCGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *GC
+		dryRun bool
+	}{
+		{"dry-run", &GC{}, true},
+		{"gc", &GC{}, false},
+		{"aggressive", &GC{Aggressive: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "chassis.yaml", `platform:
+  foundation:
+    - cluster:
+      - control
+      - nodes
+    - storage:
+      - kv
+      - sql: []
+    - network:
+      - ingress
+  interaction:
+    - observability
+    - management
+  cognition:
+    - data
+    - knowledge
+  edge: []
+`)
+			golden.WriteFile(t, dir, "src/interaction/interaction.yaml", `- hosts: platform.interaction.observability
+  roles:
+    - interaction.observability.grafana
+    - interaction.observability.loki
+- hosts: platform.interaction.management
+  roles: []
+- ansible.builtin.import_playbook: management.yaml
+- name: Shared handlers
+  hosts: platform.interaction.management
+  ansible.builtin.import_tasks: handlers.yaml
+`)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+			golden.CompareFile(t, tt.name+".interaction.yaml", dir, "src/interaction/interaction.yaml")
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package grep
+
+import (
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// GrepResult is the structured result of chassis:grep.
+type GrepResult struct {
+	Pattern string `json:"pattern"`
+	// Existing lists references to paths declared in chassis.yaml.
+	Existing []chassis.PathReference `json:"existing"`
+	// Missing lists references to paths not declared in chassis.yaml, likely stale.
+	Missing []chassis.PathReference `json:"missing"`
+
+	message.Log
+}
+
+// Grep implements the chassis:grep command
+type Grep struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir     string
+	Pattern string
+
+	result *GrepResult
+}
+
+// Result returns the structured result for JSON output.
+func (g *Grep) Result() any {
+	return g.result
+}
+
+// Execute runs the grep action
+func (g *Grep) Execute() error {
+	endPhase := g.Phase("load chassis")
+	c, err := chassis.Load(g.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	var roots []string
+	for _, p := range c.Flatten() {
+		if !strings.Contains(p, ".") {
+			roots = append(roots, p)
+		}
+	}
+
+	endPhase = g.Phase("search files")
+	refs, err := chassis.GrepPaths(g.Dir, roots, g.matches)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	g.result = &GrepResult{
+		Pattern:  g.Pattern,
+		Existing: []chassis.PathReference{},
+		Missing:  []chassis.PathReference{},
+	}
+	files := make(map[string]bool)
+	for _, ref := range refs {
+		files[ref.File] = true
+		if c.Exists(ref.Path) {
+			g.result.Existing = append(g.result.Existing, ref)
+		} else {
+			g.result.Missing = append(g.result.Missing, ref)
+		}
+	}
+
+	if len(refs) == 0 {
+		g.Report(message.NoReferences, g.Pattern)
+		return nil
+	}
+	g.print("Existing paths:", g.result.Existing)
+	g.print("Missing paths:", g.result.Missing)
+	g.Report(message.ReferencesFound, len(refs), len(files), len(g.result.Missing))
+	return nil
+}
+
+// matches reports whether a path token matches the pattern: a plain path
+// matches itself and its descendants, a pattern with * segments is matched
+// like an allocation expression term.
+func (g *Grep) matches(chassisPath string) bool {
+	if strings.Contains(g.Pattern, "*") {
+		return pkgchassis.MatchPattern(g.Pattern, chassisPath)
+	}
+	return chassisPath == g.Pattern || pkgchassis.IsDescendantOf(chassisPath, g.Pattern)
+}
+
+// print lists references under a heading.
+func (g *Grep) print(heading string, refs []chassis.PathReference) {
+	if len(refs) == 0 {
+		return
+	}
+	g.Term().Info().Println(heading)
+	for _, ref := range refs {
+		g.Term().Printfln("  %s:%d: %s", ref.File, ref.Line, ref.Path)
+		g.Term().Printfln("      %s", ref.Context)
+	}
+}
@@ -0,0 +1,24 @@
+package grep
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestGrepGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Grep
+	}{
+		{"path", &Grep{Pattern: "platform.foundation.cluster"}},
+		{"glob", &Grep{Pattern: "platform.*.data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Target kinds.
+const (
+	KindChassis   = "chassis"
+	KindComponent = "component"
+	KindNode      = "node"
+)
+
+// ImpactResult is the structured output for chassis:impact
+type ImpactResult struct {
+	Target     string   `json:"target"`
+	Kind       string   `json:"kind"`
+	Chassis    []string `json:"chassis"`    // chassis paths affected by the change
+	Nodes      []string `json:"nodes"`      // hostname@platform of affected nodes
+	Components []string `json:"components"` // components re-deployed
+	Playbooks  []string `json:"playbooks"`  // playbooks involved
+	Untouched  []string `json:"untouched"`  // sibling chassis paths left alone
+
+	message.Log
+}
+
+// Impact implements the chassis:impact command
+type Impact struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir    string
+	Target string
+	Kind   string // chassis, component or node; detected if empty
+
+	Distribution pkgchassis.Strategy
+
+	result *ImpactResult
+}
+
+// Result returns the structured result for JSON output
+func (i *Impact) Result() any {
+	return i.result
+}
+
+// allocatedNode is a node with its effective allocations.
+type allocatedNode struct {
+	name    string // hostname@platform
+	chassis []string
+}
+
+// Execute runs the impact action
+func (i *Impact) Execute() error {
+	endPhase := i.Phase("load chassis")
+	c, err := pkgchassis.Load(i.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	distributor, err := pkgchassis.NewDistributor(i.Distribution)
+	if err != nil {
+		return err
+	}
+
+	endPhase = i.Phase("load nodes")
+	nodesByPlatform, err := chassis.LoadNodesByPlatform(i.Dir)
+	endPhase()
+	if err != nil {
+		i.Log().Debug("Failed to load nodes", "error", err)
+		i.Degrade("failed to load nodes", err)
+	}
+	var nodes []allocatedNode
+	for _, platformNodes := range nodesByPlatform {
+		for _, n := range pkgchassis.Allocate(c, distributor, chassis.DeclaredNodes(platformNodes)) {
+			nodes = append(nodes, allocatedNode{name: n.DisplayName(), chassis: n.Paths()})
+		}
+	}
+
+	endPhase = i.Phase("load attachments")
+	attachments, err := chassis.LoadAttachments(i.Dir, "")
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	kind := i.Kind
+	if kind == "" {
+		kind = detectKind(c, nodes, attachments, i.Target)
+		if kind == "" {
+			return fmt.Errorf("no chassis path, component or node named %q", i.Target)
+		}
+	}
+
+	// Collect the affected chassis paths first; nodes and components follow from them
+	scope := make(map[string]bool)
+	affectedNodes := make(map[string]bool)
+	affectedComponents := make(map[string]bool)
+	switch kind {
+	case KindChassis:
+		if !c.Exists(i.Target) {
+			return fmt.Errorf("chassis %q not found in chassis.yaml", i.Target)
+		}
+		for _, p := range c.FlattenWithPrefix(i.Target) {
+			scope[p] = true
+		}
+	case KindComponent:
+		for _, a := range attachments {
+			if a.Component == i.Target {
+				scope[a.Chassis] = true
+			}
+		}
+		if len(scope) == 0 {
+			return fmt.Errorf("component %q is not attached to any chassis path", i.Target)
+		}
+		affectedComponents[i.Target] = true
+	case KindNode:
+		for _, n := range nodes {
+			if nodeMatches(n.name, i.Target) {
+				affectedNodes[n.name] = true
+				for _, p := range n.chassis {
+					scope[p] = true
+				}
+			}
+		}
+		if len(affectedNodes) == 0 {
+			return fmt.Errorf("node %q not found", i.Target)
+		}
+	default:
+		return fmt.Errorf("invalid kind %q: must be chassis, component or node", kind)
+	}
+
+	if kind != KindNode {
+		for _, n := range nodes {
+			for _, p := range n.chassis {
+				if scope[p] {
+					affectedNodes[n.name] = true
+					break
+				}
+			}
+		}
+	}
+	affectedPlaybooks := make(map[string]bool)
+	for _, a := range attachments {
+		if !scope[a.Chassis] {
+			continue
+		}
+		if kind == KindComponent && a.Component != i.Target {
+			continue
+		}
+		affectedComponents[a.Component] = true
+		affectedPlaybooks[a.Playbook] = true
+	}
+
+	i.result = &ImpactResult{
+		Target:     i.Target,
+		Kind:       kind,
+		Chassis:    sortedKeys(scope),
+		Nodes:      sortedKeys(affectedNodes),
+		Components: sortedKeys(affectedComponents),
+		Playbooks:  sortedKeys(affectedPlaybooks),
+		Untouched:  untouchedSiblings(c, scope),
+	}
+
+	i.Term().Info().Printfln("Impact of %s %s", kind, i.Target)
+	i.printSection("Chassis", i.result.Chassis)
+	nodeNames := make([]string, 0, len(i.result.Nodes))
+	for _, n := range i.result.Nodes {
+		nodeNames = append(nodeNames, chassis.NodeNameOf(n))
+	}
+	i.printSection("Nodes", nodeNames)
+	i.printSection("Components", i.result.Components)
+	i.printSection("Playbooks", i.result.Playbooks)
+	i.printSection("Untouched siblings", i.result.Untouched)
+	return nil
+}
+
+// printSection prints a heading with the entry count, followed by the entries.
+func (i *Impact) printSection(title string, entries []string) {
+	i.Term().Info().Printfln("%s (%d)", title, len(entries))
+	for _, e := range entries {
+		i.Term().Printfln("  %s", e)
+	}
+}
+
+// detectKind resolves an untyped target, preferring chassis paths over
+// nodes over components. It returns "" if nothing matches.
+func detectKind(c *pkgchassis.Chassis, nodes []allocatedNode, attachments []chassis.Attachment, target string) string {
+	if c.Exists(target) {
+		return KindChassis
+	}
+	for _, n := range nodes {
+		if nodeMatches(n.name, target) {
+			return KindNode
+		}
+	}
+	for _, a := range attachments {
+		if a.Component == target {
+			return KindComponent
+		}
+	}
+	return ""
+}
+
+// nodeMatches reports whether a hostname@platform name matches a target
+// given as hostname or hostname@platform.
+func nodeMatches(name, target string) bool {
+	if strings.Contains(target, "@") {
+		return name == target
+	}
+	return strings.HasPrefix(name, target+"@")
+}
+
+// untouchedSiblings returns the siblings of affected paths that are neither
+// affected nor related to an affected path as ancestor or descendant.
+func untouchedSiblings(c *pkgchassis.Chassis, scope map[string]bool) []string {
+	related := func(p string) bool {
+		for s := range scope {
+			if p == s || pkgchassis.IsDescendantOf(p, s) || pkgchassis.IsDescendantOf(s, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	childrenMap := c.ChildrenMap()
+	untouched := make(map[string]bool)
+	for s := range scope {
+		for _, sibling := range childrenMap[pkgchassis.Parent(s)] {
+			if !related(sibling) {
+				untouched[sibling] = true
+			}
+		}
+	}
+	return sortedKeys(untouched)
+}
+
+// sortedKeys returns the keys of a set in sorted order, never nil.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
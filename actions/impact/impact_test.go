@@ -0,0 +1,25 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestImpactGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Impact
+	}{
+		{"chassis", &Impact{Target: "platform.foundation.cluster"}},
+		{"component", &Impact{Target: "interaction.observability.grafana"}},
+		{"node", &Impact{Target: "prod-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Import formats.
+const (
+	FormatCSV      = "csv"
+	FormatSnapshot = "snapshot"
+)
+
+// SkippedRow is an input row that was not imported.
+type SkippedRow struct {
+	Line   int    `json:"line"` // CSV line or 1-based snapshot node entry
+	Reason string `json:"reason"`
+}
+
+// ImportResult is the structured result of chassis:import.
+type ImportResult struct {
+	Format    string       `json:"format"`
+	Created   []string     `json:"created"`
+	Updated   []string     `json:"updated"`
+	Unchanged []string     `json:"unchanged"`
+	Skipped   []SkippedRow `json:"skipped"`
+	Verified  []string     `json:"verified,omitempty"` // snapshot checks passed: checksum, signature
+	DryRun    bool         `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// allocationRow is a parsed CSV row.
+type allocationRow struct {
+	line     int
+	hostname string
+	platform string
+	chassis  []string
+}
+
+// Import implements the chassis:import command
+type Import struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir              string
+	File             string
+	Format           string
+	RequireSignature bool // fail if a snapshot has no detached signature
+	Config           chassis.Config
+
+	result *ImportResult
+}
+
+// Result returns the structured result for JSON output.
+func (i *Import) Result() any {
+	return i.result
+}
+
+// Execute runs the import action
+func (i *Import) Execute() error {
+	format := i.Format
+	if format == "" {
+		format = FormatCSV
+	}
+	if format != FormatCSV && format != FormatSnapshot {
+		return fmt.Errorf("unknown import format %q (supported: %s, %s)", i.Format, FormatCSV, FormatSnapshot)
+	}
+
+	endPhase := i.Phase("load chassis")
+	c, err := chassis.Load(i.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(i.File)
+	if err != nil {
+		return err
+	}
+
+	i.result = &ImportResult{
+		Format:    format,
+		Created:   []string{},
+		Updated:   []string{},
+		Unchanged: []string{},
+		Skipped:   []SkippedRow{},
+		DryRun:    i.DryRun(),
+	}
+
+	endPhase = i.Phase("read " + i.File)
+	var rows []allocationRow
+	var skipped []SkippedRow
+	if format == FormatSnapshot {
+		rows, skipped, err = i.readSnapshot(data, c)
+	} else {
+		rows, skipped, err = readAllocationsCSV(bytes.NewReader(data), c)
+	}
+	endPhase()
+	if err != nil {
+		return err
+	}
+	i.result.Skipped = append(i.result.Skipped, skipped...)
+
+	if i.DryRun() {
+		i.Report(message.DryRun)
+	}
+
+	endPhase = i.Phase("write nodes")
+	for _, row := range rows {
+		name := row.hostname + "@" + row.platform
+		nodeFile := chassis.NodeFile(i.Dir, row.platform, row.hostname)
+		_, statErr := os.Stat(nodeFile)
+		exists := statErr == nil
+
+		if i.DryRun() {
+			i.Term().Printfln("  %s: chassis = [%s]", nodeFile, strings.Join(row.chassis, ", "))
+			if exists {
+				i.result.Updated = append(i.result.Updated, name)
+			} else {
+				i.result.Created = append(i.result.Created, name)
+			}
+			continue
+		}
+
+		changed, err := chassis.SetAllocations(nodeFile, row.hostname, row.chassis)
+		if err != nil {
+			i.result.Skipped = append(i.result.Skipped, SkippedRow{Line: row.line, Reason: err.Error()})
+			continue
+		}
+		switch {
+		case !changed:
+			i.result.Unchanged = append(i.result.Unchanged, name)
+		case exists:
+			i.result.Updated = append(i.result.Updated, name)
+		default:
+			i.result.Created = append(i.result.Created, name)
+		}
+	}
+	endPhase()
+
+	i.print()
+	return nil
+}
+
+// print reports the import summary.
+func (i *Import) print() {
+	r := i.result
+	if !i.DryRun() {
+		i.Report(message.Imported, i.File, len(r.Created), len(r.Updated), len(r.Unchanged))
+	}
+	if len(r.Skipped) > 0 {
+		i.Report(message.ImportSkipped, len(r.Skipped))
+		for _, s := range r.Skipped {
+			i.Term().Printfln("  line %d: %s", s.Line, s.Reason)
+		}
+	}
+}
+
+// readSnapshot verifies and decodes a snapshot written by chassis:export.
+// The embedded checksum is always verified when present, a detached signature
+// whenever a signature file exists next to the snapshot.
+func (i *Import) readSnapshot(data []byte, c *chassis.Chassis) ([]allocationRow, []SkippedRow, error) {
+	snap, err := export.ParseSnapshot(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if snap.Checksum != "" {
+		if err := snap.Verify(); err != nil {
+			return nil, nil, err
+		}
+		i.result.Verified = append(i.result.Verified, "checksum")
+	} else {
+		i.Report(message.SnapshotUnchecked, i.File)
+	}
+
+	sigFile := export.SignatureFile(i.File)
+	if _, err := os.Stat(sigFile); err == nil {
+		command := i.Config.Export.Snapshot.VerifyCommand
+		if command == "" {
+			return nil, nil, fmt.Errorf("%s found but export.snapshot.verify_command is not configured", sigFile)
+		}
+		if err := export.VerifySignature(command, sigFile, data); err != nil {
+			return nil, nil, err
+		}
+		i.result.Verified = append(i.result.Verified, "signature")
+	} else if i.RequireSignature {
+		return nil, nil, fmt.Errorf("%s is not signed: %s not found", i.File, sigFile)
+	}
+
+	var rows []allocationRow
+	var skipped []SkippedRow
+	seen := make(firstRows)
+	for idx, n := range snap.Nodes {
+		record := append([]string{n.Hostname, n.Platform}, n.Chassis...)
+		row, reason := parseAllocationRecord(record, c)
+		if reason != "" {
+			skipped = append(skipped, SkippedRow{Line: idx + 1, Reason: reason})
+			continue
+		}
+		row.line = idx + 1
+		if reason := seen.check(row); reason != "" {
+			skipped = append(skipped, SkippedRow{Line: row.line, Reason: reason})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, skipped, nil
+}
+
+// readAllocationsCSV parses rows of hostname, platform, chassis paths.
+// Chassis paths may be spread over the remaining columns or separated by ";"
+// within a cell. A header row starting with "hostname" is ignored.
+// Rows referencing unknown chassis paths are skipped.
+func readAllocationsCSV(r io.Reader, c *chassis.Chassis) ([]allocationRow, []SkippedRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rows []allocationRow
+	var skipped []SkippedRow
+	seen := make(firstRows)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(rows) == 0 && len(skipped) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "hostname") {
+			continue
+		}
+
+		row, reason := parseAllocationRecord(record, c)
+		if reason != "" {
+			skipped = append(skipped, SkippedRow{Line: line, Reason: reason})
+			continue
+		}
+		row.line = line
+		if reason := seen.check(row); reason != "" {
+			skipped = append(skipped, SkippedRow{Line: line, Reason: reason})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, skipped, nil
+}
+
+// firstRows holds the line of the first row of each hostname@platform.
+type firstRows map[string]int
+
+// check records row, returning a reason to skip it if a previous row
+// already listed its node.
+func (s firstRows) check(row allocationRow) string {
+	key := row.hostname + "@" + row.platform
+	if first, ok := s[key]; ok {
+		return fmt.Sprintf("duplicate of line %d for %s", first, key)
+	}
+	s[key] = row.line
+	return ""
+}
+
+// parseAllocationRecord validates a CSV record, returning a reason if it must be skipped.
+func parseAllocationRecord(record []string, c *chassis.Chassis) (allocationRow, string) {
+	if len(record) < 3 {
+		return allocationRow{}, "expected hostname, platform and at least one chassis path"
+	}
+
+	row := allocationRow{
+		hostname: strings.TrimSpace(record[0]),
+		platform: strings.TrimSpace(record[1]),
+	}
+	if row.hostname == "" || row.platform == "" {
+		return row, "hostname and platform are required"
+	}
+	if strings.ContainsAny(row.hostname+row.platform, `/\`) {
+		return row, "hostname and platform must not contain path separators"
+	}
+	// Both name files and directories, inst/<platform>/nodes/<hostname>.yaml
+	for _, name := range []string{row.hostname, row.platform} {
+		if name == "." || name == ".." {
+			return row, fmt.Sprintf("hostname and platform must not be %q", name)
+		}
+	}
+
+	for _, cell := range record[2:] {
+		for _, p := range strings.Split(cell, ";") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if err := pkgchassis.ValidatePath(p); err != nil {
+				return row, err.Error()
+			}
+			if !c.Exists(p) {
+				return row, fmt.Sprintf("chassis %q not found", p)
+			}
+			row.chassis = append(row.chassis, p)
+		}
+	}
+	if len(row.chassis) == 0 {
+		return row, "no chassis path"
+	}
+	return row, ""
+}
@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestImportGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		dryRun bool
+	}{
+		{"csv", FormatCSV, false},
+		{"dry-run", FormatCSV, true},
+		{"unknown-format", "xlsx", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "nodes.csv", `hostname,platform,chassis
+prod-1,prod,platform.foundation.cluster.control;platform.foundation.storage.kv
+prod-2,prod,platform.foundation.cluster.nodes,platform.foundation.storage.kv
+prod-7,prod,platform.foundation.network.ingress
+prod-8,prod,platform.foundation.compute
+prod-9
+prod-7,prod,platform.foundation.cluster.nodes
+..,prod,platform.foundation.cluster.nodes
+prod-3,.,platform.foundation.cluster.nodes
+`)
+			i := &Import{Dir: dir, File: filepath.Join(dir, "nodes.csv"), Format: tt.format}
+			i.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, i)
+			golden.CompareFile(t, tt.name+".prod-1.yaml", dir, "inst/prod/nodes/prod-1.yaml")
+		})
+	}
+}
+
+func TestImportSnapshotGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	golden.WriteFile(t, dir, "snapshot.json", `{
+  "version": 1,
+  "chassis": [],
+  "nodes": [
+    {"hostname": "prod-7", "platform": "prod", "chassis": ["platform.foundation.network.ingress"]},
+    {"hostname": "prod-7", "platform": "prod", "chassis": ["platform.foundation.cluster.nodes"]},
+    {"hostname": "prod-8", "platform": "..", "chassis": ["platform.foundation.cluster.nodes"]},
+    {"hostname": "..", "platform": "prod", "chassis": ["platform.foundation.cluster.nodes"]}
+  ]
+}
+`)
+	i := &Import{Dir: dir, File: filepath.Join(dir, "snapshot.json"), Format: FormatSnapshot}
+	golden.Run(t, "snapshot", dir, i)
+	golden.CompareFile(t, "snapshot.prod-7.yaml", dir, "inst/prod/nodes/prod-7.yaml")
+}
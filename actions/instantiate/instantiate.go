@@ -0,0 +1,150 @@
+package instantiate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// InstantiateResult is the structured result of chassis:instantiate.
+type InstantiateResult struct {
+	Template string   `json:"template"`
+	Chassis  string   `json:"chassis"`
+	Added    []string `json:"added"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Instantiate implements the chassis:instantiate command
+type Instantiate struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir      string
+	Template string
+	Chassis  string
+	Params   []string // key=value template parameters
+	Config   chassis.Config
+
+	result *InstantiateResult
+}
+
+// Result returns the structured result for JSON output.
+func (i *Instantiate) Result() any {
+	return i.result
+}
+
+// Execute runs the instantiate action
+func (i *Instantiate) Execute() error {
+	params, err := parseParams(i.Params)
+	if err != nil {
+		return err
+	}
+
+	tpl, err := i.Config.Template(i.Template)
+	if err != nil {
+		return err
+	}
+
+	if err := pkgchassis.ValidatePath(i.Chassis); err != nil {
+		return err
+	}
+
+	rendered, err := tpl.Render(i.Chassis, params)
+	if err != nil {
+		return err
+	}
+
+	endPhase := i.Phase("load chassis")
+	c, err := chassis.Load(i.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	if c.Exists(i.Chassis) {
+		return fmt.Errorf("chassis %q already exists", i.Chassis)
+	}
+
+	added := []string{i.Chassis}
+	for _, rel := range rendered {
+		added = append(added, i.Chassis+"."+rel)
+	}
+
+	for _, p := range added {
+		if c.Exists(p) {
+			continue
+		}
+		if err := i.Config.Limits.CheckAdd(c.Chassis, p); err != nil {
+			return err
+		}
+		if err := c.Add(p); err != nil {
+			return fmt.Errorf("failed to add chassis path: %w", err)
+		}
+	}
+
+	i.result = &InstantiateResult{
+		Template: i.Template,
+		Chassis:  i.Chassis,
+		Added:    added,
+		DryRun:   i.DryRun(),
+	}
+
+	if i.DryRun() {
+		i.Report(message.DryRun)
+		for _, p := range added {
+			i.Term().Printfln("  chassis.yaml: + %s", p)
+		}
+		i.Term().Printfln("  %s: %s template=%s", pkgchassis.MetaFile, i.Chassis, i.Template)
+		return nil
+	}
+
+	i.WarnAliasExpansion(c)
+	endPhase = i.Phase("save chassis")
+	err = c.Save(i.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	meta, err := pkgchassis.LoadMeta(i.Dir)
+	if err != nil {
+		return err
+	}
+	meta[i.Chassis] = pkgchassis.Annotations{
+		Template:       i.Template,
+		TemplatePaths:  rendered,
+		TemplateParams: params,
+	}
+	if err := chassis.SaveMeta(i.Dir, meta); err != nil {
+		return err
+	}
+
+	i.Report(message.TemplateInstantiated, i.Template, i.Chassis, len(added))
+	for _, p := range added {
+		i.Term().Printfln("  + %s", p)
+	}
+	return nil
+}
+
+// parseParams parses key=value pairs.
+func parseParams(pairs []string) (map[string]string, error) {
+	params := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid parameter %q: expected key=value", pair)
+		}
+		params[k] = v
+	}
+	return params, nil
+}
@@ -0,0 +1,34 @@
+package instantiate
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestInstantiateGolden(t *testing.T) {
+	config := chassis.Config{Templates: map[string][]interface{}{
+		"service": {"web", map[string]interface{}{"data": []interface{}{"${engine}"}}},
+	}}
+	tests := []struct {
+		name   string
+		action *Instantiate
+		dryRun bool
+	}{
+		{"instantiate", &Instantiate{Template: "service", Chassis: "platform.interaction.shop", Params: []string{"engine=postgres"}}, false},
+		{"dry-run", &Instantiate{Template: "service", Chassis: "platform.interaction.shop", Params: []string{"engine=postgres"}}, true},
+		{"missing-param", &Instantiate{Template: "service", Chassis: "platform.interaction.shop"}, false},
+		{"unknown-template", &Instantiate{Template: "cluster", Chassis: "platform.interaction.shop"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.Config = config
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+)
+
+// LintResult is the structured result of chassis:lint.
+type LintResult struct {
+	Findings []validate.Finding `json:"findings"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+	// Fixes are the automatic fixes of the findings (--fix).
+	Fixes []validate.Fix `json:"fixes,omitempty"`
+	// Deferred are rules whose fixes change files fixed by another rule;
+	// they are fixed by running again.
+	Deferred []string `json:"deferred,omitempty"`
+	Applied  bool     `json:"applied,omitempty"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+	// FixErrors are the files fixes could not be written to.
+	FixErrors []chassis.FileError `json:"fix_errors,omitempty"`
+
+	message.Log
+}
+
+// Lint implements the chassis:lint command
+type Lint struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir    string
+	Rules  []string // rule names to check and fix, all if empty
+	Fix    bool
+	Config chassis.Config
+
+	result *LintResult
+}
+
+// Result returns the structured result for JSON output.
+func (l *Lint) Result() any {
+	return l.result
+}
+
+// Mutates reports whether the action applies fixes.
+func (l *Lint) Mutates() bool {
+	return l.Fix && !l.DryRun()
+}
+
+// Execute runs the lint action
+func (l *Lint) Execute() error {
+	endPhase := l.Phase("load repository")
+	ctx, err := validate.Load(l.Dir, l.Config)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = l.Phase("check rules")
+	findings, err := validate.Run(ctx, l.Rules...)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	errs, warnings := validate.Count(findings)
+	l.result = &LintResult{Findings: findings, Errors: errs, Warnings: warnings}
+	l.printFindings()
+
+	if !l.Fix {
+		fixable := 0
+		for _, f := range findings {
+			if validate.Fixable(f.Rule) {
+				fixable++
+			}
+		}
+		if fixable > 0 {
+			l.Report(message.LintFixable, fixable)
+		}
+		if errs > 0 || (l.Strict() && warnings > 0) {
+			return fmt.Errorf("lint failed: %d error(s), %d warning(s)", errs, warnings)
+		}
+		if len(findings) == 0 {
+			l.Report(message.ChassisValid)
+		}
+		return nil
+	}
+
+	endPhase = l.Phase("compute fixes")
+	fixes, deferred, err := validate.Fixes(ctx, l.Rules...)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	l.result.Fixes, l.result.Deferred, l.result.DryRun = fixes, deferred, l.DryRun()
+	if len(fixes) == 0 {
+		l.Report(message.NothingToFix)
+		return nil
+	}
+
+	if l.DryRun() {
+		l.Report(message.DryRun)
+	}
+	for _, f := range fixes {
+		l.Term().Info().Printfln("[%s] %s: %s", f.Rule, f.File, f.Description)
+		l.Term().Print(f.Diff)
+	}
+	if l.DryRun() {
+		return nil
+	}
+
+	endPhase = l.Phase("apply fixes")
+	var fixErrs []error
+	for _, f := range fixes {
+		if err := f.Apply(); err != nil {
+			fixErrs = append(fixErrs, err)
+		}
+	}
+	endPhase()
+	l.result.Applied = true
+	if len(fixErrs) > 0 {
+		l.result.FixErrors = chassis.FileErrors(errors.Join(fixErrs...))
+		l.Report(message.FilesNotUpdated, len(l.result.FixErrors))
+		cli.PrintFileErrors(l.Term(), l.result.FixErrors)
+		return fmt.Errorf("%d of %d fix(es) could not be applied", len(fixErrs), len(fixes))
+	}
+	l.Report(message.LintFixed, len(fixes))
+	if len(deferred) > 0 {
+		l.Report(message.LintDeferred, strings.Join(deferred, ", "))
+	}
+	return nil
+}
+
+// printFindings lists the findings, marking those with automatic fixes.
+func (l *Lint) printFindings() {
+	for _, f := range l.result.Findings {
+		subject := f.Chassis
+		if f.Node != "" {
+			subject = f.Node
+		}
+		line := fmt.Sprintf("[%s] %s: %s", f.Rule, subject, f.Message)
+		if f.File != "" {
+			line += " (" + f.File + ")"
+		}
+		if validate.Fixable(f.Rule) {
+			line += " [fixable]"
+		}
+		if f.Severity == validate.SeverityError {
+			l.Term().Error().Println(line)
+		} else {
+			l.Term().Warning().Println(line)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestLintGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Lint
+		dryRun bool
+	}{
+		{"report", &Lint{}, false},
+		{"fix-dry-run", &Lint{Fix: true}, true},
+		{"fix", &Lint{Fix: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "inst/prod/nodes/prod-5.yaml", `hostname: prod-5
+chassis:
+  - platform.Foundation.cluster.nodes
+  - platform.foundation.cluster.nodes
+`)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+
+			golden.CompareFile(t, tt.name+".prod-5.yaml", dir, "inst/prod/nodes/prod-5.yaml")
+		})
+	}
+}
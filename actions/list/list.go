@@ -1,37 +1,94 @@
 package list
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
-	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
-	"github.com/plasmash/plasmactl-component/pkg/component"
-	"github.com/plasmash/plasmactl-node/pkg/node"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
 // TreeEntry enriches a chassis path with its allocated nodes and attached components.
 type TreeEntry struct {
-	Path       string   `json:"path"`
-	Nodes      []string `json:"nodes,omitempty"`
-	Components []string `json:"components,omitempty"`
+	Path     string   `json:"path"`
+	Parent   string   `json:"parent,omitempty"`
+	Depth    int      `json:"depth"`
+	Children []string `json:"children,omitempty"`
+	Empty    bool     `json:"empty,omitempty"`
+	Nodes    []string `json:"nodes,omitempty"`
+	// Quarantined lists the nodes of Nodes marked quarantined.
+	Quarantined []string `json:"quarantined,omitempty"`
+	Components  []string `json:"components,omitempty"`
 }
 
+// TreeNode is a chassis path nested in the hierarchy, for --format=json-tree.
+type TreeNode struct {
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	Empty       bool        `json:"empty,omitempty"`
+	Nodes       []string    `json:"nodes,omitempty"`
+	Quarantined []string    `json:"quarantined,omitempty"`
+	Components  []string    `json:"components,omitempty"`
+	Children    []*TreeNode `json:"children"`
+}
+
+// Output formats.
+const (
+	FormatJSONTree = "json-tree"
+)
+
+// PathChange describes how a chassis path changed since a git ref.
+type PathChange struct {
+	Path              string   `json:"path"`
+	Change            string   `json:"change"` // added, removed or changed
+	NodesAdded        []string `json:"nodes_added,omitempty"`
+	NodesRemoved      []string `json:"nodes_removed,omitempty"`
+	ComponentsAdded   []string `json:"components_added,omitempty"`
+	ComponentsRemoved []string `json:"components_removed,omitempty"`
+}
+
+// Path change kinds.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
 // ListResult is the structured output for chassis:list
 type ListResult struct {
 	Chassis []string    `json:"chassis"`
+	Empty   []string    `json:"empty,omitempty"`
 	Tree    []TreeEntry `json:"tree,omitempty"`
+	// Hierarchy nests the paths under their parents (--format=json-tree).
+	Hierarchy []*TreeNode  `json:"hierarchy,omitempty"`
+	Changes   []PathChange `json:"changes,omitempty"`
+
+	message.Log
 }
 
 // List implements the chassis:list command
 type List struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
 
-	Dir     string
-	Chassis string
-	Tree    bool
+	Dir          string
+	Chassis      string
+	Tree         bool
+	Format       string // "json-tree" prints the hierarchy as nested JSON
+	ChangedSince string // git ref to list changed paths against
+	Match        string // regular expression paths must match
+	Exclude      string // regular expression paths must not match
+
+	Distribution pkgchassis.Strategy
 
 	result *ListResult
 }
@@ -43,7 +100,18 @@ func (l *List) Result() any {
 
 // Execute runs the list action
 func (l *List) Execute() error {
-	c, err := chassis.Load(l.Dir)
+	endPhase := l.Phase("load chassis")
+	c, err := pkgchassis.Load(l.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	if l.Format != "" && l.Format != FormatJSONTree {
+		return fmt.Errorf("unknown format %q (supported: %s)", l.Format, FormatJSONTree)
+	}
+
+	filter, err := chassis.NewPathFilter(l.Match, l.Exclude)
 	if err != nil {
 		return err
 	}
@@ -51,16 +119,30 @@ func (l *List) Execute() error {
 	// Initialize result early so --json always returns an object, never null
 	l.result = &ListResult{Chassis: []string{}}
 
-	paths := c.FlattenWithPrefix(l.Chassis)
+	if l.ChangedSince != "" {
+		return l.listChanges(c, filter)
+	}
+
+	paths := filter.Filter(c.FlattenWithPrefix(l.Chassis))
 	if len(paths) == 0 {
-		l.Term().Warning().Println("No chassis paths found")
+		l.Report(message.NoChassisPaths)
 		return nil
 	}
 
 	l.result.Chassis = paths
+	for _, p := range paths {
+		if c.IsPlaceholder(p) {
+			l.result.Empty = append(l.result.Empty, p)
+		}
+	}
 
+	if l.Format == FormatJSONTree {
+		return l.printJSONTree(c, paths)
+	}
 	if l.Tree {
-		l.printTreeWithRelations(c, paths)
+		if err := l.printTreeWithRelations(c, paths); err != nil {
+			return err
+		}
 	} else {
 		// Flat output - one per line, scriptable
 		for _, c := range l.result.Chassis {
@@ -71,33 +153,129 @@ func (l *List) Execute() error {
 	return nil
 }
 
-
 // printTreeWithRelations prints the chassis tree with nodes (🖥) and components (🧩) inline
-func (l *List) printTreeWithRelations(c *chassis.Chassis, paths []string) {
+func (l *List) printTreeWithRelations(c *pkgchassis.Chassis, paths []string) error {
+	chassisToNodes, chassisToComponents, quarantined, err := l.loadRelations(l.Dir, c)
+	if err != nil {
+		return err
+	}
+
+	// Populate tree entries in result
+	childrenMap := c.ChildrenMap()
+	for _, p := range paths {
+		entry := TreeEntry{
+			Path:     p,
+			Parent:   pkgchassis.Parent(p),
+			Depth:    strings.Count(p, "."),
+			Children: childrenMap[p],
+			Empty:    c.IsPlaceholder(p),
+		}
+		if nodes, ok := chassisToNodes[p]; ok {
+			entry.Nodes = nodes
+			for _, n := range nodes {
+				if quarantined[n] {
+					entry.Quarantined = append(entry.Quarantined, n)
+				}
+			}
+		}
+		if comps, ok := chassisToComponents[p]; ok {
+			entry.Components = comps
+		}
+		l.result.Tree = append(l.result.Tree, entry)
+	}
+
+	// Print tree starting from the roots
+	for _, child := range c.TreeOf(paths) {
+		printNodeWithRelations(l.Term(), child, "", "", chassisToNodes, chassisToComponents, quarantined)
+	}
+
+	return nil
+}
+
+// printJSONTree prints the paths as nested JSON objects, with their
+// nodes and components, for web UIs rendering the hierarchy directly.
+func (l *List) printJSONTree(c *pkgchassis.Chassis, paths []string) error {
+	chassisToNodes, chassisToComponents, quarantined, err := l.loadRelations(l.Dir, c)
+	if err != nil {
+		return err
+	}
+
+	var convert func(n *pkgchassis.TreeNode) *TreeNode
+	convert = func(n *pkgchassis.TreeNode) *TreeNode {
+		node := &TreeNode{
+			Name:       n.Name,
+			Path:       n.Path,
+			Empty:      n.Placeholder,
+			Nodes:      chassisToNodes[n.Path],
+			Components: chassisToComponents[n.Path],
+			Children:   make([]*TreeNode, 0, len(n.Children)),
+		}
+		for _, name := range node.Nodes {
+			if quarantined[name] {
+				node.Quarantined = append(node.Quarantined, name)
+			}
+		}
+		for _, child := range n.Children {
+			node.Children = append(node.Children, convert(child))
+		}
+		return node
+	}
+	for _, n := range c.TreeOf(paths) {
+		l.result.Hierarchy = append(l.result.Hierarchy, convert(n))
+	}
+
+	data, err := json.MarshalIndent(l.result.Hierarchy, "", "  ")
+	if err != nil {
+		return err
+	}
+	l.Term().Printfln("%s", data)
+	return nil
+}
+
+// loadRelations maps chassis paths of the repository at dir to their
+// effective nodes (hostname@platform) and attached components, sorted.
+// Quarantined holds the quarantined nodes.
+func (l *List) loadRelations(dir string, c *pkgchassis.Chassis) (chassisToNodes, chassisToComponents map[string][]string, quarantined map[string]bool, err error) {
+	distributor, err := pkgchassis.NewDistributor(l.Distribution)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Load nodes and compute allocations
-	nodesByPlatform, err := node.LoadByPlatform(l.Dir)
+	endPhase := l.Phase("load nodes")
+	nodesByPlatform, err := chassis.LoadNodesByPlatform(dir)
+	endPhase()
 	if err != nil {
 		l.Log().Debug("Failed to load nodes", "error", err)
+		l.Degrade("failed to load nodes", err)
 	}
-	chassisToNodes := make(map[string][]string)
+	chassisToNodes = make(map[string][]string)
+	quarantined = make(map[string]bool)
 
 	for _, nodes := range nodesByPlatform {
-		allocations := nodes.Allocations(c)
-		for _, n := range nodes {
-			for _, chassisPath := range allocations[n.Hostname] {
-				chassisToNodes[chassisPath] = append(chassisToNodes[chassisPath], n.DisplayName())
+		allocations := pkgchassis.Allocate(c, distributor, chassis.DeclaredNodes(nodes))
+		for i, n := range nodes {
+			name := allocations[i].DisplayName()
+			if n.Quarantined {
+				quarantined[name] = true
+			}
+			for _, chassisPath := range allocations[i].Paths() {
+				chassisToNodes[chassisPath] = append(chassisToNodes[chassisPath], name)
 			}
 		}
 	}
 
 	// Load components
-	components, err := component.LoadFromPlaybooks(l.Dir)
+	endPhase = l.Phase("load components")
+	attachments, err := chassis.LoadAttachments(dir, "")
+	endPhase()
 	if err != nil {
 		l.Log().Debug("Failed to load components", "error", err)
+		l.Degrade("failed to load components", err)
 	}
-	chassisToComponents := make(map[string][]string)
-	for _, comp := range components {
-		chassisToComponents[comp.Chassis] = append(chassisToComponents[comp.Chassis], comp.Name)
+	chassisToComponents = make(map[string][]string)
+	for _, a := range attachments {
+		chassisToComponents[a.Chassis] = append(chassisToComponents[a.Chassis], a.Component)
 	}
 
 	// Sort the relations for consistent output
@@ -108,80 +286,131 @@ func (l *List) printTreeWithRelations(c *chassis.Chassis, paths []string) {
 		sort.Strings(chassisToComponents[chassisPath])
 	}
 
-	// Populate tree entries in result
-	for _, p := range paths {
-		entry := TreeEntry{Path: p}
-		if nodes, ok := chassisToNodes[p]; ok {
-			entry.Nodes = nodes
+	return chassisToNodes, chassisToComponents, quarantined, nil
+}
+
+// listChanges lists the chassis paths added, removed, or with changed
+// nodes or components since the ChangedSince git ref, among the paths
+// selected by filter.
+func (l *List) listChanges(c *pkgchassis.Chassis, filter *chassis.PathFilter) error {
+	if l.Tree {
+		return fmt.Errorf("--changed-since can't be combined with --tree")
+	}
+
+	endPhase := l.Phase("checkout " + l.ChangedSince)
+	oldDir, cleanup, err := chassis.CheckoutRef(l.Dir, l.ChangedSince)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	old, err := pkgchassis.Load(oldDir)
+	if err != nil {
+		return fmt.Errorf("chassis at %s: %w", l.ChangedSince, err)
+	}
+	oldNodes, oldComponents, _, err := l.loadRelations(oldDir, old)
+	if err != nil {
+		return err
+	}
+	newNodes, newComponents, _, err := l.loadRelations(l.Dir, c)
+	if err != nil {
+		return err
+	}
+
+	oldPaths := filter.Filter(old.FlattenWithPrefix(l.Chassis))
+	newPaths := filter.Filter(c.FlattenWithPrefix(l.Chassis))
+	inOld := make(map[string]bool, len(oldPaths))
+	for _, p := range oldPaths {
+		inOld[p] = true
+	}
+	inNew := make(map[string]bool, len(newPaths))
+	for _, p := range newPaths {
+		inNew[p] = true
+	}
+
+	changes := []PathChange{}
+	for _, p := range newPaths {
+		change := PathChange{Path: p, Change: ChangeAdded}
+		if inOld[p] {
+			change.Change = ChangeChanged
 		}
-		if comps, ok := chassisToComponents[p]; ok {
-			entry.Components = comps
+		change.NodesAdded, change.NodesRemoved = diffSorted(oldNodes[p], newNodes[p])
+		change.ComponentsAdded, change.ComponentsRemoved = diffSorted(oldComponents[p], newComponents[p])
+		if change.Change == ChangeChanged && len(change.NodesAdded)+len(change.NodesRemoved)+
+			len(change.ComponentsAdded)+len(change.ComponentsRemoved) == 0 {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for _, p := range oldPaths {
+		if !inNew[p] {
+			changes = append(changes, PathChange{
+				Path:              p,
+				Change:            ChangeRemoved,
+				NodesRemoved:      oldNodes[p],
+				ComponentsRemoved: oldComponents[p],
+			})
 		}
-		l.result.Tree = append(l.result.Tree, entry)
 	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
 
-	// Build tree structure
-	tree := buildTree(paths)
+	l.result.Changes = changes
+	for _, change := range changes {
+		l.result.Chassis = append(l.result.Chassis, change.Path)
+	}
 
-	// Print tree starting from root's children
-	for _, child := range tree.children {
-		printNodeWithRelations(l.Term(), child, "", "", chassisToNodes, chassisToComponents)
+	if len(changes) == 0 {
+		l.Report(message.NoChanges, l.ChangedSince)
+		return nil
 	}
-}
 
-type treeNode struct {
-	name     string
-	fullPath string
-	children []*treeNode
+	// One line per path, scriptable: +added, -removed, ~changed
+	marks := map[string]string{ChangeAdded: "+", ChangeRemoved: "-", ChangeChanged: "~"}
+	for _, change := range changes {
+		l.Term().Printfln("%s %s", marks[change.Change], change.Path)
+	}
+	return nil
 }
 
-func buildTree(paths []string) *treeNode {
-	root := &treeNode{name: ""}
-
-	for _, path := range paths {
-		parts := strings.Split(path, ".")
-		current := root
-		currentPath := ""
-		for _, part := range parts {
-			if currentPath == "" {
-				currentPath = part
-			} else {
-				currentPath = currentPath + "." + part
-			}
-
-			found := false
-			for _, child := range current.children {
-				if child.name == part {
-					current = child
-					found = true
-					break
-				}
-			}
-			if !found {
-				newNode := &treeNode{name: part, fullPath: currentPath}
-				current.children = append(current.children, newNode)
-				current = newNode
-			}
+// diffSorted returns the entries only in newer and only in older.
+// Both slices must be sorted.
+func diffSorted(older, newer []string) (added, removed []string) {
+	i, j := 0, 0
+	for i < len(older) || j < len(newer) {
+		switch {
+		case j == len(newer) || (i < len(older) && older[i] < newer[j]):
+			removed = append(removed, older[i])
+			i++
+		case i == len(older) || newer[j] < older[i]:
+			added = append(added, newer[j])
+			j++
+		default:
+			i++
+			j++
 		}
 	}
-
-	return root
+	return added, removed
 }
 
-func printNodeWithRelations(term *launchr.Terminal, node *treeNode, indent, prefix string, chassisToNodes, chassisToComponents map[string][]string) {
+func printNodeWithRelations(term *launchr.Terminal, node *pkgchassis.TreeNode, indent, prefix string, chassisToNodes, chassisToComponents map[string][]string, quarantined map[string]bool) {
 	// Print this node
-	term.Printfln("%s%s", prefix, node.name)
+	if node.Placeholder {
+		term.Printfln("%s%s (empty)", prefix, node.Name)
+	} else {
+		term.Printfln("%s%s", prefix, node.Name)
+	}
 
 	// Get nodes and components for this chassis path
-	nodes := chassisToNodes[node.fullPath]
-	comps := chassisToComponents[node.fullPath]
+	nodes := chassisToNodes[node.Path]
+	comps := chassisToComponents[node.Path]
 
 	// Order: child chassis paths first (structural hierarchy), then nodes, then components
-	totalChildren := len(node.children) + len(nodes) + len(comps)
+	totalChildren := len(node.Children) + len(nodes) + len(comps)
 	childIdx := 0
 
 	// Print child chassis paths first
-	for _, child := range node.children {
+	for _, child := range node.Children {
 		childIdx++
 		isLast := childIdx == totalChildren
 
@@ -194,7 +423,7 @@ func printNodeWithRelations(term *launchr.Terminal, node *treeNode, indent, pref
 			nextIndent = indent + "│   "
 		}
 
-		printNodeWithRelations(term, child, nextIndent, childPrefix, chassisToNodes, chassisToComponents)
+		printNodeWithRelations(term, child, nextIndent, childPrefix, chassisToNodes, chassisToComponents, quarantined)
 	}
 
 	// Print nodes allocated to this chassis path
@@ -207,7 +436,11 @@ func printNodeWithRelations(term *launchr.Terminal, node *treeNode, indent, pref
 		} else {
 			childPrefix = indent + "├── "
 		}
-		term.Printfln("%s🖥 %s", childPrefix, n)
+		if quarantined[n] {
+			term.Printfln("%s🖥 %s (quarantined)", childPrefix, chassis.NodeNameOf(n))
+			continue
+		}
+		term.Printfln("%s🖥 %s", childPrefix, chassis.NodeNameOf(n))
 	}
 
 	// Print components distributed to this chassis path
@@ -0,0 +1,27 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestListGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *List
+	}{
+		{"flat", &List{}},
+		{"subtree", &List{Chassis: "platform.foundation"}},
+		{"tree", &List{Tree: true}},
+		{"json-tree", &List{Format: FormatJSONTree}},
+		{"match", &List{Match: `\.(cluster|storage)`, Exclude: `kv$`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
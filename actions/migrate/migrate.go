@@ -0,0 +1,93 @@
+package migrate
+
+import (
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// legacy names the format of chassis.yaml files without header.
+const legacy = "legacy"
+
+// MigrateResult is the structured result of chassis:migrate.
+type MigrateResult struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Steps    []string `json:"steps"` // formats passed through, from first to last
+	Migrated bool     `json:"migrated"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Migrate implements the chassis:migrate command
+type Migrate struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir string
+
+	result *MigrateResult
+}
+
+// Result returns the structured result for JSON output.
+func (m *Migrate) Result() any {
+	return m.result
+}
+
+// Execute runs the migrate action
+func (m *Migrate) Execute() error {
+	endPhase := m.Phase("load chassis")
+	c, err := chassis.Load(m.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	from := formatName(c.Header().APIVersion)
+	m.result = &MigrateResult{
+		From:   from,
+		To:     from,
+		Steps:  []string{from},
+		DryRun: m.DryRun(),
+	}
+	if !c.NeedsMigration() {
+		m.Report(message.FormatCurrent, from)
+		return nil
+	}
+
+	steps := c.Migrate()
+	m.result.Steps = m.result.Steps[:0]
+	for _, v := range steps {
+		m.result.Steps = append(m.result.Steps, formatName(v))
+	}
+	m.result.To = formatName(c.Header().APIVersion)
+
+	if m.DryRun() {
+		m.Report(message.DryRun)
+		m.Term().Printfln("  chassis.yaml: %s -> %s", m.result.From, m.result.To)
+		return nil
+	}
+
+	endPhase = m.Phase("save chassis")
+	err = c.Save(m.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	m.result.Migrated = true
+	m.Report(message.FormatMigrated, m.result.From, m.result.To)
+	return nil
+}
+
+// formatName returns the name of a format version for output.
+func formatName(apiVersion string) string {
+	if apiVersion == "" {
+		return legacy
+	}
+	return apiVersion
+}
@@ -0,0 +1,32 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestMigrateGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		dryRun bool
+	}{
+		{"legacy", "", false},
+		{"dry-run", "", true},
+		{"current", "apiVersion: chassis/v1\nkind: Chassis\n", false},
+		{"unsupported", "apiVersion: chassis/v9\nkind: Chassis\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			if tt.header != "" {
+				golden.WriteFile(t, dir, "chassis.yaml", tt.header+"platform:\n    foundation:\n        - cluster\n")
+			}
+			m := &Migrate{Dir: dir}
+			m.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, m)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package migratelayout
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// MigrateLayoutResult is the structured result of chassis:migrate-layout.
+type MigrateLayoutResult struct {
+	Nodes      string                  `json:"nodes"`
+	Playbooks  string                  `json:"playbooks"`
+	Hostname   string                  `json:"hostname"`
+	Extensions []string                `json:"extensions"`
+	Moves      []chassis.FileMove      `json:"moves"`
+	Hostnames  []chassis.HostnameField `json:"hostnames"`
+	// ConfigUpdated is set when the layout was written to the config file.
+	ConfigUpdated bool `json:"config_updated"`
+	DryRun        bool `json:"dry_run,omitempty"`
+	// Errors lists files that could not be migrated.
+	Errors []chassis.FileError `json:"errors,omitempty"`
+
+	message.Log
+}
+
+// MigrateLayout implements the chassis:migrate-layout command
+type MigrateLayout struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir       string
+	Nodes     string // target node directory with <platform>, empty to keep it
+	Playbooks string // target playbook directory, empty to keep it
+	Hostname  string // target hostname source, empty to keep the current one
+	Extension string // target extension of node files and playbooks, empty to keep it
+	GitMv     bool   // move files with git mv
+
+	result *MigrateLayoutResult
+}
+
+// Result returns the structured result for JSON output.
+func (m *MigrateLayout) Result() any {
+	return m.result
+}
+
+// Execute runs the migrate-layout action
+func (m *MigrateLayout) Execute() error {
+	if m.Nodes == "" && m.Playbooks == "" && m.Hostname == "" && m.Extension == "" {
+		return fmt.Errorf("at least one of --nodes, --playbooks, --hostname and --extension is required")
+	}
+	target := chassis.CurrentLayout()
+	if m.Nodes != "" {
+		target.Nodes = m.Nodes
+	}
+	if m.Playbooks != "" {
+		target.Playbooks = m.Playbooks
+	}
+	if m.Hostname != "" {
+		target.Hostname = m.Hostname
+	}
+	if m.Extension != "" {
+		// The target extension comes first, the others stay accepted
+		exts := []string{m.Extension}
+		for _, ext := range target.FileExtensions() {
+			if ext != m.Extension {
+				exts = append(exts, ext)
+			}
+		}
+		target.Extensions = exts
+	}
+
+	endPhase := m.Phase("plan migration")
+	migration, err := chassis.PlanLayoutMigration(m.Dir, target)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	current := chassis.CurrentLayout()
+	m.result = &MigrateLayoutResult{
+		Nodes:      target.NodesDir(),
+		Playbooks:  filepath.ToSlash(target.PlaybooksDir()),
+		Hostname:   target.Hostname,
+		Extensions: target.FileExtensions(),
+		Moves:      migration.Moves,
+		Hostnames:  migration.Hostnames,
+		DryRun:     m.DryRun(),
+	}
+	if m.result.Hostname == "" {
+		m.result.Hostname = chassis.HostnameFromFilename
+	}
+	if migration.Empty() && current.TrustsYAMLHostname() == target.TrustsYAMLHostname() &&
+		current.NodesDir() == target.NodesDir() && current.PlaybooksDir() == target.PlaybooksDir() &&
+		slices.Equal(current.FileExtensions(), target.FileExtensions()) {
+		m.Report(message.LayoutCurrent)
+		return nil
+	}
+
+	if m.DryRun() {
+		m.Report(message.DryRun)
+		m.print()
+		m.Term().Printfln("  %s: layout.nodes: %s, layout.playbooks: %s, layout.hostname: %s, layout.extensions: %v",
+			chassis.ConfigFile, m.result.Nodes, m.result.Playbooks, m.result.Hostname, m.result.Extensions)
+		return nil
+	}
+
+	endPhase = m.Phase("migrate files")
+	moved, err := chassis.ApplyLayoutMigration(m.Dir, migration, m.GitMv)
+	endPhase()
+	m.result.Moves = moved
+	if m.result.Moves == nil {
+		m.result.Moves = []chassis.FileMove{}
+	}
+	m.result.Errors = chassis.FileErrors(err)
+	if len(m.result.Errors) > 0 {
+		// The config keeps the current layout, which still reads the files
+		// migrated so far unless their extension was dropped or they moved
+		// to another directory
+		m.print()
+		m.Report(message.FilesNotUpdated, len(m.result.Errors))
+		cli.PrintFileErrors(m.Term(), m.result.Errors)
+		return fmt.Errorf("layout migration incomplete, %s not updated: fix the listed files and run it again", chassis.ConfigFile)
+	}
+
+	endPhase = m.Phase("update config")
+	err = chassis.SetLayoutConfig(m.Dir, target)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	m.result.ConfigUpdated = true
+	chassis.SetLayout(target)
+
+	m.print()
+	m.Report(message.LayoutMigrated, len(m.result.Moves), len(m.result.Hostnames), chassis.ConfigFile)
+	return nil
+}
+
+// print lists the file moves and hostname fields of the migration.
+func (m *MigrateLayout) print() {
+	if len(m.result.Moves) > 0 {
+		m.Term().Info().Println("File moves:")
+		for _, mv := range m.result.Moves {
+			m.Term().Printfln("  - %s -> %s", mv.Old, mv.New)
+		}
+	}
+	if len(m.result.Hostnames) > 0 {
+		m.Term().Info().Println("Hostname fields:")
+		for _, h := range m.result.Hostnames {
+			m.Term().Printfln("  - %s: hostname: %s", h.File, h.Hostname)
+		}
+	}
+}
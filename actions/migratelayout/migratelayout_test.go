@@ -0,0 +1,44 @@
+package migratelayout
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestMigrateLayoutGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *MigrateLayout
+		dryRun bool
+		node   string // node file of prod-5 afterwards
+	}{
+		{"extension", &MigrateLayout{Extension: ".yml"}, false, "inst/prod/nodes/prod-5.yml"},
+		{"dry-run", &MigrateLayout{Extension: ".yml"}, true, "inst/prod/nodes/prod-5.yaml"},
+		{"hostname", &MigrateLayout{Hostname: chassis.HostnameFromYAML}, false, "inst/prod/nodes/prod-5.yaml"},
+		{"directories", &MigrateLayout{Nodes: "environments/<platform>/hosts", Playbooks: "layers"}, false,
+			"environments/prod/hosts/prod-5.yaml"},
+		{"nothing", &MigrateLayout{}, false, "inst/prod/nodes/prod-5.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The action applies the new layout to the running process
+			layout := chassis.CurrentLayout()
+			defer chassis.SetLayout(layout)
+
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "inst/prod/nodes/prod-5.yaml", "hostname: prod-5.example.com\nchassis:\n  - platform.foundation.cluster.nodes\n")
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".prod-5.yaml", dir, tt.node)
+
+			// The layout in effect afterwards reads every node
+			nodes, err := chassis.LoadNodes(dir, "")
+			if err != nil || len(nodes) != 7 {
+				t.Errorf("LoadNodes after migration = %d nodes, %v; want 7", len(nodes), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package move
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// MoveResult is the structured result of chassis:move.
+type MoveResult struct {
+	Old                string   `json:"old"`
+	New                string   `json:"new"`
+	DryRun             bool     `json:"dry_run,omitempty"`
+	UpdatedAttachments []string `json:"updated_attachments"`
+	UpdatedAllocations []string `json:"updated_allocations"`
+	// MovedFiles lists files and directories named after the path, moved by --rename-files.
+	MovedFiles []chassis.FileMove `json:"moved_files,omitempty"`
+	// RolledBack is set when a file could not be updated and every change
+	// was undone.
+	RolledBack bool `json:"rolled_back,omitempty"`
+	// Errors lists files that could not be updated.
+	Errors []chassis.FileError `json:"errors,omitempty"`
+
+	message.Log
+}
+
+// Move implements the chassis:move command
+type Move struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir       string
+	Chassis   string
+	NewParent string
+
+	RenameFiles bool // also move files and directories named after the path
+	GitMv       bool // move them with git mv
+
+	Limits pkgchassis.Limits
+
+	result *MoveResult
+}
+
+// Result returns the structured result for JSON output.
+func (m *Move) Result() any {
+	return m.result
+}
+
+// Execute runs the move action
+func (m *Move) Execute() error {
+	endPhase := m.Phase("load chassis")
+	c, err := chassis.Load(m.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	// Path-named files are matched against the tree before the move
+	paths := c.Flatten()
+	newPath, err := c.Move(m.Chassis, m.NewParent)
+	if err != nil {
+		return fmt.Errorf("failed to move chassis path: %w", err)
+	}
+	// Plays are looked up in the playbook of their layer, which a move
+	// can't change
+	if layer := chassis.LayerOf(m.Chassis); layer != chassis.LayerOf(newPath) {
+		return fmt.Errorf("cannot move %q out of layer %q: its plays would stay in the %s playbook", m.Chassis, layer, layer)
+	}
+	if err := m.Limits.Check(c.Chassis); err != nil {
+		return err
+	}
+	m.result = &MoveResult{Old: m.Chassis, New: newPath}
+
+	var moves []chassis.FileMove
+	if m.RenameFiles || m.GitMv {
+		endPhase = m.Phase("find path-named files")
+		moves, err = chassis.PathNamedFiles(m.Dir, paths, m.Chassis, newPath)
+		endPhase()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.DryRun() {
+		return m.executeDryRun(moves)
+	}
+
+	// chassis.yaml, playbooks, node files and annotations change together
+	chassis.BeginTransaction()
+
+	m.WarnAliasExpansion(c)
+	endPhase = m.Phase("save chassis")
+	err = c.Save(m.Dir)
+	endPhase()
+	m.result.Errors = append(m.result.Errors, chassis.FileErrors(err)...)
+
+	if err == nil {
+		endPhase = m.Phase("update attachments")
+		m.result.UpdatedAttachments, err = chassis.UpdateAttachments(m.Dir, m.Chassis, newPath)
+		endPhase()
+		m.result.Errors = append(m.result.Errors, chassis.FileErrors(err)...)
+
+		endPhase = m.Phase("update allocations")
+		m.result.UpdatedAllocations, err = chassis.UpdateAllocations(m.Dir, m.Chassis, newPath)
+		endPhase()
+		m.result.Errors = append(m.result.Errors, chassis.FileErrors(err)...)
+
+		m.result.Errors = append(m.result.Errors, chassis.FileErrors(m.moveMeta(newPath))...)
+
+		if len(moves) > 0 {
+			endPhase = m.Phase("move files")
+			m.result.MovedFiles, err = chassis.MoveFiles(m.Dir, moves, m.GitMv)
+			endPhase()
+			m.result.Errors = append(m.result.Errors, chassis.FileErrors(err)...)
+		}
+	}
+
+	if len(m.result.Errors) > 0 {
+		endPhase = m.Phase("roll back")
+		rollbackErr := chassis.RollbackTransaction()
+		endPhase()
+		m.result.UpdatedAttachments, m.result.UpdatedAllocations, m.result.MovedFiles = nil, nil, nil
+		m.result.RolledBack = true
+		m.Report(message.MoveRolledBack, len(m.result.Errors))
+		cli.PrintFileErrors(m.Term(), m.result.Errors)
+		err := fmt.Errorf("failed to move %s: %d file(s) could not be updated", m.Chassis, len(m.result.Errors))
+		if rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back: %w", rollbackErr))
+		}
+		return err
+	}
+	chassis.CommitTransaction()
+
+	m.Report(message.ChassisMoved, m.Chassis, newPath)
+	if len(m.result.UpdatedAttachments) > 0 {
+		m.Term().Info().Println("Updated attachments:")
+		for _, p := range m.result.UpdatedAttachments {
+			m.Term().Printfln("  - %s", p)
+		}
+	}
+	if len(m.result.UpdatedAllocations) > 0 {
+		m.Term().Info().Println("Updated allocations:")
+		for _, p := range m.result.UpdatedAllocations {
+			m.Term().Printfln("  - %s", p)
+		}
+	}
+	if len(m.result.MovedFiles) > 0 {
+		m.Term().Info().Println("Moved files:")
+		m.printMoves(m.result.MovedFiles)
+	}
+	return nil
+}
+
+// moveMeta moves annotations of the subtree to its new path.
+func (m *Move) moveMeta(newPath string) error {
+	meta, err := pkgchassis.LoadMeta(m.Dir)
+	if err != nil {
+		return err
+	}
+	if !chassis.RenameMeta(meta, m.Chassis, newPath) {
+		return nil
+	}
+	return chassis.SaveMeta(m.Dir, meta)
+}
+
+// executeDryRun shows what would change without modifying any files.
+func (m *Move) executeDryRun(moves []chassis.FileMove) error {
+	m.Report(message.DryRun)
+	m.Term().Printfln("  chassis.yaml: %s -> %s", m.result.Old, m.result.New)
+	m.result.DryRun = true
+
+	endPhase := m.Phase("load attachments")
+	attachments, err := chassis.LoadAttachments(m.Dir, m.Chassis)
+	endPhase()
+	if err != nil {
+		m.Log().Debug("Failed to load attachments", "error", err)
+		m.Degrade("failed to load attachments", err)
+	}
+	seen := make(map[string]bool)
+	m.result.UpdatedAttachments = []string{}
+	for _, a := range attachments {
+		if !seen[a.Playbook] {
+			seen[a.Playbook] = true
+			m.result.UpdatedAttachments = append(m.result.UpdatedAttachments, a.Playbook)
+		}
+	}
+
+	endPhase = m.Phase("load nodes")
+	nodesByPlatform, err := chassis.LoadNodesByPlatform(m.Dir)
+	endPhase()
+	if err != nil {
+		m.Log().Debug("Failed to load nodes", "error", err)
+		m.Degrade("failed to load nodes", err)
+	}
+	m.result.UpdatedAllocations = []string{}
+	for _, nodes := range nodesByPlatform {
+		for _, n := range chassis.NodesForChassis(nodes, m.Chassis) {
+			rel, err := filepath.Rel(m.Dir, n.File)
+			if err != nil {
+				rel = n.File
+			}
+			m.result.UpdatedAllocations = append(m.result.UpdatedAllocations, rel)
+		}
+	}
+	sort.Strings(m.result.UpdatedAllocations)
+
+	if len(m.result.UpdatedAttachments) > 0 {
+		m.Term().Info().Println("Would update attachments:")
+		for _, p := range m.result.UpdatedAttachments {
+			m.Term().Printfln("  - %s", p)
+		}
+	}
+	if len(m.result.UpdatedAllocations) > 0 {
+		m.Term().Info().Println("Would update allocations:")
+		for _, p := range m.result.UpdatedAllocations {
+			m.Term().Printfln("  - %s", p)
+		}
+	}
+
+	m.result.MovedFiles = moves
+	if len(moves) > 0 {
+		m.Term().Info().Println("Would move files:")
+		m.printMoves(moves)
+	}
+	return nil
+}
+
+// printMoves lists file moves.
+func (m *Move) printMoves(moves []chassis.FileMove) {
+	for _, mv := range moves {
+		m.Term().Printfln("  - %s -> %s", mv.Old, mv.New)
+	}
+}
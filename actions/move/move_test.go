@@ -0,0 +1,80 @@
+package move
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestMoveGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Move
+		dryRun bool
+	}{
+		{"move", &Move{Chassis: "platform.foundation.storage.kv", NewParent: "platform.foundation.cluster"}, false},
+		{"dry-run", &Move{Chassis: "platform.foundation.storage.kv", NewParent: "platform.foundation.cluster"}, true},
+		{"other-layer", &Move{Chassis: "platform.foundation.storage", NewParent: "platform.cognition"}, false},
+		{"into-itself", &Move{Chassis: "platform.foundation", NewParent: "platform.foundation.cluster"}, false},
+		{"unknown", &Move{Chassis: "platform.foundation.compute", NewParent: "platform.cognition"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+			golden.CompareFile(t, tt.name+".prod-2.yaml", dir, "inst/prod/nodes/prod-2.yaml")
+			golden.CompareFile(t, tt.name+".foundation.yaml", dir, "src/foundation/foundation.yaml")
+		})
+	}
+}
+
+func TestMoveRenameFilesGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // a file in the way of a move, rolling back the action
+		dryRun   bool
+	}{
+		{"rename-files", "", false},
+		{"rename-files-dry-run", "", true},
+		{"rename-files-conflict", "group_vars/platform.foundation.cluster.kv/main.yaml", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "group_vars/platform.foundation.storage.kv/main.yaml", "kv_port: 2379\n")
+			golden.WriteFile(t, dir, "group_vars/platform_foundation_storage_kv.yaml", "kv_replicas: 3\n")
+			if tt.existing != "" {
+				golden.WriteFile(t, dir, tt.existing, "kv_port: 2380\n")
+			}
+			action := &Move{
+				Dir:         dir,
+				Chassis:     "platform.foundation.storage.kv",
+				NewParent:   "platform.foundation.cluster",
+				RenameFiles: true,
+			}
+			action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+
+			var files []string
+			err := filepath.WalkDir(filepath.Join(dir, "group_vars"), func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(dir, path)
+				files = append(files, filepath.ToSlash(rel))
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden.Compare(t, tt.name+".files", []byte(strings.Join(files, "\n")+"\n"))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package nodes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Output formats.
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+)
+
+// NodeInfo is a node with its declared and effective chassis paths.
+type NodeInfo struct {
+	Hostname string `json:"hostname"`
+	Platform string `json:"platform"`
+	// Direct lists the entries of the node file's chassis list.
+	Direct []string `json:"direct"`
+	// Effective lists the paths the node is allocated to after distribution.
+	Effective   []string `json:"effective"`
+	Quarantined bool     `json:"quarantined,omitempty"`
+}
+
+// NodesResult is the structured result of chassis:nodes.
+type NodesResult struct {
+	Chassis string     `json:"chassis,omitempty"`
+	Nodes   []NodeInfo `json:"nodes"`
+
+	message.Log
+}
+
+// Nodes implements the chassis:nodes command
+type Nodes struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Chassis  string // only nodes allocated to this subtree
+	Platform string
+	Format   string
+
+	Distribution pkgchassis.Strategy
+
+	result *NodesResult
+}
+
+// Result returns the structured result for JSON output.
+func (n *Nodes) Result() any {
+	return n.result
+}
+
+// Execute runs the nodes action
+func (n *Nodes) Execute() error {
+	if n.Format != "" && n.Format != FormatTable && n.Format != FormatCSV {
+		return fmt.Errorf("unknown format %q (supported: %s, %s)", n.Format, FormatTable, FormatCSV)
+	}
+
+	endPhase := n.Phase("load chassis")
+	c, err := pkgchassis.Load(n.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if n.Chassis != "" && !c.Exists(n.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", n.Chassis)
+	}
+
+	distributor, err := pkgchassis.NewDistributor(n.Distribution)
+	if err != nil {
+		return err
+	}
+
+	endPhase = n.Phase("load nodes")
+	nodesByPlatform, err := chassis.LoadNodesForPath(n.Dir, c, n.Chassis)
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		n.Log().Debug("Failed to load some node files", "error", skipped)
+		n.Degrade("failed to load some node files", skipped)
+	}
+
+	n.result = &NodesResult{Chassis: n.Chassis, Nodes: []NodeInfo{}}
+	for platform, nodes := range nodesByPlatform {
+		if n.Platform != "" && platform != n.Platform {
+			continue
+		}
+		allocations := pkgchassis.Allocate(c, distributor, chassis.DeclaredNodes(nodes))
+		for i, node := range nodes {
+			if n.Chassis != "" && !allocations[i].AllocatedTo(n.Chassis) {
+				continue
+			}
+			n.result.Nodes = append(n.result.Nodes, NodeInfo{
+				Hostname:    node.Hostname,
+				Platform:    platform,
+				Direct:      append([]string{}, node.Chassis...),
+				Effective:   append([]string{}, allocations[i].Paths()...),
+				Quarantined: node.Quarantined,
+			})
+		}
+	}
+	sort.Slice(n.result.Nodes, func(i, j int) bool {
+		a, b := n.result.Nodes[i], n.result.Nodes[j]
+		if a.Platform != b.Platform {
+			return a.Platform < b.Platform
+		}
+		return a.Hostname < b.Hostname
+	})
+
+	if n.Format == FormatCSV {
+		return n.printCSV()
+	}
+	if len(n.result.Nodes) == 0 {
+		n.Report(message.NoNodes)
+		return nil
+	}
+	n.printTable()
+	return nil
+}
+
+// printTable prints one aligned row per node. Quarantined nodes are marked
+// with an asterisk.
+func (n *Nodes) printTable() {
+	table := [][]string{{"HOSTNAME", "PLATFORM", "DIRECT", "EFFECTIVE"}}
+	for _, node := range n.result.Nodes {
+		hostname := node.Hostname
+		if node.Quarantined {
+			hostname += "*"
+		}
+		table = append(table, []string{hostname, node.Platform, joinOrDash(node.Direct), joinOrDash(node.Effective)})
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, line := range table {
+		for i, cell := range line {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	quarantined := false
+	for _, line := range table {
+		var b strings.Builder
+		for i, cell := range line {
+			if i < len(line)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], cell)
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		n.Term().Printfln("  %s", b.String())
+		quarantined = quarantined || strings.HasSuffix(line[0], "*")
+	}
+	if quarantined {
+		n.Term().Printfln("  * quarantined")
+	}
+}
+
+// printCSV prints a header and one record per node; lists are space-separated.
+func (n *Nodes) printCSV() error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{"hostname", "platform", "direct", "effective", "quarantined"}}
+	for _, node := range n.result.Nodes {
+		records = append(records, []string{
+			node.Hostname,
+			node.Platform,
+			strings.Join(node.Direct, " "),
+			strings.Join(node.Effective, " "),
+			fmt.Sprint(node.Quarantined),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	n.Term().Printf("%s", buf.String())
+	return nil
+}
+
+// joinOrDash joins paths with commas, or returns "-" for none.
+func joinOrDash(paths []string) string {
+	if len(paths) == 0 {
+		return "-"
+	}
+	return strings.Join(paths, ",")
+}
@@ -0,0 +1,25 @@
+package nodes
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestNodesGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Nodes
+	}{
+		{"subtree", &Nodes{Chassis: "platform.foundation"}},
+		{"platform", &Nodes{Chassis: "platform", Platform: "dev"}},
+		{"csv", &Nodes{Chassis: "platform.interaction", Format: FormatCSV}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
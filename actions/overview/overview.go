@@ -0,0 +1,279 @@
+package overview
+
+import (
+	"strings"
+	"time"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// overviewDepth is the number of tree levels shown (root and layers).
+const overviewDepth = 2
+
+// overviewFindings is the number of validation findings printed; the JSON
+// result lists all of them.
+const overviewFindings = 10
+
+// overviewChanges is the number of recent changes listed.
+const overviewChanges = 5
+
+// LayerSummary describes a top-level chassis branch.
+type LayerSummary struct {
+	Path        string `json:"path"`
+	Paths       int    `json:"paths"`
+	Nodes       int    `json:"nodes"`
+	Attachments int    `json:"attachments"`
+}
+
+// Counts aggregates the size of the platform repository.
+type Counts struct {
+	Paths       int `json:"paths"`
+	Leaves      int `json:"leaves"`
+	Empty       int `json:"empty"`
+	Platforms   int `json:"platforms"`
+	Nodes       int `json:"nodes"`
+	Components  int `json:"components"`
+	Attachments int `json:"attachments"`
+}
+
+// Change is a mutating action recorded in a backup set.
+type Change struct {
+	Name    string    `json:"name"` // backup set, as accepted by chassis:restore
+	Action  string    `json:"action"`
+	Created time.Time `json:"created"`
+	Files   int       `json:"files"`
+}
+
+// OverviewResult is the structured output for chassis:overview
+type OverviewResult struct {
+	Roots  []string       `json:"roots"`
+	Layers []LayerSummary `json:"layers"`
+	Counts Counts         `json:"counts"`
+	// Changes are the latest changes recorded in backup sets, newest first.
+	Changes []Change `json:"changes"`
+	// Findings are the outstanding findings of chassis:validate.
+	Findings []validate.Finding `json:"findings"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+}
+
+// Overview implements the chassis:overview command
+type Overview struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+
+	Dir    string
+	Config chassis.Config
+
+	result *OverviewResult
+}
+
+// Result returns the structured result for JSON output
+func (o *Overview) Result() any {
+	return o.result
+}
+
+// Execute runs the overview action
+func (o *Overview) Execute() error {
+	endPhase := o.Phase("load chassis")
+	c, err := pkgchassis.Load(o.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	distributor, err := pkgchassis.NewDistributor(o.Config.Distribution)
+	if err != nil {
+		return err
+	}
+
+	endPhase = o.Phase("load nodes")
+	nodes, err := chassis.LoadNodes(o.Dir, "")
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		o.Log().Debug("Failed to load some node files", "error", skipped)
+		o.Degrade("failed to load some node files", skipped)
+	}
+	nodesByPlatform := make(map[string][]chassis.Node)
+	for _, n := range nodes {
+		nodesByPlatform[n.Platform] = append(nodesByPlatform[n.Platform], n)
+	}
+
+	endPhase = o.Phase("load components")
+	attachments, err := chassis.LoadAttachments(o.Dir, "")
+	endPhase()
+	if err != nil {
+		o.Log().Debug("Failed to load components", "error", err)
+		o.Degrade("failed to load components", err)
+	}
+
+	paths := c.Flatten()
+	o.result = &OverviewResult{Roots: []string{}, Layers: []LayerSummary{}, Changes: []Change{}, Findings: []validate.Finding{}}
+
+	counts := &o.result.Counts
+	counts.Paths = len(paths)
+	counts.Platforms = len(nodesByPlatform)
+	for _, p := range paths {
+		if !c.HasChildren(p) {
+			counts.Leaves++
+		}
+		if c.IsPlaceholder(p) {
+			counts.Empty++
+		}
+		if strings.Count(p, ".") == 0 {
+			o.result.Roots = append(o.result.Roots, p)
+		}
+	}
+
+	// Effective allocations and attachments per layer
+	nodesPerLayer := make(map[string]map[string]bool)
+	for _, nodes := range nodesByPlatform {
+		counts.Nodes += len(nodes)
+		for _, n := range pkgchassis.Allocate(c, distributor, chassis.DeclaredNodes(nodes)) {
+			for _, cp := range n.Paths() {
+				layer := truncate(cp, overviewDepth)
+				if nodesPerLayer[layer] == nil {
+					nodesPerLayer[layer] = make(map[string]bool)
+				}
+				nodesPerLayer[layer][n.DisplayName()] = true
+			}
+		}
+	}
+
+	attachmentsPerLayer := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, a := range attachments {
+		if !seen[a.Component] {
+			seen[a.Component] = true
+			counts.Components++
+		}
+		if a.Chassis != "" {
+			counts.Attachments++
+			attachmentsPerLayer[truncate(a.Chassis, overviewDepth)]++
+		}
+	}
+
+	for _, root := range o.result.Roots {
+		for _, layer := range c.Children(root) {
+			o.result.Layers = append(o.result.Layers, LayerSummary{
+				Path:        layer,
+				Paths:       len(c.FlattenWithPrefix(layer)),
+				Nodes:       len(nodesPerLayer[layer]),
+				Attachments: attachmentsPerLayer[layer],
+			})
+		}
+	}
+
+	endPhase = o.Phase("load changes")
+	sets, err := chassis.ListBackups(o.Dir)
+	endPhase()
+	if err != nil {
+		o.Log().Debug("Failed to list backups", "error", err)
+		o.Degrade("failed to list backups", err)
+	}
+	for i := len(sets) - 1; i >= 0 && len(o.result.Changes) < overviewChanges; i-- {
+		o.result.Changes = append(o.result.Changes, Change{
+			Name:    sets[i].Name,
+			Action:  sets[i].Action,
+			Created: sets[i].Created,
+			Files:   len(sets[i].Entries),
+		})
+	}
+
+	endPhase = o.Phase("check rules")
+	findings, err := o.findings()
+	endPhase()
+	if err != nil {
+		o.Log().Debug("Failed to check rules", "error", err)
+		o.Degrade("failed to check rules", err)
+	}
+	if findings != nil {
+		o.result.Findings = findings
+	}
+	o.result.Errors, o.result.Warnings = validate.Count(o.result.Findings)
+
+	o.print()
+	return nil
+}
+
+// findings runs the rules chassis:validate runs by default.
+func (o *Overview) findings() ([]validate.Finding, error) {
+	ctx, err := validate.Load(o.Dir, o.Config)
+	if err != nil {
+		return nil, err
+	}
+	return validate.Run(ctx)
+}
+
+// print renders the dashboard to the terminal.
+func (o *Overview) print() {
+	o.Term().Info().Printfln("Chassis (depth %d)", overviewDepth)
+	for _, root := range o.result.Roots {
+		o.Term().Printfln("%s", root)
+
+		var layers []LayerSummary
+		for _, l := range o.result.Layers {
+			if pkgchassis.Parent(l.Path) == root {
+				layers = append(layers, l)
+			}
+		}
+		for i, l := range layers {
+			prefix := "├── "
+			if i == len(layers)-1 {
+				prefix = "└── "
+			}
+			name := l.Path[len(root)+1:]
+			o.Term().Printfln("%s%-20s %4d paths  %4d nodes  %4d attachments", prefix, name, l.Paths, l.Nodes, l.Attachments)
+		}
+	}
+
+	c := o.result.Counts
+	o.Term().Info().Println("Counts")
+	o.Term().Printfln("  paths:        %d (%d leaves, %d empty)", c.Paths, c.Leaves, c.Empty)
+	o.Term().Printfln("  platforms:    %d", c.Platforms)
+	o.Term().Printfln("  nodes:        %d", c.Nodes)
+	o.Term().Printfln("  components:   %d", c.Components)
+	o.Term().Printfln("  attachments:  %d", c.Attachments)
+
+	o.Term().Info().Println("Recent changes")
+	if len(o.result.Changes) == 0 {
+		o.Term().Printfln("  none recorded (enable backup.enabled or pass --backup to record changes)")
+	}
+	for _, ch := range o.result.Changes {
+		o.Term().Printfln("  %s  %-24s %d file(s)  %s", ch.Created.Format(time.RFC3339), ch.Action, ch.Files, ch.Name)
+	}
+
+	o.Term().Info().Printfln("Findings (%d errors, %d warnings)", o.result.Errors, o.result.Warnings)
+	if len(o.result.Findings) == 0 {
+		o.Term().Printfln("  none")
+	}
+	for i, f := range o.result.Findings {
+		if i == overviewFindings {
+			o.Term().Printfln("  ... %d more, see chassis:validate", len(o.result.Findings)-overviewFindings)
+			break
+		}
+		subject := f.Chassis
+		if f.Node != "" {
+			subject = f.Node
+		}
+		o.Term().Printfln("  [%s] %s %s: %s", f.Severity, f.Rule, subject, f.Message)
+	}
+}
+
+// truncate keeps the first depth segments of a chassis path.
+func truncate(chassisPath string, depth int) string {
+	parts := strings.Split(chassisPath, ".")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, ".")
+}
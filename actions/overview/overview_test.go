@@ -0,0 +1,32 @@
+package overview
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestOverviewGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		action *Overview
+	}{
+		{"overview", nil, &Overview{}},
+		{"changes-and-findings", map[string]string{
+			"inst/dev/nodes/dev-3.yaml":                         "hostname: dev-3\nchassis:\n  - platform.foundation.k8s\n",
+			".plasmactl/backups/20260102T030405Z/manifest.json": `{"action": "chassis:rename", "created": "2026-01-02T03:04:05Z", "entries": [{"path": "chassis.yaml"}, {"path": "inst/dev/nodes/dev-1.yaml"}]}`,
+			".plasmactl/backups/20260103T030405Z/manifest.json": `{"action": "chassis:attach", "created": "2026-01-03T03:04:05Z", "entries": [{"path": "src/foundation/foundation.yaml"}]}`,
+		}, &Overview{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			for name, content := range tt.files {
+				golden.WriteFile(t, dir, name, content)
+			}
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package parent
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ParentResult is the structured result of chassis:parent.
+type ParentResult struct {
+	Chassis string `json:"chassis"`
+	// Parent is empty for a root path.
+	Parent string `json:"parent"`
+
+	message.Log
+}
+
+// Parent implements the chassis:parent command
+type Parent struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir     string
+	Chassis string
+
+	result *ParentResult
+}
+
+// Result returns the structured result for JSON output.
+func (p *Parent) Result() any {
+	return p.result
+}
+
+// Execute runs the parent action. The parent is printed alone on its
+// line for scripting; nothing is printed for a root path.
+func (p *Parent) Execute() error {
+	endPhase := p.Phase("load chassis")
+	c, err := pkgchassis.Load(p.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(p.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", p.Chassis)
+	}
+
+	p.result = &ParentResult{Chassis: p.Chassis, Parent: pkgchassis.Parent(p.Chassis)}
+	if p.result.Parent != "" {
+		p.Term().Printfln("%s", p.result.Parent)
+	}
+	return nil
+}
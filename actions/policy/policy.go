@@ -0,0 +1,222 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+)
+
+// Subcommands of chassis:policy.
+const (
+	CommandShow  = "show"
+	CommandSet   = "set"
+	CommandCheck = "check"
+)
+
+// Setting is a policy setting with its effective severity.
+type Setting struct {
+	Name string `json:"name"`
+	// Rule is the chassis:validate rule the setting configures.
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Default is set when the severity isn't configured.
+	Default bool                `json:"default,omitempty"`
+	Layers  map[string][]string `json:"layers,omitempty"`
+}
+
+// PolicyResult is the structured result of chassis:policy.
+type PolicyResult struct {
+	Command  string    `json:"command"`
+	Settings []Setting `json:"settings"`
+	DryRun   bool      `json:"dry_run,omitempty"`
+	// Findings, Errors and Warnings are set by check.
+	Findings []validate.Finding `json:"findings,omitempty"`
+	Errors   int                `json:"errors,omitempty"`
+	Warnings int                `json:"warnings,omitempty"`
+
+	message.Log
+}
+
+// Policy implements the chassis:policy command
+type Policy struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir     string
+	Command string
+	Key     string // set: setting to change
+	Value   string // set: new value
+	Config  chassis.Config
+
+	result *PolicyResult
+}
+
+// Result returns the structured result for JSON output.
+func (p *Policy) Result() any {
+	return p.result
+}
+
+// Mutates reports whether the action writes the config file.
+func (p *Policy) Mutates() bool {
+	return p.Command == CommandSet && !p.DryRun()
+}
+
+// Execute runs the policy action
+func (p *Policy) Execute() error {
+	switch p.Command {
+	case CommandShow:
+		p.result = &PolicyResult{Command: p.Command, Settings: settings(p.Config.Policy)}
+		p.print()
+		return nil
+	case CommandSet:
+		return p.set()
+	case CommandCheck:
+		return p.check()
+	default:
+		return fmt.Errorf("unknown subcommand %q (supported: %s, %s, %s)", p.Command, CommandShow, CommandSet, CommandCheck)
+	}
+}
+
+// set changes a setting in the config file.
+func (p *Policy) set() error {
+	if p.Key == "" {
+		return fmt.Errorf("set requires a key, e.g. %s or %s.layers.<prefix>", chassis.PolicyLeafOnly, chassis.PolicyLayerOwnership)
+	}
+	endPhase := p.Phase("update config")
+	policy, err := chassis.SetPolicy(p.Dir, p.Key, p.Value, p.DryRun())
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	p.result = &PolicyResult{Command: p.Command, Settings: settings(policy), DryRun: p.DryRun()}
+	if p.DryRun() {
+		p.Report(message.DryRun)
+	}
+	p.Report(message.PolicySet, p.Key, p.Value, chassis.ConfigFile)
+	p.print()
+	return nil
+}
+
+// check runs the validation rules enabled by the policy.
+func (p *Policy) check() error {
+	p.result = &PolicyResult{Command: p.Command, Settings: settings(p.Config.Policy)}
+	var rules []string
+	for _, s := range p.result.Settings {
+		if s.Severity != chassis.PolicyOff {
+			rules = append(rules, s.Rule)
+		}
+	}
+	if len(rules) == 0 {
+		p.Report(message.PolicyInactive)
+		return nil
+	}
+
+	endPhase := p.Phase("load repository")
+	ctx, err := validate.Load(p.Dir, p.Config)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	endPhase = p.Phase("check rules")
+	findings, err := validate.Run(ctx, rules...)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	errs, warnings := validate.Count(findings)
+	p.result.Findings, p.result.Errors, p.result.Warnings = findings, errs, warnings
+	if len(findings) == 0 {
+		p.Report(message.PolicyCompliant, strings.Join(rules, ", "))
+		return nil
+	}
+	for _, f := range findings {
+		subject := f.Chassis
+		if f.Node != "" {
+			subject = f.Node
+		}
+		line := fmt.Sprintf("[%s] %s: %s", f.Rule, subject, f.Message)
+		if f.File != "" {
+			line += " (" + f.File + ")"
+		}
+		if f.Severity == validate.SeverityError {
+			p.Term().Error().Println(line)
+		} else {
+			p.Term().Warning().Println(line)
+		}
+	}
+	if errs > 0 || (p.Strict() && warnings > 0) {
+		return fmt.Errorf("policy check failed: %d error(s), %d warning(s)", errs, warnings)
+	}
+	p.Report(message.ValidateWarnings, warnings)
+	return nil
+}
+
+// print lists the settings with their effective severity.
+func (p *Policy) print() {
+	for _, s := range p.result.Settings {
+		severity := s.Severity
+		if s.Default {
+			severity += " (default)"
+		}
+		p.Term().Printfln("%s (%s): %s", s.Name, s.Rule, severity)
+		if s.Name != chassis.PolicyLayerOwnership {
+			continue
+		}
+		if len(s.Layers) == 0 {
+			p.Term().Printfln("  layers: naming convention, <prefix>.* roles under <root>.<prefix>")
+			continue
+		}
+		prefixes := make([]string, 0, len(s.Layers))
+		for prefix := range s.Layers {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			p.Term().Printfln("  %s.*: %s", prefix, strings.Join(s.Layers[prefix], ", "))
+		}
+	}
+}
+
+// settings returns the policy settings with their effective severities:
+// layer ownership is checked as warning by default, leaf-only is opt-in and
+// capacity limits are errors.
+func settings(policy chassis.Policy) []Setting {
+	layerOwnership := Setting{
+		Name:     chassis.PolicyLayerOwnership,
+		Rule:     validate.RuleComponentLayerOwnership,
+		Severity: policy.LayerOwnership.Severity,
+		Layers:   policy.LayerOwnership.Layers,
+	}
+	if layerOwnership.Severity == "" {
+		layerOwnership.Severity, layerOwnership.Default = chassis.PolicyWarning, true
+	}
+	leafOnly := Setting{
+		Name:     chassis.PolicyLeafOnly,
+		Rule:     validate.RuleChassisLeafOnly,
+		Severity: policy.LeafOnly.Severity,
+	}
+	if leafOnly.Severity == "" {
+		leafOnly.Severity, leafOnly.Default = chassis.PolicyOff, true
+	}
+	capacity := Setting{
+		Name:     chassis.PolicyCapacity,
+		Rule:     validate.RuleChassisCapacity,
+		Severity: policy.Capacity.Severity,
+	}
+	if capacity.Severity == "" {
+		capacity.Severity, capacity.Default = chassis.PolicyError, true
+	}
+	return []Setting{layerOwnership, leafOnly, capacity}
+}
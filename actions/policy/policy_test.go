@@ -0,0 +1,35 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestPolicyGolden(t *testing.T) {
+	strict := chassis.Config{Policy: chassis.Policy{LeafOnly: chassis.LeafOnly{Severity: chassis.PolicyError}}}
+	tests := []struct {
+		name   string
+		action *Policy
+		dryRun bool
+	}{
+		{"show", &Policy{Command: CommandShow}, false},
+		{"check", &Policy{Command: CommandCheck}, false},
+		{"check-leaf-only", &Policy{Command: CommandCheck, Config: strict}, false},
+		{"set-dry-run", &Policy{Command: CommandSet, Key: chassis.PolicyLeafOnly, Value: "warning"}, true},
+		{"set", &Policy{Command: CommandSet, Key: chassis.PolicyLeafOnly, Value: "warning"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+
+			if tt.action.Command == CommandSet && !tt.dryRun {
+				golden.CompareFile(t, tt.name+".config.yaml", dir, chassis.ConfigFile)
+			}
+		})
+	}
+}
@@ -3,26 +3,57 @@ package query
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/launchrctl/launchr/pkg/action"
-	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
-	"github.com/plasmash/plasmactl-component/pkg/component"
-	"github.com/plasmash/plasmactl-node/pkg/node"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
+// Identifier kinds.
+const (
+	KindNode      = "node"
+	KindComponent = "component"
+)
+
+// Match lists the chassis paths of the identifier as one kind.
+type Match struct {
+	Kind  string   `json:"kind"` // node or component
+	Paths []string `json:"paths"`
+}
+
 // QueryResult is the structured output for chassis:query
 type QueryResult struct {
+	// Found is false when the identifier matched no node or component.
+	Found bool     `json:"found"`
 	Paths []string `json:"paths"`
+	// Matches separates the paths by kind (--all).
+	Matches []Match `json:"matches,omitempty"`
+	// Ambiguous is set when the identifier names both a node and a component (--all).
+	Ambiguous bool `json:"ambiguous,omitempty"`
+	// EmptyAllocations explains why the node named by the identifier, on
+	// each platform declaring it, resolves to no chassis path.
+	EmptyAllocations []pkgchassis.EmptyAllocation `json:"empty_allocations,omitempty"`
+
+	message.Log
 }
 
 // Query implements the chassis:query command
 type Query struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
 
 	Dir        string
 	Identifier string
 	Kind       string // "node" or "component" to narrow search
+	All        bool   // report matches of each kind separately
+
+	Distribution pkgchassis.Strategy
 
 	result *QueryResult
 }
@@ -30,16 +61,24 @@ type Query struct {
 // Execute runs the query action
 func (q *Query) Execute() error {
 	// Load chassis for distribution computation
-	c, err := chassis.Load(q.Dir)
+	endPhase := q.Phase("load chassis")
+	c, err := pkgchassis.Load(q.Dir)
+	endPhase()
 	if err != nil {
 		return err
 	}
 
-	var chassisPaths []string
+	distributor, err := pkgchassis.NewDistributor(q.Distribution)
+	if err != nil {
+		return err
+	}
+
+	var nodePaths, componentPaths []string
+	var empty []pkgchassis.EmptyAllocation
 
 	// Search based on kind or search both when unspecified
-	searchNode := q.Kind == "" || q.Kind == "node"
-	searchComponent := q.Kind == "" || q.Kind == "component"
+	searchNode := q.Kind == "" || q.Kind == KindNode
+	searchComponent := q.Kind == "" || q.Kind == KindComponent
 
 	if q.Kind != "" && !searchNode && !searchComponent {
 		return fmt.Errorf("invalid kind %q: must be \"node\" or \"component\"", q.Kind)
@@ -47,67 +86,104 @@ func (q *Query) Execute() error {
 
 	// Search in nodes (allocations with distribution)
 	if searchNode {
-		nodesByPlatform, err := node.LoadByPlatform(q.Dir)
+		// Only the node and the nodes sharing its paths are needed
+		endPhase := q.Phase("load nodes")
+		nodesByPlatform, err := chassis.LoadNodesForHost(q.Dir, c, q.Identifier)
+		endPhase()
 		if err != nil {
 			q.Log().Debug("Failed to load nodes", "error", err)
+			q.Degrade("failed to load nodes", err)
 		}
 
 		for _, nodes := range nodesByPlatform {
 			// Compute effective allocations for all nodes in this platform
-			allocations := nodes.Allocations(c)
-
-			for _, n := range nodes {
+			declared := chassis.DeclaredNodes(nodes)
+			allocations := pkgchassis.Allocate(c, distributor, declared)
+			for _, n := range allocations {
 				if n.Hostname == q.Identifier {
 					// Use effective allocations (after distribution)
-					chassisPaths = append(chassisPaths, allocations[n.Hostname]...)
+					nodePaths = append(nodePaths, n.Paths()...)
 				}
 			}
+			for _, e := range pkgchassis.EmptyAllocations(c, declared, allocations) {
+				if e.Hostname == q.Identifier {
+					empty = append(empty, e)
+				}
+			}
+		}
+		sort.Slice(empty, func(i, j int) bool { return empty[i].Platform < empty[j].Platform })
+		for _, e := range empty {
+			q.Report(message.AllocationEmpty, chassis.NodeName(e.Hostname, e.Platform), strings.Join(e.Reasons, "; "))
 		}
 	}
 
 	// Search in attachments (components) — always search when applicable, no short-circuit
 	if searchComponent {
-		components, err := component.LoadFromPlaybooks(q.Dir)
+		endPhase := q.Phase("load components")
+		attachments, err := chassis.LoadAttachments(q.Dir, "")
+		endPhase()
 		if err != nil {
 			q.Log().Debug("Failed to load components", "error", err)
+			q.Degrade("failed to load components", err)
 		}
 
-		attachmentsMap := components.Attachments(c)
+		attachmentsMap := chassis.AttachmentPaths(attachments)
 		if attached, ok := attachmentsMap[q.Identifier]; ok {
-			chassisPaths = append(chassisPaths, attached...)
+			componentPaths = append(componentPaths, attached...)
 		}
 	}
 
-	if len(chassisPaths) == 0 {
+	unique := uniqueSorted(append(append([]string(nil), nodePaths...), componentPaths...))
+	if len(unique) == 0 {
+		q.result = &QueryResult{Paths: []string{}, EmptyAllocations: empty}
 		return fmt.Errorf("no chassis paths found for %q (searched as %s)", q.Identifier, q.searchDescription())
 	}
 
-	// Remove duplicates and sort
+	q.result = &QueryResult{Found: true, Paths: unique, EmptyAllocations: empty}
+	if !q.All {
+		for _, s := range unique {
+			q.Term().Printfln("%s", s)
+		}
+		return nil
+	}
+
+	for _, m := range []Match{{KindNode, uniqueSorted(nodePaths)}, {KindComponent, uniqueSorted(componentPaths)}} {
+		if len(m.Paths) == 0 {
+			continue
+		}
+		q.result.Matches = append(q.result.Matches, m)
+		q.Term().Info().Printfln("%s (%d paths)", m.Kind, len(m.Paths))
+		for _, s := range m.Paths {
+			q.Term().Printfln("  %s", s)
+		}
+	}
+	if len(q.result.Matches) > 1 {
+		q.result.Ambiguous = true
+		q.Report(message.QueryAmbiguous, q.Identifier)
+	}
+	return nil
+}
+
+// uniqueSorted returns paths without duplicates, sorted.
+func uniqueSorted(paths []string) []string {
 	seen := make(map[string]bool)
 	var unique []string
-	for _, p := range chassisPaths {
+	for _, p := range paths {
 		if !seen[p] {
 			seen[p] = true
 			unique = append(unique, p)
 		}
 	}
 	sort.Strings(unique)
-
-	q.result = &QueryResult{Paths: unique}
-
-	for _, s := range unique {
-		q.Term().Printfln("%s", s)
-	}
-
-	return nil
+	return unique
 }
 
 // searchDescription returns a human-readable description of what was searched.
 func (q *Query) searchDescription() string {
 	switch q.Kind {
-	case "node":
+	case KindNode:
 		return "node"
-	case "component":
+	case KindComponent:
 		return "component"
 	default:
 		return "node and component"
@@ -0,0 +1,26 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestQueryGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Query
+	}{
+		{"node", &Query{Identifier: "prod-4"}},
+		{"component", &Query{Identifier: "foundation.cluster.etcd"}},
+		{"kind", &Query{Identifier: "dev-2", Kind: KindNode}},
+		{"unknown", &Query{Identifier: "nothing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
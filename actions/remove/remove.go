@@ -2,29 +2,42 @@ package remove
 
 import (
 	"fmt"
-	"strings"
+	"sort"
 
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/plasmash/plasmactl-chassis/internal/chassis"
-	"github.com/plasmash/plasmactl-node/pkg/node"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
 // RemoveResult is the structured result of chassis:remove.
 type RemoveResult struct {
 	Chassis            string   `json:"chassis"`
 	DryRun             bool     `json:"dry_run,omitempty"`
-	AllocatedNodes     []string `json:"allocated_nodes,omitempty"`
-	AttachedComponents []string `json:"attached_components,omitempty"`
+	AllocatedNodes     []string `json:"allocated_nodes"`
+	AttachedComponents []string `json:"attached_components"`
+	// AllocationFiles and AttachmentFiles count the node files and playbooks
+	// considered when looking for allocations and attachments.
+	AllocationFiles chassis.FileCounts `json:"allocation_files"`
+	AttachmentFiles chassis.FileCounts `json:"attachment_files"`
+
+	message.Log
 }
 
 // Remove implements the chassis:remove command
 type Remove struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
 
 	Dir     string
 	Chassis string
-	DryRun  bool
+
+	Distribution pkgchassis.Strategy
 
 	result *RemoveResult
 }
@@ -36,7 +49,9 @@ func (r *Remove) Result() any {
 
 // Execute runs the remove action
 func (r *Remove) Execute() error {
+	endPhase := r.Phase("load chassis")
 	c, err := chassis.Load(r.Dir)
+	endPhase()
 	if err != nil {
 		return err
 	}
@@ -45,29 +60,48 @@ func (r *Remove) Execute() error {
 		return fmt.Errorf("chassis %q not found", r.Chassis)
 	}
 
+	distributor, err := pkgchassis.NewDistributor(r.Distribution)
+	if err != nil {
+		return err
+	}
+
 	// Check for allocated nodes using distributed allocations
-	nodesByPlatform, err := node.LoadByPlatform(r.Dir)
+	endPhase = r.Phase("load nodes")
+	var nodesByPlatform map[string][]chassis.Node
+	allocationFiles, err := chassis.CountFiles(func() (err error) {
+		nodesByPlatform, err = chassis.LoadNodesByPlatform(r.Dir)
+		return err
+	})
+	endPhase()
 	if err != nil {
 		r.Log().Debug("Failed to load nodes", "error", err)
+		r.Degrade("failed to load nodes", err)
 	}
 
 	var allocatedNodes []string
+	allocatedFiles := make(map[string]bool)
 	for _, nodes := range nodesByPlatform {
-		allocations := nodes.Allocations(c.Chassis)
-		for _, n := range nodes {
-			for _, cp := range allocations[n.Hostname] {
-				if cp == r.Chassis || strings.HasPrefix(cp, r.Chassis+".") {
-					allocatedNodes = append(allocatedNodes, n.DisplayName())
-					break
-				}
+		for _, n := range pkgchassis.Allocate(c.Chassis, distributor, chassis.DeclaredNodes(nodes)) {
+			if n.AllocatedTo(r.Chassis) {
+				allocatedNodes = append(allocatedNodes, n.DisplayName())
+				allocatedFiles[n.File] = true
 			}
 		}
 	}
+	sort.Strings(allocatedNodes)
+	allocationFiles.Matched = len(allocatedFiles)
 
 	// Check for attached components
-	attachments, err := chassis.LoadAttachments(r.Dir, r.Chassis)
+	endPhase = r.Phase("load attachments")
+	var attachments []chassis.Attachment
+	attachmentFiles, err := chassis.CountFiles(func() (err error) {
+		attachments, err = chassis.LoadAttachments(r.Dir, r.Chassis)
+		return err
+	})
+	endPhase()
 	if err != nil {
 		r.Log().Debug("Failed to load attachments", "error", err)
+		r.Degrade("failed to load attachments", err)
 	}
 
 	var attachedComponents []string
@@ -76,19 +110,21 @@ func (r *Remove) Execute() error {
 	}
 
 	// Dry-run: report what would block removal
-	if r.DryRun {
+	if r.DryRun() {
 		r.result = &RemoveResult{
 			Chassis:            r.Chassis,
 			DryRun:             true,
 			AllocatedNodes:     allocatedNodes,
 			AttachedComponents: attachedComponents,
+			AllocationFiles:    allocationFiles,
+			AttachmentFiles:    attachmentFiles,
 		}
 
-		r.Term().Info().Println("[dry-run] No changes will be made")
+		r.Report(message.DryRun)
 		if len(allocatedNodes) > 0 {
 			r.Term().Info().Println("Allocated nodes:")
 			for _, n := range allocatedNodes {
-				r.Term().Printfln("  %s", n)
+				r.Term().Printfln("  %s", chassis.NodeNameOf(n))
 			}
 		}
 		if len(attachedComponents) > 0 {
@@ -98,7 +134,7 @@ func (r *Remove) Execute() error {
 			}
 		}
 		if len(allocatedNodes) == 0 && len(attachedComponents) == 0 {
-			r.Term().Success().Printfln("Safe to remove: %s", r.Chassis)
+			r.Report(message.ChassisRemovable, r.Chassis)
 		}
 		return nil
 	}
@@ -107,7 +143,7 @@ func (r *Remove) Execute() error {
 	if len(allocatedNodes) > 0 {
 		r.Term().Info().Println("Allocated nodes:")
 		for _, n := range allocatedNodes {
-			r.Term().Printfln("  %s", n)
+			r.Term().Printfln("  %s", chassis.NodeNameOf(n))
 		}
 		return fmt.Errorf("cannot remove chassis %q: %d node(s) are allocated (deallocate them first)", r.Chassis, len(allocatedNodes))
 	}
@@ -125,11 +161,29 @@ func (r *Remove) Execute() error {
 		return err
 	}
 
-	if err := c.Save(r.Dir); err != nil {
+	r.WarnAliasExpansion(c)
+	endPhase = r.Phase("save chassis")
+	err = c.Save(r.Dir)
+	endPhase()
+	if err != nil {
 		return err
 	}
 
-	r.result = &RemoveResult{Chassis: r.Chassis}
-	r.Term().Success().Printfln("Removed: %s", r.Chassis)
+	// Drop annotations of the removed subtree
+	meta, err := pkgchassis.LoadMeta(r.Dir)
+	if err == nil && chassis.RemoveMeta(meta, r.Chassis) {
+		err = chassis.SaveMeta(r.Dir, meta)
+	}
+	if err != nil {
+		r.Report(message.MetaUpdateFailed, pkgchassis.MetaFile, err)
+		r.Degrade("failed to update "+pkgchassis.MetaFile, err)
+	}
+
+	r.result = &RemoveResult{
+		Chassis:         r.Chassis,
+		AllocationFiles: allocationFiles,
+		AttachmentFiles: attachmentFiles,
+	}
+	r.Report(message.ChassisRemoved, r.Chassis)
 	return nil
 }
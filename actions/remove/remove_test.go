@@ -0,0 +1,29 @@
+package remove
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestRemoveGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		chassis string
+		dryRun  bool
+	}{
+		{"allocated", "platform.foundation.cluster", false},
+		{"dry-run", "platform.foundation.network", true},
+		{"unused", "platform.foundation.network", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			r := &Remove{Dir: dir, Chassis: tt.chassis}
+			r.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, r)
+
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
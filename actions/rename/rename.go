@@ -2,9 +2,14 @@ package rename
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
 // RenameResult is the structured result of chassis:rename.
@@ -12,19 +17,48 @@ type RenameResult struct {
 	Old                string   `json:"old"`
 	New                string   `json:"new"`
 	DryRun             bool     `json:"dry_run,omitempty"`
-	UpdatedAttachments []string `json:"updated_attachments,omitempty"`
-	UpdatedAllocations []string `json:"updated_allocations,omitempty"`
+	UpdatedAttachments []string `json:"updated_attachments"`
+	UpdatedAllocations []string `json:"updated_allocations"`
+	// Rewrites lists chassis path tokens replaced in playbook values by --deep.
+	Rewrites []chassis.Rewrite `json:"rewrites,omitempty"`
+	// MovedFiles lists files and directories named after the path, moved by --rename-files.
+	MovedFiles []chassis.FileMove `json:"moved_files,omitempty"`
+	// Merged is set when the old path was merged into an existing new path (--merge).
+	Merged bool `json:"merged,omitempty"`
+	// Conflicts lists child paths that existed under both paths and were merged.
+	Conflicts []string `json:"conflicts,omitempty"`
+	// GroupChanges lists nodes whose inventory group membership would change
+	// beyond the renamed group names (dry run).
+	GroupChanges []chassis.GroupChange `json:"group_changes,omitempty"`
+	// AttachmentFiles and AllocationFiles count the playbooks and node files
+	// considered, e.g. to detect a rename that updated no allocation file.
+	AttachmentFiles chassis.FileCounts `json:"attachment_files"`
+	AllocationFiles chassis.FileCounts `json:"allocation_files"`
+	// Errors lists files that could not be updated.
+	Errors []chassis.FileError `json:"errors,omitempty"`
+
+	message.Log
 }
 
 // Rename implements the chassis:rename command
 type Rename struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir   string
+	Old   string
+	New   string
+	Deep  bool // also rewrite path tokens in playbook values, e.g. vars and delegate_to
+	Merge bool // merge into the new path if it already exists
+
+	RenameFiles bool // also move files and directories named after the path
+	GitMv       bool // move them with git mv
 
-	Dir    string
-	Old    string
-	New    string
-	DryRun bool
+	Distribution pkgchassis.Strategy
 
 	result *RenameResult
 }
@@ -36,7 +70,9 @@ func (r *Rename) Result() any {
 
 // Execute runs the rename action
 func (r *Rename) Execute() error {
+	endPhase := r.Phase("load chassis")
 	c, err := chassis.Load(r.Dir)
+	endPhase()
 	if err != nil {
 		return err
 	}
@@ -45,43 +81,114 @@ func (r *Rename) Execute() error {
 		return fmt.Errorf("chassis %q does not exist", r.Old)
 	}
 
-	if c.Exists(r.New) {
-		return fmt.Errorf("chassis %q already exists", r.New)
+	merging := r.Merge && c.Exists(r.New)
+	if c.Exists(r.New) && !merging {
+		return fmt.Errorf("chassis %q already exists (use --merge to merge into it)", r.New)
 	}
 
-	if r.DryRun {
-		return r.executeDryRun()
+	// Path-named files are matched against the tree before the rename
+	var moves []chassis.FileMove
+	if r.RenameFiles || r.GitMv {
+		endPhase = r.Phase("find path-named files")
+		moves, err = chassis.PathNamedFiles(r.Dir, c.Flatten(), r.Old, r.New)
+		endPhase()
+		if err != nil {
+			return err
+		}
 	}
 
-	// Rename in chassis.yaml
-	if err := c.Rename(r.Old, r.New); err != nil {
-		return fmt.Errorf("failed to rename chassis path: %w", err)
+	// Merge or rename in chassis.yaml; dry runs discard the change
+	var conflicts []string
+	if merging {
+		conflicts, err = c.Merge(r.Old, r.New)
+		if err != nil {
+			return fmt.Errorf("failed to merge chassis path: %w", err)
+		}
 	}
 
-	if err := c.Save(r.Dir); err != nil {
-		return err
+	if r.DryRun() {
+		if !merging {
+			if err := c.Rename(r.Old, r.New); err != nil {
+				return fmt.Errorf("failed to rename chassis path: %w", err)
+			}
+		}
+		if err := r.executeDryRun(c, moves); err != nil {
+			return err
+		}
+		r.result.Merged, r.result.Conflicts = merging, conflicts
+		r.printConflicts()
+		return nil
 	}
 
-	// Update attachments
-	updatedAttachments, err := chassis.UpdateAttachments(r.Dir, r.Old, r.New)
-	if err != nil {
-		r.Term().Warning().Printfln("Chassis renamed but failed to update attachments: %s", err)
+	if !merging {
+		if err := c.Rename(r.Old, r.New); err != nil {
+			return fmt.Errorf("failed to rename chassis path: %w", err)
+		}
 	}
 
-	// Update allocations
-	updatedAllocations, err := chassis.UpdateAllocations(r.Dir, r.Old, r.New)
+	r.WarnAliasExpansion(c)
+	endPhase = r.Phase("save chassis")
+	err = c.Save(r.Dir)
+	endPhase()
 	if err != nil {
-		r.Term().Warning().Printfln("Chassis renamed but failed to update allocations: %s", err)
+		return err
 	}
 
 	r.result = &RenameResult{
-		Old:                r.Old,
-		New:                r.New,
-		UpdatedAttachments: updatedAttachments,
-		UpdatedAllocations: updatedAllocations,
+		Old:       r.Old,
+		New:       r.New,
+		Merged:    merging,
+		Conflicts: conflicts,
 	}
 
-	r.Term().Success().Printfln("Renamed: %s -> %s", r.Old, r.New)
+	// Update attachments
+	endPhase = r.Phase("update attachments")
+	var updatedAttachments []string
+	r.result.AttachmentFiles, err = chassis.CountFiles(func() (err error) {
+		updatedAttachments, err = chassis.UpdateAttachments(r.Dir, r.Old, r.New)
+		return err
+	})
+	endPhase()
+	r.result.UpdatedAttachments = updatedAttachments
+	r.result.Errors = append(r.result.Errors, chassis.FileErrors(err)...)
+
+	// Update allocations
+	endPhase = r.Phase("update allocations")
+	var updatedAllocations []string
+	r.result.AllocationFiles, err = chassis.CountFiles(func() (err error) {
+		updatedAllocations, err = chassis.UpdateAllocations(r.Dir, r.Old, r.New)
+		return err
+	})
+	endPhase()
+	r.result.UpdatedAllocations = updatedAllocations
+	r.result.Errors = append(r.result.Errors, chassis.FileErrors(err)...)
+
+	// Rewrite path tokens elsewhere in playbooks
+	if r.Deep {
+		endPhase = r.Phase("rewrite references")
+		rewrites, err := chassis.UpdateReferences(r.Dir, r.Old, r.New, false)
+		endPhase()
+		r.result.Rewrites = rewrites
+		r.result.Errors = append(r.result.Errors, chassis.FileErrors(err)...)
+	}
+
+	// Move annotations along with the renamed subtree
+	r.result.Errors = append(r.result.Errors, chassis.FileErrors(r.renameMeta(merging))...)
+
+	if len(moves) > 0 {
+		endPhase = r.Phase("move files")
+		moved, err := chassis.MoveFiles(r.Dir, moves, r.GitMv)
+		endPhase()
+		r.result.MovedFiles = moved
+		r.result.Errors = append(r.result.Errors, chassis.FileErrors(err)...)
+	}
+
+	if merging {
+		r.Report(message.ChassisMerged, r.Old, r.New)
+		r.printConflicts()
+	} else {
+		r.Report(message.ChassisRenamed, r.Old, r.New)
+	}
 	if len(updatedAttachments) > 0 {
 		r.Term().Info().Println("Updated attachments:")
 		for _, p := range updatedAttachments {
@@ -94,19 +201,59 @@ func (r *Rename) Execute() error {
 			r.Term().Printfln("  - %s", p)
 		}
 	}
+	if len(r.result.Rewrites) > 0 {
+		r.Term().Info().Println("Rewritten references (review these):")
+		r.printRewrites()
+	}
+	if len(r.result.MovedFiles) > 0 {
+		r.Term().Info().Println("Moved files:")
+		r.printMoves(r.result.MovedFiles)
+	}
+	if len(r.result.Errors) > 0 {
+		r.Report(message.RenameIncomplete, len(r.result.Errors))
+		cli.PrintFileErrors(r.Term(), r.result.Errors)
+		r.Degrade("rename incomplete", fmt.Errorf("%d file(s) could not be updated", len(r.result.Errors)))
+	}
 
 	return nil
 }
 
+// renameMeta moves annotations of the old subtree to the new path.
+// When merging, annotations of the existing path take precedence.
+func (r *Rename) renameMeta(merge bool) error {
+	meta, err := pkgchassis.LoadMeta(r.Dir)
+	if err != nil {
+		return err
+	}
+	var moved bool
+	if merge {
+		moved = chassis.MergeMeta(meta, r.Old, r.New)
+	} else {
+		moved = chassis.RenameMeta(meta, r.Old, r.New)
+	}
+	if !moved {
+		return nil
+	}
+	return chassis.SaveMeta(r.Dir, meta)
+}
+
 // executeDryRun shows what would change without modifying any files.
-func (r *Rename) executeDryRun() error {
-	r.Term().Info().Println("[dry-run] No changes will be made")
+// The renamed tree c is only held in memory.
+func (r *Rename) executeDryRun(c *chassis.Chassis, moves []chassis.FileMove) error {
+	r.Report(message.DryRun)
 	r.Term().Printfln("  chassis.yaml: %s -> %s", r.Old, r.New)
 
 	// Find affected attachment files
-	attachments, err := chassis.LoadAttachments(r.Dir, r.Old)
+	endPhase := r.Phase("load attachments")
+	var attachments []chassis.Attachment
+	attachmentFiles, err := chassis.CountFiles(func() (err error) {
+		attachments, err = chassis.LoadAttachments(r.Dir, r.Old)
+		return err
+	})
+	endPhase()
 	if err != nil {
 		r.Log().Debug("Failed to load attachments", "error", err)
+		r.Degrade("failed to load attachments", err)
 	}
 
 	seen := make(map[string]bool)
@@ -119,17 +266,29 @@ func (r *Rename) executeDryRun() error {
 	}
 
 	// Find affected allocation files
-	nodesByPlatform, err := chassis.LoadNodesByPlatform(r.Dir)
+	endPhase = r.Phase("load nodes")
+	var nodesByPlatform map[string][]chassis.Node
+	allocationFiles, err := chassis.CountFiles(func() (err error) {
+		nodesByPlatform, err = chassis.LoadNodesByPlatform(r.Dir)
+		return err
+	})
+	endPhase()
 	if err != nil {
 		r.Log().Debug("Failed to load nodes", "error", err)
+		r.Degrade("failed to load nodes", err)
 	}
 
 	var affectedNodeFiles []string
-	for platform, nodes := range nodesByPlatform {
+	for _, nodes := range nodesByPlatform {
 		for _, n := range chassis.NodesForChassis(nodes, r.Old) {
-			affectedNodeFiles = append(affectedNodeFiles, fmt.Sprintf("inst/%s/nodes/%s.yaml", platform, n.Hostname))
+			rel, err := filepath.Rel(r.Dir, n.File)
+			if err != nil {
+				rel = n.File
+			}
+			affectedNodeFiles = append(affectedNodeFiles, rel)
 		}
 	}
+	sort.Strings(affectedNodeFiles)
 
 	if len(affectedPlaybooks) > 0 {
 		r.Term().Info().Println("Would update attachments:")
@@ -150,7 +309,95 @@ func (r *Rename) executeDryRun() error {
 		DryRun:             true,
 		UpdatedAttachments: affectedPlaybooks,
 		UpdatedAllocations: affectedNodeFiles,
+		AttachmentFiles:    attachmentFiles,
+		AllocationFiles:    allocationFiles,
+	}
+	// Node files are only read; those that would be updated are the matches
+	r.result.AllocationFiles.Matched = len(affectedNodeFiles)
+
+	if r.Deep {
+		endPhase = r.Phase("find references")
+		rewrites, err := chassis.UpdateReferences(r.Dir, r.Old, r.New, true)
+		endPhase()
+		if err != nil {
+			r.Log().Debug("Failed to find references", "error", err)
+			r.Degrade("failed to find references", err)
+		}
+		r.result.Rewrites = rewrites
+		if len(rewrites) > 0 {
+			r.Term().Info().Println("Would rewrite references:")
+			r.printRewrites()
+		}
+	}
+
+	r.result.MovedFiles = moves
+	if len(moves) > 0 {
+		r.Term().Info().Println("Would move files:")
+		r.printMoves(moves)
+	}
+
+	return r.simulateGroups(c, nodesByPlatform)
+}
+
+// simulateGroups reports nodes whose effective inventory group membership
+// would change beyond the renamed group names.
+func (r *Rename) simulateGroups(c *chassis.Chassis, nodesByPlatform map[string][]chassis.Node) error {
+	distributor, err := pkgchassis.NewDistributor(r.Distribution)
+	if err != nil {
+		return err
+	}
+	before, err := chassis.Load(r.Dir)
+	if err != nil {
+		return err
+	}
+	var nodes []chassis.Node
+	for _, platformNodes := range nodesByPlatform {
+		nodes = append(nodes, platformNodes...)
 	}
 
+	endPhase := r.Phase("simulate group membership")
+	r.result.GroupChanges = chassis.RenameGroupChanges(before, c, distributor, nodes, r.Old, r.New)
+	endPhase()
+	if len(r.result.GroupChanges) == 0 {
+		r.Report(message.GroupsUnchanged)
+		return nil
+	}
+	r.Report(message.GroupsChanged, len(r.result.GroupChanges))
+	for _, change := range r.result.GroupChanges {
+		r.Term().Printfln("  - %s", chassis.NodeNameOf(change.Node))
+		for _, g := range change.Joined {
+			r.Term().Printfln("      + %s", g)
+		}
+		for _, g := range change.Left {
+			r.Term().Printfln("      - %s", g)
+		}
+	}
 	return nil
 }
+
+// printConflicts lists child paths that existed under both merged paths.
+func (r *Rename) printConflicts() {
+	if len(r.result.Conflicts) == 0 {
+		return
+	}
+	r.Report(message.MergeConflicts, len(r.result.Conflicts), r.Old, r.New)
+	for _, p := range r.result.Conflicts {
+		r.Term().Printfln("  - %s", p)
+	}
+}
+
+// printMoves lists file moves.
+func (r *Rename) printMoves(moves []chassis.FileMove) {
+	for _, m := range moves {
+		r.Term().Printfln("  - %s -> %s", m.Old, m.New)
+	}
+}
+
+// printRewrites lists each rewrite site for review.
+func (r *Rename) printRewrites() {
+	for _, rw := range r.result.Rewrites {
+		r.Term().Printfln("  - %s:%d", rw.File, rw.Line)
+		r.Term().Printfln("      - %s", rw.Old)
+		r.Term().Printfln("      + %s", rw.New)
+	}
+}
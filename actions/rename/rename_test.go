@@ -0,0 +1,33 @@
+package rename
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestRenameGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Rename
+		dryRun bool
+	}{
+		{"rename", &Rename{Old: "platform.foundation.cluster", New: "platform.foundation.k8s"}, false},
+		{"dry-run", &Rename{Old: "platform.foundation.cluster", New: "platform.foundation.k8s"}, true},
+		{"exists", &Rename{Old: "platform.foundation.cluster", New: "platform.foundation.storage"}, false},
+		{"merge", &Rename{Old: "platform.foundation.network", New: "platform.foundation.storage", Merge: true}, false},
+		{"unknown", &Rename{Old: "platform.foundation.compute", New: "platform.foundation.k8s"}, false},
+		{"layer", &Rename{Old: "platform.foundation", New: "platform.base"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+			golden.CompareFile(t, tt.name+".prod-1.yaml", dir, "inst/prod/nodes/prod-1.yaml")
+			golden.CompareFile(t, tt.name+".foundation.yaml", dir, "src/foundation/foundation.yaml")
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package reorder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// ReorderResult is the structured result of chassis:reorder.
+type ReorderResult struct {
+	Chassis  string   `json:"chassis"`
+	Children []string `json:"children"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Reorder implements the chassis:reorder command
+type Reorder struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithMessages
+
+	Dir    string
+	Parent string
+	Order  []string // children in their new order
+	Alpha  bool     // sort children alphabetically
+
+	result *ReorderResult
+}
+
+// Result returns the structured result for JSON output.
+func (r *Reorder) Result() any {
+	return r.result
+}
+
+// Execute runs the reorder action
+func (r *Reorder) Execute() error {
+	if r.Alpha == (len(r.Order) > 0) {
+		return fmt.Errorf("exactly one of --order and --alpha is required")
+	}
+
+	endPhase := r.Phase("load chassis")
+	c, err := chassis.Load(r.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	children, err := c.Reorder(r.Parent, r.Order, r.Alpha)
+	if err != nil {
+		return fmt.Errorf("failed to reorder chassis path: %w", err)
+	}
+	r.result = &ReorderResult{Chassis: r.Parent, Children: children, DryRun: r.DryRun()}
+
+	if r.DryRun() {
+		r.Report(message.DryRun)
+		r.Term().Printfln("  chassis.yaml: %s: %s", r.Parent, strings.Join(children, ", "))
+		return nil
+	}
+
+	r.WarnAliasExpansion(c)
+	endPhase = r.Phase("save chassis")
+	err = c.Save(r.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	r.Report(message.ChassisReordered, r.Parent, strings.Join(children, ", "))
+	return nil
+}
@@ -0,0 +1,29 @@
+package reorder
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestReorderGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Reorder
+		dryRun bool
+	}{
+		{"order", &Reorder{Parent: "platform.foundation", Order: []string{"network", "storage", "cluster"}}, false},
+		{"dry-run", &Reorder{Parent: "platform.foundation", Order: []string{"network", "storage", "cluster"}}, true},
+		{"alpha", &Reorder{Parent: "platform.interaction", Alpha: true}, false},
+		{"incomplete", &Reorder{Parent: "platform.foundation", Order: []string{"network"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package restore
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// RestoreResult is the structured result of chassis:restore.
+type RestoreResult struct {
+	Set     string                `json:"set,omitempty"`
+	Action  string                `json:"action,omitempty"`
+	Files   []chassis.BackupEntry `json:"files"`
+	Backups []chassis.BackupSet   `json:"backups,omitempty"` // --list
+	DryRun  bool                  `json:"dry_run,omitempty"`
+	Errors  []chassis.FileError   `json:"errors,omitempty"`
+
+	message.Log
+}
+
+// Restore implements the chassis:restore command
+type Restore struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir  string
+	Set  string // backup set name, empty for the latest
+	List bool
+
+	result *RestoreResult
+}
+
+// Result returns the structured result for JSON output.
+func (r *Restore) Result() any {
+	return r.result
+}
+
+// Mutates reports whether the action reverts files.
+func (r *Restore) Mutates() bool {
+	return !r.List && !r.DryRun()
+}
+
+// Execute runs the restore action
+func (r *Restore) Execute() error {
+	if r.List {
+		return r.list()
+	}
+
+	endPhase := r.Phase("restore files")
+	set, err := chassis.RestoreBackup(r.Dir, r.Set, r.DryRun())
+	endPhase()
+	if set.Name == "" {
+		return err
+	}
+	r.result = &RestoreResult{
+		Set:    set.Name,
+		Action: set.Action,
+		Files:  set.Entries,
+		DryRun: r.DryRun(),
+		Errors: chassis.FileErrors(err),
+	}
+
+	if r.DryRun() {
+		r.Report(message.DryRun)
+		r.Report(message.BackupRestorable, len(set.Entries), set.Name, set.Action)
+		for _, e := range set.Entries {
+			r.Term().Printfln("  %s", describe(e))
+		}
+		return nil
+	}
+	if len(r.result.Errors) > 0 {
+		r.Report(message.RestoreFailed, set.Name, len(r.result.Errors))
+		cli.PrintFileErrors(r.Term(), r.result.Errors)
+		r.Degrade("restore incomplete", fmt.Errorf("%d file(s) could not be restored", len(r.result.Errors)))
+		return nil
+	}
+	r.Report(message.BackupRestored, len(set.Entries), set.Name, set.Action)
+	return nil
+}
+
+// list reports the backup sets of the repository.
+func (r *Restore) list() error {
+	sets, err := chassis.ListBackups(r.Dir)
+	if err != nil {
+		return err
+	}
+	r.result = &RestoreResult{Files: []chassis.BackupEntry{}, Backups: sets}
+	if len(sets) == 0 {
+		r.Report(message.NoBackups)
+		return nil
+	}
+	for _, s := range sets {
+		r.Term().Printfln("%s  %-24s %d file(s)", s.Name, s.Action, len(s.Entries))
+	}
+	return nil
+}
+
+// describe renders how an entry is reverted.
+func describe(e chassis.BackupEntry) string {
+	switch {
+	case e.MovedTo != "":
+		return e.MovedTo + " -> " + e.Path
+	case e.Created:
+		return "- " + e.Path
+	default:
+		return "~ " + e.Path
+	}
+}
@@ -6,51 +6,110 @@ import (
 	"strings"
 
 	"github.com/launchrctl/launchr/pkg/action"
-	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
-	"github.com/plasmash/plasmactl-component/pkg/component"
-	"github.com/plasmash/plasmactl-node/pkg/node"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
 
 // AllocationInfo represents a node allocation
 type AllocationInfo struct {
-	Node     string   `json:"node"`
-	Platform string   `json:"platform"`
-	Chassis  []string `json:"chassis"`
+	Node        string   `json:"node"`
+	Platform    string   `json:"platform"`
+	Chassis     []string `json:"chassis"`
+	Quarantined bool     `json:"quarantined,omitempty"`
+	// Provenance tells for each path of Chassis whether the node file
+	// allocates it directly or distribution added it.
+	Provenance []pkgchassis.Allocation `json:"provenance,omitempty"`
+	// External holds the live facts of the node from the configured
+	// source (--facts), if it knows the node.
+	External map[string]any `json:"external,omitempty"`
 }
 
-// DisplayName returns the node formatted as "hostname@platform".
+// DisplayName returns the node formatted for human-facing output,
+// "hostname@platform" unless display.node is configured.
 func (a AllocationInfo) DisplayName() string {
-	return a.Node + "@" + a.Platform
+	return chassis.NodeName(a.Node, a.Platform)
 }
 
 // AttachmentInfo represents a component attachment
 type AttachmentInfo struct {
 	Component string `json:"component"`
 	Version   string `json:"version,omitempty"`
-	Chassis   string `json:"chassis"`
+	// Constraint is the version constraint recorded with the attachment
+	Constraint string `json:"constraint,omitempty"`
+	Chassis    string `json:"chassis"`
 }
 
-// DisplayName returns the component formatted as "name@version".
+// DisplayName returns the component formatted for human-facing output,
+// "name@version" unless display.component is configured, followed by the
+// attachment's version constraint if any.
 func (a AttachmentInfo) DisplayName() string {
-	return component.FormatDisplayName(a.Component, a.Version)
+	name := chassis.ComponentName(a.Component, a.Version)
+	if a.Constraint != "" {
+		name += " (" + a.Constraint + ")"
+	}
+	return name
+}
+
+// Matrix pivots effective allocations into rows per chassis path and
+// columns per platform.
+type Matrix struct {
+	Platforms []string    `json:"platforms"`
+	Rows      []MatrixRow `json:"rows"`
+	// Quarantined lists quarantined nodes as hostname@platform.
+	Quarantined []string `json:"quarantined,omitempty"`
+}
+
+// MatrixRow lists the nodes of each platform allocated to a chassis path.
+type MatrixRow struct {
+	Chassis string              `json:"chassis"`
+	Nodes   map[string][]string `json:"nodes"`
+}
+
+// PlatformSummary counts the nodes of a platform effectively allocated
+// under the queried chassis path.
+type PlatformSummary struct {
+	Platform    string `json:"platform"`
+	Nodes       int    `json:"nodes"`
+	Quarantined int    `json:"quarantined,omitempty"`
 }
 
 // ShowResult is the structured output for chassis:show
 type ShowResult struct {
 	Chassis     string           `json:"chassis,omitempty"`
-	Allocations []AllocationInfo `json:"allocations,omitempty"`
-	Attachments []AttachmentInfo `json:"attachments,omitempty"`
+	Allocations []AllocationInfo `json:"allocations"`
+	Attachments []AttachmentInfo `json:"attachments"`
+	Matrix      *Matrix          `json:"matrix,omitempty"`
+	// Platforms summarizes the allocations per platform of the repository,
+	// including platforms without any node under the chassis path.
+	Platforms []PlatformSummary `json:"platforms,omitempty"`
+	// EmptyAllocations explains the nodes whose entries resolve to no
+	// chassis path.
+	EmptyAllocations []pkgchassis.EmptyAllocation `json:"empty_allocations,omitempty"`
+
+	message.Log
 }
 
 // Show implements the chassis:show command
 type Show struct {
 	action.WithLogger
 	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
 
 	Dir      string
 	Chassis  string
 	Platform string
 	Kind     string // "allocations" or "attachments" to filter
+	Format   string // "wide" pivots allocations into platform columns
+	Facts    bool   // enrich allocations with live facts
+	Match    string // regular expression paths must match
+	Exclude  string // regular expression paths must not match
+
+	Distribution pkgchassis.Strategy
+	FactsSource  chassis.FactsConfig
 
 	result *ShowResult
 }
@@ -62,7 +121,9 @@ func (s *Show) Result() any {
 
 // Execute runs the show action
 func (s *Show) Execute() error {
-	c, err := chassis.Load(s.Dir)
+	endPhase := s.Phase("load chassis")
+	c, err := pkgchassis.Load(s.Dir)
+	endPhase()
 	if err != nil {
 		return err
 	}
@@ -72,55 +133,86 @@ func (s *Show) Execute() error {
 		return fmt.Errorf("chassis %q not found in chassis.yaml", s.Chassis)
 	}
 
+	if s.Facts && !s.FactsSource.Configured() {
+		return fmt.Errorf("--facts requires a facts source: set facts.command or facts.endpoint in %s", chassis.ConfigFile)
+	}
+
+	distributor, err := pkgchassis.NewDistributor(s.Distribution)
+	if err != nil {
+		return err
+	}
+	filter, err := chassis.NewPathFilter(s.Match, s.Exclude)
+	if err != nil {
+		return err
+	}
+
 	showAllocations := s.Kind == "" || s.Kind == "allocations"
 	showAttachments := s.Kind == "" || s.Kind == "attachments"
 
-	// Load all nodes by platform
-	nodesByPlatform, err := node.LoadByPlatform(s.Dir)
+	// Load the nodes by platform, only those relevant to the chassis path
+	endPhase = s.Phase("load nodes")
+	nodesByPlatform, err := chassis.LoadNodesForPath(s.Dir, c, s.Chassis)
+	endPhase()
 	if err != nil {
 		s.Log().Debug("Failed to load nodes", "error", err)
+		s.Degrade("failed to load nodes", err)
 	}
 
 	// Filter by platform if specified
 	if s.Platform != "" {
-		filtered := make(map[string]node.Nodes)
+		filtered := make(map[string][]chassis.Node)
 		if nodes, ok := nodesByPlatform[s.Platform]; ok {
 			filtered[s.Platform] = nodes
 		}
 		nodesByPlatform = filtered
 	}
 
-	// Load components from playbooks
-	components, err := component.LoadFromPlaybooks(s.Dir)
-	if err != nil {
-		s.Log().Debug("Failed to load components", "error", err)
-	}
-
-	// Build version map for quick lookup
+	// Load attachments from playbooks, with the version constraints recorded with the roles
+	var attachments []chassis.Attachment
 	versionMap := make(map[string]string)
-	for _, comp := range components {
-		versionMap[comp.Name] = comp.Version
+	constraints := make(map[[2]string]string)
+	if showAttachments {
+		endPhase = s.Phase("load components")
+		attachments, err = chassis.LoadAttachments(s.Dir, s.Chassis)
+		if err != nil {
+			s.Log().Debug("Failed to load attachments", "error", err)
+			s.Degrade("failed to load attachments", err)
+		}
+		versionMap, err = chassis.ComponentVersions(s.Dir)
+		endPhase()
+		if err != nil {
+			s.Log().Debug("Failed to load components", "error", err)
+			s.Degrade("failed to load components", err)
+		}
+		for _, a := range attachments {
+			if a.Version != "" {
+				constraints[[2]string{a.Chassis, a.Component}] = a.Version
+			}
+		}
 	}
 
 	// Get attachments map (component → chassis paths)
-	attachmentsMap := components.Attachments(c)
+	attachmentsMap := chassis.AttachmentPaths(attachments)
 
 	// Collect component attachments for the chassis path
 	type componentInfo struct {
-		chassis   string
-		component string
-		version   string
+		chassis    string
+		component  string
+		version    string
+		constraint string
 	}
 	var compInfos []componentInfo
 
 	for compName, chassisPaths := range attachmentsMap {
 		for _, chassisPath := range chassisPaths {
 			// Check if chassis path matches query (exact match or descendant)
-			if s.Chassis == "" || chassisPath == s.Chassis || chassis.IsDescendantOf(chassisPath, s.Chassis) {
+			inPath := s.Chassis == "" || chassisPath == s.Chassis || pkgchassis.IsDescendantOf(chassisPath, s.Chassis)
+			if inPath && filter.Matches(chassisPath) {
 				compInfos = append(compInfos, componentInfo{
-					chassis:   chassisPath,
-					component: compName,
-					version:   versionMap[compName],
+					chassis:    chassisPath,
+					component:  compName,
+					version:    versionMap[compName],
+					constraint: constraints[[2]string{chassisPath, compName}],
 				})
 			}
 		}
@@ -136,11 +228,14 @@ func (s *Show) Execute() error {
 
 	// Collect all node allocations (EFFECTIVE - after distribution)
 	type nodeInfo struct {
-		platform string
-		node     string
-		chassis  []string // effective chassis paths after distribution
+		platform    string
+		node        string
+		allocations []pkgchassis.Allocation // effective chassis paths after distribution
+
+		quarantined bool
 	}
 	var nodes []nodeInfo
+	var empty []pkgchassis.EmptyAllocation
 
 	// Get sorted platform names
 	var platforms []string
@@ -153,29 +248,36 @@ func (s *Show) Execute() error {
 		platformNodes := nodesByPlatform[platform]
 
 		// Compute effective allocations for all nodes in this platform
-		allocations := platformNodes.Allocations(c)
-
-		for _, n := range platformNodes {
-			effectiveChassis := allocations[n.Hostname]
+		declared := chassis.DeclaredNodes(platformNodes)
+		allocations := pkgchassis.Allocate(c, distributor, declared)
+		for _, e := range pkgchassis.EmptyAllocations(c, declared, allocations) {
+			if s.Chassis == "" || referencesPath(e.Entries, s.Chassis) {
+				empty = append(empty, e)
+			}
+		}
 
+		for i, n := range platformNodes {
 			// If chassis filter is specified, check if node is allocated to it
-			if s.Chassis != "" {
-				found := false
-				for _, chassisPath := range effectiveChassis {
-					if chassisPath == s.Chassis || chassis.IsDescendantOf(chassisPath, s.Chassis) {
-						found = true
-						break
-					}
-				}
-				if !found {
-					continue
+			if s.Chassis != "" && !allocations[i].AllocatedTo(s.Chassis) {
+				continue
+			}
+
+			// Only the paths selected by --match and --exclude are shown
+			var selected []pkgchassis.Allocation
+			for _, a := range allocations[i].Allocations {
+				if filter.Matches(a.Path) {
+					selected = append(selected, a)
 				}
 			}
+			if filter != nil && len(selected) == 0 {
+				continue
+			}
 
 			nodes = append(nodes, nodeInfo{
-				platform: platform,
-				node:     n.Hostname,
-				chassis:  effectiveChassis,
+				platform:    platform,
+				node:        n.Hostname,
+				allocations: selected,
+				quarantined: n.Quarantined,
 			})
 		}
 	}
@@ -190,44 +292,80 @@ func (s *Show) Execute() error {
 
 	// Build result
 	s.result = &ShowResult{
-		Chassis: s.Chassis,
+		Chassis:          s.Chassis,
+		EmptyAllocations: empty,
 	}
 
 	for _, n := range nodes {
-		s.result.Allocations = append(s.result.Allocations, AllocationInfo{
-			Node:     n.node,
-			Platform: n.platform,
-			Chassis:  n.chassis,
-		})
+		info := AllocationInfo{
+			Node:        n.node,
+			Platform:    n.platform,
+			Quarantined: n.quarantined,
+			Provenance:  n.allocations,
+		}
+		for _, a := range n.allocations {
+			info.Chassis = append(info.Chassis, a.Path)
+		}
+		s.result.Allocations = append(s.result.Allocations, info)
 	}
 
 	for _, comp := range compInfos {
 		s.result.Attachments = append(s.result.Attachments, AttachmentInfo{
-			Component: comp.component,
-			Version:   comp.version,
-			Chassis:   comp.chassis,
+			Component:  comp.component,
+			Version:    comp.version,
+			Constraint: comp.constraint,
+			Chassis:    comp.chassis,
 		})
 	}
 
+	if s.Facts && len(s.result.Allocations) > 0 {
+		endPhase = s.Phase("load facts")
+		s.enrich()
+		endPhase()
+	}
+
+	if s.Format == "wide" && showAllocations {
+		s.result.Matrix = buildMatrix(filter.Filter(c.FlattenWithPrefix(s.Chassis)), platforms, s.result.Allocations)
+	}
+	if showAllocations {
+		s.result.Platforms = s.platformSummary(s.result.Allocations)
+	}
+
 	// Output
+	if showAllocations {
+		for _, e := range empty {
+			s.Report(message.AllocationEmpty, chassis.NodeName(e.Hostname, e.Platform), strings.Join(e.Reasons, "; "))
+		}
+	}
 	hasAllocations := showAllocations && len(s.result.Allocations) > 0
 	hasAttachments := showAttachments && len(s.result.Attachments) > 0
 
 	if !hasAllocations && !hasAttachments {
-		s.Term().Info().Println("No allocations or attachments found")
+		s.Report(message.NothingToShow)
 		return nil
 	}
 
-	if hasAllocations {
+	if hasAllocations && s.result.Matrix != nil {
+		s.Term().Info().Printfln("Allocations (%d nodes)", len(s.result.Allocations))
+		s.printMatrix(s.result.Matrix)
+	} else if hasAllocations {
 		s.Term().Info().Printfln("Allocations (%d nodes)", len(s.result.Allocations))
 		for _, n := range s.result.Allocations {
 			chassisStr := strings.Join(n.Chassis, ", ")
 			if len(chassisStr) > 60 {
 				chassisStr = chassisStr[:57] + "..."
 			}
-			s.Term().Printfln("  %s  [%s]", n.DisplayName(), chassisStr)
+			facts := formatFacts(n.External)
+			if n.Quarantined {
+				s.Term().Printfln("  %s (quarantined)  [%s]%s", n.DisplayName(), chassisStr, facts)
+				continue
+			}
+			s.Term().Printfln("  %s  [%s]%s", n.DisplayName(), chassisStr, facts)
 		}
 	}
+	if showAllocations {
+		s.printPlatforms(s.result.Platforms)
+	}
 
 	if hasAttachments {
 		s.Term().Info().Printfln("Attachments (%d components)", len(s.result.Attachments))
@@ -238,3 +376,186 @@ func (s *Show) Execute() error {
 
 	return nil
 }
+
+// enrich merges the live facts of the configured source into the
+// allocations. Nodes are shown without facts if the source fails.
+func (s *Show) enrich() {
+	nodes := make([]chassis.FactsNode, len(s.result.Allocations))
+	for i, a := range s.result.Allocations {
+		nodes[i] = chassis.FactsNode{Hostname: a.Node, Platform: a.Platform}
+	}
+	facts, err := chassis.LoadFacts(s.Dir, s.FactsSource, nodes)
+	if err != nil {
+		s.Report(message.FactsUnavailable, err)
+		s.Degrade("failed to load facts", err)
+		return
+	}
+	for i, a := range s.result.Allocations {
+		if f, ok := facts[a.Node+"@"+a.Platform]; ok && len(f) > 0 {
+			s.result.Allocations[i].External = f
+		}
+	}
+}
+
+// formatFacts renders facts as " key=value ...", sorted by key.
+func formatFacts(facts map[string]any) string {
+	keys := make([]string, 0, len(facts))
+	for k := range facts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, facts[k])
+	}
+	return b.String()
+}
+
+// platformSummary counts allocations per platform of the repository, or of
+// the selected platform only.
+func (s *Show) platformSummary(allocations []AllocationInfo) []PlatformSummary {
+	platforms := []string{s.Platform}
+	if s.Platform == "" {
+		var err error
+		if platforms, err = chassis.Platforms(s.Dir); err != nil {
+			s.Log().Debug("Failed to list platforms", "error", err)
+			s.Degrade("failed to list platforms", err)
+		}
+	}
+	summary := make([]PlatformSummary, 0, len(platforms))
+	index := make(map[string]int, len(platforms))
+	for i, p := range platforms {
+		index[p] = i
+		summary = append(summary, PlatformSummary{Platform: p})
+	}
+	for _, a := range allocations {
+		i, ok := index[a.Platform]
+		if !ok {
+			continue
+		}
+		summary[i].Nodes++
+		if a.Quarantined {
+			summary[i].Quarantined++
+		}
+	}
+	return summary
+}
+
+// printPlatforms prints the node count of each platform and reports
+// platforms without nodes under the chassis path.
+func (s *Show) printPlatforms(summary []PlatformSummary) {
+	if len(summary) == 0 {
+		return
+	}
+	var missing []string
+	width := 0
+	for _, p := range summary {
+		width = max(width, len(p.Platform))
+		if p.Nodes == 0 {
+			missing = append(missing, p.Platform)
+		}
+	}
+	s.Term().Info().Printfln("Platforms (%d of %d with nodes)", len(summary)-len(missing), len(summary))
+	for _, p := range summary {
+		count := "-"
+		if p.Nodes > 0 {
+			count = fmt.Sprintf("%d", p.Nodes)
+		}
+		if p.Quarantined > 0 {
+			count += fmt.Sprintf(" (%d quarantined)", p.Quarantined)
+		}
+		s.Term().Printfln("  %-*s  %s", width, p.Platform, count)
+	}
+	if len(missing) > 0 {
+		s.Report(message.PlatformsUncovered, len(missing), strings.Join(missing, ", "))
+	}
+}
+
+// buildMatrix places every allocated node under each chassis path of paths it
+// is effectively allocated to, in the column of its platform.
+func buildMatrix(paths, platforms []string, allocations []AllocationInfo) *Matrix {
+	m := &Matrix{Platforms: platforms, Rows: make([]MatrixRow, 0, len(paths))}
+	index := make(map[string]int, len(paths))
+	for i, p := range paths {
+		index[p] = i
+		row := MatrixRow{Chassis: p, Nodes: make(map[string][]string, len(platforms))}
+		for _, platform := range platforms {
+			row.Nodes[platform] = []string{}
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	// Allocations are sorted by platform and node, so cells come out sorted
+	for _, a := range allocations {
+		if a.Quarantined {
+			m.Quarantined = append(m.Quarantined, a.Node+"@"+a.Platform)
+		}
+		for _, p := range a.Chassis {
+			if i, ok := index[p]; ok {
+				m.Rows[i].Nodes[a.Platform] = append(m.Rows[i].Nodes[a.Platform], a.Node)
+			}
+		}
+	}
+	return m
+}
+
+// printMatrix prints the matrix as a table with one column per platform.
+// Quarantined nodes are marked with an asterisk.
+func (s *Show) printMatrix(m *Matrix) {
+	quarantined := make(map[string]bool, len(m.Quarantined))
+	for _, q := range m.Quarantined {
+		quarantined[q] = true
+	}
+
+	header := append([]string{"CHASSIS"}, m.Platforms...)
+	table := [][]string{header}
+	for _, row := range m.Rows {
+		line := []string{row.Chassis}
+		for _, platform := range m.Platforms {
+			var names []string
+			for _, hostname := range row.Nodes[platform] {
+				if quarantined[hostname+"@"+platform] {
+					hostname += "*"
+				}
+				names = append(names, hostname)
+			}
+			cell := strings.Join(names, ",")
+			if cell == "" {
+				cell = "-"
+			}
+			line = append(line, cell)
+		}
+		table = append(table, line)
+	}
+
+	widths := make([]int, len(header))
+	for _, line := range table {
+		for i, cell := range line {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, line := range table {
+		var b strings.Builder
+		for i, cell := range line {
+			if i < len(line)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[i], cell)
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		s.Term().Printfln("  %s", b.String())
+	}
+	if len(m.Quarantined) > 0 {
+		s.Term().Printfln("  * quarantined")
+	}
+}
+
+// referencesPath reports whether an allocation entry names chassisPath or
+// one of its descendants.
+func referencesPath(entries []string, chassisPath string) bool {
+	for _, entry := range entries {
+		if pkgchassis.ReferencesPath(entry, chassisPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package show
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestShowGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	tests := []struct {
+		name   string
+		action *Show
+	}{
+		{"root", &Show{Chassis: "platform"}},
+		{"subtree", &Show{Chassis: "platform.foundation.cluster"}},
+		{"platform", &Show{Chassis: "platform", Platform: "prod"}},
+		{"attachments", &Show{Chassis: "platform.interaction", Kind: "attachments"}},
+		{"wide", &Show{Chassis: "platform.foundation", Format: "wide"}},
+		{"unknown", &Show{Chassis: "platform.unknown"}},
+		{"facts", &Show{Chassis: "platform.foundation.cluster", Facts: true, FactsSource: chassis.FactsConfig{
+			// Unknown nodes and empty facts are left out
+			Command: `echo '{"prod-1@prod": {"up": true, "ip": "10.0.0.1"}, "prod-2@prod": {}, "prod-9@prod": {"up": false}}'`,
+		}}},
+		{"facts-unavailable", &Show{Chassis: "platform.foundation.cluster", Facts: true, FactsSource: chassis.FactsConfig{
+			Command: "echo inventory unreachable >&2; exit 2",
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = dir
+			golden.Run(t, tt.name, dir, tt.action)
+		})
+	}
+}
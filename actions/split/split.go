@@ -0,0 +1,302 @@
+package split
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Reassignment kinds.
+const (
+	KindNode      = "node"
+	KindComponent = "component"
+)
+
+// Assignment lists the nodes and components moved to one new path.
+//
+//	eu:
+//	  nodes: [web1, web2@prod]
+//	  components: [interaction.applications.portal]
+type Assignment struct {
+	// Nodes are hostnames, optionally qualified as hostname@platform.
+	Nodes      []string `yaml:"nodes"`
+	Components []string `yaml:"components"`
+}
+
+// Reassignment is a node allocation or component attachment moved to a new path.
+type Reassignment struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	To   string `json:"to"`
+	File string `json:"file"`
+}
+
+// SplitResult is the structured result of chassis:split.
+type SplitResult struct {
+	Chassis    string         `json:"chassis"`
+	Into       []string       `json:"into"`
+	Created    []string       `json:"created"`
+	Reassigned []Reassignment `json:"reassigned"`
+	// Unmatched lists assigned nodes and components not directly allocated
+	// or attached to the split path.
+	Unmatched []string            `json:"unmatched,omitempty"`
+	Errors    []chassis.FileError `json:"errors,omitempty"`
+	DryRun    bool                `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// Split implements the chassis:split command
+type Split struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir     string
+	Chassis string
+	Into    []string // names of the new sibling paths
+	Assign  string   // mapping file of new path name -> Assignment
+	Limits  pkgchassis.Limits
+
+	result *SplitResult
+}
+
+// Result returns the structured result for JSON output.
+func (s *Split) Result() any {
+	return s.result
+}
+
+// Execute runs the split action
+func (s *Split) Execute() error {
+	if len(s.Into) == 0 {
+		return fmt.Errorf("--into requires at least one path name")
+	}
+	assignments, err := s.loadAssignments()
+	if err != nil {
+		return err
+	}
+
+	endPhase := s.Phase("load chassis")
+	c, err := chassis.Load(s.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if !c.Exists(s.Chassis) {
+		return fmt.Errorf("chassis %q not found in chassis.yaml", s.Chassis)
+	}
+
+	s.result = &SplitResult{
+		Chassis:    s.Chassis,
+		Created:    []string{},
+		Reassigned: []Reassignment{},
+		DryRun:     s.DryRun(),
+	}
+
+	// Create the siblings in order, right after the split path
+	prev := s.Chassis
+	paths := make(map[string]string, len(s.Into))
+	for _, name := range s.Into {
+		p := chassis.SiblingPath(s.Chassis, name)
+		paths[name] = p
+		s.result.Into = append(s.result.Into, p)
+		if !c.Exists(p) {
+			if err := pkgchassis.ValidatePath(p); err != nil {
+				return err
+			}
+			if err := s.Limits.CheckAdd(c.Chassis, p); err != nil {
+				return err
+			}
+			if err := c.AddNextTo(p, prev, false); err != nil {
+				return fmt.Errorf("failed to add chassis path: %w", err)
+			}
+			s.result.Created = append(s.result.Created, p)
+		}
+		prev = p
+	}
+	for _, name := range sortedKeys(assignments) {
+		if _, ok := paths[name]; !ok {
+			return fmt.Errorf("%s assigns to %q, which is not listed in --into", s.Assign, name)
+		}
+	}
+
+	endPhase = s.Phase("plan reassignments")
+	err = s.plan(assignments, paths)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	if s.DryRun() {
+		s.Report(message.DryRun)
+		for _, p := range s.result.Created {
+			s.Term().Printfln("  chassis.yaml: + %s", p)
+		}
+		s.printReassignments()
+		return nil
+	}
+
+	if len(s.result.Created) > 0 {
+		s.WarnAliasExpansion(c)
+		endPhase = s.Phase("save chassis")
+		err = c.Save(s.Dir)
+		endPhase()
+		if err != nil {
+			return err
+		}
+	}
+
+	endPhase = s.Phase("reassign")
+	s.apply()
+	endPhase()
+
+	s.Report(message.ChassisSplit, s.Chassis, strings.Join(s.result.Into, ", "))
+	s.printReassignments()
+	if len(s.result.Errors) > 0 {
+		s.Report(message.FilesNotUpdated, len(s.result.Errors))
+		cli.PrintFileErrors(s.Term(), s.result.Errors)
+		s.Degrade("split incomplete", fmt.Errorf("%d file(s) could not be updated", len(s.result.Errors)))
+	}
+	return nil
+}
+
+// loadAssignments reads the mapping file, if any.
+func (s *Split) loadAssignments() (map[string]Assignment, error) {
+	if s.Assign == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.Assign)
+	if err != nil {
+		return nil, err
+	}
+	var assignments map[string]Assignment
+	if err := yaml.Unmarshal(data, &assignments); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.Assign, err)
+	}
+	return assignments, nil
+}
+
+// plan lists the node allocations and component attachments to move.
+// Only direct allocations to the split path are moved; nodes allocated
+// through expressions or descendants must be reassigned by hand.
+func (s *Split) plan(assignments map[string]Assignment, paths map[string]string) error {
+	names := sortedKeys(assignments)
+
+	nodes, err := chassis.LoadNodes(s.Dir, "")
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		s.Log().Debug("Failed to load some node files", "error", skipped)
+		s.Degrade("failed to load some node files", skipped)
+	}
+	attachments, err := chassis.LoadAttachments(s.Dir, s.Chassis)
+	if err != nil {
+		s.Log().Debug("Failed to load some attachments", "error", err)
+		s.Degrade("failed to load some attachments", err)
+	}
+
+	for _, name := range names {
+		to := paths[name]
+		for _, host := range assignments[name].Nodes {
+			matched := false
+			for _, n := range nodes {
+				if host != n.Hostname && host != n.Hostname+"@"+n.Platform {
+					continue
+				}
+				if slices.Contains(n.Chassis, s.Chassis) {
+					s.result.Reassigned = append(s.result.Reassigned, Reassignment{KindNode, n.Hostname + "@" + n.Platform, to, n.File})
+					matched = true
+				}
+			}
+			if !matched {
+				s.result.Unmatched = append(s.result.Unmatched, host)
+			}
+		}
+		for _, component := range assignments[name].Components {
+			matched := false
+			for _, a := range attachments {
+				if a.Component == component && a.Chassis == s.Chassis && !slices.Contains(s.reassignedFiles(KindComponent, component), a.Playbook) {
+					s.result.Reassigned = append(s.result.Reassigned, Reassignment{KindComponent, component, to, a.Playbook})
+					matched = true
+				}
+			}
+			if !matched {
+				s.result.Unmatched = append(s.result.Unmatched, component)
+			}
+		}
+	}
+	return nil
+}
+
+// reassignedFiles returns the files already planned for a node or component.
+func (s *Split) reassignedFiles(kind, name string) []string {
+	var files []string
+	for _, r := range s.result.Reassigned {
+		if r.Kind == kind && r.Name == name {
+			files = append(files, r.File)
+		}
+	}
+	return files
+}
+
+// apply rewrites node files and playbooks, one playbook write per file.
+func (s *Split) apply() {
+	roles := make(map[string]map[string]string) // playbook -> component -> new path
+	var playbooks []string
+	for _, r := range s.result.Reassigned {
+		if r.Kind == KindNode {
+			if _, err := chassis.ReplaceAllocation(r.File, s.Chassis, r.To); err != nil {
+				s.result.Errors = append(s.result.Errors, chassis.FileErrors(err)...)
+			}
+			continue
+		}
+		if roles[r.File] == nil {
+			roles[r.File] = make(map[string]string)
+			playbooks = append(playbooks, r.File)
+		}
+		roles[r.File][r.Name] = r.To
+	}
+	for _, playbook := range playbooks {
+		if _, err := chassis.MoveRoles(playbook, s.Chassis, roles[playbook]); err != nil {
+			s.result.Errors = append(s.result.Errors, chassis.FileErrors(err)...)
+		}
+	}
+}
+
+// printReassignments lists moved nodes and components, and assignments matching nothing.
+func (s *Split) printReassignments() {
+	for _, r := range s.result.Reassigned {
+		s.Term().Printfln("  - %s %s -> %s (%s)", r.Kind, r.Name, r.To, r.File)
+	}
+	if len(s.result.Unmatched) > 0 {
+		s.Report(message.SplitUnmatched, len(s.result.Unmatched), s.Chassis)
+		for _, name := range s.result.Unmatched {
+			s.Term().Printfln("  - %s", name)
+		}
+	}
+}
+
+// sortedKeys returns the names of assignments, sorted.
+func sortedKeys(assignments map[string]Assignment) []string {
+	names := make([]string, 0, len(assignments))
+	for name := range assignments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,36 @@
+package split
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestSplitGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Split
+		dryRun bool
+	}{
+		{"into", &Split{Chassis: "platform.foundation.storage", Into: []string{"cache", "queue"}}, false},
+		{"assign", &Split{Chassis: "platform.foundation.storage.kv", Into: []string{"cache"}, Assign: "assign.yaml"}, false},
+		{"dry-run", &Split{Chassis: "platform.foundation.storage.kv", Into: []string{"cache"}, Assign: "assign.yaml"}, true},
+		{"no-into", &Split{Chassis: "platform.foundation.storage.kv"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "assign.yaml", "cache:\n  nodes: [prod-2@prod]\n  components: [foundation.storage.redis]\n")
+			tt.action.Dir = dir
+			if tt.action.Assign != "" {
+				tt.action.Assign = filepath.Join(dir, tt.action.Assign)
+			}
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+			golden.CompareFile(t, tt.name+".prod-2.yaml", dir, "inst/prod/nodes/prod-2.yaml")
+			golden.CompareFile(t, tt.name+".foundation.yaml", dir, "src/foundation/foundation.yaml")
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package templateupgrade
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// InstancePlan is the upgrade plan of a single template instance.
+type InstancePlan struct {
+	Chassis string `json:"chassis"`
+	chassis.TemplatePlan
+}
+
+// TemplateUpgradeResult is the structured result of chassis:template-upgrade.
+type TemplateUpgradeResult struct {
+	Template  string         `json:"template"`
+	DryRun    bool           `json:"dry_run,omitempty"`
+	Instances []InstancePlan `json:"instances"`
+
+	message.Log
+}
+
+// TemplateUpgrade implements the chassis:template-upgrade command
+type TemplateUpgrade struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Template string
+	Chassis  string // restrict to a single instance
+	Config   chassis.Config
+
+	result *TemplateUpgradeResult
+}
+
+// Result returns the structured result for JSON output.
+func (t *TemplateUpgrade) Result() any {
+	return t.result
+}
+
+// Execute runs the template-upgrade action
+func (t *TemplateUpgrade) Execute() error {
+	tpl, err := t.Config.Template(t.Template)
+	if err != nil {
+		return err
+	}
+
+	endPhase := t.Phase("load chassis")
+	c, err := chassis.Load(t.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	meta, err := pkgchassis.LoadMeta(t.Dir)
+	if err != nil {
+		return err
+	}
+
+	t.result = &TemplateUpgradeResult{
+		Template:  t.Template,
+		DryRun:    t.DryRun(),
+		Instances: []InstancePlan{},
+	}
+
+	// Plan every instance of the template
+	rendered := make(map[string][]string)
+	for _, instance := range meta.Paths() {
+		a := meta[instance]
+		if a.Template != t.Template || (t.Chassis != "" && instance != t.Chassis) {
+			continue
+		}
+		if !c.Exists(instance) {
+			t.Report(message.TemplateInstanceMissing, instance, pkgchassis.MetaFile)
+			continue
+		}
+
+		current, err := tpl.Render(instance, a.TemplateParams)
+		if err != nil {
+			return fmt.Errorf("instance %s: %w", instance, err)
+		}
+		rendered[instance] = current
+
+		plan := chassis.PlanTemplateUpgrade(a.TemplatePaths, current, func(rel string) bool {
+			return c.Exists(instance + "." + rel)
+		})
+		t.result.Instances = append(t.result.Instances, InstancePlan{Chassis: instance, TemplatePlan: plan})
+	}
+
+	if len(t.result.Instances) == 0 {
+		if t.Chassis != "" {
+			return fmt.Errorf("chassis %q is not an instance of template %q", t.Chassis, t.Template)
+		}
+		t.Report(message.TemplateNoInstances, t.Template)
+		return nil
+	}
+
+	if t.DryRun() {
+		t.Report(message.DryRun)
+	}
+	for _, inst := range t.result.Instances {
+		t.printPlan(inst)
+	}
+	if t.DryRun() {
+		return nil
+	}
+
+	for _, inst := range t.result.Instances {
+		if err := t.apply(c, meta, inst, rendered[inst.Chassis]); err != nil {
+			return fmt.Errorf("instance %s: %w", inst.Chassis, err)
+		}
+	}
+
+	t.WarnAliasExpansion(c)
+	endPhase = t.Phase("save chassis")
+	err = c.Save(t.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+	if err := chassis.SaveMeta(t.Dir, meta); err != nil {
+		return err
+	}
+
+	t.Report(message.TemplateUpgraded, len(t.result.Instances), t.Template)
+	return nil
+}
+
+// apply performs an instance plan in memory and rewrites references on disk.
+func (t *TemplateUpgrade) apply(c *chassis.Chassis, meta pkgchassis.Meta, inst InstancePlan, rendered []string) error {
+	for _, r := range inst.Rename {
+		oldPath := inst.Chassis + "." + r.Old
+		newPath := inst.Chassis + "." + r.New
+		if err := c.Rename(oldPath, newPath); err != nil {
+			return err
+		}
+		if _, err := chassis.UpdateAttachments(t.Dir, oldPath, newPath); err != nil {
+			t.Report(message.AttachmentsFailed, oldPath)
+			cli.PrintFileErrors(t.Term(), chassis.FileErrors(err))
+			t.Degrade("failed to update attachments for "+oldPath, err)
+		}
+		if _, err := chassis.UpdateAllocations(t.Dir, oldPath, newPath); err != nil {
+			t.Report(message.AllocationsFailed, oldPath)
+			cli.PrintFileErrors(t.Term(), chassis.FileErrors(err))
+			t.Degrade("failed to update allocations for "+oldPath, err)
+		}
+		chassis.RenameMeta(meta, oldPath, newPath)
+	}
+
+	for _, rel := range inst.Add {
+		p := inst.Chassis + "." + rel
+		if err := t.Config.Limits.CheckAdd(c.Chassis, p); err != nil {
+			return err
+		}
+		if err := c.Add(p); err != nil {
+			return err
+		}
+	}
+
+	a := meta[inst.Chassis]
+	a.TemplatePaths = rendered
+	meta[inst.Chassis] = a
+	return nil
+}
+
+// printPlan prints the plan of a single instance.
+func (t *TemplateUpgrade) printPlan(inst InstancePlan) {
+	if inst.IsEmpty() {
+		t.Term().Printfln("%s: up to date", inst.Chassis)
+		return
+	}
+	t.Term().Info().Printfln("%s", inst.Chassis)
+	for _, r := range inst.Rename {
+		t.Term().Printfln("  ~ %s -> %s", r.Old, r.New)
+	}
+	for _, p := range inst.Add {
+		t.Term().Printfln("  + %s", p)
+	}
+	for _, p := range inst.Obsolete {
+		t.Term().Printfln("  ! %s (no longer in template, left in place)", p)
+	}
+}
@@ -0,0 +1,44 @@
+package templateupgrade
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/actions/instantiate"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestTemplateUpgradeGolden(t *testing.T) {
+	v1 := chassis.Config{Templates: map[string][]interface{}{
+		"service": {"web", "db"},
+	}}
+	v2 := chassis.Config{Templates: map[string][]interface{}{
+		"service": {"web", "db", "cache"},
+		"worker":  {"queue"},
+	}}
+	tests := []struct {
+		name   string
+		action *TemplateUpgrade
+		dryRun bool
+	}{
+		{"upgrade", &TemplateUpgrade{Template: "service"}, false},
+		{"dry-run", &TemplateUpgrade{Template: "service"}, true},
+		{"instance", &TemplateUpgrade{Template: "service", Chassis: "platform.interaction.shop"}, false},
+		{"no-instances", &TemplateUpgrade{Template: "worker"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			for _, instance := range []string{"platform.interaction.shop", "platform.cognition.search"} {
+				if err := (&instantiate.Instantiate{Dir: dir, Template: "service", Chassis: instance, Config: v1}).Execute(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tt.action.Dir = dir
+			tt.action.Config = v2
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+			golden.CompareFile(t, tt.name+".chassis.yaml", dir, "chassis.yaml")
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+)
+
+// ValidateResult is the structured result of chassis:validate.
+type ValidateResult struct {
+	Findings []validate.Finding `json:"findings"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+
+	message.Log
+}
+
+// Validate implements the chassis:validate command
+type Validate struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir    string
+	Rules  []string // rule names to check, all if empty
+	Config chassis.Config
+
+	result *ValidateResult
+}
+
+// Result returns the structured result for JSON output.
+func (v *Validate) Result() any {
+	return v.result
+}
+
+// Execute runs the validate action
+func (v *Validate) Execute() error {
+	endPhase := v.Phase("load repository")
+	ctx, err := validate.Load(v.Dir, v.Config)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = v.Phase("check rules")
+	findings, err := validate.Run(ctx, v.Rules...)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	errs, warnings := validate.Count(findings)
+	v.result = &ValidateResult{
+		Findings: findings,
+		Errors:   errs,
+		Warnings: warnings,
+	}
+
+	if len(findings) == 0 {
+		v.Report(message.ChassisValid)
+		return nil
+	}
+
+	for _, f := range findings {
+		subject := f.Chassis
+		if f.Node != "" {
+			subject = f.Node
+		}
+		line := fmt.Sprintf("[%s] %s: %s", f.Rule, subject, f.Message)
+		if f.File != "" {
+			line += " (" + f.File + ")"
+		}
+		if f.Severity == validate.SeverityError {
+			v.Term().Error().Println(line)
+		} else {
+			v.Term().Warning().Println(line)
+		}
+	}
+
+	if errs > 0 || (v.Strict() && warnings > 0) {
+		return fmt.Errorf("validation failed: %d error(s), %d warning(s)", errs, warnings)
+	}
+	v.Report(message.ValidateWarnings, warnings)
+	return nil
+}
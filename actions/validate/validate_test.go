@@ -0,0 +1,51 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestValidateGolden(t *testing.T) {
+	clean := golden.Repo(t)
+	broken := golden.Repo(t)
+	golden.WriteFile(t, broken, "inst/prod/nodes/prod-5.yaml", `hostname: prod-5
+chassis:
+  - platform.foundation.clustr
+  - platform.Interaction.management
+`)
+	golden.WriteFile(t, broken, "inst/prod/nodes/prod-6.yaml", "hostname: prod-6\n")
+	golden.WriteFile(t, broken, "inst/dev/nodes/dev-3.yaml", "hostname: [dev-3\n")
+
+	tests := []struct {
+		name   string
+		dir    string
+		action *Validate
+	}{
+		{"clean", clean, &Validate{}},
+		{"findings", broken, &Validate{}},
+		{"rule", broken, &Validate{Rules: []string{"node-unallocated"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action.Dir = tt.dir
+			golden.Run(t, tt.name, tt.dir, tt.action)
+		})
+	}
+}
+
+func TestValidateLayoutGolden(t *testing.T) {
+	layout := chassis.CurrentLayout()
+	defer chassis.SetLayout(layout)
+	custom := chassis.Layout{Nodes: "envs/<platform>/a/b"}
+	chassis.SetLayout(custom)
+
+	dir := golden.Repo(t)
+	golden.WriteFile(t, dir, "envs/dev/a/b/dev-3.yaml", "hostname: [dev-3\n")
+	golden.Run(t, "layout", dir, &Validate{
+		Dir:    dir,
+		Rules:  []string{"node-unreadable"},
+		Config: chassis.Config{Layout: custom},
+	})
+}
@@ -0,0 +1,144 @@
+package verifynodes
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+	"github.com/plasmash/plasmactl-chassis/internal/validate"
+)
+
+// UnallocatedNode is a node without any chassis allocation.
+type UnallocatedNode struct {
+	Hostname string `json:"hostname"`
+	Platform string `json:"platform"`
+	File     string `json:"file"`
+	Missing  bool   `json:"missing"` // chassis key absent rather than empty
+}
+
+// VerifyNodesResult is the structured result of chassis:verify-nodes.
+type VerifyNodesResult struct {
+	Unallocated []UnallocatedNode `json:"unallocated"`
+	Fixed       []string          `json:"fixed,omitempty"`
+	Default     string            `json:"default,omitempty"`
+	DryRun      bool              `json:"dry_run,omitempty"`
+
+	message.Log
+}
+
+// VerifyNodes implements the chassis:verify-nodes command
+type VerifyNodes struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithDryRun
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Platform string
+	Fix      bool
+	Default  string // chassis path assigned by --fix
+
+	result *VerifyNodesResult
+}
+
+// Result returns the structured result for JSON output.
+func (v *VerifyNodes) Result() any {
+	return v.result
+}
+
+// Mutates reports whether the action writes node files, with --fix.
+func (v *VerifyNodes) Mutates() bool {
+	return v.Fix && !v.DryRun()
+}
+
+// Execute runs the verify-nodes action
+func (v *VerifyNodes) Execute() error {
+	if v.Fix && v.Default == "" {
+		return fmt.Errorf("--fix requires --default <chassis path>")
+	}
+
+	if v.Fix {
+		endPhase := v.Phase("load chassis")
+		c, err := chassis.Load(v.Dir)
+		endPhase()
+		if err != nil {
+			return err
+		}
+		if !c.Exists(v.Default) {
+			return fmt.Errorf("default chassis %q not found", v.Default)
+		}
+	}
+
+	endPhase := v.Phase("load nodes")
+	nodes, err := chassis.LoadNodes(v.Dir, v.Platform)
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		v.Log().Debug("Failed to load some node files", "error", skipped)
+		v.Degrade("failed to load some node files", skipped)
+	}
+
+	v.result = &VerifyNodesResult{Unallocated: []UnallocatedNode{}}
+	for _, n := range validate.Unallocated(nodes) {
+		v.result.Unallocated = append(v.result.Unallocated, UnallocatedNode{
+			Hostname: n.Hostname,
+			Platform: n.Platform,
+			File:     n.File,
+			Missing:  !n.ChassisDeclared,
+		})
+	}
+
+	if len(v.result.Unallocated) == 0 {
+		v.Report(message.NodesAllocated, len(nodes))
+		return nil
+	}
+
+	if !v.Fix {
+		v.Report(message.NodesUnallocated, len(v.result.Unallocated))
+		for _, n := range v.result.Unallocated {
+			v.Term().Printfln("  %s%s", chassis.NodeName(n.Hostname, n.Platform), missingNote(n))
+		}
+		return fmt.Errorf("%d node(s) receive no components; use --fix --default <chassis> to assign one", len(v.result.Unallocated))
+	}
+
+	v.result.Default = v.Default
+	v.result.DryRun = v.DryRun()
+
+	if v.DryRun() {
+		v.Report(message.DryRun)
+		for _, n := range v.result.Unallocated {
+			v.Term().Printfln("  %s: + %s", n.File, v.Default)
+		}
+		return nil
+	}
+
+	endPhase = v.Phase("update nodes")
+	defer endPhase()
+	for _, n := range v.result.Unallocated {
+		if _, err := chassis.AddAllocation(n.File, v.Default); err != nil {
+			return fmt.Errorf("failed to allocate %s@%s: %w", n.Hostname, n.Platform, err)
+		}
+		v.result.Fixed = append(v.result.Fixed, n.File)
+	}
+
+	v.Report(message.NodesFixed, len(v.result.Fixed), v.Default)
+	for _, n := range v.result.Unallocated {
+		v.Term().Printfln("  %s", chassis.NodeName(n.Hostname, n.Platform))
+	}
+	return nil
+}
+
+// missingNote explains why a node counts as unallocated.
+func missingNote(n UnallocatedNode) string {
+	if n.Missing {
+		return " (no chassis key)"
+	}
+	return " (empty chassis list)"
+}
@@ -0,0 +1,32 @@
+package verifynodes
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestVerifyNodesGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *VerifyNodes
+		dryRun bool
+	}{
+		{"report", &VerifyNodes{}, false},
+		{"platform", &VerifyNodes{Platform: "dev"}, false},
+		{"fix-dry-run", &VerifyNodes{Fix: true, Default: "platform.foundation.cluster.nodes"}, true},
+		{"fix", &VerifyNodes{Fix: true, Default: "platform.foundation.cluster.nodes"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			golden.WriteFile(t, dir, "inst/prod/nodes/prod-5.yaml", "hostname: prod-5\n")
+			golden.WriteFile(t, dir, "inst/prod/nodes/prod-6.yaml", "hostname: prod-6\nchassis: []\n")
+			tt.action.Dir = dir
+			tt.action.SetDryRun(tt.dryRun)
+			golden.Run(t, tt.name, dir, tt.action)
+
+			golden.CompareFile(t, tt.name+".prod-5.yaml", dir, "inst/prod/nodes/prod-5.yaml")
+		})
+	}
+}
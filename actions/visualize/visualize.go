@@ -0,0 +1,114 @@
+package visualize
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// DefaultTitle is the report title unless --title is set.
+const DefaultTitle = "Chassis"
+
+// VisualizeResult is the structured result of chassis:visualize.
+type VisualizeResult struct {
+	Output     string `json:"output,omitempty"`
+	Bytes      int    `json:"bytes"`
+	Paths      int    `json:"paths"`
+	Nodes      int    `json:"nodes"`
+	Components int    `json:"components"`
+
+	message.Log
+}
+
+// Visualize implements the chassis:visualize command
+type Visualize struct {
+	action.WithLogger
+	action.WithTerm
+	cli.WithTrace
+	cli.WithStrict
+	cli.WithMessages
+
+	Dir      string
+	Output   string // file to write, stdout if empty
+	Platform string
+	Title    string
+	Config   chassis.Config
+
+	result *VisualizeResult
+}
+
+// Result returns the structured result for JSON output.
+func (v *Visualize) Result() any {
+	return v.result
+}
+
+// Execute runs the visualize action
+func (v *Visualize) Execute() error {
+	endPhase := v.Phase("load chassis")
+	c, err := chassis.Load(v.Dir)
+	endPhase()
+	if err != nil {
+		return err
+	}
+
+	endPhase = v.Phase("load nodes")
+	nodes, err := chassis.LoadNodes(v.Dir, v.Platform)
+	endPhase()
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return err
+	}
+	if skipped != nil {
+		v.Log().Debug("Failed to load some node files", "error", skipped)
+		v.Degrade("failed to load some node files", skipped)
+	}
+
+	endPhase = v.Phase("load attachments")
+	attachments, err := chassis.LoadAttachments(v.Dir, "")
+	endPhase()
+	if err != nil {
+		v.Log().Debug("Failed to load attachments", "error", err)
+		v.Degrade("failed to load attachments", err)
+	}
+
+	title := v.Title
+	if title == "" {
+		title = DefaultTitle
+	}
+	endPhase = v.Phase("render html")
+	report, err := export.BuildReport(c, nodes, attachments, export.ReportOptions{
+		Dir:          v.Dir,
+		Title:        title,
+		Distribution: v.Config.Distribution,
+	})
+	var data []byte
+	if err == nil {
+		data, err = report.Render()
+	}
+	endPhase()
+	if err != nil {
+		return err
+	}
+	v.result = &VisualizeResult{
+		Output:     v.Output,
+		Bytes:      len(data),
+		Paths:      len(c.Flatten()),
+		Nodes:      len(report.Nodes),
+		Components: len(report.Components),
+	}
+
+	if v.Output == "" {
+		v.Term().Printf("%s", data)
+		return nil
+	}
+	if err := os.WriteFile(v.Output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", v.Output, err)
+	}
+	v.Report(message.Exported, "HTML report", v.Output)
+	return nil
+}
@@ -0,0 +1,12 @@
+package visualize
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+func TestVisualizeGolden(t *testing.T) {
+	dir := golden.Repo(t)
+	golden.Run(t, "report", dir, &Visualize{Dir: dir, Platform: "prod", Title: "Fixture"})
+}
@@ -0,0 +1,81 @@
+package chassis
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Reference is a chassis path referenced by playbooks or node files.
+type Reference struct {
+	Chassis string   `json:"chassis"`
+	Files   []string `json:"files"` // relative to the repository, sorted
+}
+
+// New returns an empty chassis, for repositories without chassis.yaml yet.
+func New() *Chassis {
+	return &Chassis{Chassis: &pkgchassis.Chassis{}}
+}
+
+// UndeclaredReferences returns the chassis paths targeted by the hosts of
+// playbook plays or allocated by node files, but missing from c, sorted.
+// Hosts that aren't dotted chassis paths, such as all or Ansible patterns,
+// and allocation expressions are ignored. Playbooks and node files that
+// can't be parsed are skipped.
+func UndeclaredReferences(dir string, c *Chassis) ([]Reference, error) {
+	files := make(map[string]map[string]bool)
+	add := func(chassisPath, file string) {
+		if !strings.Contains(chassisPath, ".") || pkgchassis.ValidatePath(chassisPath) != nil || c.Exists(chassisPath) {
+			return
+		}
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+		if files[chassisPath] == nil {
+			files[chassisPath] = make(map[string]bool)
+		}
+		files[chassisPath][file] = true
+	}
+
+	playbooks, err := PlaybookFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := LoadIndex(dir)
+	for _, playbook := range playbooks {
+		plays, err := parsePlaybook(dir, idx, playbook)
+		if err != nil {
+			tracer.File(playbook, TraceSkip, err.Error())
+			continue
+		}
+		for _, play := range plays {
+			add(strings.TrimSpace(play.Hosts), playbook)
+		}
+	}
+
+	err = ForEachNode(dir, "", func(n Node) error {
+		for _, entry := range n.Chassis {
+			if !pkgchassis.IsExpression(entry) {
+				add(entry, n.File)
+			}
+		}
+		return nil
+	})
+	if _, err = SplitNodeFileErrors(err); err != nil {
+		return nil, err
+	}
+
+	refs := make([]Reference, 0, len(files))
+	for chassisPath, set := range files {
+		ref := Reference{Chassis: chassisPath}
+		for file := range set {
+			ref.Files = append(ref.Files, file)
+		}
+		sort.Strings(ref.Files)
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Chassis < refs[j].Chassis })
+	return refs, nil
+}
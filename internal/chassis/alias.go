@@ -0,0 +1,51 @@
+package chassis
+
+import "gopkg.in/yaml.v3"
+
+// AliasesExpanded reports whether a write operation expanded YAML aliases
+// of chassis.yaml, so saving writes the anchored content out in place.
+func (c *Chassis) AliasesExpanded() bool {
+	return c.aliasesExpanded
+}
+
+// expandAliases replaces every alias with a copy of its anchored content
+// before a write operation, so that changing a path reached through an alias
+// doesn't silently change every other place sharing the anchor.
+func (c *Chassis) expandAliases() {
+	if !c.HasAliases() {
+		return
+	}
+	expandNode(c.YAMLNode())
+	c.aliasesExpanded = true
+}
+
+// expandNode replaces alias children of node with copies and drops anchors.
+func expandNode(node *yaml.Node) {
+	node.Anchor = ""
+	for i, child := range node.Content {
+		if child.Kind == yaml.AliasNode {
+			node.Content[i] = copyNode(child.Alias)
+		}
+		expandNode(node.Content[i])
+	}
+}
+
+// copyNode returns a deep copy of node with aliases resolved.
+func copyNode(node *yaml.Node) *yaml.Node {
+	node = resolveAlias(node)
+	cp := *node
+	cp.Anchor = ""
+	cp.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		cp.Content[i] = copyNode(child)
+	}
+	return &cp
+}
+
+// resolveAlias returns the node an alias refers to, or node itself.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,107 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// anchoredChassis shares the cluster section with the interaction layer.
+const anchoredChassis = `platform:
+  foundation:
+    - cluster: &cluster
+      - control
+      - nodes
+  interaction:
+    - cluster: *cluster
+    - observability
+`
+
+// loadChassis writes chassis.yaml to a temporary directory and loads it.
+func loadChassis(t *testing.T, content string) (*Chassis, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chassis.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, dir
+}
+
+func TestAddIntoAnchoredSection(t *testing.T) {
+	c, _ := loadChassis(t, anchoredChassis)
+	if err := c.Add("platform.foundation.cluster.etcd"); err != nil {
+		t.Fatal(err)
+	}
+	if !c.AliasesExpanded() {
+		t.Error("AliasesExpanded() = false, want true")
+	}
+
+	// The alias was expanded first, so its copy doesn't get the new path
+	want := []string{
+		"platform",
+		"platform.foundation",
+		"platform.foundation.cluster",
+		"platform.foundation.cluster.control",
+		"platform.foundation.cluster.nodes",
+		"platform.foundation.cluster.etcd",
+		"platform.interaction",
+		"platform.interaction.cluster",
+		"platform.interaction.cluster.control",
+		"platform.interaction.cluster.nodes",
+		"platform.interaction.observability",
+	}
+	if got := c.Flatten(); !slices.Equal(got, want) {
+		t.Errorf("Flatten() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestSaveExpandsAliases(t *testing.T) {
+	c, dir := loadChassis(t, anchoredChassis)
+	if err := c.Add("platform.interaction.cluster.etcd"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "chassis.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `platform:
+    foundation:
+        - cluster:
+            - control
+            - nodes
+    interaction:
+        - cluster:
+            - control
+            - nodes
+            - etcd
+        - observability
+`
+	if string(data) != want {
+		t.Errorf("saved chassis.yaml =\n%s\nwant\n%s", data, want)
+	}
+
+	saved, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.HasAliases() {
+		t.Error("saved chassis.yaml still uses aliases")
+	}
+}
+
+func TestReadOnlyKeepsAliases(t *testing.T) {
+	c, _ := loadChassis(t, anchoredChassis)
+	c.Flatten()
+	if c.AliasesExpanded() || !c.HasAliases() {
+		t.Error("reading chassis.yaml expanded its aliases")
+	}
+}
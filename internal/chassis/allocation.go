@@ -0,0 +1,344 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// NodeFile returns the path of a node file: inst/<platform>/nodes/<hostname>.yaml
+// in the default layout, or with another accepted extension if only that file
+// exists.
+func NodeFile(dir, platform, hostname string) string {
+	return pkgchassis.FindFile(filepath.Join(dir, layout.PlatformNodesDir(platform), hostname), layout.FileExtensions())
+}
+
+// AddAllocation appends a chassis path to the chassis list of a node file,
+// creating the list if needed and preserving the rest of the document.
+// It returns false if the node is already allocated to the path. Entries
+// are compared without surrounding whitespace here and in [RemoveAllocation]
+// and [ReplaceAllocation], as quoted entries may carry it.
+func AddAllocation(nodeFile, chassisPath string) (bool, error) {
+	doc, err := readNodeDocument(nodeFile)
+	if err != nil {
+		return false, err
+	}
+
+	root := doc.Content[0]
+	seq := ownMappingValue(root, "chassis")
+	if seq == nil {
+		seq = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "chassis"}, seq)
+	}
+	if seq.Kind != yaml.SequenceNode {
+		// Null or scalar value, replace with a list
+		seq.Kind = yaml.SequenceNode
+		seq.Tag = ""
+		seq.Value = ""
+		seq.Content = nil
+	}
+
+	for _, item := range seq.Content {
+		if item.Kind == yaml.ScalarNode && strings.TrimSpace(item.Value) == chassisPath {
+			return false, nil
+		}
+	}
+	if len(seq.Content) == 0 {
+		// Render a previously empty "[]" list in block style
+		seq.Style = 0
+	}
+	seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: chassisPath})
+
+	if err := writeNodeDocument(nodeFile, doc); err != nil {
+		return false, err
+	}
+	tracer.File(nodeFile, TraceWrite, "")
+	return true, nil
+}
+
+// RemoveAllocation removes the chassis list entry chassisPath from a node
+// file, preserving the rest of the document. It returns false if the node
+// isn't directly allocated to the path.
+func RemoveAllocation(nodeFile, chassisPath string) (bool, error) {
+	doc, err := readNodeDocument(nodeFile)
+	if err != nil {
+		return false, err
+	}
+
+	seq := ownMappingValue(doc.Content[0], "chassis")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return false, nil
+	}
+	kept := seq.Content[:0:0]
+	for _, item := range seq.Content {
+		if item.Kind == yaml.ScalarNode && strings.TrimSpace(item.Value) == chassisPath {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) == len(seq.Content) {
+		return false, nil
+	}
+	seq.Content = kept
+
+	if err := writeNodeDocument(nodeFile, doc); err != nil {
+		return false, err
+	}
+	tracer.File(nodeFile, TraceWrite, "")
+	return true, nil
+}
+
+// FindNode returns the node named hostname or hostname@platform. A bare
+// hostname defined under several platforms is ambiguous. Node files that
+// can't be loaded are ignored.
+func FindNode(dir, name string) (Node, error) {
+	hostname, platform, _ := strings.Cut(name, "@")
+	var found []Node
+	err := ForEachNode(dir, platform, func(n Node) error {
+		if n.Hostname == hostname {
+			found = append(found, n)
+		}
+		return nil
+	})
+	if _, err = SplitNodeFileErrors(err); err != nil {
+		return Node{}, err
+	}
+	switch len(found) {
+	case 0:
+		return Node{}, fmt.Errorf("node %q not found in %s", name, layout.NodesDir())
+	case 1:
+		return found[0], nil
+	}
+	platforms := make([]string, 0, len(found))
+	for _, n := range found {
+		platforms = append(platforms, n.Platform)
+	}
+	return Node{}, fmt.Errorf("node %q is defined under several platforms (%s): use %s@<platform>",
+		name, strings.Join(platforms, ", "), hostname)
+}
+
+// ReplaceAllocation replaces the chassis list entry oldPath of a node file
+// with newPath, dropping it instead if the node is already allocated to
+// newPath. It returns false if the node isn't directly allocated to oldPath.
+func ReplaceAllocation(nodeFile, oldPath, newPath string) (bool, error) {
+	doc, err := readNodeDocument(nodeFile)
+	if err != nil {
+		return false, err
+	}
+
+	seq := ownMappingValue(doc.Content[0], "chassis")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return false, nil
+	}
+	found, present := -1, false
+	for i, item := range seq.Content {
+		if item.Kind != yaml.ScalarNode {
+			continue
+		}
+		switch strings.TrimSpace(item.Value) {
+		case oldPath:
+			found = i
+		case newPath:
+			present = true
+		}
+	}
+	if found < 0 {
+		return false, nil
+	}
+	if present {
+		seq.Content = append(seq.Content[:found], seq.Content[found+1:]...)
+	} else {
+		seq.Content[found].Value = newPath
+	}
+
+	if err := writeNodeDocument(nodeFile, doc); err != nil {
+		return false, err
+	}
+	tracer.File(nodeFile, TraceWrite, "")
+	return true, nil
+}
+
+// SetAllocations replaces the chassis list of a node file, creating the file
+// with a hostname field if it doesn't exist. It returns false if the file
+// already declares exactly these paths.
+func SetAllocations(nodeFile, hostname string, paths []string) (bool, error) {
+	doc, err := readNodeDocument(nodeFile)
+	if os.IsNotExist(err) {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+		doc.Content[0].Content = append(doc.Content[0].Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "hostname"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: hostname})
+		if err := os.MkdirAll(filepath.Dir(nodeFile), 0755); err != nil {
+			return false, checkPermission("create", filepath.Dir(nodeFile), err)
+		}
+	} else if err != nil {
+		return false, err
+	}
+
+	root := doc.Content[0]
+	seq := ownMappingValue(root, "chassis")
+	if seq == nil {
+		seq = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "chassis"}, seq)
+	} else if seq.Kind == yaml.SequenceNode && sameScalars(seq, paths) {
+		return false, nil
+	}
+
+	seq.Kind = yaml.SequenceNode
+	seq.Tag = ""
+	seq.Value = ""
+	seq.Style = 0
+	seq.Content = nil
+	for _, p := range paths {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p})
+	}
+
+	if err := writeNodeDocument(nodeFile, doc); err != nil {
+		return false, err
+	}
+	tracer.File(nodeFile, TraceWrite, "")
+	return true, nil
+}
+
+// EditAllocations returns the content of a node file before and after
+// replacing the entries of its chassis list with the result of edit, without
+// writing it. After is nil if the file has no chassis list or edit keeps it.
+func EditAllocations(nodeFile string, edit func(entries []string) []string) (before, after []byte, err error) {
+	before, err = os.ReadFile(nodeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := readNodeDocument(nodeFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	seq := mappingValue(doc.Content[0], "chassis")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return before, nil, nil
+	}
+	var entries []string
+	for _, item := range seq.Content {
+		entries = append(entries, item.Value)
+	}
+	edited := edit(slices.Clone(entries))
+	if slices.Equal(edited, entries) {
+		return before, nil, nil
+	}
+
+	seq = ownMappingValue(doc.Content[0], "chassis")
+	seq.Content = nil
+	for _, e := range edited {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: e})
+	}
+	after, err = marshalDocument(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal %s: %w", nodeFile, err)
+	}
+	return before, after, nil
+}
+
+// sameScalars reports whether a sequence node holds exactly values, in order.
+func sameScalars(seq *yaml.Node, values []string) bool {
+	if len(seq.Content) != len(values) {
+		return false
+	}
+	for i, item := range seq.Content {
+		if item.Kind != yaml.ScalarNode || item.Value != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readNodeDocument parses a node file into a YAML document with a mapping root.
+func readNodeDocument(nodeFile string) (*yaml.Node, error) {
+	data, err := os.ReadFile(nodeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", nodeFile, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: node file is not a mapping", nodeFile)
+	}
+	return &doc, nil
+}
+
+// writeNodeDocument writes a YAML document back to a node file.
+func writeNodeDocument(nodeFile string, doc *yaml.Node) error {
+	data, err := marshalDocument(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", nodeFile, err)
+	}
+	return writeFile(nodeFile, data)
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+// Keys inherited through YAML merge keys (<<) are found as well, explicit
+// keys taking precedence over merged ones. Aliases are resolved.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if isMergeKey(k) {
+			merges = append(merges, v)
+			continue
+		}
+		if k.Value == key {
+			return resolveAlias(v)
+		}
+	}
+	for _, m := range merges {
+		m = resolveAlias(m)
+		sources := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			// Earlier mappings in a merge list take precedence
+			sources = m.Content
+		}
+		for _, src := range sources {
+			if v := mappingValue(src, key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// ownMappingValue is like mappingValue, but a value inherited through a merge
+// key or an alias is first copied into the mapping itself, so editing it
+// leaves the anchored content shared with other mappings untouched.
+func ownMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if k := node.Content[i]; !isMergeKey(k) && k.Value == key {
+			if node.Content[i+1].Kind == yaml.AliasNode {
+				node.Content[i+1] = copyNode(node.Content[i+1])
+			}
+			return node.Content[i+1]
+		}
+	}
+	v := mappingValue(node, key)
+	if v == nil {
+		return nil
+	}
+	v = copyNode(v)
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	return v
+}
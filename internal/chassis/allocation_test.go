@@ -0,0 +1,58 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// paddedNode allocates a node to a quoted entry with surrounding spaces.
+const paddedNode = `hostname: node-1
+chassis:
+  - " platform.foundation.cluster "
+  - platform.foundation.storage
+`
+
+func TestAllocationPaddedEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(file string) (bool, error)
+		changed bool
+		want    string
+	}{
+		{"add", func(file string) (bool, error) {
+			return AddAllocation(file, "platform.foundation.cluster")
+		}, false, paddedNode},
+		{"remove", func(file string) (bool, error) {
+			return RemoveAllocation(file, "platform.foundation.cluster")
+		}, true, "hostname: node-1\nchassis:\n    - platform.foundation.storage\n"},
+		{"replace", func(file string) (bool, error) {
+			return ReplaceAllocation(file, "platform.foundation.cluster", "platform.foundation.k8s")
+		}, true, "hostname: node-1\nchassis:\n    - \"platform.foundation.k8s\"\n    - platform.foundation.storage\n"},
+		{"replace with existing", func(file string) (bool, error) {
+			return ReplaceAllocation(file, "platform.foundation.storage", "platform.foundation.cluster")
+		}, true, "hostname: node-1\nchassis:\n    - \" platform.foundation.cluster \"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "node-1.yaml")
+			if err := os.WriteFile(file, []byte(paddedNode), 0644); err != nil {
+				t.Fatal(err)
+			}
+			changed, err := tt.edit(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("node file:\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// LayerPlaybook returns the playbook holding the plays of a chassis path,
+// src/<layer>/<layer>.yaml in the default layout, with another accepted
+// extension if only that file exists.
+func LayerPlaybook(dir, chassisPath string) (string, error) {
+	layer := LayerOf(chassisPath)
+	if layer == "" {
+		return "", fmt.Errorf("chassis %q has no layer: attach components to <root>.<layer> or below", chassisPath)
+	}
+	return pkgchassis.FindFile(filepath.Join(dir, layout.PlaybooksDir(), layer, layer), layout.FileExtensions()), nil
+}
+
+// AttachRole adds role to the first play of a playbook targeting hosts,
+// appending a play if there is none and creating the playbook if it
+// doesn't exist. With a version, the entry is written in the mapping form
+// {role: <role>, version: <version>}. It returns false if a play targeting
+// hosts already has the role. With dryRun the playbook isn't written.
+func AttachRole(playbookPath, hosts, role, version string, dryRun bool) (bool, error) {
+	doc, err := readPlaybookDocument(playbookPath)
+	if os.IsNotExist(err) {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.SequenceNode}}}
+	} else if err != nil {
+		return false, err
+	}
+
+	plays := doc.Content[0]
+	for _, play := range plays.Content {
+		if playHosts(play) != hosts {
+			continue
+		}
+		if roles := ownMappingValue(play, "roles"); roles != nil && roles.Kind == yaml.SequenceNode {
+			for _, entry := range roles.Content {
+				if roleName(entry) == role {
+					return false, nil
+				}
+			}
+		}
+	}
+
+	entry := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: role}
+	if version != "" {
+		entry = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "role"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: role},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: version, Style: yaml.DoubleQuotedStyle},
+		}}
+	}
+	if roles := targetRoles(plays, hosts); roles != nil {
+		if len(roles.Content) == 0 {
+			// Render a previously empty "[]" list in block style
+			roles.Style = 0
+		}
+		roles.Content = append(roles.Content, entry)
+	} else {
+		plays.Content = append(plays.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "hosts"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: hosts},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "roles"},
+			{Kind: yaml.SequenceNode, Content: []*yaml.Node{entry}},
+		}})
+	}
+
+	if dryRun {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(playbookPath), 0755); err != nil {
+		return false, checkPermission("create", filepath.Dir(playbookPath), err)
+	}
+	if err := writePlaybookDocument(playbookPath, doc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Detachment is the outcome of [DetachRole].
+type Detachment struct {
+	// Detached is false if no play targeting the hosts had the role.
+	Detached bool `json:"detached"`
+	// EmptyPlays counts the plays left without roles or tasks.
+	EmptyPlays int `json:"empty_plays,omitempty"`
+	// RemovedPlays counts the empty plays removed.
+	RemovedPlays int `json:"removed_plays,omitempty"`
+}
+
+// DetachRole removes role, in string or mapping form, from every play of a
+// playbook targeting hosts. Plays left without roles or tasks are counted,
+// and removed if removeEmpty is set. With dryRun the playbook isn't written.
+func DetachRole(playbookPath, hosts, role string, removeEmpty, dryRun bool) (Detachment, error) {
+	var d Detachment
+	doc, err := readPlaybookDocument(playbookPath)
+	if os.IsNotExist(err) {
+		return d, nil
+	} else if err != nil {
+		return d, err
+	}
+
+	plays := doc.Content[0]
+	kept := plays.Content[:0:0]
+	for _, play := range plays.Content {
+		roles := ownMappingValue(play, "roles")
+		if playHosts(play) != hosts || roles == nil || roles.Kind != yaml.SequenceNode {
+			kept = append(kept, play)
+			continue
+		}
+		entries := roles.Content[:0:0]
+		for _, entry := range roles.Content {
+			if roleName(entry) != role {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == len(roles.Content) {
+			kept = append(kept, play)
+			continue
+		}
+		d.Detached = true
+		roles.Content = entries
+		if isEmptyPlay(play) {
+			d.EmptyPlays++
+			if removeEmpty {
+				d.RemovedPlays++
+				continue
+			}
+		}
+		kept = append(kept, play)
+	}
+	if !d.Detached {
+		tracer.File(playbookPath, TraceNoMatch, "no play of "+hosts+" has "+role)
+		return d, nil
+	}
+	if dryRun {
+		return d, nil
+	}
+	plays.Content = kept
+	return d, writePlaybookDocument(playbookPath, doc)
+}
+
+// readPlaybookDocument parses a playbook into a YAML document with a list
+// of plays as root.
+func readPlaybookDocument(playbookPath string) (*yaml.Node, error) {
+	data, err := os.ReadFile(playbookPath)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", playbookPath, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.SequenceNode}}}
+	}
+	if doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: not a list of plays", playbookPath)
+	}
+	tracer.File(playbookPath, TraceRead, "")
+	return &doc, nil
+}
+
+// writePlaybookDocument writes a YAML document back to a playbook.
+func writePlaybookDocument(playbookPath string, doc *yaml.Node) error {
+	data, err := marshalDocument(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", playbookPath, err)
+	}
+	if err := writeFile(playbookPath, data); err != nil {
+		return err
+	}
+	tracer.File(playbookPath, TraceWrite, "")
+	return nil
+}
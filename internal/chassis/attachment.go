@@ -1,237 +1,377 @@
 package chassis
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-chassis/pkg/attachment"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+	"github.com/plasmash/plasmactl-component/pkg/component"
 )
 
 // Attachment represents a component attached to a chassis path
-type Attachment struct {
-	Component string
-	Playbook  string
-	Chassis   string
-}
-
-// LoadAttachments scans playbooks for component attachments to a chassis path
+type Attachment = attachment.Attachment
+
+// LoadAttachments scans playbooks for component attachments to a chassis path.
+// An empty chassis path returns all attachments. A chassis path pinning the
+// layer, e.g. platform.foundation.cluster, is only looked up in that layer's
+// playbook, src/foundation/foundation.yaml. For a root path, only playbooks
+// the persistent index lists with related plays are parsed; without a fresh
+// index all are. Playbooks that can't be parsed are skipped.
 func LoadAttachments(dir, chassisPath string) ([]Attachment, error) {
-	var attachments []Attachment
+	playbooks, err := PlaybookFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := LoadIndex(dir)
+	opts := attachment.Options{
+		Chassis:      chassisPath,
+		PlaybooksDir: filepath.ToSlash(layout.PlaybooksDir()),
+		Parse:        func(path string) ([]attachment.Play, error) { return parsePlaybook(dir, idx, path) },
+		Trace:        tracer.File,
+	}
+
+	// A pinned layer narrows the scan to its playbook, otherwise a fresh index does
+	if layer := LayerOf(chassisPath); layer != "" {
+		playbooks = layerPlaybooks(playbooks, layer)
+	} else if chassisPath != "" && idx.PlaybooksFresh(dir) {
+		playbooks = idx.relatedPlaybooks(dir, playbooks, chassisPath)
+	}
+	opts.Playbooks = playbooks
 
-	// Scan src/<layer>/<layer>.yaml playbooks
-	srcDir := filepath.Join(dir, "src")
-	entries, err := os.ReadDir(srcDir)
+	attachments, err := attachment.Load(context.Background(), dir, opts)
+	if err != nil && !onlyPlaybookErrors(err) {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// PlaybookFiles returns the playbook path of every layer directory under
+// [Layout.PlaybooksDir], src/ in the default layout, like
+// [attachment.Playbooks], with the extensions of the layout.
+func PlaybookFiles(dir string) ([]string, error) {
+	srcDir := filepath.Join(dir, layout.PlaybooksDir())
+	layers, err := subDirs(dir, layout.PlaybooksDir())
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			// Not nil, which makes attachment.Options scan the directory again
+			return []string{}, nil
 		}
 		return nil, err
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	playbooks := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		playbook := pkgchassis.FindFile(filepath.Join(srcDir, layer, layer), layout.FileExtensions())
+		if !ignored(dir, playbook, false) {
+			playbooks = append(playbooks, playbook)
 		}
+	}
+	return playbooks, nil
+}
 
-		playbookPath := filepath.Join(srcDir, entry.Name(), entry.Name()+".yaml")
-		data, err := os.ReadFile(playbookPath)
-		if err != nil {
-			continue
-		}
+// LayerOf returns the layer segment of a chassis path, empty for a root
+// path. Plays of a layer live in its playbook, src/<layer>/<layer>.yaml.
+func LayerOf(chassisPath string) string {
+	parts := strings.SplitN(chassisPath, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
 
-		// Parse playbook - roles can be strings or dicts with "role" key
-		var plays []struct {
-			Hosts string        `yaml:"hosts"`
-			Roles []interface{} `yaml:"roles"`
-		}
-		if err := yaml.Unmarshal(data, &plays); err != nil {
-			continue
+// layerPlaybooks returns the playbook of a layer among playbooks, tracing
+// the others as skipped. The result is empty, not nil, without one.
+func layerPlaybooks(playbooks []string, layer string) []string {
+	narrowed := []string{}
+	for _, path := range playbooks {
+		if filepath.Base(filepath.Dir(path)) == layer {
+			narrowed = append(narrowed, path)
+		} else {
+			tracer.File(path, TraceSkip, "other layer")
 		}
+	}
+	return narrowed
+}
 
-		for _, play := range plays {
-			// Match exact chassis path or children
-			if play.Hosts == chassisPath || strings.HasPrefix(play.Hosts, chassisPath+".") {
-				for _, r := range play.Roles {
-					var roleName string
-					switch role := r.(type) {
-					case string:
-						// Simple string: "- foundation.applications.os"
-						roleName = role
-					case map[string]interface{}:
-						// Dict with role key: "- role: foundation.applications.cluster"
-						if name, ok := role["role"].(string); ok {
-							roleName = name
-						}
-					}
-					if roleName != "" {
-						attachments = append(attachments, Attachment{
-							Component: roleName,
-							Playbook:  playbookPath,
-							Chassis:   play.Hosts,
-						})
-					}
-				}
+// onlyPlaybookErrors reports whether err consists of [attachment.PlaybookError] values only.
+func onlyPlaybookErrors(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !onlyPlaybookErrors(e) {
+				return false
 			}
 		}
+		return true
 	}
+	var pbErr *attachment.PlaybookError
+	return errors.As(err, &pbErr)
+}
 
-	return attachments, nil
+// cachedPlaybook holds the plays parsed from a playbook with a given content hash.
+type cachedPlaybook struct {
+	sum   [sha256.Size]byte
+	plays []attachment.Play
 }
 
-// HasAttachments checks if a chassis path has any component attachments
-func HasAttachments(dir, chassisPath string) (bool, []Attachment, error) {
-	attachments, err := LoadAttachments(dir, chassisPath)
-	if err != nil {
-		return false, nil, err
+// playbookCache avoids re-parsing unchanged playbooks within a process. Across
+// processes, the plays stored in the persistent index serve the same purpose.
+var playbookCache = struct {
+	sync.Mutex
+	entries map[string]cachedPlaybook
+	stats   CacheStats
+}{entries: make(map[string]cachedPlaybook)}
+
+// CacheStats counts lookups of the playbook parse cache, in memory and in
+// the persistent index.
+type CacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// HitRate returns the share of lookups served from the cache, or 0 without lookups.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
 	}
-	return len(attachments) > 0, attachments, nil
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
 }
 
-// UpdateAttachments renames chassis path references in all playbooks
-func UpdateAttachments(dir, oldChassis, newChassis string) ([]string, error) {
-	var updatedFiles []string
+// Sub returns the lookups counted since an earlier snapshot.
+func (s CacheStats) Sub(earlier CacheStats) CacheStats {
+	return CacheStats{Hits: s.Hits - earlier.Hits, Misses: s.Misses - earlier.Misses}
+}
+
+// PlaybookCacheStats returns the lookups of the playbook parse cache since process start.
+func PlaybookCacheStats() CacheStats {
+	playbookCache.Lock()
+	defer playbookCache.Unlock()
+	return playbookCache.stats
+}
 
-	srcDir := filepath.Join(dir, "src")
-	entries, err := os.ReadDir(srcDir)
+// parsePlaybook returns the plays of a playbook, reusing those parsed
+// earlier in the process or stored in idx, the index of the repository at
+// dir, if the file content is unchanged.
+func parsePlaybook(dir string, idx *Index, playbookPath string) ([]attachment.Play, error) {
+	data, err := os.ReadFile(playbookPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, &attachment.PlaybookError{Path: playbookPath, Op: "read", Err: err}
+	}
+	sum := sha256.Sum256(data)
+
+	playbookCache.Lock()
+	cached, ok := playbookCache.entries[playbookPath]
+	hit := ok && cached.sum == sum
+	playbookCache.Unlock()
+	if hit {
+		countPlaybookLookup(true)
+		tracer.File(playbookPath, TraceRead, "cached")
+		return cached.plays, nil
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	plays, indexed := idx.indexedPlays(dir, playbookPath, sum)
+	countPlaybookLookup(indexed)
+	if indexed {
+		tracer.File(playbookPath, TraceRead, "indexed")
+	} else {
+		if plays, err = attachment.ParsePlays(data); err != nil {
+			return nil, &attachment.PlaybookError{Path: playbookPath, Op: "parse", Err: err}
 		}
+		tracer.File(playbookPath, TraceRead, "")
+	}
 
-		playbookPath := filepath.Join(srcDir, entry.Name(), entry.Name()+".yaml")
-		data, err := os.ReadFile(playbookPath)
-		if err != nil {
-			continue
-		}
+	playbookCache.Lock()
+	playbookCache.entries[playbookPath] = cachedPlaybook{sum: sum, plays: plays}
+	playbookCache.Unlock()
+	return plays, nil
+}
 
-		// Parse as yaml.Node to preserve formatting
-		var doc yaml.Node
-		if err := yaml.Unmarshal(data, &doc); err != nil {
-			continue
+// countPlaybookLookup counts a lookup of the playbook parse cache.
+func countPlaybookLookup(hit bool) {
+	playbookCache.Lock()
+	defer playbookCache.Unlock()
+	if hit {
+		playbookCache.stats.Hits++
+	} else {
+		playbookCache.stats.Misses++
+	}
+}
+
+// HasAttachments checks if a chassis path has any component attachments
+func HasAttachments(dir, chassisPath string) (bool, []Attachment, error) {
+	attachments, err := LoadAttachments(dir, chassisPath)
+	if err != nil {
+		return false, nil, err
+	}
+	return len(attachments) > 0, attachments, nil
+}
+
+// AttachmentPaths returns the chassis paths each component is attached to,
+// sorted and without duplicates.
+func AttachmentPaths(attachments []Attachment) map[string][]string {
+	result := make(map[string][]string)
+	for _, a := range attachments {
+		if a.Chassis != "" && !slices.Contains(result[a.Component], a.Chassis) {
+			result[a.Component] = append(result[a.Component], a.Chassis)
 		}
+	}
+	for name := range result {
+		slices.Sort(result[name])
+	}
+	return result
+}
 
-		updated := updateHostsInNode(&doc, oldChassis, newChassis)
-		if updated {
-			newData, err := yaml.Marshal(&doc)
-			if err != nil {
-				continue
-			}
-			if err := os.WriteFile(playbookPath, newData, 0644); err != nil {
-				continue
-			}
-			updatedFiles = append(updatedFiles, playbookPath)
+// ComponentVersions returns the version of every component known to the
+// component package, by name. Attachments are read with [LoadAttachments];
+// only versions, a property of the component rather than of a playbook,
+// come from there.
+func ComponentVersions(dir string) (map[string]string, error) {
+	components, err := component.LoadFromPlaybooks(dir)
+	versions := make(map[string]string, len(components))
+	for _, comp := range components {
+		if comp.Version != "" {
+			versions[comp.Name] = comp.Version
 		}
 	}
+	return versions, err
+}
 
-	return updatedFiles, nil
+// UpdateAttachments renames chassis path references in all playbooks.
+// Files that can't be written don't stop the update; their errors are
+// joined in the returned error alongside the files that were updated.
+func UpdateAttachments(dir, oldChassis, newChassis string) ([]string, error) {
+	playbooks, err := PlaybookFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return attachment.Update(context.Background(), dir, oldChassis, newChassis, attachment.Options{
+		Playbooks:    playbooks,
+		PlaybooksDir: filepath.ToSlash(layout.PlaybooksDir()),
+		Write:        writeFile,
+		Trace:        tracer.File,
+	})
 }
 
-// updateHostsInNode recursively updates hosts fields in a yaml.Node
-func updateHostsInNode(node *yaml.Node, oldChassis, newChassis string) bool {
-	updated := false
+// UpdateAllocations renames chassis path references in all node files.
+// Platforms are processed in parallel; updated files and trace events are
+// reported in platform order, as if processed sequentially.
+// Write errors are reported like in [UpdateAttachments].
+func UpdateAllocations(dir, oldChassis, newChassis string) ([]string, error) {
+	names, err := Platforms(dir)
+	if err != nil {
+		return nil, err
+	}
+	platforms := make([]string, 0, len(names))
+	for _, platform := range names {
+		platforms = append(platforms, filepath.Join(dir, layout.PlatformNodesDir(platform)))
+	}
 
-	switch node.Kind {
-	case yaml.DocumentNode:
-		for _, child := range node.Content {
-			if updateHostsInNode(child, oldChassis, newChassis) {
-				updated = true
-			}
-		}
-	case yaml.SequenceNode:
-		for _, child := range node.Content {
-			if updateHostsInNode(child, oldChassis, newChassis) {
-				updated = true
+	shards := make([]allocationShard, len(platforms))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(platforms)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				shards[i].update(dir, platforms[i], oldChassis, newChassis)
 			}
-		}
-	case yaml.MappingNode:
-		for i := 0; i < len(node.Content); i += 2 {
-			key := node.Content[i]
-			value := node.Content[i+1]
+		}()
+	}
+	for i := range platforms {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-			if key.Value == "hosts" && value.Kind == yaml.ScalarNode {
-				// Check for exact match or prefix match
-				if value.Value == oldChassis {
-					value.Value = newChassis
-					updated = true
-				} else if strings.HasPrefix(value.Value, oldChassis+".") {
-					value.Value = newChassis + value.Value[len(oldChassis):]
-					updated = true
-				}
-			} else {
-				if updateHostsInNode(value, oldChassis, newChassis) {
-					updated = true
-				}
-			}
+	var updatedFiles []string
+	var writeErrs []error
+	for _, shard := range shards {
+		for _, e := range shard.events {
+			tracer.File(e.path, e.event, e.detail)
 		}
+		updatedFiles = append(updatedFiles, shard.updated...)
+		writeErrs = append(writeErrs, shard.errs...)
 	}
+	return updatedFiles, errors.Join(writeErrs...)
+}
 
-	return updated
+// traceEvent is a trace event recorded by a worker, replayed in order afterwards.
+type traceEvent struct {
+	path, event, detail string
 }
 
-// UpdateAllocations renames chassis path references in all node files
-func UpdateAllocations(dir, oldChassis, newChassis string) ([]string, error) {
-	var updatedFiles []string
+// allocationShard is the part of [UpdateAllocations] covering one platform.
+type allocationShard struct {
+	updated []string
+	errs    []error
+	events  []traceEvent
+}
+
+func (s *allocationShard) trace(path, event, detail string) {
+	s.events = append(s.events, traceEvent{path, event, detail})
+}
 
-	instDir := filepath.Join(dir, "inst")
-	platforms, err := os.ReadDir(instDir)
+// update renames chassis path references in the node files of nodesDir,
+// below the repository at dir.
+func (s *allocationShard) update(dir, nodesDir, oldChassis, newChassis string) {
+	nodeFiles, err := os.ReadDir(nodesDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, err
+		s.trace(nodesDir, TraceSkip, err.Error())
+		return
 	}
 
-	for _, platform := range platforms {
-		if !platform.IsDir() {
+	for _, nodeFile := range nodeFiles {
+		nodePath := filepath.Join(nodesDir, nodeFile.Name())
+		if _, ok := pkgchassis.TrimExtension(nodeFile.Name(), layout.FileExtensions()); nodeFile.IsDir() || !ok {
+			s.trace(nodePath, TraceSkip, "not a "+strings.Join(layout.FileExtensions(), " or ")+" file")
+			continue
+		}
+		if rel, err := filepath.Rel(dir, nodePath); err == nil && repoIgnores(dir).Ignored(rel, false) {
+			s.trace(nodePath, TraceSkip, "ignored")
 			continue
 		}
 
-		nodesDir := filepath.Join(instDir, platform.Name(), "nodes")
-		nodeFiles, err := os.ReadDir(nodesDir)
+		data, err := os.ReadFile(nodePath)
 		if err != nil {
+			s.trace(nodePath, TraceSkip, err.Error())
 			continue
 		}
 
-		for _, nodeFile := range nodeFiles {
-			if nodeFile.IsDir() || !strings.HasSuffix(nodeFile.Name(), ".yaml") {
-				continue
-			}
-
-			nodePath := filepath.Join(nodesDir, nodeFile.Name())
-			data, err := os.ReadFile(nodePath)
-			if err != nil {
-				continue
-			}
-
-			// Parse as yaml.Node to preserve formatting
-			var doc yaml.Node
-			if err := yaml.Unmarshal(data, &doc); err != nil {
-				continue
-			}
+		// Parse as yaml.Node to preserve formatting
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			s.trace(nodePath, TraceSkip, "parse error: "+err.Error())
+			continue
+		}
 
-			updated := updateChassisInNode(&doc, oldChassis, newChassis)
-			if updated {
-				newData, err := yaml.Marshal(&doc)
-				if err != nil {
-					continue
-				}
-				if err := os.WriteFile(nodePath, newData, 0644); err != nil {
-					continue
-				}
-				updatedFiles = append(updatedFiles, nodePath)
-			}
+		updated := updateChassisInNode(&doc, oldChassis, newChassis)
+		if !updated {
+			s.trace(nodePath, TraceNoMatch, "no chassis entry references "+oldChassis)
+			continue
 		}
+		s.trace(nodePath, TraceMatch, "")
+		newData, err := marshalDocument(&doc)
+		if err != nil {
+			s.trace(nodePath, TraceSkip, "marshal error: "+err.Error())
+			continue
+		}
+		if err := writeFileTraced(nodePath, newData, s.trace); err != nil {
+			s.trace(nodePath, TraceSkip, "write error: "+err.Error())
+			s.errs = append(s.errs, err)
+			continue
+		}
+		s.trace(nodePath, TraceWrite, "")
+		s.updated = append(s.updated, nodePath)
 	}
-
-	return updatedFiles, nil
 }
 
 // updateChassisInNode updates chassis array entries in a yaml.Node
@@ -251,18 +391,25 @@ func updateChassisInNode(node *yaml.Node, oldChassis, newChassis string) bool {
 			value := node.Content[i+1]
 
 			if key.Value == "chassis" && value.Kind == yaml.SequenceNode {
-				// Update chassis array entries
+				// Update chassis array entries, dropping entries that became
+				// duplicates when merging into an already allocated path
+				seen := make(map[string]bool, len(value.Content))
+				entries := value.Content[:0]
 				for _, item := range value.Content {
-					if item.Kind == yaml.ScalarNode {
-						if item.Value == oldChassis {
-							item.Value = newChassis
-							updated = true
-						} else if strings.HasPrefix(item.Value, oldChassis+".") {
-							item.Value = newChassis + item.Value[len(oldChassis):]
-							updated = true
-						}
+					if item.Kind != yaml.ScalarNode {
+						entries = append(entries, item)
+						continue
+					}
+					if renamed, ok := pkgchassis.RenameAllocation(item.Value, oldChassis, newChassis); ok {
+						item.Value = renamed
+						updated = true
+					}
+					if !seen[item.Value] {
+						seen[item.Value] = true
+						entries = append(entries, item)
 					}
 				}
+				value.Content = entries
 			} else {
 				if updateChassisInNode(value, oldChassis, newChassis) {
 					updated = true
@@ -0,0 +1,126 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// writeFleet writes a repository with platforms of nodes each, every node
+// allocated to chassisPath, and returns the node files in platform order.
+func writeFleet(tb testing.TB, dir string, platforms, nodes int, chassisPath string) []string {
+	tb.Helper()
+	var files []string
+	for p := range platforms {
+		nodesDir := filepath.Join(dir, "inst", fmt.Sprintf("platform-%02d", p), "nodes")
+		if err := os.MkdirAll(nodesDir, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		for n := range nodes {
+			hostname := fmt.Sprintf("node-%04d", n)
+			file := filepath.Join(nodesDir, hostname+".yaml")
+			content := fmt.Sprintf("hostname: %s\nchassis:\n  - %s\n  - platform.interaction.observability\n", hostname, chassisPath)
+			if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+				tb.Fatal(err)
+			}
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// recordingTracer records trace events in the order they are reported.
+type recordingTracer struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingTracer) File(path, event, detail string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, path+" "+event+" "+detail)
+}
+
+// TestUpdateAllocationsOrder checks that the sharded update reports files
+// and trace events in platform order on every run. Run it with -race, since
+// platforms are updated by parallel workers.
+func TestUpdateAllocationsOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := writeFleet(t, dir, 12, 25, "platform.foundation.cluster")
+	defer SetTracer(nil)
+
+	var first []string
+	for run := range 6 {
+		// Rename back and forth, so every run rewrites every file
+		oldChassis, newChassis := "platform.foundation.cluster", "platform.foundation.k8s"
+		if run%2 == 1 {
+			oldChassis, newChassis = newChassis, oldChassis
+		}
+		tracer := &recordingTracer{}
+		SetTracer(tracer)
+
+		updated, err := UpdateAllocations(dir, oldChassis, newChassis)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(updated, files) {
+			t.Fatalf("run %d: updated files are not in platform order:\n got %v\nwant %v", run, updated, files)
+		}
+		switch run {
+		case 0:
+			first = tracer.events
+		case 2, 4:
+			if !slices.Equal(tracer.events, first) {
+				t.Fatalf("run %d: trace events differ from the first run", run)
+			}
+		}
+	}
+}
+
+// BenchmarkUpdateAllocations renames an allocation across 16 platforms of
+// 250 nodes with 1 to 16 workers, as set by GOMAXPROCS. Up to the number
+// of CPUs, ns/op should drop about linearly with the workers, and files/s
+// rise accordingly:
+//
+//	go test ./internal/chassis -run '^$' -bench UpdateAllocations
+func BenchmarkUpdateAllocations(b *testing.B) {
+	dir := b.TempDir()
+	files := writeFleet(b, dir, 16, 250, "platform.foundation.cluster")
+
+	paths := [2]string{"platform.foundation.cluster", "platform.foundation.k8s"}
+	run := 0
+	for _, procs := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("procs-%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			for b.Loop() {
+				if _, err := UpdateAllocations(dir, paths[run%2], paths[(run+1)%2]); err != nil {
+					b.Fatal(err)
+				}
+				run++
+			}
+			b.ReportMetric(float64(len(files)*b.N)/b.Elapsed().Seconds(), "files/s")
+		})
+	}
+}
+
+// TestLayerPlaybooksTrace checks that playbooks of other layers are traced
+// as skipped with a bare reason, which the tracer wraps in parentheses.
+func TestLayerPlaybooksTrace(t *testing.T) {
+	tracer := &recordingTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	playbooks := []string{"src/foundation/foundation.yaml", "src/interaction/interaction.yaml"}
+	got := layerPlaybooks(playbooks, "foundation")
+	if !slices.Equal(got, playbooks[:1]) {
+		t.Errorf("layer playbooks = %v, want %v", got, playbooks[:1])
+	}
+	want := []string{"src/interaction/interaction.yaml " + TraceSkip + " other layer"}
+	if !slices.Equal(tracer.events, want) {
+		t.Errorf("trace events = %q, want %q", tracer.events, want)
+	}
+}
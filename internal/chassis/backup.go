@@ -0,0 +1,313 @@
+package chassis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackupDir holds backup sets, relative to the repository root.
+const BackupDir = ".plasmactl/backups"
+
+// backupManifest is the file listing the entries of a backup set.
+const backupManifest = "manifest.json"
+
+// BackupConfig holds settings of backups taken by mutating actions.
+//
+//	chassis:
+//	  backup:
+//	    enabled: true
+type BackupConfig struct {
+	// Enabled backs up files by default, as if --backup was given.
+	Enabled bool `yaml:"enabled"`
+}
+
+var backupConfig BackupConfig
+
+// SetBackupConfig installs the backup settings used by [BackupByDefault].
+func SetBackupConfig(c BackupConfig) {
+	backupConfig = c
+}
+
+// BackupByDefault reports whether mutating actions back up files without --backup.
+func BackupByDefault() bool {
+	return backupConfig.Enabled
+}
+
+// BackupEntry is a file change recorded in a backup set. Entries are
+// undone in reverse order by [RestoreBackup].
+type BackupEntry struct {
+	// Path is the file or directory, relative to the repository root.
+	Path string `json:"path"`
+	// Created is set when the file didn't exist before it was written.
+	Created bool `json:"created,omitempty"`
+	// MovedTo is set when Path was moved instead of written.
+	MovedTo string `json:"moved_to,omitempty"`
+}
+
+// BackupSet is the set of files backed up by one action invocation,
+// stored under [BackupDir]/<name>.
+type BackupSet struct {
+	Name    string        `json:"name"`
+	Action  string        `json:"action"`
+	Created time.Time     `json:"created"`
+	Entries []BackupEntry `json:"entries"`
+}
+
+// backup is the backup set being recorded, if any.
+var backup struct {
+	sync.Mutex
+	root    string // absolute repository root
+	set     *BackupSet
+	dir     string // directory of the set, created on the first entry
+	written map[string]bool
+}
+
+// BeginBackup starts recording a backup set for action in the repository
+// at dir: until [EndBackup], every file is copied before it is first
+// written or removed, and moves are recorded.
+func BeginBackup(dir, action string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	name := now.Format("20060102T150405Z")
+	base := filepath.Join(root, BackupDir)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(filepath.Join(base, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", now.Format("20060102T150405Z"), i)
+	}
+
+	backup.Lock()
+	defer backup.Unlock()
+	backup.root = root
+	backup.set = &BackupSet{Name: name, Action: action, Created: now}
+	backup.dir = filepath.Join(base, name)
+	backup.written = make(map[string]bool)
+	return nil
+}
+
+// EndBackup stops recording and writes the manifest of the backup set.
+// It returns nil if no file was changed.
+func EndBackup() (*BackupSet, error) {
+	backup.Lock()
+	defer backup.Unlock()
+	set := backup.set
+	backup.set = nil
+	if set == nil || len(set.Entries) == 0 {
+		return nil, nil
+	}
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(backup.dir, backupManifest)
+	if err := checkPermission("write", path, os.WriteFile(path, append(data, '\n'), 0644)); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// backupRel returns the path of a file relative to the repository being
+// backed up. It returns false if no backup is recorded or the file is
+// outside the repository.
+func backupRel(path string) (string, bool) {
+	if backup.set == nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(backup.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// backupFile copies path into the backup set before it is first written
+// or removed. A file that doesn't exist yet is recorded as created.
+func backupFile(path string) error {
+	backup.Lock()
+	defer backup.Unlock()
+	rel, ok := backupRel(path)
+	if !ok || backup.written[rel] {
+		return nil
+	}
+
+	entry := BackupEntry{Path: rel}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		entry.Created = true
+	case err != nil:
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	default:
+		dst := filepath.Join(backup.dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return checkPermission("write", dst, err)
+		}
+		if err := checkPermission("write", dst, os.WriteFile(dst, data, 0644)); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := os.MkdirAll(backup.dir, 0755); err != nil {
+		return checkPermission("write", backup.dir, err)
+	}
+	backup.written[rel] = true
+	backup.set.Entries = append(backup.set.Entries, entry)
+	return nil
+}
+
+// backupMove records the move of src to dst in the backup set.
+func backupMove(src, dst string) {
+	backup.Lock()
+	defer backup.Unlock()
+	from, ok := backupRel(src)
+	if !ok {
+		return
+	}
+	to, ok := backupRel(dst)
+	if !ok {
+		return
+	}
+	if err := os.MkdirAll(backup.dir, 0755); err != nil {
+		return
+	}
+	backup.set.Entries = append(backup.set.Entries, BackupEntry{Path: from, MovedTo: to})
+}
+
+// ListBackups returns the backup sets of the repository at dir, oldest first.
+func ListBackups(dir string) ([]BackupSet, error) {
+	base := filepath.Join(dir, BackupDir)
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sets []BackupSet
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		set, err := readBackup(base, e.Name())
+		if err != nil {
+			tracer.File(filepath.Join(base, e.Name()), TraceSkip, err.Error())
+			continue
+		}
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if !sets[i].Created.Equal(sets[j].Created) {
+			return sets[i].Created.Before(sets[j].Created)
+		}
+		return sets[i].Name < sets[j].Name
+	})
+	return sets, nil
+}
+
+// readBackup reads the manifest of the backup set name below base.
+func readBackup(base, name string) (BackupSet, error) {
+	path := filepath.Join(base, name, backupManifest)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return BackupSet{}, err
+	}
+	tracer.File(path, TraceRead, "")
+	var set BackupSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return BackupSet{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	set.Name = name
+	return set, nil
+}
+
+// RestoreBackup reverts the files of the backup set name, or of the latest
+// set if name is empty, and deletes the set once every entry is restored.
+// Entries are undone newest first: moved files are moved back, created
+// files removed and the others overwritten with their backup copy.
+// Entries that fail don't stop the others; their errors are joined.
+func RestoreBackup(dir, name string, dryRun bool) (BackupSet, error) {
+	if name == "" {
+		sets, err := ListBackups(dir)
+		if err != nil {
+			return BackupSet{}, err
+		}
+		if len(sets) == 0 {
+			return BackupSet{}, fmt.Errorf("no backup found in %s", filepath.Join(dir, BackupDir))
+		}
+		name = sets[len(sets)-1].Name
+	}
+	base := filepath.Join(dir, BackupDir)
+	set, err := readBackup(base, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return BackupSet{}, fmt.Errorf("backup %q not found in %s", name, base)
+		}
+		return BackupSet{}, err
+	}
+	if dryRun {
+		return set, nil
+	}
+
+	var errs []error
+	for i := len(set.Entries) - 1; i >= 0; i-- {
+		if err := restoreEntry(dir, filepath.Join(base, name), set.Entries[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return set, errors.Join(errs...)
+	}
+	return set, checkPermission("remove", filepath.Join(base, name), os.RemoveAll(filepath.Join(base, name)))
+}
+
+// restoreEntry undoes one entry of the backup set stored in setDir.
+func restoreEntry(dir, setDir string, e BackupEntry) error {
+	path := filepath.Join(dir, filepath.FromSlash(e.Path))
+	switch {
+	case e.MovedTo != "":
+		src := filepath.Join(dir, filepath.FromSlash(e.MovedTo))
+		if _, err := os.Lstat(path); err == nil {
+			return &fs.PathError{Op: "restore", Path: path, Err: fmt.Errorf("%s already exists", e.Path)}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return checkPermission("create", filepath.Dir(path), err)
+		}
+		if err := checkPermission("move", src, os.Rename(src, path)); err != nil {
+			return err
+		}
+		tracer.File(path, TraceWrite, "moved back from "+e.MovedTo)
+	case e.Created:
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return checkPermission("remove", path, err)
+		}
+		tracer.File(path, TraceWrite, "removed")
+	default:
+		data, err := os.ReadFile(filepath.Join(setDir, filepath.FromSlash(e.Path)))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return checkPermission("write", path, err)
+		}
+		if err := writeFile(path, data); err != nil {
+			return err
+		}
+		tracer.File(path, TraceWrite, "restored")
+	}
+	return nil
+}
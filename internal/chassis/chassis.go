@@ -2,6 +2,7 @@
 package chassis
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,12 +16,26 @@ import (
 // Chassis wraps the public Chassis type with write operations.
 type Chassis struct {
 	*pkgchassis.Chassis
+
+	aliasesExpanded bool
 }
 
-// Node represents a node file from inst/<platform>/nodes/<hostname>.yaml
+// Node represents a node file, inst/<platform>/nodes/<hostname>.yaml in the
+// default layout, see [Layout.Nodes].
 type Node struct {
 	Hostname string   `yaml:"hostname"`
 	Chassis  []string `yaml:"chassis"`
+	// Quarantined marks a drained or broken host that stays in the chassis
+	// model but is out of rotation.
+	Quarantined bool `yaml:"quarantined"`
+
+	Platform         string `yaml:"-"` // platform directory, under inst/ in the default layout
+	File             string `yaml:"-"` // path of the node file
+	FileHostname     string `yaml:"-"` // hostname derived from the file name
+	DeclaredHostname string `yaml:"-"` // hostname field as written in the file
+	ChassisDeclared  bool   `yaml:"-"` // whether the chassis key is present at all
+
+	Fields map[string]interface{} `yaml:"-"` // all top-level fields of the node file
 }
 
 // Load reads and parses chassis.yaml from the given directory
@@ -29,17 +44,34 @@ func Load(dir string) (*Chassis, error) {
 	if err != nil {
 		return nil, err
 	}
+	if layout.StrictScalars && len(pub.Normalized()) > 0 {
+		var errs []error
+		for _, n := range pub.Normalized() {
+			errs = append(errs, fmt.Errorf("chassis.yaml:%d: segment %q has stray whitespace or quotes (expected %q)",
+				n.Line, n.Original, n.Normalized))
+		}
+		return nil, errors.Join(errs...)
+	}
 	return &Chassis{Chassis: pub}, nil
 }
 
 // Save writes the chassis configuration to chassis.yaml preserving order
 func (c *Chassis) Save(dir string) error {
 	path := filepath.Join(dir, "chassis.yaml")
-	data, err := yaml.Marshal(c.YAMLNode())
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data)
+}
+
+// Marshal returns the content Save would write to chassis.yaml.
+func (c *Chassis) Marshal() ([]byte, error) {
+	data, err := marshalDocument(c.Document())
 	if err != nil {
-		return fmt.Errorf("failed to marshal chassis: %w", err)
+		return nil, fmt.Errorf("failed to marshal chassis: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	return data, nil
 }
 
 // Add adds a new chassis path preserving YAML order
@@ -48,6 +80,10 @@ func (c *Chassis) Add(chassisPath string) error {
 	if err := pkgchassis.ValidatePath(chassisPath); err != nil {
 		return err
 	}
+	if err := pkgchassis.CheckReserved(chassisPath, reservedNames); err != nil {
+		return err
+	}
+	c.expandAliases()
 
 	parts := strings.Split(chassisPath, ".")
 
@@ -55,6 +91,11 @@ func (c *Chassis) Add(chassisPath string) error {
 		return fmt.Errorf("chassis path %q already exists", chassisPath)
 	}
 
+	// Refuse near-duplicates, which Exists and Rename wouldn't tell apart from intent
+	if existing, ok := c.NearMatch(chassisPath); ok {
+		return fmt.Errorf("chassis path %q conflicts with existing %q (differs only in case or whitespace)", chassisPath, existing)
+	}
+
 	// Work with yaml.Node to preserve order
 	node := c.YAMLNode()
 	if node == nil || len(node.Content) == 0 {
@@ -235,6 +276,7 @@ func (c *Chassis) Remove(chassisPath string) error {
 	if len(parts) < 1 || chassisPath == "" {
 		return fmt.Errorf("chassis path cannot be empty")
 	}
+	c.expandAliases()
 
 	if !c.Exists(chassisPath) {
 		return fmt.Errorf("chassis path %q does not exist", chassisPath)
@@ -378,18 +420,6 @@ func removePathFromSequence(seqNode *yaml.Node, path []string) bool {
 	return false
 }
 
-// GetTree returns the chassis as a tree structure for display
-func (c *Chassis) GetTree() map[string]interface{} {
-	tree := make(map[string]interface{})
-	d := c.RawData()
-	for root, layers := range d {
-		for layer, chassis := range layers {
-			tree[root+"."+layer] = chassisToTree(chassis)
-		}
-	}
-	return tree
-}
-
 // addChassisPath adds a chassis path to the nested structure
 func addChassisPath(chassis []interface{}, path []string) []interface{} {
 	if len(path) == 0 {
@@ -476,97 +506,180 @@ func removeChassisPath(chassis []interface{}, path []string) ([]interface{}, boo
 	return chassis, false
 }
 
-// chassisToTree converts chassis structure to a displayable tree
-func chassisToTree(chassis []interface{}) interface{} {
-	if len(chassis) == 0 {
-		return nil
-	}
-
-	result := make(map[string]interface{})
-	for _, c := range chassis {
-		switch item := c.(type) {
-		case string:
-			result[item] = nil
-		case map[string]interface{}:
-			for name, sub := range item {
-				if subSlice, ok := sub.([]interface{}); ok {
-					result[name] = chassisToTree(subSlice)
-				} else {
-					result[name] = nil
-				}
-			}
-		}
-	}
-	return result
-}
-
-// LoadNodes loads all nodes from inst/<platform>/nodes/ directory
+// LoadNodes loads all nodes from the node directory of each platform,
+// inst/<platform>/nodes/ in the default layout. Node files that can't be read or parsed are skipped and returned as joined
+// [NodeFileError]s along with the other nodes.
 func LoadNodes(dir, platform string) ([]Node, error) {
 	var nodes []Node
 
-	instDir := filepath.Join(dir, "inst")
 	if platform != "" {
 		// Load from specific platform
-		nodes, err := loadNodesFromPlatform(instDir, platform)
-		if err != nil {
-			return nil, err
-		}
-		return nodes, nil
+		return loadNodesFromPlatform(dir, platform)
 	}
 
 	// Load from all platforms
-	entries, err := os.ReadDir(instDir)
+	platforms, err := Platforms(dir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read inst directory: %w", err)
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		platformNodes, err := loadNodesFromPlatform(instDir, entry.Name())
-		if err != nil {
-			continue // Skip platforms with errors
+	var skipped []error
+	for _, platform := range platforms {
+		platformNodes, err := loadNodesFromPlatform(dir, platform)
+		// Skip platforms with errors, but report skipped node files
+		if nodeErrs, _ := SplitNodeFileErrors(err); nodeErrs != nil {
+			skipped = append(skipped, nodeErrs)
 		}
 		nodes = append(nodes, platformNodes...)
 	}
 
-	return nodes, nil
+	return nodes, errors.Join(skipped...)
 }
 
-func loadNodesFromPlatform(instDir, platform string) ([]Node, error) {
-	nodesDir := filepath.Join(instDir, platform, "nodes")
-	entries, err := os.ReadDir(nodesDir)
+// Platforms returns the platforms of the repository at dir: the directories
+// of [Layout.PlatformsDir], inst/ in the default layout, in name order.
+func Platforms(dir string) ([]string, error) {
+	platforms, err := subDirs(dir, layout.PlatformsDir())
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
+		return nil, fmt.Errorf("failed to read platforms directory: %w", err)
+	}
+	return platforms, nil
+}
+
+// subDirs returns the subdirectories of rel, a directory of the repository
+// at root such as inst/ or src/, following symlinks, see [pkgchassis.Dirs],
+// and tracing the entries it skips. Ignored subdirectories are left out.
+func subDirs(root, rel string) ([]string, error) {
+	dir := filepath.Join(root, rel)
+	names, err := pkgchassis.Dirs(dir, func(name, reason string) {
+		tracer.File(filepath.Join(dir, name), TraceSkip, reason)
+	})
+	if err != nil {
 		return nil, err
 	}
+	kept := names[:0]
+	for _, name := range names {
+		if !ignored(root, filepath.Join(dir, name), true) {
+			kept = append(kept, name)
+		}
+	}
+	return kept, nil
+}
 
+func loadNodesFromPlatform(dir, platform string) ([]Node, error) {
 	var nodes []Node
+	err := walkPlatformNodes(dir, platform, true, func(node Node) error {
+		nodes = append(nodes, node)
+		return nil
+	})
+	return nodes, err
+}
+
+// ForEachNode calls fn with every node of platform, or of all platforms if
+// platform is empty, reading one node file at a time. Unlike [LoadNodes],
+// nodes carry only the fields the chassis uses and Fields is nil, so memory
+// stays bounded by what fn keeps on fleets with large node files. It stops
+// at the first error returned by fn. Node files that can't be read or
+// parsed are skipped and returned as joined [NodeFileError]s.
+func ForEachNode(dir, platform string, fn func(Node) error) error {
+	if platform != "" {
+		return walkPlatformNodes(dir, platform, false, fn)
+	}
+
+	platforms, err := Platforms(dir)
+	if err != nil {
+		return err
+	}
+	var skipped []error
+	for _, platform := range platforms {
+		// Platforms that can't be read are skipped, errors of fn are not
+		var fnErr error
+		err := walkPlatformNodes(dir, platform, false, func(node Node) error {
+			fnErr = fn(node)
+			return fnErr
+		})
+		if fnErr != nil {
+			return fnErr
+		}
+		if nodeErrs, _ := SplitNodeFileErrors(err); nodeErrs != nil {
+			skipped = append(skipped, nodeErrs)
+		}
+	}
+	return errors.Join(skipped...)
+}
+
+// walkPlatformNodes calls fn with each node of a platform of the repository
+// at dir, parsed with all top-level fields if fields is set. Files that
+// can't be loaded are returned as joined [NodeFileError]s once all others
+// were walked.
+func walkPlatformNodes(dir, platform string, fields bool, fn func(Node) error) error {
+	nodesDir := filepath.Join(dir, layout.PlatformNodesDir(platform))
+	entries, err := os.ReadDir(nodesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var skipped []error
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+		nodePath := filepath.Join(nodesDir, entry.Name())
+		if _, ok := pkgchassis.TrimExtension(entry.Name(), layout.FileExtensions()); entry.IsDir() || !ok {
+			tracer.File(nodePath, TraceSkip, "not a "+strings.Join(layout.FileExtensions(), " or ")+" file")
 			continue
 		}
-
-		data, err := os.ReadFile(filepath.Join(nodesDir, entry.Name()))
-		if err != nil {
+		if ignored(dir, nodePath, false) {
 			continue
 		}
-
-		var node Node
-		if err := yaml.Unmarshal(data, &node); err != nil {
+		node, err := loadNodeFile(nodePath, platform, fields)
+		if err != nil {
+			skipped = append(skipped, err)
 			continue
 		}
-		node.Hostname = strings.TrimSuffix(entry.Name(), ".yaml")
-		nodes = append(nodes, node)
+		if err := fn(node); err != nil {
+			return err
+		}
 	}
+	return errors.Join(skipped...)
+}
 
-	return nodes, nil
+// loadNodeFile parses a node file of a platform, with all top-level fields
+// if fields is set. Files that can't be read or parsed are traced and
+// returned as a [NodeFileError].
+func loadNodeFile(nodePath, platform string, fields bool) (Node, error) {
+	var node Node
+	data, err := os.ReadFile(nodePath)
+	if err != nil {
+		tracer.File(nodePath, TraceSkip, err.Error())
+		return node, &NodeFileError{Path: nodePath, Err: err}
+	}
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err == nil {
+		err = doc.Decode(&node)
+	}
+	if err == nil && fields {
+		err = doc.Decode(&node.Fields)
+	}
+	if err != nil {
+		tracer.File(nodePath, TraceSkip, "parse error: "+err.Error())
+		return node, &NodeFileError{Path: nodePath, Err: fmt.Errorf("parse error: %w", err)}
+	}
+	tracer.File(nodePath, TraceRead, "")
+	node.DeclaredHostname = node.Hostname
+	node.FileHostname, _ = pkgchassis.TrimExtension(filepath.Base(nodePath), layout.FileExtensions())
+	if !layout.TrustsYAMLHostname() || node.DeclaredHostname == "" {
+		node.Hostname = node.FileHostname
+	}
+	node.Platform = platform
+	node.File = nodePath
+	node.ChassisDeclared = len(doc.Content) > 0 && mappingValue(doc.Content[0], "chassis") != nil
+	return node, nil
 }
 
 // NodesForChassis returns nodes allocated to a chassis path or its children
@@ -574,8 +687,8 @@ func NodesForChassis(nodes []Node, chassisPath string) []Node {
 	var result []Node
 	for _, node := range nodes {
 		for _, c := range node.Chassis {
-			// Match exact chassis path or children
-			if c == chassisPath || strings.HasPrefix(c, chassisPath+".") {
+			// Match exact chassis path or children, also inside expressions
+			if pkgchassis.ReferencesPath(c, chassisPath) {
 				result = append(result, node)
 				break
 			}
@@ -584,37 +697,44 @@ func NodesForChassis(nodes []Node, chassisPath string) []Node {
 	return result
 }
 
-// LoadNodesByPlatform groups nodes by their platform
+// LoadNodesByPlatform groups nodes by their platform. Like with
+// [LoadNodes], skipped node files are returned along with the nodes.
 func LoadNodesByPlatform(dir string) (map[string][]Node, error) {
 	result := make(map[string][]Node)
 
-	instDir := filepath.Join(dir, "inst")
-	entries, err := os.ReadDir(instDir)
+	platforms, err := Platforms(dir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return result, nil
-		}
-		return nil, fmt.Errorf("failed to read inst directory: %w", err)
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		nodes, err := loadNodesFromPlatform(instDir, entry.Name())
-		if err != nil {
-			continue
+	var skipped []error
+	for _, platform := range platforms {
+		nodes, err := loadNodesFromPlatform(dir, platform)
+		if nodeErrs, _ := SplitNodeFileErrors(err); nodeErrs != nil {
+			skipped = append(skipped, nodeErrs)
 		}
 		if len(nodes) > 0 {
-			result[entry.Name()] = nodes
+			result[platform] = nodes
 		}
 	}
 
-	return result, nil
+	return result, errors.Join(skipped...)
+}
+
+// nodesByPlatform groups nodes read by [ForEachNode] by their platform,
+// returning skipped node files along with them.
+func nodesByPlatform(dir string) (map[string][]Node, error) {
+	result := make(map[string][]Node)
+	err := ForEachNode(dir, "", func(n Node) error {
+		result[n.Platform] = append(result[n.Platform], n)
+		return nil
+	})
+	return result, err
 }
 
 // Rename renames a chassis path preserving YAML order
 func (c *Chassis) Rename(oldPath, newPath string) error {
+	c.expandAliases()
 	oldParts := strings.Split(oldPath, ".")
 	newParts := strings.Split(newPath, ".")
 
@@ -636,6 +756,14 @@ func (c *Chassis) Rename(oldPath, newPath string) error {
 	if diffIdx == -1 {
 		return fmt.Errorf("old and new paths are identical")
 	}
+	// Plays are looked up in the playbook of their layer, which a rename
+	// can't move
+	if layer := LayerOf(oldPath); layer != LayerOf(newPath) {
+		return fmt.Errorf("cannot rename layer %q: its plays would stay in the %s playbook", layer, layer)
+	}
+	if err := pkgchassis.CheckReserved(newPath, reservedNames); err != nil {
+		return err
+	}
 
 	// Update yaml.Node
 	node := c.YAMLNode()
@@ -649,6 +777,74 @@ func (c *Chassis) Rename(oldPath, newPath string) error {
 	return nil
 }
 
+// Merge moves the children of oldPath below the existing newPath and
+// removes oldPath. Children existing under both paths are merged
+// recursively; their paths below newPath are returned as conflicts.
+func (c *Chassis) Merge(oldPath, newPath string) ([]string, error) {
+	if !c.Exists(oldPath) {
+		return nil, fmt.Errorf("chassis path %q does not exist", oldPath)
+	}
+	if !c.Exists(newPath) {
+		return nil, fmt.Errorf("chassis path %q does not exist", newPath)
+	}
+	if oldPath == newPath || pkgchassis.IsDescendantOf(oldPath, newPath) || pkgchassis.IsDescendantOf(newPath, oldPath) {
+		return nil, fmt.Errorf("cannot merge %q and %q: one contains the other", oldPath, newPath)
+	}
+	if layer := LayerOf(oldPath); layer != LayerOf(newPath) {
+		return nil, fmt.Errorf("cannot merge %q out of layer %q: its plays would stay in the %s playbook", oldPath, layer, layer)
+	}
+	c.expandAliases()
+
+	var conflicts []string
+	for _, p := range c.FlattenWithPrefix(oldPath) {
+		if p == oldPath {
+			continue
+		}
+		target := newPath + p[len(oldPath):]
+		if c.Exists(target) {
+			conflicts = append(conflicts, target)
+			continue
+		}
+		if err := c.Add(target); err != nil {
+			return nil, err
+		}
+	}
+	return conflicts, c.Remove(oldPath)
+}
+
+// Move relocates oldPath with all its descendants below newParent, or to
+// the root if newParent is empty, and returns the new path. The subtree
+// keeps its order and is appended after the existing children.
+func (c *Chassis) Move(oldPath, newParent string) (string, error) {
+	if !c.Exists(oldPath) {
+		return "", fmt.Errorf("chassis path %q does not exist", oldPath)
+	}
+	if newParent != "" && !c.Exists(newParent) {
+		return "", fmt.Errorf("chassis path %q does not exist", newParent)
+	}
+	if newParent == oldPath || pkgchassis.IsDescendantOf(newParent, oldPath) {
+		return "", fmt.Errorf("cannot move %q below itself", oldPath)
+	}
+	if pkgchassis.Parent(oldPath) == newParent {
+		return "", fmt.Errorf("chassis path %q is already below %q", oldPath, newParent)
+	}
+	newPath := oldPath[strings.LastIndex(oldPath, ".")+1:]
+	if newParent != "" {
+		newPath = newParent + "." + newPath
+	}
+	if c.Exists(newPath) {
+		return "", fmt.Errorf("chassis path %q already exists", newPath)
+	}
+	c.expandAliases()
+
+	for _, p := range c.FlattenWithPrefix(oldPath) {
+		if err := c.Add(newPath + p[len(oldPath):]); err != nil {
+			return "", err
+		}
+	}
+	return newPath, c.Remove(oldPath)
+}
+
 // renameInNode recursively finds and renames the target segment in yaml.Node
 func renameInNode(node *yaml.Node, oldParts, newParts []string, diffIdx, depth int) bool {
 	if node == nil || depth >= len(oldParts) {
@@ -767,3 +963,32 @@ func renameInChassisData(chassis []interface{}, oldPath, newPath []string, diffI
 
 	return chassis
 }
+
+// Declared returns the allocation entries of the node for [pkgchassis.Allocate].
+func (n Node) Declared() pkgchassis.DeclaredNode {
+	return pkgchassis.DeclaredNode{Hostname: n.Hostname, Platform: n.Platform, File: n.File, Chassis: n.Chassis}
+}
+
+// DeclaredNodes returns the allocation entries of nodes for [pkgchassis.Allocate].
+func DeclaredNodes(nodes []Node) []pkgchassis.DeclaredNode {
+	declared := make([]pkgchassis.DeclaredNode, 0, len(nodes))
+	for _, n := range nodes {
+		declared = append(declared, n.Declared())
+	}
+	return declared
+}
+
+// Subtract returns values of a not present in b, preserving order.
+func Subtract(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+	var result []string
+	for _, v := range a {
+		if !inB[v] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
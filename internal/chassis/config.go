@@ -0,0 +1,61 @@
+package chassis
+
+import (
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// ConfigKey is the key of the chassis section in the plasmactl config file.
+const ConfigKey = "chassis"
+
+// ConfigFile is the plasmactl config file, relative to the repository root.
+const ConfigFile = ".plasmactl/config.yaml"
+
+// Config holds repository-level chassis settings read from .plasmactl/config.yaml
+//
+//	chassis:
+//	  distribution: nearest-leaf
+//	  limits:
+//	    max_depth: 12
+type Config struct {
+	// Distribution selects how direct node allocations spread over the tree.
+	Distribution pkgchassis.Strategy `yaml:"distribution"`
+	// Limits guards against pathological trees when adding paths.
+	Limits pkgchassis.Limits `yaml:"limits"`
+	// Templates are reusable subtree definitions, see [Template].
+	Templates map[string][]interface{} `yaml:"templates"`
+	// Layout describes how repository files are interpreted.
+	Layout Layout `yaml:"layout"`
+	// Policy configures lint rules of chassis:validate.
+	Policy Policy `yaml:"policy"`
+	// Export configures chassis:export formats.
+	Export ExportConfig `yaml:"export"`
+	// Lock configures the repository lock of mutating actions.
+	Lock LockConfig `yaml:"lock"`
+	// Display formats nodes and components in human-facing output.
+	Display DisplayConfig `yaml:"display"`
+	// Backup configures backups taken by mutating actions.
+	Backup BackupConfig `yaml:"backup"`
+	// Telemetry configures opt-in usage metrics.
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+	// Retry configures retries of file operations on network file systems.
+	Retry RetryConfig `yaml:"retry"`
+	// Facts configures the source of live node facts for chassis:show --facts.
+	Facts FactsConfig `yaml:"facts"`
+	// Strict makes every action run as with --strict.
+	Strict bool `yaml:"strict"`
+	// ReservedNames are segment names rejected in chassis paths,
+	// see [pkgchassis.DefaultReservedNames].
+	ReservedNames []string `yaml:"reserved_names"`
+}
+
+var strictByDefault bool
+
+// SetStrictByDefault installs the strict setting used by [StrictByDefault].
+func SetStrictByDefault(v bool) {
+	strictByDefault = v
+}
+
+// StrictByDefault reports whether actions run in strict mode without --strict.
+func StrictByDefault() bool {
+	return strictByDefault
+}
@@ -0,0 +1,97 @@
+package chassis
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DisplayConfig holds templates formatting nodes and components in
+// human-facing output. Structured results keep hostname@platform and
+// component names as is.
+//
+//	chassis:
+//	  display:
+//	    node: "{{.Hostname}}.{{.Platform}}.example.com"
+//	    component: "{{.Name}}{{with .Version}} v{{.}}{{end}}"
+type DisplayConfig struct {
+	// Node formats a node from .Hostname and .Platform.
+	// Empty uses hostname@platform.
+	Node string `yaml:"node"`
+	// Component formats a component from .Name and .Version, which may be
+	// empty. Empty uses name@version, or the name alone without a version.
+	Component string `yaml:"component"`
+}
+
+// Validate checks that the templates parse.
+func (d DisplayConfig) Validate() error {
+	_, _, err := d.templates()
+	return err
+}
+
+// templates parses the configured templates, nil for defaults.
+func (d DisplayConfig) templates() (node, component *template.Template, err error) {
+	if d.Node != "" {
+		if node, err = template.New("node").Option("missingkey=error").Parse(d.Node); err != nil {
+			return nil, nil, fmt.Errorf("invalid display node template: %w", err)
+		}
+	}
+	if d.Component != "" {
+		if component, err = template.New("component").Option("missingkey=error").Parse(d.Component); err != nil {
+			return nil, nil, fmt.Errorf("invalid display component template: %w", err)
+		}
+	}
+	return node, component, nil
+}
+
+var nodeTemplate, componentTemplate *template.Template
+
+// SetDisplayConfig installs the templates used by [NodeName] and [ComponentName].
+// Invalid templates are ignored, see [DisplayConfig.Validate].
+func SetDisplayConfig(d DisplayConfig) {
+	var err error
+	if nodeTemplate, componentTemplate, err = d.templates(); err != nil {
+		nodeTemplate, componentTemplate = nil, nil
+	}
+}
+
+// NodeName formats a node for human-facing output.
+func NodeName(hostname, platform string) string {
+	name := hostname + "@" + platform
+	if nodeTemplate == nil {
+		return name
+	}
+	data := struct{ Hostname, Platform string }{hostname, platform}
+	return execute(nodeTemplate, data, name)
+}
+
+// NodeNameOf formats a node given as hostname@platform for human-facing output.
+func NodeNameOf(id string) string {
+	i := strings.LastIndex(id, "@")
+	if i < 0 {
+		return id
+	}
+	return NodeName(id[:i], id[i+1:])
+}
+
+// ComponentName formats a component for human-facing output.
+func ComponentName(name, version string) string {
+	fallback := name
+	if version != "" {
+		fallback += "@" + version
+	}
+	if componentTemplate == nil {
+		return fallback
+	}
+	data := struct{ Name, Version string }{name, version}
+	return execute(componentTemplate, data, fallback)
+}
+
+// execute renders t, returning fallback if it fails.
+func execute(t *template.Template, data any, fallback string) string {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return fallback
+	}
+	return b.String()
+}
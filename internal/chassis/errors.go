@@ -0,0 +1,177 @@
+package chassis
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// PermissionError reports a file that couldn't be modified because of
+// missing permissions or a read-only file system.
+type PermissionError struct {
+	Path     string
+	Op       string // operation that failed: write or remove
+	ReadOnly bool   // the file system is mounted read-only
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *PermissionError) Error() string {
+	return e.reason() + "; " + e.Suggestion()
+}
+
+// reason describes the failure without the suggestion.
+func (e *PermissionError) reason() string {
+	if e.ReadOnly {
+		return fmt.Sprintf("cannot %s %s: read-only file system", e.Op, e.Path)
+	}
+	return fmt.Sprintf("cannot %s %s: write permission required", e.Op, e.Path)
+}
+
+// Unwrap returns the underlying error.
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// Suggestion tells the operator how to make the file writable.
+func (e *PermissionError) Suggestion() string {
+	if e.ReadOnly {
+		return "work on a writable checkout of the repository"
+	}
+	return fmt.Sprintf("grant write permission on %s and its directory %s to the current user (e.g. chown or chmod u+w)",
+		e.Path, filepath.Dir(e.Path))
+}
+
+// NodeFileError reports a node file that can't be read or parsed. Node
+// loaders skip such files and return them, joined, alongside the nodes
+// they could load.
+type NodeFileError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *NodeFileError) Error() string {
+	return fmt.Sprintf("skipped node file %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NodeFileError) Unwrap() error {
+	return e.Err
+}
+
+// SplitNodeFileErrors separates the node files err reports as skipped, a
+// soft failure, from the other errors it joins.
+func SplitNodeFileErrors(err error) (skipped, other error) {
+	if err == nil {
+		return nil, nil
+	}
+	var skippedErrs, otherErrs []error
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, e := range errs {
+		var nodeErr *NodeFileError
+		if errors.As(e, &nodeErr) {
+			skippedErrs = append(skippedErrs, e)
+		} else {
+			otherErrs = append(otherErrs, e)
+		}
+	}
+	return errors.Join(skippedErrs...), errors.Join(otherErrs...)
+}
+
+// FileError is a per-file failure reported in action results.
+type FileError struct {
+	File       string `json:"file"`
+	Error      string `json:"error"`
+	Suggestion string `json:"suggestion,omitempty"`
+	// Transient marks failures network file systems produce transiently,
+	// which may succeed when the action runs again.
+	Transient bool `json:"transient,omitempty"`
+}
+
+// FileErrors flattens an error, possibly joined from several files, into
+// per-file entries for action results.
+func FileErrors(err error) []FileError {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []FileError
+		for _, e := range joined.Unwrap() {
+			result = append(result, FileErrors(e)...)
+		}
+		return result
+	}
+
+	var nodeErr *NodeFileError
+	if errors.As(err, &nodeErr) {
+		return []FileError{{File: nodeErr.Path, Error: nodeErr.Err.Error()}}
+	}
+	var transientErr *TransientError
+	if errors.As(err, &transientErr) {
+		return []FileError{{File: transientErr.Path, Error: transientErr.Error(), Suggestion: transientErr.Suggestion(), Transient: true}}
+	}
+	var permErr *PermissionError
+	if errors.As(err, &permErr) {
+		return []FileError{{File: permErr.Path, Error: permErr.reason(), Suggestion: permErr.Suggestion()}}
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return []FileError{{File: pathErr.Path, Error: err.Error()}}
+	}
+	return []FileError{{Error: err.Error()}}
+}
+
+// checkPermission wraps err in a [PermissionError] if it is caused by
+// missing permissions or a read-only file system.
+func checkPermission(op, path string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, syscall.EROFS):
+		return &PermissionError{Path: path, Op: op, ReadOnly: true, Err: err}
+	case errors.Is(err, fs.ErrPermission):
+		return &PermissionError{Path: path, Op: op, Err: err}
+	default:
+		return err
+	}
+}
+
+// writeFile writes data to path, reporting permission problems as [PermissionError].
+// The file is backed up first while a backup is recorded, see [BeginBackup],
+// and journaled while a transaction is, see [BeginTransaction].
+// Transient failures are retried, see [RetryConfig].
+func writeFile(path string, data []byte) error {
+	return writeFileTraced(path, data, tracer.File)
+}
+
+// writeFileTraced is [writeFile] reporting retries to trace, for workers
+// recording their events to replay them in order.
+func writeFileTraced(path string, data []byte, trace func(path, event, detail string)) error {
+	if err := backupFile(path); err != nil {
+		return err
+	}
+	if err := journalFile(path); err != nil {
+		return err
+	}
+	return checkPermission("write", path, retryFileOp("write", path, trace, func() error {
+		return os.WriteFile(path, data, 0644)
+	}))
+}
+
+// ReplaceFile writes data to an existing file of the repository, like the
+// mutating actions do: the file is backed up first while a backup is
+// recorded, and the write is traced.
+func ReplaceFile(path string, data []byte) error {
+	if err := writeFile(path, data); err != nil {
+		return err
+	}
+	tracer.File(path, TraceWrite, "")
+	return nil
+}
@@ -0,0 +1,125 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExportConfig holds settings of chassis:export formats.
+//
+//	chassis:
+//	  export:
+//	    inventory:
+//	      host_vars: [ip:ansible_host, ansible_user]
+type ExportConfig struct {
+	Inventory InventoryConfig `yaml:"inventory"`
+	Snapshot  SnapshotConfig  `yaml:"snapshot"`
+	SSH       SSHConfig       `yaml:"ssh"`
+	DOT       DOTConfig       `yaml:"dot"`
+	// Obfuscation configures chassis:export --obfuscate.
+	Obfuscation ObfuscationConfig `yaml:"obfuscation"`
+}
+
+// DefaultObfuscationKeyEnv is the environment variable holding the
+// obfuscation key unless export.obfuscation.key_env is set.
+const DefaultObfuscationKeyEnv = "CHASSIS_OBFUSCATION_KEY"
+
+// ObfuscationConfig holds settings of obfuscated exports. The key itself
+// is kept out of the repository.
+//
+//	chassis:
+//	  export:
+//	    obfuscation:
+//	      key_env: VENDOR_REPORT_KEY
+type ObfuscationConfig struct {
+	// KeyEnv is the environment variable holding the HMAC key.
+	KeyEnv string `yaml:"key_env"`
+}
+
+// Key returns the obfuscation key from the environment.
+func (c ObfuscationConfig) Key() ([]byte, error) {
+	env := c.KeyEnv
+	if env == "" {
+		env = DefaultObfuscationKeyEnv
+	}
+	key := os.Getenv(env)
+	if key == "" {
+		return nil, fmt.Errorf("obfuscation requires a key in the %s environment variable", env)
+	}
+	return []byte(key), nil
+}
+
+// SSHConfig holds settings of the SSH config export.
+//
+//	chassis:
+//	  export:
+//	    ssh:
+//	      host_field: ip
+//	      user: ops
+//	      proxy_jump: bastion.example.com
+type SSHConfig struct {
+	// HostField is the node file field holding the address to connect to.
+	// Nodes without it are reached by hostname.
+	HostField string `yaml:"host_field"`
+	// User is the login user of every host, if set.
+	User string `yaml:"user"`
+	// ProxyJump is the jump host every connection goes through, if set.
+	ProxyJump string `yaml:"proxy_jump"`
+}
+
+// DOTConfig holds Graphviz attributes of the DOT export, added to or
+// overriding the default attributes of each kind of vertex.
+//
+//	chassis:
+//	  export:
+//	    dot:
+//	      graph: {rankdir: TB}
+//	      component: {fillcolor: lightyellow, style: filled}
+type DOTConfig struct {
+	Graph     map[string]string `yaml:"graph"`
+	Chassis   map[string]string `yaml:"chassis"`
+	Node      map[string]string `yaml:"node"`
+	Component map[string]string `yaml:"component"`
+}
+
+// SnapshotConfig holds the external commands used to sign and verify snapshots.
+//
+//	chassis:
+//	  export:
+//	    snapshot:
+//	      sign_command: gpg --detach-sign --armor
+//	      verify_command: gpg --verify {signature} -
+type SnapshotConfig struct {
+	// SignCommand reads the snapshot on stdin and writes a detached signature to stdout.
+	SignCommand string `yaml:"sign_command"`
+	// VerifyCommand reads the snapshot on stdin and exits non-zero if the
+	// signature file substituted for {signature} doesn't match. The path
+	// is passed as a quoted shell parameter, so {signature} must not be
+	// put in single quotes.
+	VerifyCommand string `yaml:"verify_command"`
+}
+
+// InventoryConfig holds settings of the Ansible inventory export.
+type InventoryConfig struct {
+	// HostVars lists node file fields embedded as host variables.
+	// An entry "field:var" renames the field in the inventory.
+	HostVars []string `yaml:"host_vars"`
+	// ExcludeQuarantined leaves quarantined nodes out of the inventory
+	// instead of listing them in the quarantined group, and out of every
+	// other format, like --exclude-quarantined.
+	ExcludeQuarantined bool `yaml:"exclude_quarantined"`
+}
+
+// HostVarMapping returns node file field → inventory variable for the allowlist.
+func (c InventoryConfig) HostVarMapping() map[string]string {
+	mapping := make(map[string]string, len(c.HostVars))
+	for _, entry := range c.HostVars {
+		field, name, ok := strings.Cut(entry, ":")
+		if !ok {
+			name = field
+		}
+		mapping[field] = name
+	}
+	return mapping
+}
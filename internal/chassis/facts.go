@@ -0,0 +1,151 @@
+package chassis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultFactsTimeout bounds a facts lookup unless facts.timeout is set.
+const DefaultFactsTimeout = 10 * time.Second
+
+// FactsConfig configures the external source of live node facts, such as
+// up/down state, IP address or serial number, shown by chassis:show --facts.
+// The source receives a [FactsRequest] and answers with [Facts].
+//
+//	chassis:
+//	  facts:
+//	    command: scripts/facts.sh
+//	    timeout: 5s
+type FactsConfig struct {
+	// Command is run through the shell in the repository, with the
+	// request on stdin, and writes the facts to stdout.
+	Command string `yaml:"command"`
+	// Endpoint receives the request as a JSON POST and answers with the facts.
+	Endpoint string `yaml:"endpoint"`
+	// Timeout bounds the lookup. Zero uses [DefaultFactsTimeout].
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Configured reports whether a facts source is set.
+func (f FactsConfig) Configured() bool {
+	return f.Command != "" || f.Endpoint != ""
+}
+
+// Validate checks that at most one source is set and the endpoint is an
+// http or https URL.
+func (f FactsConfig) Validate() error {
+	if f.Command != "" && f.Endpoint != "" {
+		return fmt.Errorf("facts: set either command or endpoint, not both")
+	}
+	if f.Timeout < 0 {
+		return fmt.Errorf("facts: negative timeout %s", f.Timeout)
+	}
+	if f.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(f.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid facts endpoint %q: expected an http or https URL", f.Endpoint)
+	}
+	return nil
+}
+
+// FactsRequest lists the nodes facts are requested for.
+//
+//	{"nodes": [{"hostname": "node1", "platform": "dev"}]}
+type FactsRequest struct {
+	Nodes []FactsNode `json:"nodes"`
+}
+
+// FactsNode identifies a node in a [FactsRequest].
+type FactsNode struct {
+	Hostname string `json:"hostname"`
+	Platform string `json:"platform"`
+}
+
+// Facts are live node facts by hostname@platform, as answered by the source.
+// Nodes the source knows nothing about are left out.
+//
+//	{"node1@dev": {"up": true, "ip": "10.0.0.1", "serial": "ABC123"}}
+type Facts map[string]map[string]any
+
+// LoadFacts asks the configured source for the facts of nodes.
+func LoadFacts(dir string, cfg FactsConfig, nodes []FactsNode) (Facts, error) {
+	if !cfg.Configured() {
+		return nil, errors.New("no facts source configured: set facts.command or facts.endpoint")
+	}
+	request, err := json.Marshal(FactsRequest{Nodes: nodes})
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultFactsTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var data []byte
+	if cfg.Command != "" {
+		data, err = factsFromCommand(ctx, dir, cfg.Command, request)
+	} else {
+		data, err = factsFromEndpoint(ctx, cfg.Endpoint, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var facts Facts
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse facts: %w", err)
+	}
+	return facts, nil
+}
+
+// factsFromCommand runs command through the shell with the request on stdin.
+func factsFromCommand(ctx context.Context, dir, command string, request []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait for children of the shell holding the output open
+	cmd.WaitDelay = 100 * time.Millisecond
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.New("facts command timed out")
+		}
+		return nil, fmt.Errorf("facts command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// factsFromEndpoint posts the request to endpoint.
+func factsFromEndpoint(ctx context.Context, endpoint string, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("facts endpoint failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return nil, fmt.Errorf("facts endpoint failed: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("facts endpoint returned %s", resp.Status)
+	}
+	return body.Bytes(), nil
+}
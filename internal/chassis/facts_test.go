@@ -0,0 +1,105 @@
+package chassis
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var factsNodes = []FactsNode{{Hostname: "node-1", Platform: "prod"}, {Hostname: "node-2", Platform: "prod"}}
+
+func TestLoadFactsEndpoint(t *testing.T) {
+	var got FactsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"node-1@prod": {"up": true, "ip": "10.0.0.1"}}`))
+	}))
+	defer server.Close()
+
+	facts, err := LoadFacts(t.TempDir(), FactsConfig{Endpoint: server.URL}, factsNodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Nodes, factsNodes) {
+		t.Errorf("requested %+v, want %+v", got.Nodes, factsNodes)
+	}
+	want := Facts{"node-1@prod": {"up": true, "ip": "10.0.0.1"}}
+	if !reflect.DeepEqual(facts, want) {
+		t.Errorf("facts = %v, want %v", facts, want)
+	}
+}
+
+func TestLoadFactsCommand(t *testing.T) {
+	dir := t.TempDir()
+	// The command runs in the repository with the request on stdin
+	command := `cat > request.json && echo '{"node-2@prod": {"serial": "ABC123"}}'`
+	facts, err := LoadFacts(dir, FactsConfig{Command: command}, factsNodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Facts{"node-2@prod": {"serial": "ABC123"}}
+	if !reflect.DeepEqual(facts, want) {
+		t.Errorf("facts = %v, want %v", facts, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "request.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request FactsRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(request.Nodes, factsNodes) {
+		t.Errorf("requested %+v, want %+v", request.Nodes, factsNodes)
+	}
+}
+
+func TestLoadFactsErrors(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-unblock
+			return
+		}
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	tests := []struct {
+		name string
+		cfg  FactsConfig
+		want string
+	}{
+		{"unconfigured", FactsConfig{}, "no facts source configured"},
+		{"command fails", FactsConfig{Command: "echo inventory unreachable >&2; exit 2"}, "facts command failed: exit status 2: inventory unreachable"},
+		{"command times out", FactsConfig{Command: "sleep 5", Timeout: 100 * time.Millisecond}, "facts command timed out"},
+		{"command output", FactsConfig{Command: "echo not json"}, "failed to parse facts"},
+		{"endpoint fails", FactsConfig{Endpoint: server.URL}, "facts endpoint returned 503 Service Unavailable"},
+		{"endpoint times out", FactsConfig{Endpoint: server.URL + "/slow", Timeout: 100 * time.Millisecond}, "facts endpoint failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			facts, err := LoadFacts(t.TempDir(), tt.cfg, factsNodes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, %v; want an error containing %q", facts, err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("failed after %s, want the timeout to stop the lookup", elapsed)
+			}
+		})
+	}
+}
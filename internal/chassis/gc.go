@@ -0,0 +1,160 @@
+package chassis
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// playSettingKeys are play keys that don't make a play do anything. Any
+// other key, including ones unknown here, counts as content.
+var playSettingKeys = []string{"hosts", "name", "vars", "gather_facts"}
+
+// playListKeys are play keys holding a list of roles or tasks, which don't
+// count as content while empty.
+var playListKeys = []string{"roles", "tasks", "pre_tasks", "post_tasks", "handlers"}
+
+// PrunablePaths returns empty chassis containers that can be removed, deepest
+// first: roots and layers declared empty, and with aggressive also nested
+// paths declared with an empty list (e.g., "- cluster: []"). Paths in keep
+// are never returned.
+func (c *Chassis) PrunablePaths(aggressive bool, keep func(chassisPath string) bool) []string {
+	var result []string
+	paths := c.Flatten()
+	for i := len(paths) - 1; i >= 0; i-- {
+		p := paths[i]
+		depth := strings.Count(p, ".") + 1
+		if depth > 2 && !aggressive {
+			continue
+		}
+		if !c.IsEmpty(p) || keep(p) {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// Prune removes empty chassis containers until none is left, since removing
+// the last child of a container leaves the container empty. It returns the
+// removed paths in removal order.
+func (c *Chassis) Prune(aggressive bool, keep func(chassisPath string) bool) ([]string, error) {
+	var removed []string
+	for {
+		candidates := c.PrunablePaths(aggressive, keep)
+		if len(candidates) == 0 {
+			return removed, nil
+		}
+		for _, p := range candidates {
+			if err := c.Remove(p); err != nil {
+				return removed, err
+			}
+			removed = append(removed, p)
+		}
+	}
+}
+
+// EmptyPlay is a play without roles or tasks found in a layer playbook.
+type EmptyPlay struct {
+	Playbook string `json:"playbook"`
+	Hosts    string `json:"hosts"`
+	Index    int    `json:"index"` // position of the play in the playbook
+}
+
+// PruneEmptyPlays removes plays without roles or tasks from all layer
+// playbooks, typically left behind when the last component was detached.
+// With dryRun no file is written.
+func PruneEmptyPlays(dir string, dryRun bool) ([]EmptyPlay, error) {
+	var pruned []EmptyPlay
+	var writeErrs []error
+
+	playbooks, err := PlaybookFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, playbookPath := range playbooks {
+		data, err := os.ReadFile(playbookPath)
+		if err != nil {
+			tracer.File(playbookPath, TraceSkip, err.Error())
+			continue
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			tracer.File(playbookPath, TraceSkip, "parse error: "+err.Error())
+			continue
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+			tracer.File(playbookPath, TraceSkip, "not a list of plays")
+			continue
+		}
+		tracer.File(playbookPath, TraceRead, "")
+
+		plays := doc.Content[0]
+		var kept []*yaml.Node
+		for i, play := range plays.Content {
+			if !isEmptyPlay(play) {
+				kept = append(kept, play)
+				continue
+			}
+			hosts := ""
+			if h := mappingValue(play, "hosts"); h != nil {
+				hosts = h.Value
+			}
+			pruned = append(pruned, EmptyPlay{Playbook: playbookPath, Hosts: hosts, Index: i})
+		}
+		if len(kept) == len(plays.Content) {
+			tracer.File(playbookPath, TraceNoMatch, "no empty plays")
+			continue
+		}
+		tracer.File(playbookPath, TraceMatch, "")
+		if dryRun {
+			continue
+		}
+
+		plays.Content = kept
+		newData, err := marshalDocument(&doc)
+		if err != nil {
+			tracer.File(playbookPath, TraceSkip, "marshal error: "+err.Error())
+			continue
+		}
+		if err := writeFile(playbookPath, newData); err != nil {
+			tracer.File(playbookPath, TraceSkip, "write error: "+err.Error())
+			writeErrs = append(writeErrs, err)
+			continue
+		}
+		tracer.File(playbookPath, TraceWrite, "")
+	}
+
+	return pruned, errors.Join(writeErrs...)
+}
+
+// isEmptyPlay reports whether a play mapping has nothing but settings and
+// empty lists of roles or tasks. Imports, in short or ansible.builtin form,
+// and keys unknown here keep a play.
+func isEmptyPlay(play *yaml.Node) bool {
+	if play.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(play.Content); i += 2 {
+		key, v := play.Content[i].Value, play.Content[i+1]
+		if slices.Contains(playSettingKeys, key) {
+			continue
+		}
+		if !slices.Contains(playListKeys, key) {
+			return false
+		}
+		if v.Kind == yaml.ScalarNode && v.Tag == "!!null" {
+			continue
+		}
+		if (v.Kind == yaml.SequenceNode || v.Kind == yaml.MappingNode) && len(v.Content) == 0 {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package chassis
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CheckoutRef extracts the repository state at a git ref into a temporary
+// directory, so it can be loaded like a working tree. dir may be a
+// subdirectory of the git repository; the extracted tree mirrors it.
+// The returned cleanup function removes the directory.
+func CheckoutRef(dir, ref string) (string, func(), error) {
+	// git archive only accepts trees of the whole repository when run at its
+	// top level, so address the subdirectory as <ref>:<prefix>.
+	out, err := runGit(dir, "rev-parse", "--show-toplevel", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+	lines := strings.SplitN(strings.TrimRight(out.String(), "\n"), "\n", 2)
+	top, prefix := lines[0], ""
+	if len(lines) > 1 {
+		prefix = lines[1]
+	}
+
+	archive, err := runGit(top, "archive", "--format=tar", ref+":"+prefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "chassis-ref-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	if err := extractTar(archive, tmp); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("extract %s: %w", ref, err)
+	}
+	return tmp, cleanup, nil
+}
+
+// runGit runs a git command in dir and returns its output.
+func runGit(dir string, args ...string) (*bytes.Buffer, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return &stdout, nil
+}
+
+// extractTar writes the directories and regular files of a tar stream below dest.
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dest)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package chassis
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PathReference is a chassis path token found in a YAML scalar.
+type PathReference struct {
+	File    string `json:"file"` // relative to the repository
+	Line    int    `json:"line"`
+	Path    string `json:"path"`
+	Context string `json:"context"` // line of the scalar holding the token
+}
+
+// GrepPaths searches the keys and values of every YAML file in dir, except
+// chassis.yaml and hidden directories, for tokens that look like chassis
+// paths: dotted names starting with one of roots, e.g. platform. References
+// are returned for tokens accepted by match, in file and line order.
+func GrepPaths(dir string, roots []string, match func(chassisPath string) bool) ([]PathReference, error) {
+	var refs []PathReference
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if path != dir && (strings.HasPrefix(name, ".") || ignored(dir, path, d.IsDir())) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || name == "chassis.yaml" || !isYAMLFile(name) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			tracer.File(path, TraceSkip, err.Error())
+			return nil
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			tracer.File(path, TraceSkip, "parse error: "+err.Error())
+			return nil
+		}
+		tracer.File(path, TraceRead, "")
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		found := grepScalars(&doc, roots, match)
+		if len(found) == 0 {
+			tracer.File(path, TraceNoMatch, "no matching path token")
+			return nil
+		}
+		tracer.File(path, TraceMatch, "")
+		for i := range found {
+			found[i].File = rel
+		}
+		refs = append(refs, found...)
+		return nil
+	})
+	return refs, err
+}
+
+// isYAMLFile reports whether a file name has a YAML extension.
+func isYAMLFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// grepScalars collects matching path tokens of every scalar below node.
+func grepScalars(node *yaml.Node, roots []string, match func(string) bool) []PathReference {
+	if node.Kind != yaml.ScalarNode {
+		var refs []PathReference
+		for _, child := range node.Content {
+			refs = append(refs, grepScalars(child, roots, match)...)
+		}
+		return refs
+	}
+
+	var refs []PathReference
+	for i, line := range strings.Split(node.Value, "\n") {
+		for _, token := range pathTokens(line, roots) {
+			if !match(token) {
+				continue
+			}
+			n := node.Line + i
+			if node.Style == yaml.LiteralStyle || node.Style == yaml.FoldedStyle {
+				// Block scalars start on the line after their indicator
+				n++
+			}
+			refs = append(refs, PathReference{Line: n, Path: token, Context: strings.TrimSpace(line)})
+		}
+	}
+	return refs
+}
+
+// pathTokens returns the dotted tokens of s starting with one of roots,
+// delimited by characters that can't be part of a path. File names such
+// as platform.yaml are not paths.
+func pathTokens(s string, roots []string) []string {
+	var tokens []string
+	for start := 0; start < len(s); {
+		if !isPathChar(s[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(s) && (isPathChar(s[end]) || s[end] == '.') {
+			end++
+		}
+		token := strings.TrimRight(s[start:end], ".")
+		start = end
+		root, rest, dotted := strings.Cut(token, ".")
+		if !dotted || rest == "" || isYAMLFile(token) {
+			continue
+		}
+		for _, r := range roots {
+			if root == r {
+				tokens = append(tokens, token)
+				break
+			}
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,166 @@
+package chassis
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ignoreRule is a parsed gitignore pattern.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreMatcher matches repository paths against gitignore patterns: those
+// of the .gitignore at the repository root followed by layout.ignore, so
+// the latter can re-include what git ignores. Like git, a path inside an
+// ignored directory is ignored too.
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+// NewIgnoreMatcher parses patterns in gitignore syntax. Blank lines and
+// comments are skipped.
+func NewIgnoreMatcher(patterns []string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, p := range patterns {
+		if r, ok := parseIgnoreRule(p); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m
+}
+
+// Ignored reports whether a path relative to the repository root is ignored.
+func (m *IgnoreMatcher) Ignored(rel string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range parts {
+		last := i == len(parts)-1
+		if m.match(strings.Join(parts[:i+1], "/"), !last || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// match applies the rules to a single path, the last matching rule winning.
+func (m *IgnoreMatcher) match(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if (!r.dirOnly || isDir) && r.re.MatchString(path) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// parseIgnoreRule converts a gitignore pattern into a rule.
+func parseIgnoreRule(pattern string) (ignoreRule, bool) {
+	var r ignoreRule
+	pattern = strings.TrimRight(pattern, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return r, false
+	}
+	if strings.HasPrefix(pattern, "!") {
+		r.negate, pattern = true, pattern[1:]
+	}
+	pattern = strings.TrimPrefix(pattern, `\`)
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly, pattern = true, strings.TrimRight(pattern, "/")
+	}
+	// A pattern without a slash, other than a trailing one, matches at any depth
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return r, false
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return r, false
+	}
+	r.re = re
+	return r, true
+}
+
+var ignoreMatchers sync.Map // repository dir → *IgnoreMatcher
+
+// repoIgnores returns the matcher of the repository at dir, read once per process.
+func repoIgnores(dir string) *IgnoreMatcher {
+	if m, ok := ignoreMatchers.Load(dir); ok {
+		return m.(*IgnoreMatcher)
+	}
+	var patterns []string
+	if f, err := os.Open(filepath.Join(dir, ".gitignore")); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			patterns = append(patterns, scanner.Text())
+		}
+		_ = f.Close()
+	}
+	patterns = append(patterns, layout.Ignore...)
+	m, _ := ignoreMatchers.LoadOrStore(dir, NewIgnoreMatcher(patterns))
+	return m.(*IgnoreMatcher)
+}
+
+// ignored reports whether path, below the repository at dir, is ignored,
+// tracing it as skipped if so.
+func ignored(dir, path string, isDir bool) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	if !repoIgnores(dir).Ignored(rel, isDir) {
+		return false
+	}
+	tracer.File(path, TraceSkip, "ignored")
+	return true
+}
@@ -0,0 +1,396 @@
+package chassis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/pkg/attachment"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// IndexFile is the persistent index, relative to the repository root. It is
+// a local cache and shouldn't be committed. Only mutating actions write it,
+// see [RefreshIndex].
+const IndexFile = ".plasmactl/index.json"
+
+// indexVersion is increased when the index layout changes; older indexes are rebuilt.
+const indexVersion = 3
+
+// Index records which chassis paths each node file and playbook references,
+// so targeted loaders read only the files relevant to a subtree, and the
+// parsed plays of each playbook, so unchanged playbooks aren't parsed again.
+// Each section is valid while the files it covers are unchanged, see [Index.NodesFresh].
+type Index struct {
+	Version   int                        `json:"version"`
+	Nodes     map[string]IndexedNode     `json:"nodes,omitempty"`
+	Playbooks map[string]IndexedPlaybook `json:"playbooks,omitempty"`
+}
+
+// FileStamp identifies the content of a file by modification time and size.
+type FileStamp struct {
+	ModTime int64 `json:"mtime"`
+	Size    int64 `json:"size"`
+}
+
+// IndexedNode is the index entry of a node file, keyed by its path relative to the repository.
+type IndexedNode struct {
+	FileStamp
+	Platform         string   `json:"platform"`
+	FileHostname     string   `json:"file_hostname"`
+	DeclaredHostname string   `json:"hostname,omitempty"`
+	Chassis          []string `json:"chassis,omitempty"`
+	// Unreadable marks files that couldn't be read or parsed, which are
+	// loaded again every time so they keep being reported.
+	Unreadable bool `json:"unreadable,omitempty"`
+}
+
+// Hostname returns the hostname of the node according to the current layout.
+func (n IndexedNode) Hostname() string {
+	if layout.TrustsYAMLHostname() && n.DeclaredHostname != "" {
+		return n.DeclaredHostname
+	}
+	return n.FileHostname
+}
+
+// IndexedPlaybook is the index entry of a playbook, keyed by its path relative to the repository.
+type IndexedPlaybook struct {
+	FileStamp
+	// Sum is the SHA-256 of the content Plays were parsed from, empty for
+	// playbooks that couldn't be parsed.
+	Sum   string            `json:"sha256,omitempty"`
+	Plays []attachment.Play `json:"plays,omitempty"`
+}
+
+// indexedPlays returns the plays the index holds for the playbook at path
+// with content hash sum, if any.
+func (idx *Index) indexedPlays(dir, path string, sum [sha256.Size]byte) ([]attachment.Play, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return nil, false
+	}
+	p, ok := idx.Playbooks[rel]
+	if !ok || p.Sum == "" || p.Sum != hex.EncodeToString(sum[:]) {
+		return nil, false
+	}
+	return p.Plays, true
+}
+
+// LoadIndex reads the persistent index of a repository. A missing, unreadable
+// or outdated index is returned empty.
+func LoadIndex(dir string) *Index {
+	idx := &Index{Version: indexVersion}
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return idx
+	}
+	var stored Index
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != indexVersion {
+		return idx
+	}
+	return &stored
+}
+
+// Save writes the index to the repository, replacing the previous one atomically.
+func (idx *Index) Save(dir string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, IndexFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// RefreshIndex brings the persistent index up to date, re-reading only the
+// node files and playbooks added or changed since it was written, and saves
+// it. It is called once a mutating action succeeded, while the repository is
+// locked; read-only actions use a fresh index but never write it.
+func RefreshIndex(dir string) error {
+	idx := LoadIndex(dir)
+	nodeStamps, err := stampFiles(dir, nodeFileGlob())
+	if err != nil {
+		return err
+	}
+	playbookStamps, err := stampFiles(dir, playbookGlob())
+	if err != nil {
+		return err
+	}
+	idx.refreshNodes(dir, nodeStamps)
+	idx.refreshPlaybooks(dir, playbookStamps)
+	return idx.Save(dir)
+}
+
+// NodesFresh reports whether the node section covers exactly the current node files, unchanged.
+func (idx *Index) NodesFresh(dir string) bool {
+	stamps, err := stampFiles(dir, nodeFileGlob())
+	if err != nil || idx.Nodes == nil || len(stamps) != len(idx.Nodes) {
+		return false
+	}
+	for rel, stamp := range stamps {
+		if n, ok := idx.Nodes[rel]; !ok || n.FileStamp != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// PlaybooksFresh reports whether the playbook section covers exactly the current playbooks, unchanged.
+func (idx *Index) PlaybooksFresh(dir string) bool {
+	stamps, err := stampFiles(dir, playbookGlob())
+	if err != nil || idx.Playbooks == nil || len(stamps) != len(idx.Playbooks) {
+		return false
+	}
+	for rel, stamp := range stamps {
+		if p, ok := idx.Playbooks[rel]; !ok || p.FileStamp != stamp {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeFileGlob returns the glob of the node files covered by the index,
+// relative to the repository. Only files with an extension of the layout
+// are covered.
+func nodeFileGlob() string {
+	return filepath.Join(layout.PlatformNodesDir("*"), "*")
+}
+
+// playbookGlob returns the glob of the playbooks covered by the index, like
+// [nodeFileGlob].
+func playbookGlob() string {
+	return filepath.Join(layout.PlaybooksDir(), "*", "*")
+}
+
+// stampFiles returns the stamps of the regular files matching a glob, keyed
+// by their path relative to dir. Playbooks are only matched as <layer>/<layer>.yaml.
+func stampFiles(dir, glob string) (map[string]FileStamp, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, glob))
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[string]FileStamp, len(matches))
+	for _, path := range matches {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		stem, ok := pkgchassis.TrimExtension(filepath.Base(rel), layout.FileExtensions())
+		if !ok || glob == playbookGlob() && stem != filepath.Base(filepath.Dir(rel)) {
+			continue
+		}
+		if repoIgnores(dir).Ignored(rel, false) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		stamps[rel] = FileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// refreshNodes updates the node section to the files of stamps, reading
+// those that changed. The stamps are taken before reading, so files changed
+// meanwhile make the index stale.
+func (idx *Index) refreshNodes(dir string, stamps map[string]FileStamp) {
+	nodes := make(map[string]IndexedNode, len(stamps))
+	for rel, stamp := range stamps {
+		if n, ok := idx.Nodes[rel]; ok && n.FileStamp == stamp && !n.Unreadable {
+			nodes[rel] = n
+			continue
+		}
+		platform := layout.PlatformOf(rel)
+		entry := IndexedNode{FileStamp: stamp, Platform: platform, FileHostname: fileStem(rel)}
+		if node, err := loadNodeFile(filepath.Join(dir, rel), platform, false); err == nil {
+			entry.DeclaredHostname = node.DeclaredHostname
+			entry.Chassis = node.Chassis
+		} else {
+			// Unparsable files are indexed without allocations
+			entry.Unreadable = true
+		}
+		nodes[rel] = entry
+	}
+	idx.Nodes = nodes
+}
+
+// refreshPlaybooks updates the playbook section to the files of stamps,
+// parsing those that changed.
+func (idx *Index) refreshPlaybooks(dir string, stamps map[string]FileStamp) {
+	playbooks := make(map[string]IndexedPlaybook, len(stamps))
+	for rel, stamp := range stamps {
+		if p, ok := idx.Playbooks[rel]; ok && p.FileStamp == stamp {
+			playbooks[rel] = p
+			continue
+		}
+		// Unparsable playbooks are indexed without plays
+		entry := IndexedPlaybook{FileStamp: stamp}
+		if data, err := os.ReadFile(filepath.Join(dir, rel)); err == nil {
+			if plays, err := attachment.ParsePlays(data); err == nil {
+				sum := sha256.Sum256(data)
+				entry.Sum = hex.EncodeToString(sum[:])
+				entry.Plays = plays
+			}
+		}
+		playbooks[rel] = entry
+	}
+	idx.Playbooks = playbooks
+}
+
+// LoadNodesForPath loads the nodes needed to compute the effective
+// allocations of chassisPath and its descendants: nodes allocated inside
+// the subtree or above it, and transitively the nodes sharing their paths,
+// since these change how allocations are distributed. Nodes are read from
+// the files the index lists; without a fresh index all nodes are loaded.
+// An empty chassisPath loads all nodes. Like with
+// [ForEachNode], nodes carry no Fields.
+func LoadNodesForPath(dir string, c *pkgchassis.Chassis, chassisPath string) (map[string][]Node, error) {
+	if chassisPath == "" {
+		return nodesByPlatform(dir)
+	}
+	return loadRelatedNodes(dir, c, func(IndexedNode) bool { return false }, []string{chassisPath})
+}
+
+// LoadNodesForHost loads the nodes named hostname, along with the nodes
+// needed to compute their effective allocations, see [LoadNodesForPath].
+func LoadNodesForHost(dir string, c *pkgchassis.Chassis, hostname string) (map[string][]Node, error) {
+	return loadRelatedNodes(dir, c, func(n IndexedNode) bool { return n.Hostname() == hostname }, nil)
+}
+
+// loadRelatedNodes loads the nodes selected by seed and those related to
+// seed paths or the paths of selected nodes, per platform. Skipped node
+// files are returned along with the nodes, see [LoadNodes].
+func loadRelatedNodes(dir string, c *pkgchassis.Chassis, seed func(IndexedNode) bool, seedPaths []string) (map[string][]Node, error) {
+	idx := LoadIndex(dir)
+	if !idx.NodesFresh(dir) {
+		return nodesByPlatform(dir)
+	}
+
+	byPlatform := make(map[string][]string)
+	for rel, n := range idx.Nodes {
+		byPlatform[n.Platform] = append(byPlatform[n.Platform], rel)
+	}
+
+	result := make(map[string][]Node)
+	var skipped []error
+	for platform, files := range byPlatform {
+		sort.Strings(files)
+		selected := relatedNodeFiles(c, idx.Nodes, files, seed, seedPaths)
+		for _, rel := range selected {
+			node, err := loadNodeFile(filepath.Join(dir, rel), platform, false)
+			if err != nil {
+				skipped = append(skipped, err)
+				continue
+			}
+			result[platform] = append(result[platform], node)
+		}
+	}
+	return result, errors.Join(skipped...)
+}
+
+// relatedNodeFiles returns the files selected by seed or allocated to a
+// path related to the paths of interest, which grow with every selected
+// node until no further node is related. Unreadable files are always
+// selected. Files keep their order.
+func relatedNodeFiles(c *pkgchassis.Chassis, nodes map[string]IndexedNode, files []string, seed func(IndexedNode) bool, interest []string) []string {
+	paths := make(map[string][]string, len(files))
+	for _, rel := range files {
+		entries := nodes[rel].Chassis
+		for _, e := range entries {
+			for _, t := range pkgchassis.Terms(e) {
+				if t = strings.TrimPrefix(t, "!"); !strings.Contains(t, "*") {
+					paths[rel] = append(paths[rel], t)
+				}
+			}
+		}
+		paths[rel] = append(paths[rel], c.ExpandAllocations(entries)...)
+	}
+
+	selected := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, rel := range files {
+			if selected[rel] || !(nodes[rel].Unreadable || seed(nodes[rel]) || relatedPaths(paths[rel], interest)) {
+				continue
+			}
+			selected[rel] = true
+			interest = append(interest, paths[rel]...)
+			changed = true
+		}
+	}
+
+	var result []string
+	for _, rel := range files {
+		if selected[rel] {
+			result = append(result, rel)
+		}
+	}
+	return result
+}
+
+// relatedPaths reports whether a path of a equals, contains or is contained in a path of b.
+func relatedPaths(a, b []string) bool {
+	for _, p := range a {
+		for _, q := range b {
+			if p == q || pkgchassis.IsDescendantOf(p, q) || pkgchassis.IsDescendantOf(q, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relatedPlaybooks returns the playbooks the index lists with plays
+// targeting chassisPath or its descendants, tracing the others.
+func (idx *Index) relatedPlaybooks(dir string, playbooks []string, chassisPath string) []string {
+	related := []string{}
+	for _, path := range playbooks {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			related = append(related, path)
+			continue
+		}
+		targeted := false
+		for _, play := range idx.Playbooks[rel].Plays {
+			if attachment.Targets(play.Hosts, chassisPath) {
+				targeted = true
+				break
+			}
+		}
+		if !targeted {
+			tracer.File(path, TraceNoMatch, "no play targets "+chassisPath+" (indexed)")
+			continue
+		}
+		related = append(related, path)
+	}
+	return related
+}
+
+// fileStem returns the file name of a path without its layout extension.
+func fileStem(path string) string {
+	stem, _ := pkgchassis.TrimExtension(filepath.Base(path), layout.FileExtensions())
+	return stem
+}
@@ -0,0 +1,114 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// indexRepo copies testdata/merge, which has node files and a playbook,
+// and loads its chassis.
+func indexRepo(t *testing.T) (string, *Chassis) {
+	t.Helper()
+	dir := mergeRepo(t)
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, c
+}
+
+func TestReadOnlyLoadersDontWriteIndex(t *testing.T) {
+	dir, c := indexRepo(t)
+	if _, err := LoadNodesForPath(dir, c.Chassis, "platform.foundation"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAttachments(dir, "platform"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, IndexFile)); !os.IsNotExist(err) {
+		t.Errorf("loaders wrote %s", IndexFile)
+	}
+}
+
+func TestRefreshIndex(t *testing.T) {
+	dir, _ := indexRepo(t)
+	if err := RefreshIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	idx := LoadIndex(dir)
+	if !idx.NodesFresh(dir) || !idx.PlaybooksFresh(dir) {
+		t.Fatal("index is stale right after RefreshIndex")
+	}
+	var hosts []string
+	for _, play := range idx.Playbooks["src/foundation/foundation.yaml"].Plays {
+		hosts = append(hosts, play.Hosts)
+	}
+	if want := []string{"platform.foundation.cluster", "platform.foundation.storage.kv"}; !slices.Equal(hosts, want) {
+		t.Errorf("indexed hosts = %v, want %v", hosts, want)
+	}
+
+	// Only the changed node file is read again
+	changed := filepath.Join(dir, "inst", "prod", "nodes", "node-2.yaml")
+	if err := os.WriteFile(changed, []byte("hostname: node-2\nchassis:\n  - platform.foundation.cluster.nodes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tracer := &recordingTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+	if err := RefreshIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{changed + " " + TraceRead + " "}; !slices.Equal(tracer.events, want) {
+		t.Errorf("refresh read %v, want %v", tracer.events, want)
+	}
+
+	entry := LoadIndex(dir).Nodes[filepath.Join("inst", "prod", "nodes", "node-2.yaml")]
+	if want := []string{"platform.foundation.cluster.nodes"}; !slices.Equal(entry.Chassis, want) {
+		t.Errorf("indexed chassis = %v, want %v", entry.Chassis, want)
+	}
+}
+
+func TestLoadAttachmentsUsesIndexedPlays(t *testing.T) {
+	dir, _ := indexRepo(t)
+	if err := RefreshIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	playbook := filepath.Join(dir, "src", "foundation", "foundation.yaml")
+
+	load := func() []string {
+		t.Helper()
+		// Start without plays parsed earlier in the process, like a new invocation
+		playbookCache.Lock()
+		playbookCache.entries = make(map[string]cachedPlaybook)
+		playbookCache.Unlock()
+		tracer := &recordingTracer{}
+		SetTracer(tracer)
+		defer SetTracer(nil)
+		attachments, err := LoadAttachments(dir, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attachments) == 0 {
+			t.Fatal("no attachments loaded")
+		}
+		return tracer.events
+	}
+
+	if events := load(); !slices.Contains(events, playbook+" "+TraceRead+" indexed") {
+		t.Errorf("unchanged playbook wasn't read from the index: %v", events)
+	}
+
+	// A changed playbook is parsed again, even with an outdated index
+	data, err := os.ReadFile(playbook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(playbook, append(data, "\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if events := load(); !slices.Contains(events, playbook+" "+TraceRead+" ") {
+		t.Errorf("changed playbook wasn't parsed: %v", events)
+	}
+}
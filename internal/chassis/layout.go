@@ -0,0 +1,184 @@
+package chassis
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Hostname sources for node files.
+const (
+	// HostnameFromFilename derives the hostname from the node file name, <hostname>.yaml.
+	HostnameFromFilename = "filename"
+	// HostnameFromYAML uses the hostname field declared in the node file,
+	// falling back to the filename when it is absent.
+	HostnameFromYAML = "yaml"
+)
+
+// Directories of the default layout, relative to the repository root.
+const (
+	// PlatformPlaceholder stands for the platform directory in [Layout.Nodes].
+	PlatformPlaceholder = "<platform>"
+	// DefaultNodesDir holds the node files of a platform.
+	DefaultNodesDir = "inst/" + PlatformPlaceholder + "/nodes"
+	// DefaultPlaybooksDir holds a directory per layer with its playbook.
+	DefaultPlaybooksDir = pkgchassis.DefaultPlaybooksDir
+)
+
+// Layout describes how repository files are interpreted.
+//
+//	chassis:
+//	  layout:
+//	    nodes: environments/<platform>/hosts
+//	    playbooks: layers
+//	    hostname: yaml
+//	    strict_scalars: true
+//	    extensions: [.yaml, .yml]
+//	    ignore: ["*.bak", "src/examples/"]
+type Layout struct {
+	// Nodes is the directory holding the node files of a platform, with
+	// [PlatformPlaceholder] as one of its segments. Defaults to
+	// [DefaultNodesDir].
+	Nodes string `yaml:"nodes"`
+	// Playbooks is the directory holding <layer>/<layer>.yaml, the playbook
+	// of each layer. Defaults to [DefaultPlaybooksDir].
+	Playbooks string `yaml:"playbooks"`
+	// Hostname selects where node hostnames come from, see [HostnameFromFilename].
+	Hostname string `yaml:"hostname"`
+	// StrictScalars makes loading chassis.yaml fail on segments with stray
+	// whitespace or quotes instead of normalizing them.
+	StrictScalars bool `yaml:"strict_scalars"`
+	// Extensions are the accepted extensions of node files and playbooks,
+	// in order of preference; new files get the first. Defaults to
+	// [pkgchassis.DefaultExtensions].
+	Extensions []string `yaml:"extensions"`
+	// Ignore lists gitignore patterns of files and directories loaders
+	// skip, in addition to those of the repository's .gitignore.
+	Ignore []string `yaml:"ignore"`
+}
+
+// Validate checks the layout settings.
+func (l Layout) Validate() error {
+	if err := validLayoutDir("nodes", l.Nodes); err != nil {
+		return err
+	}
+	if segments := strings.Split(l.Nodes, "/"); l.Nodes != "" &&
+		(strings.Count(l.Nodes, PlatformPlaceholder) != 1 || !slices.Contains(segments[1:], PlatformPlaceholder)) {
+		return fmt.Errorf("invalid layout nodes %q: expected %s as one segment below a directory, e.g. %s",
+			l.Nodes, PlatformPlaceholder, DefaultNodesDir)
+	}
+	if err := validLayoutDir("playbooks", l.Playbooks); err != nil {
+		return err
+	}
+	for _, ext := range l.Extensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 || strings.ContainsRune(ext, '/') {
+			return fmt.Errorf("invalid layout extension %q (e.g. .yaml)", ext)
+		}
+	}
+	switch l.Hostname {
+	case "", HostnameFromFilename, HostnameFromYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown layout hostname source %q (supported: %s, %s)",
+			l.Hostname, HostnameFromFilename, HostnameFromYAML)
+	}
+}
+
+// validLayoutDir checks that a directory setting is a clean relative path
+// inside the repository.
+func validLayoutDir(name, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if path.IsAbs(dir) || path.Clean(dir) != dir || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		return fmt.Errorf("invalid layout %s %q: expected a directory relative to the repository root", name, dir)
+	}
+	return nil
+}
+
+// NodesDir returns the directory of node files with [PlatformPlaceholder],
+// slash-separated.
+func (l Layout) NodesDir() string {
+	if l.Nodes == "" {
+		return DefaultNodesDir
+	}
+	return l.Nodes
+}
+
+// PlatformsDir returns the directory holding a directory per platform, the
+// part of [Layout.NodesDir] before [PlatformPlaceholder].
+func (l Layout) PlatformsDir() string {
+	before, _, _ := strings.Cut(l.NodesDir(), "/"+PlatformPlaceholder)
+	return filepath.FromSlash(before)
+}
+
+// PlatformNodesDir returns the directory of the node files of platform.
+// A platform of "*" gives a glob pattern matching every platform.
+func (l Layout) PlatformNodesDir(platform string) string {
+	return filepath.FromSlash(strings.Replace(l.NodesDir(), PlatformPlaceholder, platform, 1))
+}
+
+// PlatformOf returns the platform of a node file path relative to the
+// repository root, as matched by [Layout.PlatformNodesDir].
+func (l Layout) PlatformOf(rel string) string {
+	i := slices.Index(strings.Split(l.NodesDir(), "/"), PlatformPlaceholder)
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	if i < 0 || i >= len(segments) {
+		return ""
+	}
+	return segments[i]
+}
+
+// PlaybooksDir returns the directory of layer playbooks.
+func (l Layout) PlaybooksDir() string {
+	if l.Playbooks == "" {
+		return DefaultPlaybooksDir
+	}
+	return filepath.FromSlash(l.Playbooks)
+}
+
+// WatchLayout returns the files a [pkgchassis.Watcher] polls in this layout.
+func (l Layout) WatchLayout() pkgchassis.WatchLayout {
+	return pkgchassis.WatchLayout{
+		Nodes:      filepath.ToSlash(l.PlatformNodesDir("*")),
+		Playbooks:  filepath.ToSlash(l.PlaybooksDir()),
+		Extensions: l.FileExtensions(),
+	}
+}
+
+// NewWatcher returns a [pkgchassis.Watcher] polling the chassis, node and
+// playbook files of the current layout below dir every interval.
+func NewWatcher(dir string, interval time.Duration) *pkgchassis.Watcher {
+	return pkgchassis.NewWatcher(dir, interval, layout.WatchLayout())
+}
+
+// FileExtensions returns the accepted extensions of node files and playbooks.
+func (l Layout) FileExtensions() []string {
+	if len(l.Extensions) == 0 {
+		return pkgchassis.DefaultExtensions
+	}
+	return l.Extensions
+}
+
+// TrustsYAMLHostname reports whether node hostnames are read from the YAML field.
+func (l Layout) TrustsYAMLHostname() bool {
+	return l.Hostname == HostnameFromYAML
+}
+
+var layout Layout
+
+// SetLayout installs the layout used by node loaders.
+func SetLayout(l Layout) {
+	layout = l
+	ignoreMatchers.Clear()
+}
+
+// CurrentLayout returns the layout used by node loaders.
+func CurrentLayout() Layout {
+	return layout
+}
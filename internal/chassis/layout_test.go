@@ -0,0 +1,82 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayoutValidateDirectories(t *testing.T) {
+	tests := []struct {
+		name  string
+		l     Layout
+		valid bool
+	}{
+		{"default", Layout{}, true},
+		{"nodes", Layout{Nodes: "environments/<platform>/hosts"}, true},
+		{"nodes-last", Layout{Nodes: "platforms/<platform>"}, true},
+		{"playbooks", Layout{Playbooks: "layers/ansible"}, true},
+		{"no-placeholder", Layout{Nodes: "inst/nodes"}, false},
+		{"two-placeholders", Layout{Nodes: "inst/<platform>/<platform>"}, false},
+		{"placeholder-first", Layout{Nodes: "<platform>/nodes"}, false},
+		{"placeholder-part", Layout{Nodes: "inst/p-<platform>/nodes"}, false},
+		{"absolute", Layout{Playbooks: "/srv/src"}, false},
+		{"outside", Layout{Playbooks: "../src"}, false},
+		{"unclean", Layout{Nodes: "inst/<platform>/nodes/"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.l.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestLayoutDirectories(t *testing.T) {
+	dir := mergeRepo(t)
+	moves := map[string]string{
+		"inst/prod/nodes": "environments/prod/hosts",
+		"src/foundation":  "layers/foundation",
+	}
+	for old, dst := range moves {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, dst)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(filepath.Join(dir, old), filepath.Join(dir, dst)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	layout := CurrentLayout()
+	defer SetLayout(layout)
+	SetLayout(Layout{Nodes: "environments/<platform>/hosts", Playbooks: "layers"})
+
+	nodes, err := LoadNodes(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[0].Platform != "prod" {
+		t.Errorf("LoadNodes = %+v, want 2 nodes of prod", nodes)
+	}
+	if got, want := NodeFile(dir, "prod", "node-1"), filepath.Join(dir, "environments", "prod", "hosts", "node-1.yaml"); got != want {
+		t.Errorf("NodeFile = %s, want %s", got, want)
+	}
+	attachments, err := LoadAttachments(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) == 0 {
+		t.Error("LoadAttachments found no attachments in layers/")
+	}
+
+	if err := RefreshIndex(dir); err != nil {
+		t.Fatal(err)
+	}
+	idx := LoadIndex(dir)
+	if n := idx.Nodes[filepath.Join("environments", "prod", "hosts", "node-1.yaml")]; n.Platform != "prod" {
+		t.Errorf("indexed node-1 = %+v, want platform prod", n)
+	}
+	if _, ok := idx.Playbooks[filepath.Join("layers", "foundation", "foundation.yaml")]; !ok {
+		t.Errorf("index lacks layers/foundation/foundation.yaml: %v", idx.Playbooks)
+	}
+}
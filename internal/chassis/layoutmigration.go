@@ -0,0 +1,249 @@
+package chassis
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// LayoutMigration lists the file changes converting a repository from the
+// current layout to another, see [PlanLayoutMigration]. Paths are relative
+// to the repository.
+type LayoutMigration struct {
+	// Layout is the target layout, written to the config file.
+	Layout Layout `json:"-"`
+	// Moves rename node files and playbooks to the target extension, and
+	// node files to the hostname they declare when hostnames come from
+	// filenames. Node files are moved to the target node directories, then
+	// layer directories to the target playbook directory.
+	Moves []FileMove `json:"moves"`
+	// Hostnames are node files given a hostname field matching their
+	// filename, so hostnames read from the field don't change.
+	Hostnames []HostnameField `json:"hostnames"`
+}
+
+// HostnameField is a hostname field written to a node file.
+type HostnameField struct {
+	File     string `json:"file"`
+	Hostname string `json:"hostname"`
+}
+
+// Empty reports whether no file needs to change.
+func (m *LayoutMigration) Empty() bool {
+	return len(m.Moves) == 0 && len(m.Hostnames) == 0
+}
+
+// PlanLayoutMigration lists the changes converting the repository at dir
+// from the current layout to target, keeping every node's hostname. Node
+// files and playbooks are renamed to the first extension of target when it
+// differs from the current one. Switching the hostname source renames
+// node files declaring another hostname to it, or writes their filename
+// into the hostname field. Node files are moved to the node directory of
+// their platform in target, and layer directories with their playbook and
+// everything else to its playbook directory. It fails if two files would
+// get the same name or a node file can't be parsed.
+func PlanLayoutMigration(dir string, target Layout) (*LayoutMigration, error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	current := CurrentLayout()
+	ext := ""
+	if primary := target.FileExtensions()[0]; primary != current.FileExtensions()[0] {
+		ext = primary
+	}
+	m := &LayoutMigration{Layout: target, Moves: []FileMove{}, Hostnames: []HostnameField{}}
+	taken := make(map[string]string)
+	addMove := func(path, newPath string) error {
+		if newPath == path {
+			return nil
+		}
+		if prev, ok := taken[newPath]; ok {
+			return fmt.Errorf("cannot migrate %s: %s is also renamed to %s", path, prev, newPath)
+		}
+		if _, err := os.Lstat(newPath); err == nil {
+			return fmt.Errorf("cannot migrate %s: %s already exists", path, newPath)
+		}
+		taken[newPath] = path
+		oldRel, _ := filepath.Rel(dir, path)
+		newRel, _ := filepath.Rel(dir, newPath)
+		m.Moves = append(m.Moves, FileMove{Old: oldRel, New: newRel})
+		return nil
+	}
+
+	err := ForEachNode(dir, "", func(n Node) error {
+		stem := n.FileHostname
+		declared := n.DeclaredHostname != "" && n.DeclaredHostname != n.FileHostname
+		switch {
+		case declared && current.TrustsYAMLHostname() && !target.TrustsYAMLHostname():
+			if strings.ContainsAny(n.DeclaredHostname, `/\`) {
+				return fmt.Errorf("cannot migrate %s: hostname %q can't be a filename", n.File, n.DeclaredHostname)
+			}
+			stem = n.DeclaredHostname
+		case declared && !current.TrustsYAMLHostname() && target.TrustsYAMLHostname():
+			rel, _ := filepath.Rel(dir, n.File)
+			m.Hostnames = append(m.Hostnames, HostnameField{File: rel, Hostname: n.FileHostname})
+		}
+		newExt := filepath.Ext(n.File)
+		if ext != "" {
+			newExt = ext
+		}
+		return addMove(n.File, filepath.Join(dir, target.PlatformNodesDir(n.Platform), stem+newExt))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ext != "" {
+		playbooks, err := PlaybookFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range playbooks {
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			stem := strings.TrimSuffix(path, filepath.Ext(path))
+			if err := addMove(path, stem+ext); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Layer directories move after their playbook was renamed in place
+	if srcDir, newDir := current.PlaybooksDir(), target.PlaybooksDir(); srcDir != newDir {
+		layers, err := subDirs(dir, srcDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, layer := range layers {
+			path, newPath := filepath.Join(dir, srcDir, layer), filepath.Join(dir, newDir, layer)
+			if strings.HasPrefix(newPath, path+string(filepath.Separator)) {
+				return nil, fmt.Errorf("cannot migrate %s: %s is inside it", path, newPath)
+			}
+			if err := addMove(path, newPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// ApplyLayoutMigration writes the hostname fields, then moves the files of
+// m below dir, with git mv if useGit is set, and removes the directories
+// left empty. Files that fail don't stop the others; their errors are
+// joined in the returned error alongside the moves applied.
+func ApplyLayoutMigration(dir string, m *LayoutMigration, useGit bool) ([]FileMove, error) {
+	var errs []error
+	for _, h := range m.Hostnames {
+		if err := setNodeHostname(filepath.Join(dir, h.File), h.Hostname); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	moved, err := MoveFiles(dir, m.Moves, useGit)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, mv := range moved {
+		removeEmptyParents(dir, filepath.Dir(mv.Old))
+	}
+	return moved, errors.Join(errs...)
+}
+
+// removeEmptyParents removes rel, a directory of the repository at dir, and
+// its parents as long as they are empty.
+func removeEmptyParents(dir, rel string) {
+	for ; rel != "." && rel != string(filepath.Separator); rel = filepath.Dir(rel) {
+		if err := os.Remove(filepath.Join(dir, rel)); err != nil {
+			return
+		}
+	}
+}
+
+// setNodeHostname sets the hostname field of a node file, keeping the
+// rest of the file as is.
+func setNodeHostname(path, hostname string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse %s: not a mapping", path)
+	}
+	setScalar(doc.Content[0], "hostname", hostname)
+	out, err := marshalDocument(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := writeFile(path, out); err != nil {
+		return err
+	}
+	tracer.File(path, TraceWrite, "hostname: "+hostname)
+	return nil
+}
+
+// SetLayoutConfig writes the directories, hostname source and extensions
+// of layout to the config file of the repository at dir, keeping the rest of the file
+// as is. Default settings are removed rather than written.
+func SetLayoutConfig(dir string, l Layout) error {
+	path := filepath.Join(dir, ConfigFile)
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", ConfigFile, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	settings := ensureMapping(ensureMapping(doc.Content[0], ConfigKey), "layout")
+	if l.NodesDir() == DefaultNodesDir {
+		deleteKey(settings, "nodes")
+	} else {
+		setScalar(settings, "nodes", l.Nodes)
+	}
+	if l.Playbooks == "" || l.Playbooks == DefaultPlaybooksDir {
+		deleteKey(settings, "playbooks")
+	} else {
+		setScalar(settings, "playbooks", l.Playbooks)
+	}
+	if l.Hostname == "" || l.Hostname == HostnameFromFilename {
+		deleteKey(settings, "hostname")
+	} else {
+		setScalar(settings, "hostname", l.Hostname)
+	}
+	deleteKey(settings, "extensions")
+	if len(l.Extensions) > 0 && !slices.Equal(l.Extensions, pkgchassis.DefaultExtensions) {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, ext := range l.Extensions {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: ext})
+		}
+		settings.Content = append(settings.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "extensions"}, seq)
+	}
+
+	out, err := marshalDocument(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", ConfigFile, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return checkPermission("create", filepath.Dir(path), err)
+	}
+	if err := writeFile(path, out); err != nil {
+		return err
+	}
+	tracer.File(path, TraceWrite, "")
+	return nil
+}
@@ -0,0 +1,167 @@
+package chassis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockFile is the lock file mutating actions hold, relative to the repository.
+const LockFile = ".plasmactl/chassis.lock"
+
+// DefaultLockTimeout is how long a mutating action waits for the repository lock.
+const DefaultLockTimeout = 30 * time.Second
+
+// lockPoll is the interval between attempts to take a held lock.
+const lockPoll = 100 * time.Millisecond
+
+// LockConfig holds settings of the repository lock.
+//
+//	chassis:
+//	  lock:
+//	    timeout: 2m
+type LockConfig struct {
+	// Timeout is how long to wait for a lock held by another invocation.
+	// Zero uses [DefaultLockTimeout]; a negative value fails immediately.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+var lockTimeout = DefaultLockTimeout
+
+// SetLockConfig installs the lock settings used by [AcquireLock].
+func SetLockConfig(c LockConfig) {
+	switch {
+	case c.Timeout == 0:
+		lockTimeout = DefaultLockTimeout
+	case c.Timeout < 0:
+		lockTimeout = 0
+	default:
+		lockTimeout = c.Timeout
+	}
+}
+
+// LockHolder identifies the invocation holding the repository lock.
+type LockHolder struct {
+	PID    int       `json:"pid"`
+	Action string    `json:"action"`
+	Host   string    `json:"host"`
+	Since  time.Time `json:"since"`
+}
+
+// LockTimeoutError reports a repository lock that wasn't released in time.
+// Holder is zero if the holder had not recorded itself yet, or was
+// releasing the lock.
+type LockTimeoutError struct {
+	Path   string
+	Holder LockHolder
+	Waited time.Duration
+}
+
+// Error implements the error interface.
+func (e *LockTimeoutError) Error() string {
+	if e.Holder == (LockHolder{}) {
+		return fmt.Sprintf("repository is locked by another invocation, not recorded in %s yet; gave up after %s",
+			e.Path, e.Waited.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("repository is locked by %s (pid %d on %s, since %s); gave up after %s",
+		e.Holder.Action, e.Holder.PID, e.Holder.Host, e.Holder.Since.Format(time.RFC3339),
+		e.Waited.Round(time.Millisecond))
+}
+
+// LockCorruptError reports a held lock whose holder can't be read.
+type LockCorruptError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *LockCorruptError) Error() string {
+	return fmt.Sprintf("repository is locked, but the holder recorded in %s can't be read: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error reading the lock.
+func (e *LockCorruptError) Unwrap() error {
+	return e.Err
+}
+
+// AcquireLock takes the lock of the repository at dir for actionName,
+// waiting for other invocations to release it up to the configured timeout.
+// The lock is an advisory lock of the operating system on [LockFile], so it
+// is released when its holder exits, even if it crashed; the file itself is
+// kept and only records the holder for waiting invocations. A holder that
+// can't be read by the timeout is reported as corrupt, unless the record is
+// empty, as between taking the lock and recording it. The returned function
+// releases the lock.
+func AcquireLock(dir, actionName string) (func(), error) {
+	path := filepath.Join(dir, LockFile)
+	host, _ := os.Hostname()
+	data, err := json.Marshal(LockHolder{PID: os.Getpid(), Action: actionName, Host: host, Since: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, checkPermission("write", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, checkPermission("write", path, err)
+	}
+
+	start := time.Now()
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+		if waited := time.Since(start); waited >= lockTimeout {
+			_ = f.Close()
+			holder, err := readLock(path)
+			if err != nil {
+				return nil, &LockCorruptError{Path: path, Err: err}
+			}
+			return nil, &LockTimeoutError{Path: path, Holder: holder, Waited: waited}
+		}
+		time.Sleep(lockPoll)
+	}
+
+	if err := writeHolder(f, data); err != nil {
+		releaseLock(f)
+		return nil, fmt.Errorf("write lock %s: %w", path, err)
+	}
+	return func() { releaseLock(f) }, nil
+}
+
+// writeHolder replaces the holder recorded in the locked file f. The
+// record is written over the previous one before the rest is cut off, so
+// the file is never empty in between.
+func writeHolder(f *os.File, data []byte) error {
+	if _, err := f.WriteAt(data, 0); err != nil {
+		return err
+	}
+	return f.Truncate(int64(len(data)))
+}
+
+// releaseLock clears the holder recorded in the locked file f, then
+// releases the lock and closes f.
+func releaseLock(f *os.File) {
+	_ = f.Truncate(0)
+	_ = unlockFile(f)
+	_ = f.Close()
+}
+
+// readLock returns the holder recorded in a lock file, zero if none is.
+func readLock(path string) (LockHolder, error) {
+	var holder LockHolder
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return holder, err
+	}
+	err = json.Unmarshal(data, &holder)
+	return holder, err
+}
@@ -0,0 +1,193 @@
+//go:build unix && !aix
+
+package chassis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// deadPID is above any pid_max, so no process runs with it.
+const deadPID = 1 << 30
+
+// writeLock writes a lock held by holder to the repository at dir.
+func writeLock(t *testing.T, dir string, holder LockHolder) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, LockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestAcquireLockIgnoresStaleHolder checks that a holder left in the lock
+// file by a process that exited doesn't block the lock.
+func TestAcquireLockIgnoresStaleHolder(t *testing.T) {
+	dir := t.TempDir()
+	host, _ := os.Hostname()
+	writeLock(t, dir, LockHolder{PID: deadPID, Action: "chassis:add", Host: host, Since: time.Now()})
+	SetLockConfig(LockConfig{Timeout: -1})
+	defer SetLockConfig(LockConfig{})
+
+	release, err := AcquireLock(dir, "chassis:rename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	holder, err := readLock(filepath.Join(dir, LockFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if holder.PID != os.Getpid() || holder.Action != "chassis:rename" {
+		t.Errorf("lock held by %+v, want this process", holder)
+	}
+	release()
+	if data, err := os.ReadFile(filepath.Join(dir, LockFile)); err != nil || len(data) != 0 {
+		t.Errorf("lock file after release = %q, %v; want it empty", data, err)
+	}
+}
+
+// TestAcquireLockWaitsForHolder checks that a held lock is never taken,
+// and that the timeout names its holder.
+func TestAcquireLockWaitsForHolder(t *testing.T) {
+	dir := t.TempDir()
+	release, err := AcquireLock(dir, "chassis:move")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetLockConfig(LockConfig{Timeout: 3 * lockPoll})
+	defer SetLockConfig(LockConfig{})
+
+	_, err = AcquireLock(dir, "chassis:add")
+	var timeout *LockTimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("got %v, want a LockTimeoutError", err)
+	}
+	if timeout.Holder.PID != os.Getpid() || timeout.Holder.Action != "chassis:move" {
+		t.Errorf("holder = %+v, want the chassis:move lock of this process", timeout.Holder)
+	}
+
+	release()
+	release, err = AcquireLock(dir, "chassis:add")
+	if err != nil {
+		t.Fatalf("lock not released: %v", err)
+	}
+	release()
+}
+
+// TestAcquireLockConcurrent checks that invocations racing for the lock
+// either take it or time out, and never report the record of a holder
+// taking or releasing the lock as corrupt.
+func TestAcquireLockConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	SetLockConfig(LockConfig{Timeout: -1})
+	defer SetLockConfig(LockConfig{})
+
+	var acquired, timedOut atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				release, err := AcquireLock(dir, fmt.Sprintf("chassis:add-%d", i))
+				var timeout *LockTimeoutError
+				switch {
+				case err == nil:
+					acquired.Add(1)
+					time.Sleep(100 * time.Microsecond)
+					release()
+				case errors.As(err, &timeout):
+					timedOut.Add(1)
+				default:
+					t.Errorf("got %v, want the lock or a LockTimeoutError", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if acquired.Load() == 0 || timedOut.Load() == 0 {
+		t.Errorf("acquired %d times, timed out %d times; want both", acquired.Load(), timedOut.Load())
+	}
+}
+
+// TestAcquireLockUnrecordedHolder checks that a lock taken by a holder that
+// hasn't recorded itself yet times out instead of being reported as corrupt.
+func TestAcquireLockUnrecordedHolder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if locked, err := tryLockFile(f); !locked || err != nil {
+		t.Fatalf("tryLockFile() = %v, %v", locked, err)
+	}
+	SetLockConfig(LockConfig{Timeout: -1})
+	defer SetLockConfig(LockConfig{})
+
+	_, err = AcquireLock(dir, "chassis:add")
+	var timeout *LockTimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("got %v, want a LockTimeoutError", err)
+	}
+	if timeout.Holder != (LockHolder{}) {
+		t.Errorf("holder = %+v, want none", timeout.Holder)
+	}
+}
+
+func TestAcquireLockCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Held by a holder whose record is unreadable
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if locked, err := tryLockFile(f); !locked || err != nil {
+		t.Fatalf("tryLockFile() = %v, %v", locked, err)
+	}
+	if _, err := f.WriteString("{pid: "); err != nil {
+		t.Fatal(err)
+	}
+	SetLockConfig(LockConfig{Timeout: -1})
+	defer SetLockConfig(LockConfig{})
+
+	_, err = AcquireLock(dir, "chassis:add")
+	var corrupt *LockCorruptError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("got %v, want a LockCorruptError", err)
+	}
+	if corrupt.Path != path {
+		t.Errorf("corrupt lock path = %s, want %s", corrupt.Path, path)
+	}
+
+	// An unreadable record of a lock nobody holds is replaced
+	_ = unlockFile(f)
+	release, err := AcquireLock(dir, "chassis:add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
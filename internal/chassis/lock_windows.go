@@ -0,0 +1,36 @@
+//go:build windows
+
+package chassis
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is the offset of the locked byte, past any holder record, so
+// locking doesn't block waiting invocations from reading it.
+const lockOffset = 1 << 62
+
+// tryLockFile takes an exclusive lock on f without waiting and reports
+// whether it did.
+func tryLockFile(f *os.File) (bool, error) {
+	ol := lockOverlapped()
+	err := windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the lock taken by [tryLockFile].
+func unlockFile(f *os.File) error {
+	ol := lockOverlapped()
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+}
+
+func lockOverlapped() windows.Overlapped {
+	return windows.Overlapped{Offset: uint32(lockOffset & 0xffffffff), OffsetHigh: uint32(lockOffset >> 32)}
+}
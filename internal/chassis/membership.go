@@ -0,0 +1,107 @@
+package chassis
+
+import (
+	"sort"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// GroupChange is a change of the inventory groups a node is effectively a member of.
+type GroupChange struct {
+	Node   string   `json:"node"` // hostname@platform
+	Joined []string `json:"joined,omitempty"`
+	Left   []string `json:"left,omitempty"`
+}
+
+// RenameGroupChanges simulates renaming oldPath to newPath in the allocations
+// of nodes and returns the nodes whose inventory group membership changes
+// beyond the renamed group names, e.g. when merging into an existing path
+// changes distribution. Before is the tree before the rename, after the tree
+// with the rename or merge applied.
+func RenameGroupChanges(before, after *Chassis, d pkgchassis.Distributor, nodes []Node, oldPath, newPath string) []GroupChange {
+	renamed := make([]Node, len(nodes))
+	for i, n := range nodes {
+		renamed[i] = n
+		renamed[i].Chassis = make([]string, len(n.Chassis))
+		for j, entry := range n.Chassis {
+			renamed[i].Chassis[j], _ = pkgchassis.RenameAllocation(entry, oldPath, newPath)
+		}
+	}
+
+	was := pkgchassis.Allocate(before.Chassis, d, DeclaredNodes(nodes))
+	is := pkgchassis.Allocate(after.Chassis, d, DeclaredNodes(renamed))
+
+	var changes []GroupChange
+	for i := range nodes {
+		// Groups before, under their names after the rename
+		old := make(map[string]bool)
+		for p := range memberships(was[i].Paths()) {
+			if p == oldPath || pkgchassis.IsDescendantOf(p, oldPath) {
+				p = newPath + p[len(oldPath):]
+			}
+			old[pkgchassis.GroupName(p)] = true
+		}
+		current := make(map[string]bool)
+		for p := range memberships(is[i].Paths()) {
+			current[pkgchassis.GroupName(p)] = true
+		}
+
+		change := GroupChange{Node: is[i].DisplayName()}
+		for g := range current {
+			if !old[g] {
+				change.Joined = append(change.Joined, g)
+			}
+		}
+		for g := range old {
+			if !current[g] {
+				change.Left = append(change.Left, g)
+			}
+		}
+		if len(change.Joined) > 0 || len(change.Left) > 0 {
+			sort.Strings(change.Joined)
+			sort.Strings(change.Left)
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Node < changes[j].Node })
+	return changes
+}
+
+// memberships returns the paths whose groups list a node allocated to paths:
+// Ansible groups inherit the hosts of their children.
+func memberships(paths []string) map[string]bool {
+	groups := make(map[string]bool)
+	for _, p := range paths {
+		for ; p != "" && !groups[p]; p = pkgchassis.Parent(p) {
+			groups[p] = true
+		}
+	}
+	return groups
+}
+
+// InventoryGroups returns the sorted inventory groups listing a node
+// allocated to paths: the groups of the paths and of their ancestors.
+func InventoryGroups(paths []string) []string {
+	groups := make([]string, 0, len(paths))
+	for p := range memberships(paths) {
+		groups = append(groups, pkgchassis.GroupName(p))
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// NodeCounts returns the number of nodes per platform directly allocated
+// to chassisPath or one of its descendants, as bounded by max_nodes and
+// min_nodes.
+func NodeCounts(c *Chassis, nodes []Node, chassisPath string) map[string]int {
+	counts := make(map[string]int)
+	for _, n := range nodes {
+		for _, p := range c.ExpandAllocations(n.Chassis) {
+			if p == chassisPath || pkgchassis.IsDescendantOf(p, chassisPath) {
+				counts[n.Platform]++
+				break
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,26 @@
+package chassis
+
+import "gopkg.in/yaml.v3"
+
+// isMergeKey reports whether a mapping key is the YAML merge key.
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.Value == "<<" && key.ShortTag() == "!!merge"
+}
+
+// marshalDocument renders a parsed YAML document back to text.
+// The parser tags merge keys explicitly, which the encoder would write out
+// as "!!merge <<", so the tag is cleared to keep the original notation.
+func marshalDocument(doc *yaml.Node) ([]byte, error) {
+	clearMergeTags(doc)
+	return yaml.Marshal(doc)
+}
+
+// clearMergeTags resets the tag of every merge key below node.
+func clearMergeTags(node *yaml.Node) {
+	if isMergeKey(node) {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		clearMergeTags(child)
+	}
+}
@@ -0,0 +1,120 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// mergeRepo copies testdata/merge, whose node files and playbook inherit
+// keys through YAML merge keys, to a temporary directory.
+func mergeRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.CopyFS(dir, os.DirFS(filepath.Join("testdata", "merge"))); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// assertFile compares a file below dir with want.
+func assertFile(t *testing.T, dir, name, want string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("%s =\n%s\nwant\n%s", name, data, want)
+	}
+}
+
+func TestLoadNodesMergeKeys(t *testing.T) {
+	nodes, err := LoadNodes(mergeRepo(t), "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		// Inherited from the merged mapping
+		"node-1": {"platform.foundation.cluster.nodes"},
+		// The explicit key takes precedence over the merged one
+		"node-2": {"platform.foundation.storage.kv"},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("loaded %d nodes, want %d", len(nodes), len(want))
+	}
+	for _, n := range nodes {
+		if !slices.Equal(n.Chassis, want[n.Hostname]) {
+			t.Errorf("%s: chassis = %v, want %v", n.Hostname, n.Chassis, want[n.Hostname])
+		}
+		if !n.ChassisDeclared {
+			t.Errorf("%s: ChassisDeclared = false, want true", n.Hostname)
+		}
+	}
+}
+
+func TestAddAllocationMergeKeys(t *testing.T) {
+	dir := mergeRepo(t)
+	if _, err := AddAllocation(NodeFile(dir, "prod", "node-1"), "platform.foundation.storage.kv"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The node gets its own list, the merged mapping is left as is
+	assertFile(t, dir, "inst/prod/nodes/node-1.yaml", `x-defaults: &defaults
+    chassis:
+        - platform.foundation.cluster.nodes
+    rack: r1
+hostname: node-1
+<<: *defaults
+chassis:
+    - platform.foundation.cluster.nodes
+    - platform.foundation.storage.kv
+`)
+	n, err := FindNode(dir, "node-1@prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"platform.foundation.cluster.nodes", "platform.foundation.storage.kv"}; !slices.Equal(n.Chassis, want) {
+		t.Errorf("chassis = %v, want %v", n.Chassis, want)
+	}
+}
+
+func TestLoadAttachmentsMergeKeys(t *testing.T) {
+	attachments, err := LoadAttachments(mergeRepo(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range attachments {
+		got = append(got, a.Component+"@"+a.Chassis)
+	}
+	slices.Sort(got)
+	want := []string{
+		"foundation.cluster.k8s@platform.foundation.cluster",
+		"foundation.cluster.k8s@platform.foundation.storage.kv",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("attachments = %v, want %v", got, want)
+	}
+}
+
+func TestAttachRoleMergeKeys(t *testing.T) {
+	dir := mergeRepo(t)
+	playbook := filepath.Join(dir, "src", "foundation", "foundation.yaml")
+	if _, err := AttachRole(playbook, "platform.foundation.storage.kv", "foundation.storage.redis", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The play gets its own roles, the anchored play keeps its own
+	assertFile(t, dir, "src/foundation/foundation.yaml", `- &cluster
+  hosts: platform.foundation.cluster
+  roles:
+    - foundation.cluster.k8s
+- <<: *cluster
+  hosts: platform.foundation.storage.kv
+  roles:
+    - foundation.cluster.k8s
+    - foundation.storage.redis
+`)
+}
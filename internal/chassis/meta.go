@@ -0,0 +1,84 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// SaveMeta writes annotations to chassis.meta.yaml, removing the file when empty.
+func SaveMeta(dir string, meta pkgchassis.Meta) error {
+	path := filepath.Join(dir, pkgchassis.MetaFile)
+	for p, a := range meta {
+		if a.IsZero() {
+			delete(meta, p)
+		}
+	}
+
+	if len(meta) == 0 {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+		if err := backupFile(path); err != nil {
+			return err
+		}
+		if err := journalFile(path); err != nil {
+			return err
+		}
+		err := retryFileOp("remove", path, tracer.File, func() error { return os.Remove(path) })
+		if err != nil && !os.IsNotExist(err) {
+			return checkPermission("remove", path, err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", pkgchassis.MetaFile, err)
+	}
+	return writeFile(path, data)
+}
+
+// RenameMeta moves annotations of oldPath and its descendants to newPath.
+// It returns true if any annotation was moved.
+func RenameMeta(meta pkgchassis.Meta, oldPath, newPath string) bool {
+	moved := false
+	for _, p := range meta.Subtree(oldPath) {
+		meta[newPath+p[len(oldPath):]] = meta[p]
+		delete(meta, p)
+		moved = true
+	}
+	return moved
+}
+
+// MergeMeta moves annotations of oldPath and its descendants to newPath
+// like [RenameMeta], except that annotations already present at the target
+// path take precedence and the moved ones are dropped.
+// It returns true if any annotation was moved or dropped.
+func MergeMeta(meta pkgchassis.Meta, oldPath, newPath string) bool {
+	changed := false
+	for _, p := range meta.Subtree(oldPath) {
+		target := newPath + p[len(oldPath):]
+		if _, exists := meta[target]; !exists {
+			meta[target] = meta[p]
+		}
+		delete(meta, p)
+		changed = true
+	}
+	return changed
+}
+
+// RemoveMeta drops annotations of chassisPath and its descendants.
+// It returns true if any annotation was removed.
+func RemoveMeta(meta pkgchassis.Meta, chassisPath string) bool {
+	removed := false
+	for _, p := range meta.Subtree(chassisPath) {
+		delete(meta, p)
+		removed = true
+	}
+	return removed
+}
@@ -0,0 +1,126 @@
+package chassis
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// FileMove is a file or directory named after a chassis path, moved along
+// with a renamed path. Both paths are relative to the repository.
+type FileMove struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// PathNamedFiles finds files and directories named after oldPath or one of
+// the given descendant paths, either dotted (group_vars/platform.foundation.cluster/)
+// or as Ansible group name (group_vars/platform_foundation_cluster.yaml), and
+// returns their moves to the matching path below newPath.
+// Moves are ordered deepest first, so they can be applied in order.
+func PathNamedFiles(dir string, paths []string, oldPath, newPath string) ([]FileMove, error) {
+	// file name stem → new stem
+	stems := make(map[string]string, 2*len(paths))
+	for _, p := range paths {
+		if p != oldPath && !pkgchassis.IsDescendantOf(p, oldPath) {
+			continue
+		}
+		renamed := newPath + p[len(oldPath):]
+		stems[p] = renamed
+		stems[pkgchassis.GroupName(p)] = pkgchassis.GroupName(renamed)
+	}
+
+	var moves []FileMove
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if path != dir && (strings.HasPrefix(name, ".") || ignored(dir, path, d.IsDir())) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == dir || name == "chassis.yaml" {
+			return nil
+		}
+		// Match the whole name or the name without a single extension
+		stem, ext := name, ""
+		if _, ok := stems[stem]; !ok {
+			if i := strings.LastIndex(name, "."); i > 0 {
+				stem, ext = name[:i], name[i:]
+			}
+		}
+		renamed, ok := stems[stem]
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		moves = append(moves, FileMove{
+			Old: rel,
+			New: filepath.Join(filepath.Dir(rel), renamed+ext),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return strings.Count(moves[i].Old, string(filepath.Separator)) > strings.Count(moves[j].Old, string(filepath.Separator))
+	})
+	return moves, nil
+}
+
+// MoveFiles applies moves below dir, with git mv if useGit is set, creating
+// the parent directories of their destinations. Moves that fail don't stop the others; their errors are joined in the
+// returned error alongside the moves applied. Within a transaction, the
+// moves applied are undone by [RollbackTransaction].
+func MoveFiles(dir string, moves []FileMove, useGit bool) ([]FileMove, error) {
+	var moved []FileMove
+	var errs []error
+	for _, m := range moves {
+		src := filepath.Join(dir, m.Old)
+		dst := filepath.Join(dir, m.New)
+		if _, err := os.Lstat(dst); err == nil {
+			errs = append(errs, &fs.PathError{Op: "move", Path: src, Err: fmt.Errorf("%s already exists", m.New)})
+			continue
+		}
+
+		err := os.MkdirAll(filepath.Dir(dst), 0755)
+		if err != nil {
+			errs = append(errs, checkPermission("create", filepath.Dir(dst), err))
+			continue
+		}
+		if useGit {
+			_, err = runGit(dir, "mv", m.Old, m.New)
+		} else {
+			err = checkPermission("move", src, retryFileOp("move", src, tracer.File, func() error {
+				return os.Rename(src, dst)
+			}))
+		}
+		if err != nil {
+			var permErr *PermissionError
+			if !errors.As(err, &permErr) {
+				err = &fs.PathError{Op: "move", Path: src, Err: err}
+			}
+			errs = append(errs, err)
+			continue
+		}
+		backupMove(src, dst)
+		journalMove(dir, m, useGit)
+		tracer.File(dst, TraceWrite, "moved from "+m.Old)
+		moved = append(moved, m)
+	}
+	return moved, errors.Join(errs...)
+}
@@ -0,0 +1,86 @@
+package chassis
+
+import (
+	"fmt"
+	"regexp"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// PathFilter selects chassis paths by regular expressions, for the --match
+// and --exclude options. Expressions aren't anchored, e.g. `\.control$`
+// selects every control leaf. A nil filter selects every path.
+type PathFilter struct {
+	match   *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// NewPathFilter compiles the expressions selecting paths matching match,
+// if set, and not matching exclude, if set. Without either it returns nil.
+func NewPathFilter(match, exclude string) (*PathFilter, error) {
+	if match == "" && exclude == "" {
+		return nil, nil
+	}
+	f := &PathFilter{}
+	var err error
+	if match != "" {
+		if f.match, err = regexp.Compile(match); err != nil {
+			return nil, fmt.Errorf("invalid --match expression: %w", err)
+		}
+	}
+	if exclude != "" {
+		if f.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, fmt.Errorf("invalid --exclude expression: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// Matches reports whether chassisPath is selected.
+func (f *PathFilter) Matches(chassisPath string) bool {
+	if f == nil {
+		return true
+	}
+	if f.match != nil && !f.match.MatchString(chassisPath) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.MatchString(chassisPath)
+}
+
+// Filter returns the selected paths, keeping their order.
+func (f *PathFilter) Filter(paths []string) []string {
+	if f == nil {
+		return paths
+	}
+	selected := []string{}
+	for _, p := range paths {
+		if f.Matches(p) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// Tree returns the selected paths along with their ancestors, keeping the
+// order of paths, so nested outputs stay connected.
+func (f *PathFilter) Tree(paths []string) []string {
+	if f == nil {
+		return paths
+	}
+	keep := make(map[string]bool)
+	for _, p := range paths {
+		if !f.Matches(p) {
+			continue
+		}
+		for q := p; q != "" && !keep[q]; q = pkgchassis.Parent(q) {
+			keep[q] = true
+		}
+	}
+	tree := []string{}
+	for _, p := range paths {
+		if keep[p] {
+			tree = append(tree, p)
+		}
+	}
+	return tree
+}
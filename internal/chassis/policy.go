@@ -0,0 +1,211 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy holds lint settings for validation rules.
+//
+//	chassis:
+//	  policy:
+//	    layer_ownership:
+//	      severity: warning
+//	      layers:
+//	        foundation: [platform.foundation]
+//	    leaf_only:
+//	      severity: error
+//	    capacity:
+//	      severity: warning
+type Policy struct {
+	// LayerOwnership checks that attached roles belong to the layer they are attached under.
+	LayerOwnership LayerOwnership `yaml:"layer_ownership"`
+	// LeafOnly checks that nodes and roles target leaf paths only.
+	LeafOnly LeafOnly `yaml:"leaf_only"`
+	// Capacity checks node counts against the limits of chassis.meta.yaml.
+	Capacity Capacity `yaml:"capacity"`
+}
+
+// Policy severities. An empty severity selects the rule default.
+const (
+	PolicyError   = "error"
+	PolicyWarning = "warning"
+	PolicyOff     = "off"
+)
+
+// LayerOwnership maps role name prefixes to the chassis paths they may be attached under.
+// Without an explicit mapping a role prefix owns the chassis layer of the same name,
+// e.g. foundation.* roles belong under <root>.foundation.
+type LayerOwnership struct {
+	Severity string              `yaml:"severity"`
+	Layers   map[string][]string `yaml:"layers"`
+}
+
+// LeafOnly makes allocations and attachments to intermediate paths findings,
+// unless the path is annotated aggregate: true in chassis.meta.yaml.
+// Without a severity the check only runs when selected by name.
+type LeafOnly struct {
+	Severity string `yaml:"severity"`
+}
+
+// Capacity makes paths with more nodes than their max_nodes annotation in
+// chassis.meta.yaml, or fewer than min_nodes, findings. Without a severity
+// they are errors.
+type Capacity struct {
+	Severity string `yaml:"severity"`
+}
+
+// Validate checks the policy settings.
+func (p Policy) Validate() error {
+	for _, s := range []struct{ name, severity string }{
+		{"layer_ownership", p.LayerOwnership.Severity},
+		{"leaf_only", p.LeafOnly.Severity},
+		{"capacity", p.Capacity.Severity},
+	} {
+		switch s.severity {
+		case "", PolicyError, PolicyWarning, PolicyOff:
+		default:
+			return fmt.Errorf("unknown %s severity %q (supported: %s, %s, %s)",
+				s.name, s.severity, PolicyError, PolicyWarning, PolicyOff)
+		}
+	}
+	return nil
+}
+
+// Owns reports whether a role may be attached to a chassis path.
+// The second return value lists the chassis paths owned by the role prefix.
+func (o LayerOwnership) Owns(role, chassisPath string) (bool, []string) {
+	prefix, _, _ := strings.Cut(role, ".")
+
+	allowed, ok := o.Layers[prefix]
+	if !ok {
+		// Naming convention: the role prefix is the layer segment of the chassis path
+		parts := strings.Split(chassisPath, ".")
+		if len(parts) < 2 {
+			return true, nil
+		}
+		allowed = []string{parts[0] + "." + prefix}
+	}
+
+	for _, a := range allowed {
+		if chassisPath == a || strings.HasPrefix(chassisPath, a+".") {
+			return true, allowed
+		}
+	}
+	return false, allowed
+}
+
+// Policy setting names, as keys under chassis.policy.
+const (
+	PolicyLayerOwnership = "layer_ownership"
+	PolicyLeafOnly       = "leaf_only"
+	PolicyCapacity       = "capacity"
+)
+
+// SetPolicy sets a policy setting in the config file of the repository at
+// dir, keeping the rest of the file as is. The key is a setting name, for
+// its severity, or layer_ownership.layers.<prefix>, for the comma-separated
+// chassis paths owned by a role prefix; an empty value removes the prefix.
+// The updated policy is validated before writing, and returned.
+func SetPolicy(dir, key, value string, dryRun bool) (Policy, error) {
+	path := filepath.Join(dir, ConfigFile)
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return Policy{}, err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Policy{}, fmt.Errorf("failed to parse %s: %w", ConfigFile, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	parts := strings.Split(key, ".")
+	switch parts[0] {
+	case PolicyLayerOwnership, PolicyLeafOnly, PolicyCapacity:
+	default:
+		return Policy{}, fmt.Errorf("unknown policy setting %q (supported: %s, %s, %s)", parts[0], PolicyLayerOwnership, PolicyLeafOnly, PolicyCapacity)
+	}
+	setting := ensureMapping(ensureMapping(ensureMapping(doc.Content[0], ConfigKey), "policy"), parts[0])
+	switch {
+	case len(parts) == 1 || (len(parts) == 2 && parts[1] == "severity"):
+		setScalar(setting, "severity", value)
+	case parts[0] == PolicyLayerOwnership && len(parts) == 3 && parts[1] == "layers":
+		layers := ensureMapping(setting, "layers")
+		deleteKey(layers, parts[2])
+		if value != "" {
+			seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, p := range strings.Split(value, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: p})
+				}
+			}
+			layers.Content = append(layers.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: parts[2]}, seq)
+		}
+	default:
+		return Policy{}, fmt.Errorf("unknown policy key %q (expected <setting>, <setting>.severity or %s.layers.<prefix>)", key, PolicyLayerOwnership)
+	}
+
+	var cfg struct {
+		Chassis Config `yaml:"chassis"`
+	}
+	if err := doc.Decode(&cfg); err != nil {
+		return Policy{}, fmt.Errorf("failed to decode %s: %w", ConfigFile, err)
+	}
+	if err := cfg.Chassis.Policy.Validate(); err != nil {
+		return Policy{}, err
+	}
+	if dryRun {
+		return cfg.Chassis.Policy, nil
+	}
+
+	out, err := marshalDocument(&doc)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to marshal %s: %w", ConfigFile, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Policy{}, checkPermission("create", filepath.Dir(path), err)
+	}
+	if err := writeFile(path, out); err != nil {
+		return Policy{}, err
+	}
+	tracer.File(path, TraceWrite, "")
+	return cfg.Chassis.Policy, nil
+}
+
+// ensureMapping returns the mapping under key in a mapping node, adding it if missing.
+func ensureMapping(m *yaml.Node, key string) *yaml.Node {
+	if v := ownMappingValue(m, key); v != nil {
+		if v.Kind != yaml.MappingNode {
+			*v = yaml.Node{Kind: yaml.MappingNode}
+		}
+		return v
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+// setScalar sets a scalar value under key in a mapping node.
+func setScalar(m *yaml.Node, key, value string) {
+	if v := ownMappingValue(m, key); v != nil {
+		*v = yaml.Node{Kind: yaml.ScalarNode, Value: value, HeadComment: v.HeadComment, LineComment: v.LineComment}
+		return
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+// deleteKey removes key and its value from a mapping node.
+func deleteKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
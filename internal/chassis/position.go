@@ -0,0 +1,249 @@
+package chassis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// SiblingPath resolves a sibling of chassisPath given as full path or as
+// segment name, e.g. "eu-west" for platform.regions.eu-west.
+func SiblingPath(chassisPath, sibling string) string {
+	if strings.Contains(sibling, ".") {
+		return sibling
+	}
+	if parent := pkgchassis.Parent(chassisPath); parent != "" {
+		return parent + "." + sibling
+	}
+	return sibling
+}
+
+// AddNextTo adds a chassis path like [Chassis.Add], placed right before or
+// after an existing sibling instead of at the end of its parent.
+func (c *Chassis) AddNextTo(chassisPath, sibling string, before bool) error {
+	sibling = SiblingPath(chassisPath, sibling)
+	if pkgchassis.Parent(sibling) != pkgchassis.Parent(chassisPath) || sibling == chassisPath {
+		return fmt.Errorf("%q is not a sibling of %q", sibling, chassisPath)
+	}
+	if !c.Exists(sibling) {
+		return fmt.Errorf("sibling %q not found in chassis.yaml", sibling)
+	}
+	if err := c.Add(chassisPath); err != nil {
+		return err
+	}
+
+	// Add appended the path to the container of its siblings
+	container, err := c.childContainer(pkgchassis.Parent(chassisPath))
+	if err != nil {
+		return err
+	}
+	name, siblingName := lastSegment(chassisPath), lastSegment(sibling)
+	if container.Kind == yaml.MappingNode {
+		movePair(container, name, siblingName, before)
+	} else {
+		moveItem(container, name, siblingName, before)
+	}
+	return nil
+}
+
+// Reorder rearranges the children of a chassis path in chassis.yaml: the
+// children named in order come first, in that order, followed by the others
+// in their current order. With alpha, children are sorted by name instead.
+// Comments move with the entries they belong to.
+func (c *Chassis) Reorder(parent string, order []string, alpha bool) ([]string, error) {
+	if parent != "" && !c.Exists(parent) {
+		return nil, fmt.Errorf("chassis path %q not found", parent)
+	}
+	c.expandAliases()
+	container, err := c.childContainer(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := childEntries(container)
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		name = lastSegment(name)
+		if _, dup := rank[name]; dup {
+			return nil, fmt.Errorf("%q is listed twice", name)
+		}
+		if !slices.ContainsFunc(entries, func(e childEntry) bool { return slices.Contains(e.names, name) }) {
+			return nil, fmt.Errorf("%q is not a child of %q", name, parent)
+		}
+		rank[name] = i
+	}
+	entryRank := func(e childEntry) int {
+		r := len(order)
+		for _, n := range e.names {
+			if i, ok := rank[n]; ok && i < r {
+				r = i
+			}
+		}
+		return r
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if alpha {
+			return entries[i].names[0] < entries[j].names[0]
+		}
+		return entryRank(entries[i]) < entryRank(entries[j])
+	})
+
+	children := []string{}
+	container.Content = container.Content[:0]
+	for _, e := range entries {
+		container.Content = append(container.Content, e.nodes...)
+		for _, n := range e.names {
+			if n != "" {
+				children = append(children, n)
+			}
+		}
+	}
+	return children, nil
+}
+
+// childContainer returns the YAML node holding the children of a chassis
+// path: a mapping for the root and platforms, a sequence below.
+func (c *Chassis) childContainer(parent string) (*yaml.Node, error) {
+	container := c.YAMLNode().Content[0]
+	if parent == "" {
+		return container, nil
+	}
+	for i, seg := range strings.Split(parent, ".") {
+		if i < 2 {
+			container = ownMappingValue(container, seg)
+		} else {
+			container = sequenceChild(container, seg)
+		}
+		if container == nil {
+			return nil, fmt.Errorf("failed to locate %q in chassis.yaml", parent)
+		}
+	}
+	return container, nil
+}
+
+// childEntry is a movable unit of a children container: a key/value pair
+// of a mapping, or a sequence item declaring one or more children.
+type childEntry struct {
+	names []string
+	nodes []*yaml.Node
+}
+
+// childEntries splits a children container into its entries.
+func childEntries(container *yaml.Node) []childEntry {
+	var entries []childEntry
+	switch container.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(container.Content); i += 2 {
+			entries = append(entries, childEntry{
+				names: []string{container.Content[i].Value},
+				nodes: []*yaml.Node{container.Content[i], container.Content[i+1]},
+			})
+		}
+	case yaml.SequenceNode:
+		for _, item := range container.Content {
+			e := childEntry{nodes: []*yaml.Node{item}}
+			if item.Kind == yaml.ScalarNode {
+				e.names = []string{item.Value}
+			}
+			for i := 0; item.Kind == yaml.MappingNode && i+1 < len(item.Content); i += 2 {
+				e.names = append(e.names, item.Content[i].Value)
+			}
+			if len(e.names) == 0 {
+				e.names = []string{""}
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// lastSegment returns the last segment of a chassis path.
+func lastSegment(chassisPath string) string {
+	return chassisPath[strings.LastIndex(chassisPath, ".")+1:]
+}
+
+// sequenceChild returns the sequence of children of the item named name in a sequence of paths.
+func sequenceChild(seq *yaml.Node, name string) *yaml.Node {
+	for _, item := range seq.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if item.Content[i].Value == name {
+				return item.Content[i+1]
+			}
+		}
+	}
+	return nil
+}
+
+// movePair moves the key/value pair of name next to the pair of sibling in a mapping.
+func movePair(m *yaml.Node, name, sibling string, before bool) {
+	from := -1
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == name {
+			from = i
+		}
+	}
+	if from < 0 {
+		return
+	}
+	pair := []*yaml.Node{m.Content[from], m.Content[from+1]}
+	rest := append(append([]*yaml.Node(nil), m.Content[:from]...), m.Content[from+2:]...)
+	for i := 0; i+1 < len(rest); i += 2 {
+		if rest[i].Value != sibling {
+			continue
+		}
+		at := i
+		if !before {
+			at = i + 2
+		}
+		m.Content = append(append(append([]*yaml.Node(nil), rest[:at]...), pair...), rest[at:]...)
+		return
+	}
+}
+
+// moveItem moves the item named name next to the item holding sibling in a sequence of paths.
+func moveItem(seq *yaml.Node, name, sibling string, before bool) {
+	from := -1
+	for i, item := range seq.Content {
+		if itemNames(item, name) {
+			from = i
+		}
+	}
+	if from < 0 {
+		return
+	}
+	item := seq.Content[from]
+	rest := append(append([]*yaml.Node(nil), seq.Content[:from]...), seq.Content[from+1:]...)
+	for i, other := range rest {
+		if !itemNames(other, sibling) {
+			continue
+		}
+		at := i
+		if !before {
+			at = i + 1
+		}
+		seq.Content = append(append(append([]*yaml.Node(nil), rest[:at]...), item), rest[at:]...)
+		return
+	}
+}
+
+// itemNames reports whether a sequence item declares the path segment name,
+// as scalar or mapping key.
+func itemNames(item *yaml.Node, name string) bool {
+	if item.Kind == yaml.ScalarNode {
+		return item.Value == name
+	}
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if item.Content[i].Value == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package chassis
+
+import (
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+var reservedNames = pkgchassis.DefaultReservedNames
+
+// SetReservedNames installs the segment names rejected by [Chassis.Add] and
+// [Chassis.Rename]. Nil selects [pkgchassis.DefaultReservedNames]; an empty
+// list disables the check.
+func SetReservedNames(names []string) {
+	if names == nil {
+		names = pkgchassis.DefaultReservedNames
+	}
+	reservedNames = names
+}
+
+// ReservedNames returns the segment names rejected in chassis paths.
+func ReservedNames() []string {
+	return reservedNames
+}
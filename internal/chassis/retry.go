@@ -0,0 +1,134 @@
+package chassis
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Retry defaults for file operations failing transiently.
+const (
+	DefaultRetryAttempts = 3
+	DefaultRetryBackoff  = 100 * time.Millisecond
+)
+
+// RetryConfig configures retries of the file writes, moves and removals of
+// mutating actions, for repositories on network file systems (NFS, SMB)
+// which occasionally fail with EIO or stale handles during large changes.
+//
+//	chassis:
+//	  retry:
+//	    attempts: 5
+//	    backoff: 200ms
+//	    rate: 50
+type RetryConfig struct {
+	// Attempts is the number of tries of an operation failing transiently.
+	// Zero uses [DefaultRetryAttempts]; 1 disables retries.
+	Attempts int `yaml:"attempts"`
+	// Backoff is the wait before the first retry, doubled for each further
+	// one. Zero uses [DefaultRetryBackoff].
+	Backoff time.Duration `yaml:"backoff"`
+	// Rate caps file operations per second; zero means unlimited.
+	Rate int `yaml:"rate"`
+}
+
+// Validate checks that the settings aren't negative.
+func (r RetryConfig) Validate() error {
+	if r.Attempts < 0 || r.Backoff < 0 || r.Rate < 0 {
+		return fmt.Errorf("retry: attempts, backoff and rate must not be negative")
+	}
+	return nil
+}
+
+var (
+	retryConfig = RetryConfig{Attempts: DefaultRetryAttempts, Backoff: DefaultRetryBackoff}
+
+	throttleMu   sync.Mutex
+	throttleNext time.Time
+)
+
+// SetRetryConfig installs the retry settings of file operations.
+func SetRetryConfig(r RetryConfig) {
+	if r.Attempts == 0 {
+		r.Attempts = DefaultRetryAttempts
+	}
+	if r.Backoff == 0 {
+		r.Backoff = DefaultRetryBackoff
+	}
+	retryConfig = r
+}
+
+// TransientError reports a file operation that kept failing with an error
+// network file systems produce transiently, such as EIO or a stale handle.
+// Running the action again may succeed.
+type TransientError struct {
+	Path     string
+	Op       string // operation that failed: write, move or remove
+	Attempts int
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("cannot %s %s: %v (transient, gave up after %d attempt(s))", e.Op, e.Path, e.Err, e.Attempts)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// Suggestion tells the operator how to deal with the failure.
+func (e *TransientError) Suggestion() string {
+	return "run the action again; raise retry.attempts or retry.backoff if the file system keeps failing"
+}
+
+// isTransient reports whether err is a failure network file systems
+// produce transiently.
+func isTransient(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EIO, syscall.ESTALE, syscall.EAGAIN, syscall.EBUSY, syscall.ETIMEDOUT, syscall.EINTR} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryFileOp runs a file operation, retrying it with backoff while it
+// fails transiently, at most [RetryConfig.Rate] operations per second.
+// Retries are reported to trace. A transient failure of the last attempt is
+// returned as [TransientError].
+func retryFileOp(op, path string, trace func(path, event, detail string), fn func() error) error {
+	backoff := retryConfig.Backoff
+	for attempt := 1; ; attempt++ {
+		throttle()
+		err := fn()
+		if err == nil || !isTransient(err) {
+			return err
+		}
+		if attempt >= retryConfig.Attempts {
+			return &TransientError{Path: path, Op: op, Attempts: attempt, Err: err}
+		}
+		trace(path, TraceRetry, err.Error())
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// throttle waits until the next file operation is allowed by the rate limit.
+func throttle() {
+	if retryConfig.Rate <= 0 {
+		return
+	}
+	throttleMu.Lock()
+	now := time.Now()
+	wait := throttleNext.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	throttleNext = now.Add(wait + time.Second/time.Duration(retryConfig.Rate))
+	throttleMu.Unlock()
+	time.Sleep(wait)
+}
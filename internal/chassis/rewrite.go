@@ -0,0 +1,132 @@
+package chassis
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rewrite is a scalar value in a playbook where a chassis path token was replaced.
+type Rewrite struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// UpdateReferences rewrites chassis path tokens in every scalar value of all
+// playbooks, such as group names in vars or delegate_to expressions. hosts
+// fields are left to [UpdateAttachments]. A token matches the exact path or
+// a descendant path, delimited by characters that can't be part of a path.
+// With dryRun no file is written and the returned sites describe what would change.
+func UpdateReferences(dir, oldChassis, newChassis string, dryRun bool) ([]Rewrite, error) {
+	var rewrites []Rewrite
+	var writeErrs []error
+
+	playbooks, err := PlaybookFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, playbookPath := range playbooks {
+		data, err := os.ReadFile(playbookPath)
+		if err != nil {
+			tracer.File(playbookPath, TraceSkip, err.Error())
+			continue
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			tracer.File(playbookPath, TraceSkip, "parse error: "+err.Error())
+			continue
+		}
+
+		sites := rewriteScalars(&doc, oldChassis, newChassis)
+		if len(sites) == 0 {
+			tracer.File(playbookPath, TraceNoMatch, "no value references "+oldChassis)
+			continue
+		}
+		for i := range sites {
+			sites[i].File = playbookPath
+		}
+		rewrites = append(rewrites, sites...)
+		tracer.File(playbookPath, TraceMatch, "")
+		if dryRun {
+			continue
+		}
+
+		newData, err := marshalDocument(&doc)
+		if err != nil {
+			tracer.File(playbookPath, TraceSkip, "marshal error: "+err.Error())
+			continue
+		}
+		if err := writeFile(playbookPath, newData); err != nil {
+			tracer.File(playbookPath, TraceSkip, "write error: "+err.Error())
+			writeErrs = append(writeErrs, err)
+			continue
+		}
+		tracer.File(playbookPath, TraceWrite, "")
+	}
+
+	return rewrites, errors.Join(writeErrs...)
+}
+
+// rewriteScalars replaces path tokens in scalar values below node, skipping
+// mapping keys and hosts fields.
+func rewriteScalars(node *yaml.Node, oldChassis, newChassis string) []Rewrite {
+	var sites []Rewrite
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			sites = append(sites, rewriteScalars(child, oldChassis, newChassis)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "hosts" {
+				continue
+			}
+			sites = append(sites, rewriteScalars(node.Content[i+1], oldChassis, newChassis)...)
+		}
+	case yaml.ScalarNode:
+		if replaced, ok := replacePathTokens(node.Value, oldChassis, newChassis); ok {
+			sites = append(sites, Rewrite{Line: node.Line, Old: node.Value, New: replaced})
+			node.Value = replaced
+		}
+	}
+
+	return sites
+}
+
+// replacePathTokens replaces oldChassis with newChassis wherever it appears
+// as a whole path or as the prefix of a descendant path.
+func replacePathTokens(s, oldChassis, newChassis string) (string, bool) {
+	var b strings.Builder
+	replaced := false
+	for {
+		i := strings.Index(s, oldChassis)
+		if i < 0 {
+			break
+		}
+		end := i + len(oldChassis)
+		before := i == 0 || !isPathChar(s[i-1]) && s[i-1] != '.'
+		after := end == len(s) || !isPathChar(s[end])
+		if before && after {
+			b.WriteString(s[:i])
+			b.WriteString(newChassis)
+			replaced = true
+		} else {
+			b.WriteString(s[:end])
+		}
+		s = s[end:]
+	}
+	b.WriteString(s)
+	return b.String(), replaced
+}
+
+// isPathChar reports whether c can be part of a chassis path segment.
+func isPathChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
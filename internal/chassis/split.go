@@ -0,0 +1,137 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MoveRoles moves roles out of the plays of a playbook targeting fromHosts:
+// each role named in targets goes to the play targeting the mapped hosts,
+// which is created right after the first source play if the playbook has
+// none. Role entries keep their form, e.g. a version constraint. Source
+// plays left without roles stay in place, see [PruneEmptyPlays].
+// It returns the names of the roles moved.
+func MoveRoles(playbookPath, fromHosts string, targets map[string]string) ([]string, error) {
+	data, err := os.ReadFile(playbookPath)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", playbookPath, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: not a list of plays", playbookPath)
+	}
+	tracer.File(playbookPath, TraceRead, "")
+
+	plays := doc.Content[0]
+	var moved []string
+	taken := make(map[string][]*yaml.Node) // target hosts -> role entries
+	var order []string
+	insertAt := -1
+	for i, play := range plays.Content {
+		if playHosts(play) != fromHosts {
+			continue
+		}
+		roles := ownMappingValue(play, "roles")
+		if roles == nil || roles.Kind != yaml.SequenceNode {
+			continue
+		}
+		kept := roles.Content[:0]
+		for _, entry := range roles.Content {
+			name := roleName(entry)
+			to, ok := targets[name]
+			if !ok {
+				kept = append(kept, entry)
+				continue
+			}
+			if insertAt < 0 {
+				insertAt = i + 1
+			}
+			if _, seen := taken[to]; !seen {
+				order = append(order, to)
+			}
+			taken[to] = append(taken[to], entry)
+			moved = append(moved, name)
+		}
+		roles.Content = kept
+	}
+	if len(moved) == 0 {
+		tracer.File(playbookPath, TraceNoMatch, "no play of "+fromHosts+" has the roles")
+		return nil, nil
+	}
+
+	for _, to := range order {
+		if roles := targetRoles(plays, to); roles != nil {
+			roles.Content = append(roles.Content, taken[to]...)
+			continue
+		}
+		play := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "hosts"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: to},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "roles"},
+			{Kind: yaml.SequenceNode, Content: taken[to]},
+		}}
+		plays.Content = append(plays.Content[:insertAt], append([]*yaml.Node{play}, plays.Content[insertAt:]...)...)
+		insertAt++
+	}
+
+	newData, err := marshalDocument(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", playbookPath, err)
+	}
+	if err := writeFile(playbookPath, newData); err != nil {
+		return nil, err
+	}
+	tracer.File(playbookPath, TraceWrite, "")
+	return moved, nil
+}
+
+// targetRoles returns the roles list of the first play targeting hosts,
+// creating it in a play that has none, or nil without such play.
+func targetRoles(plays *yaml.Node, hosts string) *yaml.Node {
+	for _, play := range plays.Content {
+		if playHosts(play) != hosts {
+			continue
+		}
+		roles := ownMappingValue(play, "roles")
+		if roles == nil {
+			roles = &yaml.Node{Kind: yaml.SequenceNode}
+			play.Content = append(play.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "roles"}, roles)
+		}
+		if roles.Kind != yaml.SequenceNode {
+			continue
+		}
+		return roles
+	}
+	return nil
+}
+
+// playHosts returns the hosts of a play, empty if not a scalar.
+func playHosts(play *yaml.Node) string {
+	if play.Kind != yaml.MappingNode {
+		return ""
+	}
+	if h := mappingValue(play, "hosts"); h != nil && h.Kind == yaml.ScalarNode {
+		return h.Value
+	}
+	return ""
+}
+
+// roleName returns the name of a roles entry, given as string or as mapping
+// with a role key.
+func roleName(entry *yaml.Node) string {
+	if entry.Kind == yaml.ScalarNode {
+		return entry.Value
+	}
+	if entry.Kind == yaml.MappingNode {
+		if r := mappingValue(entry, "role"); r != nil {
+			return r.Value
+		}
+	}
+	return ""
+}
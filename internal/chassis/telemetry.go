@@ -0,0 +1,208 @@
+package chassis
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// TelemetryFile collects usage records, one JSON object per line, relative
+// to the repository root.
+const TelemetryFile = ".plasmactl/telemetry.jsonl"
+
+// TelemetryIDFile holds the random identifier of the repository in telemetry
+// records, relative to the repository root.
+const TelemetryIDFile = ".plasmactl/telemetry-id"
+
+// telemetryTimeout bounds posting a record, so an unreachable endpoint
+// doesn't hold up the action.
+const telemetryTimeout = 2 * time.Second
+
+// TelemetryConfig holds the opt-in usage metrics settings.
+//
+//	chassis:
+//	  telemetry:
+//	    enabled: true
+//	    endpoint: https://metrics.example.com/chassis
+type TelemetryConfig struct {
+	// Enabled records every action run in [TelemetryFile].
+	Enabled bool `yaml:"enabled"`
+	// Endpoint, if set, also receives each record as a JSON POST.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Validate checks that the endpoint is an http or https URL.
+func (t TelemetryConfig) Validate() error {
+	if t.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(t.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid telemetry endpoint %q: expected an http or https URL", t.Endpoint)
+	}
+	return nil
+}
+
+var telemetryConfig TelemetryConfig
+
+// SetTelemetryConfig installs the telemetry settings used by [StartTelemetry].
+func SetTelemetryConfig(t TelemetryConfig) {
+	telemetryConfig = t
+}
+
+// RepositoryScale is the size of a repository, without any names.
+type RepositoryScale struct {
+	Paths     int `json:"paths"`
+	Platforms int `json:"platforms"`
+	Nodes     int `json:"nodes"`
+	Playbooks int `json:"playbooks"`
+}
+
+// TelemetryRecord is the anonymized usage of one action run. It holds no
+// chassis paths, hostnames, file names or option values.
+type TelemetryRecord struct {
+	Time time.Time `json:"time"`
+	// Repository is a random identifier kept in [TelemetryIDFile], telling
+	// repositories apart without anything derived from them.
+	Repository   string          `json:"repository"`
+	Action       string          `json:"action"`
+	Options      []string        `json:"options,omitempty"` // names of the options set
+	DurationMS   int64           `json:"duration_ms"`
+	Failed       bool            `json:"failed,omitempty"`
+	FilesRead    int             `json:"files_read"`
+	FilesWritten int             `json:"files_written"`
+	Scale        RepositoryScale `json:"scale"`
+}
+
+// Telemetry records an action run. It counts files as a [Tracer].
+type Telemetry struct {
+	mu     sync.Mutex
+	dir    string
+	start  time.Time
+	record TelemetryRecord
+}
+
+// StartTelemetry starts recording an action run in the repository at dir,
+// or returns nil if telemetry isn't enabled.
+func StartTelemetry(dir, action string, options []string) *Telemetry {
+	if !telemetryConfig.Enabled {
+		return nil
+	}
+	return &Telemetry{
+		dir:   dir,
+		start: time.Now(),
+		record: TelemetryRecord{
+			Repository: repositoryID(dir),
+			Action:     action,
+			Options:    options,
+		},
+	}
+}
+
+// File implements [Tracer] interface.
+func (t *Telemetry) File(_, event, _ string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch event {
+	case TraceRead:
+		t.record.FilesRead++
+	case TraceWrite:
+		t.record.FilesWritten++
+	}
+}
+
+// Finish completes the record with the outcome of the run and the scale of
+// the repository, appends it to [TelemetryFile] and posts it to the
+// configured endpoint.
+func (t *Telemetry) Finish(runErr error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record.Time = t.start.UTC().Truncate(time.Second)
+	t.record.DurationMS = time.Since(t.start).Milliseconds()
+	t.record.Failed = runErr != nil
+	t.record.Scale = repositoryScale(t.dir)
+
+	data, err := json.Marshal(t.record)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(t.dir, TelemetryFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || telemetryConfig.Endpoint == "" {
+		return err
+	}
+	return postTelemetry(telemetryConfig.Endpoint, data)
+}
+
+// postTelemetry sends a record to the endpoint.
+func postTelemetry(endpoint string, data []byte) error {
+	client := http.Client{Timeout: telemetryTimeout}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// repositoryID returns the identifier of the repository in [TelemetryIDFile],
+// creating it with a random value on first use. Being random, it can't be
+// traced back to the repository location. If it can't be stored, a new
+// value is used for this run only.
+func repositoryID(dir string) string {
+	path := filepath.Join(dir, TelemetryIDFile)
+	if data, err := os.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id
+		}
+	}
+	var random [16]byte
+	_, _ = rand.Read(random[:])
+	id := hex.EncodeToString(random[:])
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		_ = os.WriteFile(path, []byte(id+"\n"), 0644)
+	}
+	return id
+}
+
+// repositoryScale counts paths, platforms, node files and playbooks without
+// parsing node files or playbooks. Files are found like the loaders find
+// them, following the layout and ignore rules. Unreadable parts count as
+// empty.
+func repositoryScale(dir string) RepositoryScale {
+	var scale RepositoryScale
+	if c, err := pkgchassis.Load(dir); err == nil {
+		scale.Paths = len(c.Flatten())
+	}
+	platforms, _ := Platforms(dir)
+	scale.Platforms = len(platforms)
+	nodes, _ := stampFiles(dir, nodeFileGlob())
+	scale.Nodes = len(nodes)
+	playbooks, _ := PlaybookFiles(dir)
+	scale.Playbooks = len(playbooks)
+	return scale
+}
@@ -0,0 +1,43 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepositoryID(t *testing.T) {
+	dir := t.TempDir()
+	id := repositoryID(dir)
+	if len(id) != 32 {
+		t.Fatalf("repository id %q isn't 16 random bytes", id)
+	}
+	if again := repositoryID(dir); again != id {
+		t.Errorf("repository id changed from %q to %q", id, again)
+	}
+	if other := repositoryID(t.TempDir()); other == id {
+		t.Errorf("two repositories share the id %q", id)
+	}
+}
+
+func TestRepositoryScaleLayout(t *testing.T) {
+	dir := mergeRepo(t)
+	for name, content := range map[string]string{
+		"inst/prod/nodes/node-3.yml":       "hostname: node-3\n",
+		"inst/prod/nodes/node-4.yaml":      "hostname: node-4\n",
+		"inst/prod/nodes/notes.txt":        "not a node file\n",
+		"inst/prod/nodes/node-1.orig.yaml": "hostname: node-1\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer SetLayout(CurrentLayout())
+	SetLayout(Layout{Extensions: []string{".yaml", ".yml"}, Ignore: []string{"*.orig.yaml", "node-4.yaml"}})
+
+	// node-1, node-2 and node-3; node-4 and the backup copy are ignored
+	if got := repositoryScale(dir); got.Nodes != 3 || got.Playbooks != 1 || got.Platforms != 1 {
+		t.Errorf("scale = %+v, want 3 nodes, 1 playbook and 1 platform", got)
+	}
+}
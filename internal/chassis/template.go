@@ -0,0 +1,263 @@
+package chassis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// templateParamRegex matches ${name} placeholders in template segments.
+var templateParamRegex = regexp.MustCompile(`\$\{([a-z0-9_-]+)\}`)
+
+// Template is a reusable subtree definition declared under chassis.templates
+// in the plasmactl config, using the same sequence syntax as chassis.yaml layers:
+//
+//	chassis:
+//	  templates:
+//	    cluster:
+//	      - control
+//	      - worker
+//	      - storage:
+//	        - ${instance}-local
+type Template struct {
+	Name  string
+	Paths []string // relative dotted paths in declaration order
+}
+
+// Template returns the named template from the config.
+func (cfg Config) Template(name string) (*Template, error) {
+	items, ok := cfg.Templates[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Templates))
+		for n := range cfg.Templates {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("template %q not found: no templates defined under %s.templates", name, ConfigKey)
+		}
+		return nil, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(names, ", "))
+	}
+	return &Template{Name: name, Paths: flattenItems("", items)}, nil
+}
+
+// Render substitutes ${name} placeholders in the template paths.
+// The instance parameter is always set to the last segment of the target path.
+func (t *Template) Render(target string, params map[string]string) ([]string, error) {
+	values := map[string]string{"instance": target[strings.LastIndex(target, ".")+1:]}
+	for k, v := range params {
+		values[k] = v
+	}
+
+	rendered := make([]string, 0, len(t.Paths))
+	for _, p := range t.Paths {
+		var missing string
+		out := templateParamRegex.ReplaceAllStringFunc(p, func(m string) string {
+			name := templateParamRegex.FindStringSubmatch(m)[1]
+			v, ok := values[name]
+			if !ok {
+				missing = name
+			}
+			return v
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("template %q requires parameter %q", t.Name, missing)
+		}
+		if err := pkgchassis.ValidatePath(out); err != nil {
+			return nil, fmt.Errorf("template %q renders invalid path %q: %w", t.Name, out, err)
+		}
+		rendered = append(rendered, out)
+	}
+	return rendered, nil
+}
+
+// flattenItems flattens a sequence of scalars and single-key maps into dotted paths.
+func flattenItems(prefix string, items []interface{}) []string {
+	var paths []string
+	join := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "." + name
+	}
+
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			paths = append(paths, join(v))
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				paths = append(paths, join(k))
+				if sub, ok := v[k].([]interface{}); ok {
+					paths = append(paths, flattenItems(join(k), sub)...)
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// TemplateRename renames a relative template path within an instance.
+type TemplateRename struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// TemplatePlan lists the changes bringing an instance up to date with its template.
+// All paths are relative to the instance path.
+type TemplatePlan struct {
+	Add      []string         `json:"add,omitempty"`
+	Rename   []TemplateRename `json:"rename,omitempty"`
+	Obsolete []string         `json:"obsolete,omitempty"`
+}
+
+// IsEmpty reports whether the instance is up to date.
+func (p TemplatePlan) IsEmpty() bool {
+	return len(p.Add) == 0 && len(p.Rename) == 0 && len(p.Obsolete) == 0
+}
+
+// PlanTemplateUpgrade compares the paths recorded at instantiation with the
+// currently rendered template paths. A dropped path is proposed as renamed to
+// an added sibling picked by [renameCandidate]; descendants follow the renamed
+// path. Dropped paths that can't be paired are reported as
+// obsolete and left in place. Added paths already present in the instance
+// (existing reports relative paths) are skipped.
+func PlanTemplateUpgrade(recorded, current []string, existing func(rel string) bool) TemplatePlan {
+	dropped := Subtract(recorded, current)
+	added := Subtract(current, recorded)
+
+	var plan TemplatePlan
+	renamed := make(map[string]string)
+
+	// Pair renames parent by parent, shallow paths first
+	byParent := func(paths []string) map[string][]string {
+		m := make(map[string][]string)
+		for _, p := range paths {
+			m[pkgchassis.Parent(p)] = append(m[pkgchassis.Parent(p)], p)
+		}
+		return m
+	}
+	droppedByParent := byParent(dropped)
+	addedByParent := byParent(added)
+	for _, p := range dropped {
+		parent := pkgchassis.Parent(p)
+		if under(parent, renamed) != "" {
+			continue
+		}
+		if !existing(p) {
+			continue
+		}
+		newPath := renameCandidate(p, droppedByParent[parent], addedByParent[parent], renamed)
+		if newPath != "" {
+			renamed[p] = newPath
+			plan.Rename = append(plan.Rename, TemplateRename{Old: p, New: newPath})
+		}
+	}
+
+	// Descendants of renamed paths that kept their relative name are covered by the rename
+	covered := make(map[string]bool)
+	for _, p := range dropped {
+		if old := under(p, renamed); old != "" {
+			target := renamed[old] + p[len(old):]
+			if contains(added, target) {
+				covered[p] = true
+				covered[target] = true
+			}
+		}
+	}
+
+	for _, p := range added {
+		if covered[p] || isRenameTarget(p, plan.Rename) || existing(p) {
+			continue
+		}
+		plan.Add = append(plan.Add, p)
+	}
+	for _, p := range dropped {
+		if covered[p] || renamed[p] != "" || !existing(p) {
+			continue
+		}
+		plan.Obsolete = append(plan.Obsolete, p)
+	}
+
+	return plan
+}
+
+// renameCandidate picks the added sibling a dropped path was most likely renamed to:
+// the unique candidate sharing the longest name prefix (at least 3 characters),
+// or the only candidate when exactly one path was dropped and one added.
+func renameCandidate(p string, dropped, added []string, renamed map[string]string) string {
+	taken := make(map[string]bool, len(renamed))
+	for _, n := range renamed {
+		taken[n] = true
+	}
+
+	name := p[strings.LastIndex(p, ".")+1:]
+	best, bestLen, unique := "", 0, false
+	for _, candidate := range added {
+		if taken[candidate] {
+			continue
+		}
+		n := commonPrefixLen(name, candidate[strings.LastIndex(candidate, ".")+1:])
+		switch {
+		case n > bestLen:
+			best, bestLen, unique = candidate, n, true
+		case n == bestLen:
+			unique = false
+		}
+	}
+	if bestLen >= 3 && unique {
+		return best
+	}
+
+	if len(dropped) == 1 && len(added) == 1 && !taken[added[0]] {
+		return added[0]
+	}
+	return ""
+}
+
+// commonPrefixLen returns the length of the common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// under returns the renamed ancestor-or-self of p, or empty string.
+func under(p string, renamed map[string]string) string {
+	for old := range renamed {
+		if p == old || pkgchassis.IsDescendantOf(p, old) {
+			return old
+		}
+	}
+	return ""
+}
+
+// isRenameTarget reports whether p is the new path of a rename.
+func isRenameTarget(p string, renames []TemplateRename) bool {
+	for _, r := range renames {
+		if r.New == p {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks if slice contains value.
+func contains(slice []string, value string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package chassis
+
+import "sync"
+
+// Trace events reported for every file considered by loaders and rewriters.
+const (
+	TraceRead    = "read"    // file was read and parsed
+	TraceSkip    = "skip"    // file was ignored, detail explains why
+	TraceMatch   = "match"   // file references the requested chassis path
+	TraceNoMatch = "nomatch" // file was parsed but doesn't reference the path
+	TraceWrite   = "write"   // file was rewritten
+	TraceRetry   = "retry"   // file operation failed transiently and is retried
+)
+
+// Tracer receives file-level events for --trace output.
+type Tracer interface {
+	File(path, event, detail string)
+}
+
+type nopTracer struct{}
+
+func (nopTracer) File(string, string, string) {}
+
+var tracer Tracer = nopTracer{}
+
+// SetTracer installs the tracer used by loaders and rewriters.
+// Passing nil disables tracing.
+func SetTracer(t Tracer) {
+	if t == nil {
+		t = nopTracer{}
+	}
+	tracer = t
+}
+
+// MultiTracer returns a tracer forwarding events to every non-nil tracer.
+func MultiTracer(tracers ...Tracer) Tracer {
+	var m multiTracer
+	for _, t := range tracers {
+		if t != nil {
+			m = append(m, t)
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+type multiTracer []Tracer
+
+func (m multiTracer) File(path, event, detail string) {
+	for _, t := range m {
+		t.File(path, event, detail)
+	}
+}
+
+// FileCounts summarizes the files a loader or rewriter considered.
+type FileCounts struct {
+	Scanned int `json:"scanned"` // files considered
+	Matched int `json:"matched"` // files referencing the requested chassis path
+	Updated int `json:"updated"` // files rewritten
+	Skipped int `json:"skipped"` // files ignored: unreadable, unparsable or not written
+	Failed  int `json:"failed"`  // files that could not be written
+}
+
+// CountFiles runs fn and counts the files it reports to the tracer, in
+// addition to the installed tracer. Failed counts the file errors of the
+// returned error, see [FileErrors].
+func CountFiles(fn func() error) (FileCounts, error) {
+	counter := &fileCounter{events: make(map[string]map[string]bool)}
+	installed := tracer
+	tracer = multiTracer{installed, counter}
+	defer func() { tracer = installed }()
+
+	err := fn()
+	counts := counter.counts()
+	counts.Failed = len(FileErrors(err))
+	return counts, err
+}
+
+// fileCounter records the events received per file.
+type fileCounter struct {
+	mu     sync.Mutex
+	events map[string]map[string]bool
+}
+
+func (c *fileCounter) File(path, event, _ string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.events[path] == nil {
+		c.events[path] = make(map[string]bool)
+	}
+	c.events[path][event] = true
+}
+
+func (c *fileCounter) counts() FileCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := FileCounts{Scanned: len(c.events)}
+	for _, events := range c.events {
+		if events[TraceMatch] || events[TraceWrite] {
+			counts.Matched++
+		}
+		if events[TraceWrite] {
+			counts.Updated++
+		}
+		if events[TraceSkip] {
+			counts.Skipped++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,141 @@
+package chassis
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// transaction holds the original content of the files changed since
+// [BeginTransaction], if any.
+var transaction struct {
+	sync.Mutex
+	active bool
+	// original is nil for files that didn't exist before they were written.
+	original map[string][]byte
+	// moved holds the files and directories moved by [MoveFiles], by destination.
+	moved map[string]journaledMove
+	order []string
+}
+
+// journaledMove is a move of a file or directory below dir to undo on
+// rollback.
+type journaledMove struct {
+	dir    string
+	move   FileMove
+	useGit bool
+}
+
+// BeginTransaction starts recording the files written or removed, so
+// that a change spanning chassis.yaml, playbooks and node files can be
+// undone as a whole by [RollbackTransaction]. The original content is held
+// in memory until [CommitTransaction].
+func BeginTransaction() {
+	transaction.Lock()
+	defer transaction.Unlock()
+	transaction.active = true
+	transaction.original = make(map[string][]byte)
+	transaction.moved = make(map[string]journaledMove)
+	transaction.order = nil
+}
+
+// CommitTransaction stops recording and keeps the changes.
+func CommitTransaction() {
+	transaction.Lock()
+	defer transaction.Unlock()
+	transaction.active = false
+	transaction.original = nil
+	transaction.moved = nil
+	transaction.order = nil
+}
+
+// RollbackTransaction stops recording and restores the files changed
+// since [BeginTransaction], newest first: written files get their original
+// content back, created files are removed and moved files are moved back.
+// Files that fail don't stop the others; their errors are joined.
+func RollbackTransaction() error {
+	transaction.Lock()
+	original, moved, order := transaction.original, transaction.moved, transaction.order
+	transaction.active = false
+	transaction.original = nil
+	transaction.moved = nil
+	transaction.order = nil
+	transaction.Unlock()
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		path := order[i]
+		if m, ok := moved[path]; ok {
+			if err := m.undo(); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			tracer.File(filepath.Join(m.dir, m.move.Old), TraceWrite, "rolled back")
+			continue
+		}
+		data := original[path]
+		if data == nil {
+			err := retryFileOp("remove", path, tracer.File, func() error { return os.Remove(path) })
+			if err != nil && !os.IsNotExist(err) {
+				errs = append(errs, checkPermission("remove", path, err))
+				continue
+			}
+		} else if err := writeFile(path, data); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tracer.File(path, TraceWrite, "rolled back")
+	}
+	return errors.Join(errs...)
+}
+
+// journalFile records the content of path before it is first written or
+// removed while a transaction is active.
+func journalFile(path string) error {
+	transaction.Lock()
+	defer transaction.Unlock()
+	if !transaction.active {
+		return nil
+	}
+	if _, ok := transaction.original[path]; ok {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		data = nil
+	case err != nil:
+		return checkPermission("read", path, err)
+	case data == nil:
+		data = []byte{}
+	}
+	transaction.original[path] = data
+	transaction.order = append(transaction.order, path)
+	return nil
+}
+
+// journalMove records a move below dir, applied with git mv if useGit is
+// set, while a transaction is active.
+func journalMove(dir string, m FileMove, useGit bool) {
+	transaction.Lock()
+	defer transaction.Unlock()
+	if !transaction.active {
+		return
+	}
+	dst := filepath.Join(dir, m.New)
+	transaction.moved[dst] = journaledMove{dir: dir, move: m, useGit: useGit}
+	transaction.order = append(transaction.order, dst)
+}
+
+// undo moves the file or directory back the way it was moved.
+func (m journaledMove) undo() error {
+	if m.useGit {
+		_, err := runGit(m.dir, "mv", m.move.New, m.move.Old)
+		return err
+	}
+	src, dst := filepath.Join(m.dir, m.move.New), filepath.Join(m.dir, m.move.Old)
+	return checkPermission("move", src, retryFileOp("move", src, tracer.File, func() error {
+		return os.Rename(src, dst)
+	}))
+}
@@ -0,0 +1,188 @@
+// Package cli provides behaviors shared by chassis actions through the plugin runtime
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// WithDryRun provides a composition for actions supporting the global --dry-run option.
+type WithDryRun struct {
+	dryRun bool
+}
+
+// SetDryRun enables or disables dry-run mode.
+func (w *WithDryRun) SetDryRun(v bool) {
+	w.dryRun = v
+}
+
+// DryRun reports whether the action must not modify any files.
+func (w *WithDryRun) DryRun() bool {
+	return w.dryRun
+}
+
+// WithStrict provides a composition for actions supporting the global
+// --strict option. Soft failures the action works around, such as node files
+// that can't be loaded or files a rename couldn't update, are recorded with
+// [WithStrict.Degrade] and fail the action in strict mode.
+type WithStrict struct {
+	strict   bool
+	degraded []error
+}
+
+// SetStrict enables or disables strict mode.
+func (w *WithStrict) SetStrict(v bool) {
+	w.strict = v
+}
+
+// Strict reports whether soft failures and warnings must fail the action.
+func (w *WithStrict) Strict() bool {
+	return w.strict
+}
+
+// Degrade records a soft failure. The action carries on either way.
+func (w *WithStrict) Degrade(what string, err error) {
+	w.degraded = append(w.degraded, fmt.Errorf("%s: %w", what, err))
+}
+
+// Degraded returns the soft failures recorded in strict mode, joined, or nil.
+func (w *WithStrict) Degraded() error {
+	if !w.strict || len(w.degraded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: %w", errors.Join(w.degraded...))
+}
+
+// WithMessages provides a composition for actions reporting catalog messages.
+// Reported messages are printed and collected for the JSON result.
+type WithMessages struct {
+	term     *launchr.Terminal
+	messages []message.Message
+}
+
+// SetMessageTerm sets the terminal messages are printed to.
+func (w *WithMessages) SetMessageTerm(term *launchr.Terminal) {
+	w.term = term
+}
+
+// Report prints the catalog message code and records it.
+func (w *WithMessages) Report(code message.Code, args ...any) {
+	m := message.New(code, args...)
+	w.messages = append(w.messages, m)
+	if w.term == nil {
+		return
+	}
+	switch m.Level {
+	case message.LevelSuccess:
+		w.term.Success().Println(m.Text)
+	case message.LevelWarning:
+		w.term.Warning().Println(m.Text)
+	case message.LevelError:
+		w.term.Error().Println(m.Text)
+	default:
+		w.term.Info().Println(m.Text)
+	}
+}
+
+// Messages returns the messages reported so far.
+func (w *WithMessages) Messages() []message.Message {
+	return w.messages
+}
+
+// Tracer prints file-level events and phase timings for the global --trace option.
+type Tracer struct {
+	term  *launchr.Terminal
+	start time.Time
+}
+
+// NewTracer creates a tracer writing to term.
+func NewTracer(term *launchr.Terminal) *Tracer {
+	return &Tracer{term: term, start: time.Now()}
+}
+
+// File implements [chassis.Tracer] interface.
+func (t *Tracer) File(path, event, detail string) {
+	if detail != "" {
+		t.term.Printfln("[trace] %-7s %s (%s)", event, path, detail)
+		return
+	}
+	t.term.Printfln("[trace] %-7s %s", event, path)
+}
+
+// Phase marks the start of a phase and returns a function ending it.
+func (t *Tracer) Phase(name string) func() {
+	start := time.Now()
+	return func() {
+		t.term.Printfln("[trace] phase   %s took %s", name, time.Since(start).Round(time.Microsecond))
+	}
+}
+
+// Done prints the total elapsed time since the tracer was created.
+func (t *Tracer) Done() {
+	t.term.Printfln("[trace] total   %s", time.Since(t.start).Round(time.Microsecond))
+}
+
+// WithTrace provides a composition for actions supporting the global --trace option.
+type WithTrace struct {
+	tracer *Tracer
+}
+
+// SetTracer sets the tracer, nil when tracing is disabled.
+func (w *WithTrace) SetTracer(t *Tracer) {
+	w.tracer = t
+}
+
+// Phase marks the start of a traced phase and returns a function ending it.
+// It is a no-op when tracing is disabled.
+func (w *WithTrace) Phase(name string) func() {
+	if w.tracer == nil {
+		return func() {}
+	}
+	return w.tracer.Phase(name)
+}
+
+// Summary counts file events and playbook cache lookups for the global --summary option.
+type Summary struct {
+	mu      sync.Mutex
+	start   time.Time
+	cache   chassis.CacheStats
+	read    int
+	written int
+}
+
+// NewSummary starts counting from now.
+func NewSummary() *Summary {
+	return &Summary{start: time.Now(), cache: chassis.PlaybookCacheStats()}
+}
+
+// File implements [chassis.Tracer] interface.
+func (s *Summary) File(_, event, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch event {
+	case chassis.TraceRead:
+		s.read++
+	case chassis.TraceWrite:
+		s.written++
+	}
+}
+
+// Print writes the summary footer to term.
+func (s *Summary) Print(term *launchr.Terminal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cache := chassis.PlaybookCacheStats().Sub(s.cache)
+	hitRate := "unused"
+	if lookups := cache.Hits + cache.Misses; lookups > 0 {
+		hitRate = fmt.Sprintf("%d/%d hits (%.0f%%)", cache.Hits, lookups, cache.HitRate()*100)
+	}
+	term.Printfln("[summary] %s elapsed, %d file(s) read, %d file(s) written, playbook cache %s",
+		time.Since(s.start).Round(time.Millisecond), s.read, s.written, hitRate)
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+// PrintFileErrors lists per-file failures with their suggestions.
+func PrintFileErrors(term *launchr.Terminal, errs []chassis.FileError) {
+	for _, e := range errs {
+		term.Printfln("  - %s", e.Error)
+		if e.Suggestion != "" {
+			term.Printfln("    %s", e.Suggestion)
+		}
+	}
+}
+
+// WarnAliasExpansion reports that saving writes out YAML aliases of chassis.yaml.
+func (w *WithMessages) WarnAliasExpansion(c *chassis.Chassis) {
+	if c.AliasesExpanded() {
+		w.Report(message.AliasesExpanded)
+	}
+}
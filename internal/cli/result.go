@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+)
+
+// NormalizeResult fills nil slices and maps of a result with empty ones,
+// so JSON output never carries null for a collection. Fields tagged
+// omitempty are left alone: they are optional, and an absent field means
+// empty or not applicable. Nested structs, pointers and slice elements
+// are normalized as well.
+func NormalizeResult(result any) {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	normalize(v.Elem())
+}
+
+func normalize(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			normalize(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			normalize(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			field := v.Field(i)
+			if !f.Anonymous && !omitsEmpty(f) {
+				switch {
+				case field.Kind() == reflect.Slice && field.IsNil():
+					field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+				case field.Kind() == reflect.Map && field.IsNil():
+					field.Set(reflect.MakeMap(field.Type()))
+				}
+			}
+			normalize(field)
+		}
+	}
+}
+
+// omitsEmpty reports whether a struct field is tagged json omitempty or skipped.
+func omitsEmpty(f reflect.StructField) bool {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return true
+	}
+	_, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
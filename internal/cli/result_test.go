@@ -0,0 +1,113 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/actions/add"
+	"github.com/plasmash/plasmactl-chassis/actions/adopt"
+	"github.com/plasmash/plasmactl-chassis/actions/allocate"
+	"github.com/plasmash/plasmactl-chassis/actions/attach"
+	"github.com/plasmash/plasmactl-chassis/actions/auditcompare"
+	"github.com/plasmash/plasmactl-chassis/actions/balance"
+	"github.com/plasmash/plasmactl-chassis/actions/bootstrap"
+	"github.com/plasmash/plasmactl-chassis/actions/capabilities"
+	"github.com/plasmash/plasmactl-chassis/actions/children"
+	"github.com/plasmash/plasmactl-chassis/actions/compare"
+	"github.com/plasmash/plasmactl-chassis/actions/components"
+	"github.com/plasmash/plasmactl-chassis/actions/deallocate"
+	"github.com/plasmash/plasmactl-chassis/actions/detach"
+	"github.com/plasmash/plasmactl-chassis/actions/diff"
+	"github.com/plasmash/plasmactl-chassis/actions/exists"
+	"github.com/plasmash/plasmactl-chassis/actions/explain"
+	"github.com/plasmash/plasmactl-chassis/actions/export"
+	"github.com/plasmash/plasmactl-chassis/actions/gc"
+	"github.com/plasmash/plasmactl-chassis/actions/grep"
+	"github.com/plasmash/plasmactl-chassis/actions/impact"
+	"github.com/plasmash/plasmactl-chassis/actions/importer"
+	"github.com/plasmash/plasmactl-chassis/actions/instantiate"
+	"github.com/plasmash/plasmactl-chassis/actions/lint"
+	"github.com/plasmash/plasmactl-chassis/actions/list"
+	"github.com/plasmash/plasmactl-chassis/actions/migrate"
+	"github.com/plasmash/plasmactl-chassis/actions/migratelayout"
+	"github.com/plasmash/plasmactl-chassis/actions/move"
+	"github.com/plasmash/plasmactl-chassis/actions/nodes"
+	"github.com/plasmash/plasmactl-chassis/actions/overview"
+	"github.com/plasmash/plasmactl-chassis/actions/parent"
+	"github.com/plasmash/plasmactl-chassis/actions/policy"
+	"github.com/plasmash/plasmactl-chassis/actions/query"
+	"github.com/plasmash/plasmactl-chassis/actions/remove"
+	"github.com/plasmash/plasmactl-chassis/actions/rename"
+	"github.com/plasmash/plasmactl-chassis/actions/reorder"
+	"github.com/plasmash/plasmactl-chassis/actions/restore"
+	"github.com/plasmash/plasmactl-chassis/actions/show"
+	"github.com/plasmash/plasmactl-chassis/actions/split"
+	"github.com/plasmash/plasmactl-chassis/actions/templateupgrade"
+	"github.com/plasmash/plasmactl-chassis/actions/validate"
+	"github.com/plasmash/plasmactl-chassis/actions/verifynodes"
+	"github.com/plasmash/plasmactl-chassis/actions/visualize"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+// TestNormalizeResultGolden marshals every action result without any
+// collection set, which must produce empty arrays and objects, not null.
+func TestNormalizeResultGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		result any
+	}{
+		{"add", &add.AddResult{}},
+		{"adopt", &adopt.AdoptResult{}},
+		{"allocate", &allocate.AllocateResult{}},
+		{"attach", &attach.AttachResult{}},
+		{"auditcompare", &auditcompare.AuditResult{}},
+		{"balance", &balance.BalanceResult{}},
+		{"bootstrap", &bootstrap.BootstrapResult{}},
+		{"capabilities", &capabilities.CapabilitiesResult{}},
+		{"children", &children.ChildrenResult{}},
+		{"compare", &compare.CompareResult{}},
+		{"components", &components.ComponentsResult{}},
+		{"deallocate", &deallocate.DeallocateResult{}},
+		{"detach", &detach.DetachResult{}},
+		{"diff", &diff.DiffResult{}},
+		{"exists", &exists.ExistsResult{}},
+		{"explain", &explain.ExplainResult{}},
+		{"export", &export.ExportResult{}},
+		{"gc", &gc.GCResult{}},
+		{"grep", &grep.GrepResult{}},
+		{"impact", &impact.ImpactResult{}},
+		{"importer", &importer.ImportResult{}},
+		{"instantiate", &instantiate.InstantiateResult{}},
+		{"lint", &lint.LintResult{}},
+		{"list", &list.ListResult{}},
+		{"migrate", &migrate.MigrateResult{}},
+		{"migratelayout", &migratelayout.MigrateLayoutResult{}},
+		{"move", &move.MoveResult{}},
+		{"nodes", &nodes.NodesResult{}},
+		{"overview", &overview.OverviewResult{}},
+		{"parent", &parent.ParentResult{}},
+		{"policy", &policy.PolicyResult{}},
+		{"query", &query.QueryResult{}},
+		{"remove", &remove.RemoveResult{}},
+		{"rename", &rename.RenameResult{}},
+		{"reorder", &reorder.ReorderResult{}},
+		{"restore", &restore.RestoreResult{}},
+		{"show", &show.ShowResult{}},
+		{"split", &split.SplitResult{}},
+		{"templateupgrade", &templateupgrade.TemplateUpgradeResult{}},
+		{"validate", &validate.ValidateResult{}},
+		{"verifynodes", &verifynodes.VerifyNodesResult{}},
+		{"visualize", &visualize.VisualizeResult{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli.NormalizeResult(tt.result)
+			data, err := json.MarshalIndent(tt.result, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden.Compare(t, "results/"+tt.name+".json", append(data, '\n'))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-chassis/actions/compare"
+	"github.com/plasmash/plasmactl-chassis/actions/components"
+	"github.com/plasmash/plasmactl-chassis/actions/diff"
+	"github.com/plasmash/plasmactl-chassis/actions/list"
+	"github.com/plasmash/plasmactl-chassis/actions/overview"
+	"github.com/plasmash/plasmactl-chassis/actions/query"
+	"github.com/plasmash/plasmactl-chassis/actions/show"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+)
+
+// viewComponent is the only component attached in the cognition layer of
+// the fixture repository.
+const viewComponent = "cognition.data.postgres"
+
+// runView executes r with output disabled and returns its result as JSON.
+func runView(t *testing.T, r golden.Runner) string {
+	t.Helper()
+	r.SetTerm(launchr.Term())
+	_ = r.Execute() // query fails when nothing matched, which is checked on the result
+	data, err := json.Marshal(r.Result())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// attachmentViews report whether an action lists viewComponent for the
+// repository at dir. other is a copy of the repository without the
+// cognition layer playbook, for actions comparing two repositories.
+var attachmentViews = []struct {
+	name     string
+	attached func(t *testing.T, dir, other string) bool
+}{
+	{"show", func(t *testing.T, dir, _ string) bool {
+		return strings.Contains(runView(t, &show.Show{Dir: dir, Chassis: "platform.cognition"}), viewComponent)
+	}},
+	{"list", func(t *testing.T, dir, _ string) bool {
+		return strings.Contains(runView(t, &list.List{Dir: dir, Tree: true}), viewComponent)
+	}},
+	{"query", func(t *testing.T, dir, _ string) bool {
+		return strings.Contains(runView(t, &query.Query{Dir: dir, Identifier: viewComponent}), "platform.cognition.data")
+	}},
+	{"components", func(t *testing.T, dir, _ string) bool {
+		return strings.Contains(runView(t, &components.Components{Dir: dir}), viewComponent)
+	}},
+	{"compare", func(t *testing.T, dir, other string) bool {
+		return strings.Contains(runView(t, &compare.Compare{Dir: dir, Other: other}), viewComponent)
+	}},
+	{"diff", func(t *testing.T, dir, other string) bool {
+		return strings.Contains(runView(t, &diff.Diff{Dir: dir, From: other}), viewComponent)
+	}},
+	{"overview", func(t *testing.T, dir, _ string) bool {
+		o := &overview.Overview{Dir: dir}
+		runView(t, o)
+		for _, l := range o.Result().(*overview.OverviewResult).Layers {
+			if l.Path == "platform.cognition" {
+				return l.Attachments > 0
+			}
+		}
+		return false
+	}},
+}
+
+// TestViewsLoadAttachments checks that every action listing attachments
+// reads playbooks the same way.
+func TestViewsLoadAttachments(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, dir string)
+		attached bool
+	}{
+		{"yaml", func(*testing.T, string) {}, true},
+		{"yml", func(t *testing.T, dir string) {
+			playbook := filepath.Join(dir, "src", "cognition", "cognition")
+			if err := os.Rename(playbook+".yaml", playbook+".yml"); err != nil {
+				t.Fatal(err)
+			}
+		}, true},
+		{"merge-key", func(t *testing.T, dir string) {
+			golden.WriteFile(t, dir, "src/cognition/cognition.yaml", `- &data
+  hosts: platform.cognition.data
+  roles: []
+- <<: *data
+  roles:
+    - cognition.data.postgres
+`)
+		}, true},
+		{"ignored", func(t *testing.T, dir string) {
+			golden.WriteFile(t, dir, ".gitignore", "/src/cognition/cognition.yaml\n")
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := golden.Repo(t)
+			tt.setup(t, dir)
+			other := golden.CopyRepo(t, filepath.Join(filepath.Dir(dir), "other"))
+			if err := os.RemoveAll(filepath.Join(other, "src", "cognition")); err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range attachmentViews {
+				if got := v.attached(t, dir, other); got != tt.attached {
+					t.Errorf("%s: attached = %v, want %v", v.name, got, tt.attached)
+				}
+			}
+		})
+	}
+}
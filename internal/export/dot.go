@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// DOTOptions control how the Graphviz digraph is generated.
+type DOTOptions struct {
+	Config chassis.DOTConfig
+	// ExcludeQuarantined drops quarantined nodes instead of dashing them.
+	ExcludeQuarantined bool
+	// Filter selects the chassis paths drawn with their nodes and
+	// components, all if nil. Their ancestors are drawn to keep the tree
+	// connected.
+	Filter *chassis.PathFilter
+}
+
+// Default attributes of the DOT export, overridden by export.dot.
+var (
+	dotGraphAttrs     = map[string]string{"rankdir": "LR"}
+	dotChassisAttrs   = map[string]string{"shape": "folder"}
+	dotNodeAttrs      = map[string]string{"shape": "box", "style": "rounded"}
+	dotComponentAttrs = map[string]string{"shape": "component"}
+)
+
+// RenderDOT renders the chassis as a Graphviz digraph. Edges lead from
+// every path to its children, to the nodes directly allocated to it, with
+// expressions expanded, and, dashed, to the components attached to it.
+// Vertex IDs are prefixed by their kind, e.g. "node:node-01@prod", and
+// every vertex has a class attribute naming its kind, so SVG renderings
+// can be styled with CSS.
+func RenderDOT(c *chassis.Chassis, nodes []chassis.Node, attachments []chassis.Attachment, opts DOTOptions) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Generated by plasmactl chassis:export --format dot\n")
+	b.WriteString("digraph chassis {\n")
+	fmt.Fprintf(&b, "  graph %s;\n", dotAttrs(dotGraphAttrs, opts.Config.Graph, nil))
+
+	b.WriteString("\n  // chassis\n")
+	paths := opts.Filter.Tree(c.Flatten())
+	for _, p := range paths {
+		segments := strings.Split(p, ".")
+		fmt.Fprintf(&b, "  %s %s;\n", dotID("chassis:"+p), dotAttrs(dotChassisAttrs, opts.Config.Chassis, map[string]string{
+			"class": "chassis",
+			"label": segments[len(segments)-1],
+		}))
+	}
+	for _, p := range paths {
+		if parent := pkgchassis.Parent(p); parent != "" {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotID("chassis:"+parent), dotID("chassis:"+p))
+		}
+	}
+
+	sorted := make([]chassis.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !opts.ExcludeQuarantined || !n.Quarantined {
+			sorted = append(sorted, n)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Platform != sorted[j].Platform {
+			return sorted[i].Platform < sorted[j].Platform
+		}
+		return sorted[i].Hostname < sorted[j].Hostname
+	})
+	if len(sorted) > 0 {
+		b.WriteString("\n  // nodes\n")
+	}
+	for _, n := range sorted {
+		allocated := opts.Filter.Filter(c.ExpandAllocations(n.Chassis))
+		if opts.Filter != nil && len(allocated) == 0 {
+			continue
+		}
+		name := chassis.NodeName(n.Hostname, n.Platform)
+		extra := map[string]string{"class": "node", "label": name}
+		if n.Quarantined {
+			extra["class"] = "node quarantined"
+			extra["style"] = strings.Trim(dotMerge(dotNodeAttrs, opts.Config.Node)["style"]+",dashed", ",")
+		}
+		fmt.Fprintf(&b, "  %s %s;\n", dotID("node:"+name), dotAttrs(dotNodeAttrs, opts.Config.Node, extra))
+		for _, p := range allocated {
+			if p = strings.TrimSpace(p); c.Exists(p) {
+				fmt.Fprintf(&b, "  %s -> %s;\n", dotID("chassis:"+p), dotID("node:"+name))
+			}
+		}
+	}
+
+	components := make(map[string]bool)
+	edges := make(map[string]bool)
+	var lines []string
+	for _, a := range attachments {
+		if !c.Exists(a.Chassis) || !opts.Filter.Matches(a.Chassis) {
+			continue
+		}
+		if !components[a.Component] {
+			components[a.Component] = true
+			lines = append(lines, fmt.Sprintf("  %s %s;", dotID("component:"+a.Component),
+				dotAttrs(dotComponentAttrs, opts.Config.Component, map[string]string{"class": "component", "label": a.Component})))
+		}
+		edge := a.Chassis + " " + a.Component
+		if edges[edge] {
+			continue
+		}
+		edges[edge] = true
+		attrs := map[string]string{"style": "dashed"}
+		if a.Version != "" {
+			attrs["label"] = a.Version
+		}
+		lines = append(lines, fmt.Sprintf("  %s -> %s %s;", dotID("chassis:"+a.Chassis), dotID("component:"+a.Component),
+			dotAttrs(attrs, nil, nil)))
+	}
+	if len(lines) > 0 {
+		b.WriteString("\n  // components\n")
+		b.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// dotMerge returns defaults overridden by custom.
+func dotMerge(defaults, custom map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(custom))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range custom {
+		merged[k] = v
+	}
+	return merged
+}
+
+// dotAttrs renders an attribute list, e.g. [label="cluster", shape="folder"],
+// from defaults overridden by custom, then by fixed.
+func dotAttrs(defaults, custom, fixed map[string]string) string {
+	merged := dotMerge(dotMerge(defaults, custom), fixed)
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + dotID(merged[k])
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// dotID quotes a DOT identifier.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,171 @@
+// Package export renders chassis data into formats consumed by other tools
+package export
+
+import (
+	"errors"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// InventoryOptions control how an Ansible inventory is generated.
+type InventoryOptions struct {
+	Distribution pkgchassis.Strategy
+	Limits       pkgchassis.Limits
+	// HostVars maps node file fields to host variables, see [chassis.InventoryConfig].
+	HostVars map[string]string
+	// ExcludeQuarantined drops quarantined nodes from the inventory.
+	ExcludeQuarantined bool
+	// Filter selects the chassis paths exported, all if nil. Their
+	// ancestors are kept as groups without hosts of their own.
+	Filter *chassis.PathFilter
+}
+
+// QuarantinedGroup is the inventory group listing quarantined nodes, so
+// playbooks can skip them with "hosts: <group>:!quarantined".
+const QuarantinedGroup = "quarantined"
+
+// Group is an Ansible inventory group.
+type Group struct {
+	Hosts    map[string]map[string]interface{} `yaml:"hosts,omitempty"`
+	Children map[string]*Group                 `yaml:"children,omitempty"`
+}
+
+// Inventory is an Ansible YAML inventory.
+type Inventory struct {
+	All struct {
+		Hosts    map[string]map[string]interface{} `yaml:"hosts,omitempty"`
+		Children map[string]*Group                 `yaml:"children,omitempty"`
+	} `yaml:"all"`
+}
+
+// BuildInventory generates an Ansible inventory with one group per chassis path.
+// Groups are nested under their parent path through children, so the inventory
+// graph mirrors the chassis tree. Since Ansible groups inherit the hosts of their
+// children, a node is listed only in the deepest groups it is effectively allocated to.
+// Quarantined nodes are also listed in [QuarantinedGroup], or left out entirely
+// with ExcludeQuarantined. With a Filter, hosts are only listed in the selected
+// groups.
+func BuildInventory(c *chassis.Chassis, nodes []chassis.Node, opts InventoryOptions) (*Inventory, error) {
+	var errs []error
+	for _, p := range c.Flatten() {
+		if err := opts.Limits.CheckGroupName(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	distributor, err := pkgchassis.NewDistributor(opts.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ExcludeQuarantined {
+		var kept []chassis.Node
+		for _, n := range nodes {
+			if !n.Quarantined {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	}
+
+	// Hosts of several platforms are listed once, with all their paths
+	allocs := make(map[string][]string)
+	for _, n := range pkgchassis.Allocate(c.Chassis, distributor, chassis.DeclaredNodes(nodes)) {
+		allocs[n.Hostname] = append(allocs[n.Hostname], n.Paths()...)
+	}
+
+	inv := &Inventory{}
+	inv.All.Hosts = hostVars(nodes, opts.HostVars)
+	inv.All.Children = make(map[string]*Group)
+
+	// Flatten lists parents before children
+	groups := make(map[string]*Group)
+	for _, p := range opts.Filter.Tree(c.Flatten()) {
+		g := &Group{}
+		groups[p] = g
+		parent := pkgchassis.Parent(p)
+		if parent == "" {
+			inv.All.Children[pkgchassis.GroupName(p)] = g
+			continue
+		}
+		pg := groups[parent]
+		if pg.Children == nil {
+			pg.Children = make(map[string]*Group)
+		}
+		pg.Children[pkgchassis.GroupName(p)] = g
+	}
+
+	hostnames := make([]string, 0, len(allocs))
+	for hostname := range allocs {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		paths := opts.Filter.Filter(allocs[hostname])
+		inherited := make(map[string]bool)
+		for _, p := range paths {
+			if parent := pkgchassis.Parent(p); parent != "" {
+				inherited[parent] = true
+			}
+		}
+		for _, p := range paths {
+			g, ok := groups[p]
+			if !ok || inherited[p] {
+				// Path missing from chassis.yaml, or host inherited from a child group
+				continue
+			}
+			if g.Hosts == nil {
+				g.Hosts = make(map[string]map[string]interface{})
+			}
+			g.Hosts[hostname] = nil
+		}
+	}
+
+	for _, n := range nodes {
+		if !n.Quarantined {
+			continue
+		}
+		q := inv.All.Children[QuarantinedGroup]
+		if q == nil {
+			q = &Group{Hosts: make(map[string]map[string]interface{})}
+			inv.All.Children[QuarantinedGroup] = q
+		}
+		q.Hosts[n.Hostname] = nil
+	}
+
+	return inv, nil
+}
+
+// hostVars collects allowlisted node file fields per hostname.
+// Nodes without any allowlisted field are omitted.
+func hostVars(nodes []chassis.Node, mapping map[string]string) map[string]map[string]interface{} {
+	if len(mapping) == 0 {
+		return nil
+	}
+	result := make(map[string]map[string]interface{})
+	for _, n := range nodes {
+		for field, name := range mapping {
+			v, ok := n.Fields[field]
+			if !ok {
+				continue
+			}
+			if result[n.Hostname] == nil {
+				result[n.Hostname] = make(map[string]interface{})
+			}
+			result[n.Hostname][name] = v
+		}
+	}
+	return result
+}
+
+// Marshal renders the inventory as YAML.
+func (inv *Inventory) Marshal() ([]byte, error) {
+	return yaml.Marshal(inv)
+}
@@ -0,0 +1,85 @@
+package export_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/actions/list"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+	"github.com/plasmash/plasmactl-chassis/internal/golden"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// TestInventoryNesting checks that the children of inventory groups mirror
+// the hierarchy printed by chassis:list --tree, with and without a filter.
+func TestInventoryNesting(t *testing.T) {
+	dir := golden.Repo(t)
+	c, err := chassis.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := chassis.LoadNodes(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		match, exclude string
+	}{
+		{"all", "", ""},
+		{"match", `\.(cluster|storage)`, `kv$`},
+		{"exclude", "", `^platform\.interaction`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &list.List{Dir: dir, Format: list.FormatJSONTree, Match: tt.match, Exclude: tt.exclude}
+			if err := l.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			want := make(map[string][]string)
+			var walk func(parent string, children []*list.TreeNode)
+			walk = func(parent string, children []*list.TreeNode) {
+				for _, child := range children {
+					want[parent] = append(want[parent], pkgchassis.GroupName(child.Path))
+					walk(pkgchassis.GroupName(child.Path), child.Children)
+				}
+			}
+			walk("all", l.Result().(*list.ListResult).Hierarchy)
+
+			filter, err := chassis.NewPathFilter(tt.match, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inv, err := export.BuildInventory(c, nodes, export.InventoryOptions{Filter: filter})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string][]string)
+			var nest func(parent string, children map[string]*export.Group)
+			nest = func(parent string, children map[string]*export.Group) {
+				for name, g := range children {
+					// Quarantined nodes are listed in a group outside the chassis tree
+					if name == export.QuarantinedGroup {
+						continue
+					}
+					got[parent] = append(got[parent], name)
+					nest(name, g.Children)
+				}
+			}
+			nest("all", inv.All.Children)
+
+			for parent := range want {
+				slices.Sort(want[parent])
+			}
+			for parent := range got {
+				slices.Sort(got[parent])
+			}
+			if !maps.EqualFunc(got, want, slices.Equal) {
+				t.Errorf("inventory children differ from chassis:list --tree:\n got %v\nwant %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Labels attached to every node in the Prometheus export. A node allocated
+// to several paths lists them comma-separated with leading and trailing
+// commas, like Consul tags in service discovery, so relabeling regexes can
+// match ".*,platform.foundation.cluster,.*".
+const (
+	LabelPlatform    = "platform"
+	LabelLayer       = "chassis_layer"
+	LabelPath        = "chassis_path"
+	LabelQuarantined = "chassis_quarantined"
+)
+
+// LabelOptions control how monitoring labels are generated.
+type LabelOptions struct {
+	Distribution pkgchassis.Strategy
+	// ExcludeQuarantined drops quarantined nodes instead of labeling them.
+	ExcludeQuarantined bool
+	// Filter selects the chassis paths labeled, all if nil. Nodes
+	// allocated to none of them are left out.
+	Filter *chassis.PathFilter
+}
+
+// TargetGroup is an entry of a Prometheus file_sd_configs file.
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// TargetGroups is a Prometheus file_sd_configs file.
+type TargetGroups []TargetGroup
+
+// BuildTargetGroups generates one target group per node, labeled with its
+// platform and the deepest chassis paths it is effectively allocated to
+// along with their layers. Nodes are sorted by platform, then hostname.
+func BuildTargetGroups(c *chassis.Chassis, nodes []chassis.Node, opts LabelOptions) (TargetGroups, error) {
+	distributor, err := pkgchassis.NewDistributor(opts.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ExcludeQuarantined {
+		var kept []chassis.Node
+		for _, n := range nodes {
+			if !n.Quarantined {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	}
+
+	allocations := pkgchassis.Allocate(c.Chassis, distributor, chassis.DeclaredNodes(nodes))
+	groups := make(TargetGroups, 0, len(nodes))
+	for i, n := range nodes {
+		paths := deepestPaths(opts.Filter.Filter(allocations[i].Paths()))
+		if opts.Filter != nil && len(paths) == 0 {
+			continue
+		}
+		var layers []string
+		for _, p := range paths {
+			if parts := strings.Split(p, "."); len(parts) > 1 && !containsString(layers, parts[1]) {
+				layers = append(layers, parts[1])
+			}
+		}
+		sort.Strings(layers)
+
+		g := TargetGroup{
+			Targets: []string{n.Hostname},
+			Labels: map[string]string{
+				LabelPlatform: n.Platform,
+				LabelLayer:    joinLabel(layers),
+				LabelPath:     joinLabel(paths),
+			},
+		}
+		if n.Quarantined {
+			g.Labels[LabelQuarantined] = "true"
+		}
+		groups = append(groups, g)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Labels[LabelPlatform] != groups[j].Labels[LabelPlatform] {
+			return groups[i].Labels[LabelPlatform] < groups[j].Labels[LabelPlatform]
+		}
+		return groups[i].Targets[0] < groups[j].Targets[0]
+	})
+	return groups, nil
+}
+
+// deepestPaths drops the paths that are ancestors of another path, so only
+// the most specific allocations remain.
+func deepestPaths(paths []string) []string {
+	var deepest []string
+	for _, p := range paths {
+		ancestor := false
+		for _, q := range paths {
+			if pkgchassis.IsDescendantOf(q, p) {
+				ancestor = true
+				break
+			}
+		}
+		if !ancestor {
+			deepest = append(deepest, p)
+		}
+	}
+	sort.Strings(deepest)
+	return deepest
+}
+
+// joinLabel encodes several values in one label value, see [LabelPath].
+func joinLabel(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return "," + strings.Join(values, ",") + ","
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Marshal renders the target groups as indented JSON.
+func (g TargetGroups) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
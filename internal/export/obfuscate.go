@@ -0,0 +1,94 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+)
+
+// MinObfuscationKey is the minimum length of an obfuscation key in bytes.
+const MinObfuscationKey = 16
+
+// Obfuscator replaces infrastructure names by keyed HMAC-SHA256 digests,
+// so exports can be shared without leaking real names. Digests are stable
+// for a key, so obfuscated exports can still be compared with each other,
+// and can't be reversed or recomputed without the key.
+type Obfuscator struct {
+	key []byte
+	// Paths also obfuscates every chassis path segment.
+	Paths bool
+}
+
+// NewObfuscator returns an obfuscator keyed with key.
+func NewObfuscator(key []byte, paths bool) (*Obfuscator, error) {
+	if len(key) < MinObfuscationKey {
+		return nil, fmt.Errorf("obfuscation key must be at least %d bytes", MinObfuscationKey)
+	}
+	return &Obfuscator{key: key, Paths: paths}, nil
+}
+
+// digest returns the hex HMAC of a name, truncated to n characters. The
+// kind keeps a hostname and a segment with the same name apart.
+func (o *Obfuscator) digest(kind, name string, n int) string {
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write([]byte(kind + ":" + name))
+	return hex.EncodeToString(mac.Sum(nil))[:n]
+}
+
+// Hostname returns the obfuscated hostname, e.g. h-3f9a0c2e7b1d4a56.
+func (o *Obfuscator) Hostname(hostname string) string {
+	return "h-" + o.digest("hostname", hostname, 16)
+}
+
+// Path returns the chassis path with every segment obfuscated if
+// [Obfuscator.Paths] is set, e.g. s-1c4e9b2a.s-77d0f3e1.
+func (o *Obfuscator) Path(chassisPath string) string {
+	if !o.Paths {
+		return chassisPath
+	}
+	parts := strings.Split(chassisPath, ".")
+	for i, part := range parts {
+		parts[i] = "s-" + o.digest("segment", part, 8)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Apply returns obfuscated copies of the chassis and nodes. Node file
+// fields, such as addresses exported as host variables, are dropped.
+// Allocation expressions are expanded first when path segments are
+// obfuscated, as patterns wouldn't match the digests.
+func (o *Obfuscator) Apply(c *chassis.Chassis, nodes []chassis.Node) (*chassis.Chassis, []chassis.Node, error) {
+	oc := c
+	if o.Paths {
+		oc = chassis.New()
+		for _, p := range c.Flatten() {
+			if err := oc.Add(o.Path(p)); err != nil {
+				return nil, nil, fmt.Errorf("failed to obfuscate %s: %w", p, err)
+			}
+		}
+	}
+
+	obfuscated := make([]chassis.Node, 0, len(nodes))
+	for _, n := range nodes {
+		entries := n.Chassis
+		if o.Paths {
+			entries = c.ExpandAllocations(n.Chassis)
+		}
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, o.Path(strings.TrimSpace(entry)))
+		}
+		obfuscated = append(obfuscated, chassis.Node{
+			Hostname:        o.Hostname(n.Hostname),
+			Chassis:         paths,
+			Quarantined:     n.Quarantined,
+			Platform:        n.Platform,
+			ChassisDeclared: n.ChassisDeclared,
+		})
+	}
+	return oc, obfuscated, nil
+}
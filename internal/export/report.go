@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"path/filepath"
+	"sort"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+//go:embed report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+// ReportOptions control how the HTML report is built.
+type ReportOptions struct {
+	// Dir is the repository, playbook paths are made relative to it.
+	Dir          string
+	Title        string
+	Distribution pkgchassis.Strategy
+}
+
+// Report is the data rendered by the single-file HTML report of
+// chassis:visualize: the tree with the nodes and components of every path.
+type Report struct {
+	Title      string            `json:"title"`
+	Tree       []*ReportPath     `json:"tree"`
+	Nodes      []ReportNode      `json:"nodes"`
+	Components []ReportComponent `json:"components"`
+}
+
+// ReportPath is a chassis path of the report tree, in chassis.yaml order.
+type ReportPath struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Nodes are effectively allocated to the path, as hostname@platform.
+	Nodes []string `json:"nodes"`
+	// Components are attached to the path itself.
+	Components []string      `json:"components"`
+	Children   []*ReportPath `json:"children"`
+}
+
+// ReportNode is a node with its direct and effective allocations.
+type ReportNode struct {
+	Name        string   `json:"name"` // hostname@platform
+	Display     string   `json:"display"`
+	Hostname    string   `json:"hostname"`
+	Platform    string   `json:"platform"`
+	Direct      []string `json:"direct"`
+	Chassis     []string `json:"chassis"`
+	Quarantined bool     `json:"quarantined,omitempty"`
+}
+
+// ReportComponent is a component with the paths it is attached to.
+type ReportComponent struct {
+	Name        string             `json:"name"`
+	Attachments []ReportAttachment `json:"attachments"`
+}
+
+// ReportAttachment is an attachment of a component to a chassis path.
+type ReportAttachment struct {
+	Chassis  string `json:"chassis"`
+	Playbook string `json:"playbook"`
+	Version  string `json:"version,omitempty"` // version constraint, if any
+}
+
+// BuildReport gathers the report data. Allocations are computed per
+// platform, nodes are sorted by platform and hostname, components by name.
+func BuildReport(c *chassis.Chassis, nodes []chassis.Node, attachments []chassis.Attachment, opts ReportOptions) (*Report, error) {
+	distributor, err := pkgchassis.NewDistributor(opts.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	byPlatform := make(map[string][]chassis.Node)
+	for _, n := range nodes {
+		byPlatform[n.Platform] = append(byPlatform[n.Platform], n)
+	}
+	r := &Report{
+		Title:      opts.Title,
+		Nodes:      make([]ReportNode, 0, len(nodes)),
+		Components: []ReportComponent{},
+	}
+	pathNodes := make(map[string][]string)
+	for _, platformNodes := range byPlatform {
+		allocations := pkgchassis.Allocate(c.Chassis, distributor, chassis.DeclaredNodes(platformNodes))
+		for i, n := range platformNodes {
+			name := n.Hostname + "@" + n.Platform
+			paths := append([]string{}, allocations[i].Paths()...)
+			for _, p := range paths {
+				pathNodes[p] = append(pathNodes[p], name)
+			}
+			r.Nodes = append(r.Nodes, ReportNode{
+				Name:        name,
+				Display:     chassis.NodeName(n.Hostname, n.Platform),
+				Hostname:    n.Hostname,
+				Platform:    n.Platform,
+				Direct:      append([]string{}, n.Chassis...),
+				Chassis:     paths,
+				Quarantined: n.Quarantined,
+			})
+		}
+	}
+	sort.Slice(r.Nodes, func(i, j int) bool {
+		if r.Nodes[i].Platform != r.Nodes[j].Platform {
+			return r.Nodes[i].Platform < r.Nodes[j].Platform
+		}
+		return r.Nodes[i].Hostname < r.Nodes[j].Hostname
+	})
+
+	pathComponents := make(map[string][]string)
+	byComponent := make(map[string][]ReportAttachment)
+	for _, a := range attachments {
+		if rel, err := filepath.Rel(opts.Dir, a.Playbook); err == nil && opts.Dir != "" {
+			a.Playbook = rel
+		}
+		pathComponents[a.Chassis] = appendUnique(pathComponents[a.Chassis], a.Component)
+		byComponent[a.Component] = append(byComponent[a.Component], ReportAttachment{
+			Chassis:  a.Chassis,
+			Playbook: a.Playbook,
+			Version:  a.Version,
+		})
+	}
+	for name, atts := range byComponent {
+		r.Components = append(r.Components, ReportComponent{Name: name, Attachments: atts})
+	}
+	sort.Slice(r.Components, func(i, j int) bool { return r.Components[i].Name < r.Components[j].Name })
+
+	r.Tree = reportTree(c.Tree(), pathNodes, pathComponents)
+	return r, nil
+}
+
+// reportTree copies the chassis tree with the nodes and components of every path.
+func reportTree(tree []*pkgchassis.TreeNode, nodes, components map[string][]string) []*ReportPath {
+	paths := make([]*ReportPath, 0, len(tree))
+	for _, t := range tree {
+		p := &ReportPath{
+			Name:       t.Name,
+			Path:       t.Path,
+			Nodes:      nodes[t.Path],
+			Components: components[t.Path],
+			Children:   reportTree(t.Children, nodes, components),
+		}
+		sort.Strings(p.Nodes)
+		sort.Strings(p.Components)
+		if p.Nodes == nil {
+			p.Nodes = []string{}
+		}
+		if p.Components == nil {
+			p.Components = []string{}
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// appendUnique appends value unless slice contains it.
+func appendUnique(slice []string, value string) []string {
+	if containsString(slice, value) {
+		return slice
+	}
+	return append(slice, value)
+}
+
+// Render renders the report as a self-contained HTML page, with the data
+// embedded as JSON and no external resources.
+func (r *Report) Render() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
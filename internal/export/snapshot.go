@@ -0,0 +1,158 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+)
+
+// SnapshotVersion is the current snapshot format version.
+const SnapshotVersion = 1
+
+// checksumPrefix names the checksum algorithm in [Snapshot.Checksum].
+const checksumPrefix = "sha256:"
+
+// SignaturePlaceholder is replaced by the signature file path in a verify command.
+const SignaturePlaceholder = "{signature}"
+
+// SnapshotNode is the allocation of a node in a snapshot.
+type SnapshotNode struct {
+	Hostname string   `json:"hostname"`
+	Platform string   `json:"platform"`
+	Chassis  []string `json:"chassis"`
+}
+
+// Snapshot is a portable copy of the chassis paths and node allocations,
+// meant to be passed between environments and imported with chassis:import.
+type Snapshot struct {
+	Version  int            `json:"version"`
+	Chassis  []string       `json:"chassis"`
+	Nodes    []SnapshotNode `json:"nodes"`
+	Checksum string         `json:"checksum,omitempty"`
+}
+
+// BuildSnapshot captures the chassis paths and direct node allocations.
+func BuildSnapshot(c *chassis.Chassis, nodes []chassis.Node) *Snapshot {
+	s := &Snapshot{
+		Version: SnapshotVersion,
+		Chassis: c.Flatten(),
+		Nodes:   make([]SnapshotNode, 0, len(nodes)),
+	}
+	for _, n := range nodes {
+		s.Nodes = append(s.Nodes, SnapshotNode{
+			Hostname: n.Hostname,
+			Platform: n.Platform,
+			Chassis:  append([]string{}, n.Chassis...),
+		})
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool {
+		if s.Nodes[i].Platform != s.Nodes[j].Platform {
+			return s.Nodes[i].Platform < s.Nodes[j].Platform
+		}
+		return s.Nodes[i].Hostname < s.Nodes[j].Hostname
+	})
+	return s
+}
+
+// content returns the canonical encoding the checksum is computed over.
+func (s *Snapshot) content() ([]byte, error) {
+	unsealed := *s
+	unsealed.Checksum = ""
+	return json.Marshal(&unsealed)
+}
+
+// Seal embeds the content checksum.
+func (s *Snapshot) Seal() error {
+	data, err := s.content()
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	s.Checksum = checksumPrefix + hex.EncodeToString(sum[:])
+	return nil
+}
+
+// Verify checks the embedded checksum. A snapshot without checksum is
+// reported as unsealed.
+func (s *Snapshot) Verify() error {
+	if s.Checksum == "" {
+		return fmt.Errorf("snapshot has no checksum")
+	}
+	if !strings.HasPrefix(s.Checksum, checksumPrefix) {
+		return fmt.Errorf("unsupported snapshot checksum %q", s.Checksum)
+	}
+	expected := *s
+	if err := expected.Seal(); err != nil {
+		return err
+	}
+	if expected.Checksum != s.Checksum {
+		return fmt.Errorf("snapshot checksum mismatch: content was modified after export")
+	}
+	return nil
+}
+
+// Marshal renders the snapshot as indented JSON.
+func (s *Snapshot) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseSnapshot decodes a snapshot and checks its version.
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", s.Version, SnapshotVersion)
+	}
+	return &s, nil
+}
+
+// SignatureFile returns the detached signature path for a snapshot file.
+func SignatureFile(snapshotFile string) string {
+	return snapshotFile + ".sig"
+}
+
+// Sign runs command through the shell with data on stdin and returns its
+// output as detached signature, e.g. "gpg --detach-sign --armor".
+func Sign(command string, data []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sign command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// VerifySignature runs command through the shell with data on stdin, after
+// replacing [SignaturePlaceholder] with signatureFile, e.g. "gpg --verify {signature} -".
+// The path is passed as a positional parameter, never parsed by the shell.
+// A non-zero exit status means the signature is invalid.
+func VerifySignature(command, signatureFile string, data []byte) error {
+	if _, err := os.Stat(signatureFile); err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", strings.ReplaceAll(command, SignaturePlaceholder, `"$1"`), "sh", signatureFile)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("signature verification failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package export_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plasmash/plasmactl-chassis/internal/export"
+)
+
+// TestVerifySignaturePath checks that the signature path reaches the verify
+// command as one argument, whatever characters it contains.
+func TestVerifySignaturePath(t *testing.T) {
+	// Accepts a signature equal to the data
+	const command = `test "$(cat {signature})" = "$(cat)"`
+	data := []byte("snapshot")
+
+	for _, name := range []string{"snap shot", "snap;touch pwned", "$(touch pwned)", "it's"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			sigFile := export.SignatureFile(filepath.Join(dir, name+".json"))
+			if err := os.WriteFile(sigFile, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			t.Chdir(dir)
+			if err := export.VerifySignature(command, sigFile, data); err != nil {
+				t.Errorf("VerifySignature() = %v", err)
+			}
+			if err := export.VerifySignature(command, sigFile, []byte("tampered")); err == nil {
+				t.Error("VerifySignature() accepted a tampered snapshot")
+			}
+			if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+				t.Error("the signature path was run as a command")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// SSHOptions control how SSH host aliases are generated.
+type SSHOptions struct {
+	Distribution pkgchassis.Strategy
+	Config       chassis.SSHConfig
+	// ExcludeQuarantined drops quarantined nodes instead of marking them.
+	ExcludeQuarantined bool
+	// Filter selects the chassis paths given aliases, all if nil.
+	Filter *chassis.PathFilter
+}
+
+// SSHAlias is a host alias derived from a chassis path, e.g.
+// platform-foundation-cluster-control-1 for the first node of
+// platform.foundation.cluster.control.
+type SSHAlias struct {
+	Alias       string `json:"alias"`
+	Chassis     string `json:"chassis"`
+	Node        string `json:"node"`
+	Platform    string `json:"platform"`
+	HostName    string `json:"hostname"`
+	Quarantined bool   `json:"quarantined,omitempty"`
+}
+
+// BuildSSHAliases numbers the nodes of every chassis path they are
+// effectively allocated to as deepest path, sorted by platform and hostname.
+// Aliases are sorted by chassis path, then number.
+func BuildSSHAliases(c *chassis.Chassis, nodes []chassis.Node, opts SSHOptions) ([]SSHAlias, error) {
+	distributor, err := pkgchassis.NewDistributor(opts.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]chassis.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !opts.ExcludeQuarantined || !n.Quarantined {
+			sorted = append(sorted, n)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Platform != sorted[j].Platform {
+			return sorted[i].Platform < sorted[j].Platform
+		}
+		return sorted[i].Hostname < sorted[j].Hostname
+	})
+
+	byPath := make(map[string][]SSHAlias)
+	allocations := pkgchassis.Allocate(c.Chassis, distributor, chassis.DeclaredNodes(sorted))
+	for i, n := range sorted {
+		hostName := n.Hostname
+		if v, ok := n.Fields[opts.Config.HostField]; ok && opts.Config.HostField != "" && v != nil {
+			hostName = fmt.Sprint(v)
+		}
+		for _, p := range deepestPaths(opts.Filter.Filter(allocations[i].Paths())) {
+			byPath[p] = append(byPath[p], SSHAlias{
+				Alias:       fmt.Sprintf("%s-%d", strings.ReplaceAll(p, ".", "-"), len(byPath[p])+1),
+				Chassis:     p,
+				Node:        n.Hostname,
+				Platform:    n.Platform,
+				HostName:    hostName,
+				Quarantined: n.Quarantined,
+			})
+		}
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for p := range byPath {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	aliases := []SSHAlias{}
+	for _, p := range paths {
+		aliases = append(aliases, byPath[p]...)
+	}
+	return aliases, nil
+}
+
+// RenderSSHConfig renders aliases as Host blocks of an SSH config, grouped
+// by chassis path under a comment naming it.
+func RenderSSHConfig(aliases []SSHAlias, cfg chassis.SSHConfig) []byte {
+	var b bytes.Buffer
+	b.WriteString("# Generated by plasmactl chassis:export --format ssh-config\n")
+	for i, a := range aliases {
+		if i == 0 || aliases[i-1].Chassis != a.Chassis {
+			fmt.Fprintf(&b, "\n# %s\n", a.Chassis)
+		}
+		fmt.Fprintf(&b, "Host %s\n", a.Alias)
+		if a.Quarantined {
+			fmt.Fprintf(&b, "    # %s@%s (quarantined)\n", a.Node, a.Platform)
+		} else {
+			fmt.Fprintf(&b, "    # %s@%s\n", a.Node, a.Platform)
+		}
+		fmt.Fprintf(&b, "    HostName %s\n", a.HostName)
+		if cfg.User != "" {
+			fmt.Fprintf(&b, "    User %s\n", cfg.User)
+		}
+		if cfg.ProxyJump != "" {
+			fmt.Fprintf(&b, "    ProxyJump %s\n", cfg.ProxyJump)
+		}
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,167 @@
+// Package golden runs actions against a fixture repository and compares
+// their terminal output and JSON result with golden files, so tests catch
+// any change of content or order. Run the tests with -update to rewrite
+// the golden files after an intended change.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/launchrctl/launchr"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/pterm/pterm"
+
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// Placeholder replaces the fixture directory in golden files.
+const Placeholder = "<repo>"
+
+// Runner is an action as created by the plugin.
+type Runner interface {
+	SetTerm(*launchr.Terminal)
+	Execute() error
+	Result() any
+}
+
+// Repo copies the fixture repository, testdata/repo of this package, to a
+// temporary directory and returns it.
+func Repo(t testing.TB) string {
+	t.Helper()
+	return CopyRepo(t, filepath.Join(t.TempDir(), "repo"))
+}
+
+// CopyRepo copies the fixture repository to dir and returns it, e.g. for a
+// second repository next to the one of [Repo].
+func CopyRepo(t testing.TB, dir string) string {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	if err := os.CopyFS(dir, os.DirFS(filepath.Join(filepath.Dir(file), "testdata", "repo"))); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// WriteFile writes a file of the repository at dir, e.g. to add a case the
+// fixture doesn't cover.
+func WriteFile(t testing.TB, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Run executes r the way the plugin does and compares its terminal output,
+// followed by the error it returned if any, with testdata/<name>.golden and
+// its JSON result with testdata/<name>.json, relative to the test. Paths
+// below dir and its parent are written relative to [Placeholder].
+func Run(t *testing.T, name, dir string, r Runner) {
+	t.Helper()
+	var out bytes.Buffer
+	term := launchr.Term()
+	term.SetOutput(&out)
+	term.EnableOutput()
+	defer term.DisableOutput()
+	// Golden files hold plain text, without colors
+	pterm.DisableStyling()
+
+	r.SetTerm(term)
+	msgs, reportsMessages := r.(interface {
+		SetMessageTerm(*launchr.Terminal)
+		Messages() []message.Message
+	})
+	if reportsMessages {
+		msgs.SetMessageTerm(term)
+	}
+	err := r.Execute()
+	if strict, ok := r.(interface{ Degraded() error }); ok && err == nil {
+		err = strict.Degraded()
+	}
+	if err != nil {
+		out.WriteString("error: " + err.Error() + "\n")
+	}
+
+	result := r.Result()
+	if l, ok := result.(interface{ SetMessages([]message.Message) }); ok && reportsMessages && !reflect.ValueOf(result).IsNil() {
+		l.SetMessages(msgs.Messages())
+	}
+	cli.NormalizeResult(result)
+	data, jsonErr := json.MarshalIndent(result, "", "  ")
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+
+	Compare(t, name+".golden", relative(out.Bytes(), dir))
+	Compare(t, name+".json", relative(append(data, '\n'), dir))
+}
+
+// Compare compares got with the golden file testdata/<name>, rewriting it
+// instead with -update.
+func Compare(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run with -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(want)),
+			B:        difflib.SplitLines(string(got)),
+			FromFile: path,
+			ToFile:   "got",
+			Context:  3,
+		})
+		t.Errorf("output differs from %s:\n%s", path, diff)
+	}
+}
+
+// CompareFile compares the file at path, relative to dir, as written by an
+// action, with the golden file testdata/<name>.
+func CompareFile(t *testing.T, name, dir, path string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Compare(t, name, relative(data, dir))
+}
+
+// relative replaces dir by [Placeholder] and its parent, holding other
+// repositories of the test, by [Placeholder]/.., also where JSON-escaped.
+func relative(data []byte, dir string) []byte {
+	if dir == "" {
+		return data
+	}
+	s := string(data)
+	for _, r := range [][2]string{{dir, Placeholder}, {filepath.Dir(dir), Placeholder + "/.."}} {
+		escaped, _ := json.Marshal(r[0])
+		s = strings.ReplaceAll(s, strings.Trim(string(escaped), `"`), r[1])
+		s = strings.ReplaceAll(s, r[0], r[1])
+	}
+	return []byte(s)
+}
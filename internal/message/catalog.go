@@ -0,0 +1,273 @@
+package message
+
+// Message codes, grouped by the actions reporting them.
+const (
+	// Shared
+	DryRun          Code = "dry_run"
+	AliasesExpanded Code = "aliases_expanded"
+	FilesNotUpdated Code = "files_not_updated"
+
+	// chassis:add, chassis:remove, chassis:rename, chassis:reorder
+	ChassisExists     Code = "chassis_exists"
+	ChassisAdded      Code = "chassis_added"
+	ChassisReordered  Code = "chassis_reordered"
+	ChassisRemovable  Code = "chassis_removable"
+	ChassisRemoved    Code = "chassis_removed"
+	MetaUpdateFailed  Code = "meta_update_failed"
+	ChassisRenamed    Code = "chassis_renamed"
+	ChassisMerged     Code = "chassis_merged"
+	MergeConflicts    Code = "merge_conflicts"
+	RenameIncomplete  Code = "rename_incomplete"
+	AttachmentsFailed Code = "attachments_update_failed"
+	AllocationsFailed Code = "allocations_update_failed"
+
+	// chassis:move
+	ChassisMoved   Code = "chassis_moved"
+	MoveRolledBack Code = "move_rolled_back"
+
+	// chassis:allocate, chassis:deallocate
+	NodeAllocated        Code = "node_allocated"
+	NodeAlreadyAllocated Code = "node_already_allocated"
+	CapacityExceeded     Code = "capacity_exceeded"
+	NodeDeallocated      Code = "node_deallocated"
+	NodeNotAllocated     Code = "node_not_allocated"
+	NodeLeftUnallocated  Code = "node_left_unallocated"
+
+	// chassis:attach, chassis:detach
+	ComponentAttached        Code = "component_attached"
+	ComponentAlreadyAttached Code = "component_already_attached"
+	ComponentOutsideLayer    Code = "component_outside_layer"
+	ComponentDetached        Code = "component_detached"
+	ComponentNotAttached     Code = "component_not_attached"
+	PlaysLeftEmpty           Code = "plays_left_empty"
+	EmptyPlaysRemoved        Code = "empty_plays_removed"
+
+	// chassis:split
+	ChassisSplit   Code = "chassis_split"
+	SplitUnmatched Code = "split_unmatched"
+
+	// chassis:list, chassis:show, chassis:compare
+	NoChassisPaths Code = "no_chassis_paths"
+	NothingToShow  Code = "nothing_to_show"
+	ChassisMatches Code = "chassis_matches"
+	NoChanges      Code = "no_changes"
+
+	// chassis:diff
+	TopologyUnchanged Code = "topology_unchanged"
+
+	// chassis:show
+	PlatformsUncovered Code = "platforms_uncovered"
+	FactsUnavailable   Code = "facts_unavailable"
+
+	// chassis:show, chassis:query
+	AllocationEmpty Code = "allocation_empty"
+
+	// chassis:rename --dry-run
+	GroupsUnchanged Code = "groups_unchanged"
+	GroupsChanged   Code = "groups_changed"
+
+	// chassis:policy
+	PolicySet       Code = "policy_set"
+	PolicyInactive  Code = "policy_inactive"
+	PolicyCompliant Code = "policy_compliant"
+
+	// chassis:audit-compare
+	AuditMatches       Code = "audit_matches"
+	AuditUnknownGroups Code = "audit_unknown_groups"
+
+	// chassis:restore
+	NoBackups        Code = "no_backups"
+	BackupRestorable Code = "backup_restorable"
+	BackupRestored   Code = "backup_restored"
+	RestoreFailed    Code = "restore_failed"
+
+	// chassis:nodes, chassis:components
+	NoNodes      Code = "no_nodes"
+	NoComponents Code = "no_components"
+
+	// chassis:query
+	QueryAmbiguous Code = "query_ambiguous"
+
+	// chassis:grep
+	ReferencesFound Code = "references_found"
+	NoReferences    Code = "no_references"
+
+	// chassis:validate, chassis:verify-nodes
+	ChassisValid     Code = "chassis_valid"
+	ValidateWarnings Code = "validate_warnings"
+	NodesAllocated   Code = "nodes_allocated"
+	NodesUnallocated Code = "nodes_unallocated"
+	NodesFixed       Code = "nodes_fixed"
+
+	// chassis:instantiate, chassis:template-upgrade
+	TemplateInstantiated    Code = "template_instantiated"
+	TemplateInstanceMissing Code = "template_instance_missing"
+	TemplateNoInstances     Code = "template_no_instances"
+	TemplateUpgraded        Code = "template_upgraded"
+
+	// chassis:export, chassis:import
+	Exported          Code = "exported"
+	SnapshotSigned    Code = "snapshot_signed"
+	SnapshotUnchecked Code = "snapshot_unchecked"
+	Imported          Code = "imported"
+	ImportSkipped     Code = "import_skipped"
+
+	// chassis:balance
+	Balanced         Code = "balanced"
+	BalanceSuggested Code = "balance_suggested"
+	BalanceApplied   Code = "balance_applied"
+	BalanceShort     Code = "balance_short"
+
+	// chassis:bootstrap
+	NodesBootstrapped Code = "nodes_bootstrapped"
+	BootstrapKept     Code = "bootstrap_kept"
+
+	// chassis:adopt
+	NothingToAdopt Code = "nothing_to_adopt"
+	AdoptSuggested Code = "adopt_suggested"
+	Adopted        Code = "adopted"
+
+	// chassis:lint
+	LintFixable  Code = "lint_fixable"
+	NothingToFix Code = "nothing_to_fix"
+	LintFixed    Code = "lint_fixed"
+	LintDeferred Code = "lint_deferred"
+
+	// chassis:migrate
+	FormatCurrent  Code = "format_current"
+	FormatMigrated Code = "format_migrated"
+
+	// chassis:migrate-layout
+	LayoutCurrent  Code = "layout_current"
+	LayoutMigrated Code = "layout_migrated"
+
+	// chassis:gc
+	NothingToPrune Code = "nothing_to_prune"
+	PruneKept      Code = "prune_kept"
+	Pruned         Code = "pruned"
+)
+
+type entry struct {
+	level  Level
+	format string
+}
+
+// catalog holds the English text of every message.
+var catalog = map[Code]entry{
+	DryRun:          {LevelInfo, "[dry-run] No changes will be made"},
+	AliasesExpanded: {LevelWarning, "chassis.yaml uses anchors/aliases; they are expanded in place so the change only affects the requested path"},
+	FilesNotUpdated: {LevelWarning, "%d file(s) could not be updated:"},
+
+	ChassisExists:     {LevelInfo, "Already exists: %s"},
+	ChassisAdded:      {LevelSuccess, "Added: %s"},
+	ChassisReordered:  {LevelSuccess, "Reordered children of %s: %s"},
+	ChassisRemovable:  {LevelSuccess, "Safe to remove: %s"},
+	ChassisRemoved:    {LevelSuccess, "Removed: %s"},
+	MetaUpdateFailed:  {LevelWarning, "Chassis removed but failed to update %s: %s"},
+	ChassisRenamed:    {LevelSuccess, "Renamed: %s -> %s"},
+	ChassisMerged:     {LevelSuccess, "Merged: %s into %s"},
+	MergeConflicts:    {LevelWarning, "%d child path(s) existed under both %s and %s and were merged:"},
+	RenameIncomplete:  {LevelWarning, "Chassis renamed but %d file(s) could not be updated:"},
+	AttachmentsFailed: {LevelWarning, "Failed to update attachments for %s:"},
+	AllocationsFailed: {LevelWarning, "Failed to update allocations for %s:"},
+
+	ChassisMoved:   {LevelSuccess, "Moved: %s -> %s"},
+	MoveRolledBack: {LevelError, "Move rolled back, %d file(s) could not be updated:"},
+
+	ChassisSplit:   {LevelSuccess, "Split %s into %s"},
+	SplitUnmatched: {LevelWarning, "%d assigned node(s) or component(s) are not directly allocated or attached to %s:"},
+
+	NodeAllocated:        {LevelSuccess, "Allocated %s to %s"},
+	NodeAlreadyAllocated: {LevelInfo, "%s is already allocated to %s"},
+	CapacityExceeded:     {LevelWarning, "%s gets %d node(s) of platform %s, max_nodes is %d"},
+	NodeDeallocated:      {LevelSuccess, "Deallocated %s from %s"},
+	NodeNotAllocated:     {LevelInfo, "%s is not directly allocated to %s"},
+	NodeLeftUnallocated:  {LevelWarning, "%s has no allocation left and receives no components"},
+
+	ComponentAttached:        {LevelSuccess, "Attached %s to %s in %s"},
+	ComponentAlreadyAttached: {LevelInfo, "%s is already attached to %s"},
+	ComponentOutsideLayer:    {LevelWarning, "%s is attached outside its layer (expected under %s)"},
+	ComponentDetached:        {LevelSuccess, "Detached %s from %s in %s"},
+	ComponentNotAttached:     {LevelInfo, "%s is not directly attached to %s"},
+	PlaysLeftEmpty:           {LevelWarning, "%d play(s) of %s in %s are left without roles or tasks"},
+	EmptyPlaysRemoved:        {LevelInfo, "Removed %d empty play(s) of %s"},
+
+	NoChassisPaths: {LevelWarning, "No chassis paths found"},
+	NothingToShow:  {LevelInfo, "No allocations or attachments found"},
+	ChassisMatches: {LevelSuccess, "Chassis matches %s"},
+	NoChanges:      {LevelSuccess, "No chassis changes since %s"},
+
+	TopologyUnchanged: {LevelSuccess, "No topology changes between %s and %s"},
+
+	PlatformsUncovered: {LevelWarning, "No nodes on %d platform(s): %s"},
+	FactsUnavailable:   {LevelWarning, "Live facts unavailable: %s"},
+
+	AllocationEmpty: {LevelWarning, "%s has no effective allocation: %s"},
+
+	GroupsUnchanged: {LevelSuccess, "No node changes inventory group membership beyond the renamed groups"},
+	GroupsChanged:   {LevelWarning, "%d node(s) would change inventory group membership:"},
+
+	PolicySet:       {LevelSuccess, "Set policy %s to %q in %s"},
+	PolicyInactive:  {LevelInfo, "No policy rule is enabled"},
+	PolicyCompliant: {LevelSuccess, "Repository complies with the policy (%s)"},
+
+	AuditMatches:       {LevelSuccess, "All %d host(s) match %s"},
+	AuditUnknownGroups: {LevelInfo, "Ignored %d group(s) not derived from the chassis: %s"},
+
+	NoBackups:        {LevelInfo, "No backups found"},
+	BackupRestorable: {LevelInfo, "Would restore %d file(s) from backup %s (%s)"},
+	BackupRestored:   {LevelSuccess, "Restored %d file(s) from backup %s (%s)"},
+	RestoreFailed:    {LevelWarning, "Backup %s kept: %d file(s) could not be restored:"},
+
+	NoNodes:      {LevelInfo, "No nodes found"},
+	NoComponents: {LevelInfo, "No attached components found"},
+
+	QueryAmbiguous: {LevelWarning, "%s names both a node and a component; narrow the query with --kind"},
+
+	ReferencesFound: {LevelInfo, "%d reference(s) in %d file(s), %d to missing paths"},
+	NoReferences:    {LevelInfo, "No references match %s"},
+
+	ChassisValid:     {LevelSuccess, "Chassis is valid"},
+	ValidateWarnings: {LevelInfo, "%d warning(s)"},
+	NodesAllocated:   {LevelSuccess, "All %d nodes are allocated"},
+	NodesUnallocated: {LevelWarning, "%d node(s) without chassis allocation:"},
+	NodesFixed:       {LevelSuccess, "Allocated %d node(s) to %s"},
+
+	TemplateInstantiated:    {LevelSuccess, "Instantiated %s at %s (%d paths)"},
+	TemplateInstanceMissing: {LevelWarning, "Skipping %s: annotated in %s but missing from chassis.yaml"},
+	TemplateNoInstances:     {LevelInfo, "No instances of template %s"},
+	TemplateUpgraded:        {LevelSuccess, "Upgraded %d instance(s) of %s"},
+
+	Exported:          {LevelSuccess, "Exported %s to %s"},
+	SnapshotSigned:    {LevelSuccess, "Signed snapshot: %s"},
+	SnapshotUnchecked: {LevelWarning, "%s has no checksum, its content can't be verified"},
+	Imported:          {LevelSuccess, "Imported %s: %d created, %d updated, %d unchanged"},
+	ImportSkipped:     {LevelWarning, "%d row(s) skipped:"},
+
+	Balanced:         {LevelSuccess, "Children of %s are balanced"},
+	BalanceSuggested: {LevelInfo, "%d allocation(s) suggested; run with --apply to write them"},
+	BalanceApplied:   {LevelSuccess, "Allocated %d node(s) below %s"},
+	BalanceShort:     {LevelWarning, "%d child path(s) stay below %d node(s): not enough unallocated nodes"},
+
+	NodesBootstrapped: {LevelSuccess, "Generated %d node file(s) for platform %s"},
+	BootstrapKept:     {LevelInfo, "Kept %d existing node file(s)"},
+
+	NothingToAdopt: {LevelSuccess, "Every chassis path referenced by playbooks and node files is declared"},
+	AdoptSuggested: {LevelInfo, "%d undeclared path(s) found; run with --apply to add them to chassis.yaml"},
+	Adopted:        {LevelSuccess, "Added %d path(s) to chassis.yaml"},
+
+	LintFixable:  {LevelInfo, "%d finding(s) have automatic fixes; run with --fix to apply them"},
+	NothingToFix: {LevelSuccess, "Nothing to fix"},
+	LintFixed:    {LevelSuccess, "Applied %d fix(es)"},
+	LintDeferred: {LevelInfo, "Fixes of %s change files fixed by another rule; run chassis:lint --fix again"},
+
+	FormatCurrent:  {LevelSuccess, "chassis.yaml already uses the current format (%s)"},
+	FormatMigrated: {LevelSuccess, "Migrated chassis.yaml from %s to %s"},
+
+	LayoutCurrent:  {LevelSuccess, "The repository already uses this layout"},
+	LayoutMigrated: {LevelSuccess, "Migrated layout: moved %d file(s), wrote %d hostname field(s) and updated %s"},
+
+	NothingToPrune: {LevelSuccess, "Nothing to prune"},
+	PruneKept:      {LevelInfo, "Kept %d empty path(s) still referenced by nodes or playbooks: %s"},
+	Pruned:         {LevelSuccess, "Pruned %d path(s) and %d play(s)"},
+}
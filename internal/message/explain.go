@@ -0,0 +1,168 @@
+package message
+
+import "sort"
+
+// Explanation details a message for chassis:explain.
+type Explanation struct {
+	Description string
+	Causes      []string
+	Remediation []string
+}
+
+// explanations holds details for messages that call for operator action.
+var explanations = map[Code]Explanation{
+	AliasesExpanded: {
+		Description: "chassis.yaml shares subtrees through YAML anchors and aliases. Before a change, aliases are replaced by copies of the anchored content, so only the requested path is modified and the saved file no longer uses them.",
+		Causes:      []string{"chassis.yaml declares anchors (&name) referenced by aliases (*name)"},
+		Remediation: []string{"Review the saved chassis.yaml; re-introduce anchors by hand if the sharing was intended"},
+	},
+	FilesNotUpdated: {
+		Description: "Some files could not be rewritten; the listed files still reference the old state.",
+		Causes:      []string{"The files or their directories are read-only", "The files are owned by another user"},
+		Remediation: []string{"Follow the suggestion printed for each file, then re-run the action"},
+	},
+	RenameIncomplete: {
+		Description: "chassis.yaml was updated, but some playbooks or node files still reference the old path.",
+		Causes:      []string{"The listed files are read-only or owned by another user"},
+		Remediation: []string{"Fix the permissions and update the references by hand, or rename back and retry"},
+	},
+	MergeConflicts: {
+		Description: "Children with the same name existed under both merged paths. Their subtrees were combined, and nodes and playbooks of both now target the same path.",
+		Causes:      []string{"The consolidated layers had overlapping children"},
+		Remediation: []string{"Review the listed paths: check that the combined allocations and attachments are intended"},
+	},
+	GroupsChanged: {
+		Description: "Applying the rename would change which inventory groups the listed nodes belong to, beyond the renamed group names. Playbooks targeting those groups would run on different hosts.",
+		Causes: []string{
+			"Merging into an existing path changes how distribution spreads nodes over the combined children",
+			"An allocation expression matches different paths after the rename",
+		},
+		Remediation: []string{"Review the joined and left groups of each node before applying, and adjust allocations if needed"},
+	},
+	MoveRolledBack: {
+		Description: "chassis:move updates chassis.yaml, playbooks, node files and annotations together. One of them could not be written, so the others were restored and nothing was moved.",
+		Causes: []string{
+			"The listed files are read-only or owned by another user",
+			"The file system failed transiently, e.g. a network mount",
+		},
+		Remediation: []string{"Fix the permissions of the listed files and run the move again"},
+	},
+	CapacityExceeded: {
+		Description: "The allocation puts more nodes of the platform under the path than its max_nodes annotation allows. policy.capacity.severity is warning, so the node was allocated anyway.",
+		Causes:      []string{"The path is full, e.g. a control plane of a fixed size"},
+		Remediation: []string{"Deallocate another node, allocate this one elsewhere, or raise max_nodes in chassis.meta.yaml"},
+	},
+	NodeLeftUnallocated: {
+		Description: "The node file lists no chassis path anymore, so the node is in no inventory group and chassis:validate reports it (node-unallocated).",
+		Causes:      []string{"The last allocation of the node was removed"},
+		Remediation: []string{"Allocate the node elsewhere with chassis:allocate, or remove its node file if it was decommissioned"},
+	},
+	ComponentOutsideLayer: {
+		Description: "The role prefix doesn't own the chassis path per policy.layer_ownership. The severity is warning, so the role was attached anyway and chassis:validate reports it (component-layer-ownership).",
+		Causes:      []string{"The role belongs to another layer, or the layer isn't declared in policy.layer_ownership.layers"},
+		Remediation: []string{"Attach the role under its own layer, or declare the layer in policy.layer_ownership.layers"},
+	},
+	PlaysLeftEmpty: {
+		Description: "The detached role was the last one of the play, which now targets its hosts without running anything.",
+		Causes:      []string{"The play only attached the detached role"},
+		Remediation: []string{"Run chassis:detach with --remove-empty, or chassis:gc to remove empty plays of every playbook"},
+	},
+	AllocationEmpty: {
+		Description: "The node file lists chassis entries, but after expressions and distribution none of them is a path of chassis.yaml, so the node is in no inventory group and receives no components. The reasons name each included term.",
+		Causes: []string{
+			"The allocated path was removed or renamed without updating the node file",
+			"A pattern matches no path, or only paths its exclusions remove",
+		},
+		Remediation: []string{"Allocate the node to an existing path with chassis:allocate, or fix its entries; chassis:validate lists the unknown ones (node-unknown-chassis)"},
+	},
+	SplitUnmatched: {
+		Description: "The assignment file names nodes or components that chassis:split can't move: only node files listing the split path itself and plays targeting it exactly are rewritten.",
+		Causes: []string{
+			"The name is misspelled or belongs to another path",
+			"The node is allocated through an expression or to a descendant of the split path",
+		},
+		Remediation: []string{"Check the names with chassis:show, then reassign the remaining ones by hand"},
+	},
+	AttachmentsFailed: {
+		Description: "Playbooks referencing a renamed template path could not be updated.",
+		Causes:      []string{"The listed playbooks are read-only or owned by another user"},
+		Remediation: []string{"Fix the permissions and update the hosts fields by hand"},
+	},
+	AllocationsFailed: {
+		Description: "Node files referencing a renamed template path could not be updated.",
+		Causes:      []string{"The listed node files are read-only or owned by another user"},
+		Remediation: []string{"Fix the permissions and update the chassis lists by hand"},
+	},
+	MetaUpdateFailed: {
+		Description: "The chassis path was removed, but its annotations are still in chassis.meta.yaml.",
+		Causes:      []string{"chassis.meta.yaml is read-only or malformed"},
+		Remediation: []string{"Remove the stale entries from chassis.meta.yaml by hand"},
+	},
+	NoChassisPaths: {
+		Description: "chassis.yaml has no paths, or none below the requested prefix.",
+		Causes:      []string{"The prefix is misspelled", "chassis.yaml is empty"},
+		Remediation: []string{"Check the prefix with plasmactl chassis:list", "Add paths with plasmactl chassis:add"},
+	},
+	NodesUnallocated: {
+		Description: "Nodes without chassis allocation receive no components.",
+		Causes:      []string{"A node file has no chassis key or an empty chassis list"},
+		Remediation: []string{"Run plasmactl chassis:verify-nodes --fix --default <chassis>"},
+	},
+	TemplateInstanceMissing: {
+		Description: "chassis.meta.yaml records a template instance whose path no longer exists.",
+		Causes:      []string{"The instance was removed or renamed outside chassis:remove and chassis:rename"},
+		Remediation: []string{"Remove the stale entry from chassis.meta.yaml, or re-instantiate the template"},
+	},
+	SnapshotUnchecked: {
+		Description: "The snapshot carries no checksum, so corruption or tampering can't be detected.",
+		Causes:      []string{"The snapshot was exported without --checksum, or written by hand"},
+		Remediation: []string{"Re-export the snapshot with --checksum, and --sign if it crosses trust boundaries"},
+	},
+	ImportSkipped: {
+		Description: "Some rows could not be imported; the reason is printed per line.",
+		Causes:      []string{"Unknown chassis paths", "Malformed rows", "Rows duplicating an earlier row"},
+		Remediation: []string{"Fix the listed rows, or add the missing paths with plasmactl chassis:add, then import again"},
+	},
+	BalanceShort: {
+		Description: "There are fewer unallocated nodes than needed to bring every child path to the desired count.",
+		Causes:      []string{"Not all new machines have node files yet", "The desired count per child is too high for the fleet"},
+		Remediation: []string{"Add node files for the missing machines, or lower --per-child"},
+	},
+	PruneKept: {
+		Description: "Empty chassis paths were kept because nodes or playbooks still reference them.",
+		Causes:      []string{"Nodes are allocated to, or plays target, the paths directly"},
+		Remediation: []string{"Move the allocations and plays to other paths first, then run chassis:gc again"},
+	},
+	FactsUnavailable: {
+		Description: "chassis:show --facts could not get live facts from the configured source, so nodes are shown without them.",
+		Causes:      []string{"The facts command failed or the endpoint is unreachable", "The source took longer than facts.timeout", "The source didn't answer with a JSON object of facts by hostname@platform"},
+		Remediation: []string{"Run the facts command by hand with the request on stdin, or check the endpoint", "Raise facts.timeout for slow sources"},
+	},
+	LintDeferred: {
+		Description: "Several rules fix the same file; only the fix of the first rule was applied, as the others were computed against the unfixed content.",
+		Causes:      []string{"A node file both has duplicate allocations and allocations with the wrong casing", "chassis.yaml has stray scalars and unsorted children"},
+		Remediation: []string{"Run chassis:lint --fix again until nothing is deferred"},
+	},
+}
+
+// Explain returns the explanation of a message.
+func Explain(code Code) (Explanation, bool) {
+	e, ok := explanations[code]
+	return e, ok
+}
+
+// Lookup returns the catalog level and text template of a message.
+func Lookup(code Code) (level Level, format string, ok bool) {
+	e, ok := catalog[code]
+	return e.level, e.format, ok
+}
+
+// Codes returns all catalog codes in alphabetical order.
+func Codes() []Code {
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
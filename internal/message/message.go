@@ -0,0 +1,50 @@
+// Package message is the catalog of user-facing outcome messages. Every message
+// has a stable code reported next to its text in JSON results, so wrapping tools
+// can map outcomes without parsing text, and texts can be localized without
+// touching action logic.
+package message
+
+import "fmt"
+
+// Code identifies a catalog message. Codes are part of the JSON output and
+// must not change once released.
+type Code string
+
+// Level is the terminal style a message is printed with.
+type Level string
+
+// Message levels.
+const (
+	LevelInfo    Level = "info"
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Message is a rendered catalog message.
+type Message struct {
+	Code  Code   `json:"code"`
+	Level Level  `json:"level"`
+	Text  string `json:"text"`
+}
+
+// New renders the catalog message code with args.
+// Unknown codes render as the code itself at info level.
+func New(code Code, args ...any) Message {
+	e, ok := catalog[code]
+	if !ok {
+		return Message{Code: code, Level: LevelInfo, Text: string(code)}
+	}
+	return Message{Code: code, Level: e.level, Text: fmt.Sprintf(e.format, args...)}
+}
+
+// Log carries the messages reported by an action in its JSON result.
+// Result types embed it; the plugin runtime fills it after execution.
+type Log struct {
+	Messages []Message `json:"messages"`
+}
+
+// SetMessages replaces the logged messages.
+func (l *Log) SetMessages(messages []Message) {
+	l.Messages = messages
+}
@@ -0,0 +1,435 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// RuleChassisGroupName flags paths whose derived Ansible group name exceeds limits.max_group_name.
+const RuleChassisGroupName = "chassis-group-name"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisGroupName,
+		Description: "Group names derived from chassis paths must fit limits.max_group_name for inventory export",
+		Causes: []string{
+			"Deeply nested or verbose chassis paths",
+			"A low limits.max_group_name",
+		},
+		Remediation: []string{
+			"Shorten the path with plasmactl chassis:rename",
+			"Or raise limits.max_group_name if the inventory consumer accepts longer names",
+		},
+		Check: checkChassisGroupName,
+	})
+}
+
+func checkChassisGroupName(ctx *Context) []Finding {
+	var findings []Finding
+	for _, p := range ctx.Chassis.Flatten() {
+		if err := ctx.Config.Limits.CheckGroupName(p); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Chassis:  p,
+				Message:  err.Error(),
+			})
+		}
+	}
+	return findings
+}
+
+// RuleChassisReservedName flags paths with segments named like implicit Ansible inventory names.
+const RuleChassisReservedName = "chassis-reserved-name"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisReservedName,
+		Description: "Chassis path segments must not use reserved inventory names such as all, ungrouped or localhost (reserved_names)",
+		Causes: []string{
+			"A segment named after an implicit Ansible group or host",
+			"chassis.yaml was edited by hand, bypassing the check of chassis:add and chassis:rename",
+		},
+		Remediation: []string{
+			"Rename the segment with plasmactl chassis:rename",
+		},
+		Check: checkChassisReservedName,
+	})
+}
+
+func checkChassisReservedName(ctx *Context) []Finding {
+	reserved := ctx.Config.ReservedNames
+	if reserved == nil {
+		reserved = pkgchassis.DefaultReservedNames
+	}
+	var findings []Finding
+	for _, p := range ctx.Chassis.Flatten() {
+		// Report each offending segment once, at the path introducing it
+		if seg := lastSegment(p); slices.Contains(reserved, seg) {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Chassis:  p,
+				Message:  fmt.Sprintf("segment %q of %q is a reserved inventory name", seg, p),
+			})
+		}
+	}
+	return findings
+}
+
+// lastSegment returns the last segment of a chassis path.
+func lastSegment(chassisPath string) string {
+	return chassisPath[strings.LastIndex(chassisPath, ".")+1:]
+}
+
+// RuleChassisStrayScalars flags path segments normalized on load.
+const RuleChassisStrayScalars = "chassis-stray-scalars"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisStrayScalars,
+		Description: "Path segments in chassis.yaml must not carry stray whitespace or quotes",
+		Causes: []string{
+			"chassis.yaml was edited by hand with quoted or padded segments",
+		},
+		Remediation: []string{
+			"Run any mutating chassis action, which writes the segments back trimmed",
+			"Or remove the stray characters by hand",
+		},
+		Check: checkChassisStrayScalars,
+		Fix:   fixChassisYAML(func(c *chassis.Chassis) (bool, error) { return len(c.Normalized()) > 0, nil }, "write segments back trimmed"),
+	})
+}
+
+func checkChassisStrayScalars(ctx *Context) []Finding {
+	var findings []Finding
+	for _, n := range ctx.Chassis.Normalized() {
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			File:     "chassis.yaml",
+			Message:  fmt.Sprintf("line %d: segment %q is read as %q; saving the chassis rewrites it", n.Line, n.Original, n.Normalized),
+		})
+	}
+	return findings
+}
+
+// fixChassisYAML returns a fix rewriting chassis.yaml after edit changed a
+// fresh copy of the chassis. Edit reports whether there is anything to fix.
+func fixChassisYAML(edit func(c *chassis.Chassis) (bool, error), description string) func(ctx *Context) ([]Fix, error) {
+	return func(ctx *Context) ([]Fix, error) {
+		c, err := chassis.Load(ctx.Dir)
+		if err != nil {
+			return nil, err
+		}
+		if changed, err := edit(c); err != nil || !changed {
+			return nil, err
+		}
+		path := filepath.Join(ctx.Dir, "chassis.yaml")
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		after, err := c.Marshal()
+		if err != nil || bytes.Equal(before, after) {
+			return nil, err
+		}
+		return []Fix{newFix(ctx, path, description, before, after)}, nil
+	}
+}
+
+// RuleChassisUnsortedChildren flags paths whose children aren't sorted by name.
+const RuleChassisUnsortedChildren = "chassis-unsorted-children"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisUnsortedChildren,
+		Description: "Children of every chassis path are sorted by name, for repositories preferring alphabetical order over hand-picked order",
+		Causes: []string{
+			"Paths were added at the end instead of in order",
+		},
+		Remediation: []string{
+			"Run plasmactl chassis:lint --fix --rules chassis-unsorted-children",
+			"Or sort the children of a path with plasmactl chassis:reorder --alpha",
+		},
+		Check:   checkChassisUnsortedChildren,
+		Enabled: func(*Context) bool { return false },
+		Fix: fixChassisYAML(func(c *chassis.Chassis) (bool, error) {
+			parents := unsortedParents(c)
+			for _, p := range parents {
+				if _, err := c.Reorder(p, nil, true); err != nil {
+					return false, err
+				}
+			}
+			return len(parents) > 0, nil
+		}, "sort children by name"),
+	})
+}
+
+func checkChassisUnsortedChildren(ctx *Context) []Finding {
+	var findings []Finding
+	for _, p := range unsortedParents(ctx.Chassis) {
+		msg := "children aren't sorted by name"
+		if p == "" {
+			msg = "root paths aren't sorted by name"
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Chassis:  p,
+			Message:  msg,
+		})
+	}
+	return findings
+}
+
+// unsortedParents returns the paths whose children aren't sorted by name,
+// in tree order. Root paths count as children of "".
+func unsortedParents(c *chassis.Chassis) []string {
+	children := c.ChildrenMap()
+	for _, p := range c.Flatten() {
+		if pkgchassis.Parent(p) == "" {
+			children[""] = append(children[""], p)
+		}
+	}
+	var parents []string
+	for _, p := range append([]string{""}, c.Flatten()...) {
+		if !sort.StringsAreSorted(children[p]) {
+			parents = append(parents, p)
+		}
+	}
+	return parents
+}
+
+// RuleChassisLeafOnly flags allocations and attachments to intermediate paths.
+const RuleChassisLeafOnly = "chassis-leaf-only"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisLeafOnly,
+		Description: "Nodes and roles target leaf paths only, unless the intermediate path is annotated aggregate: true (policy.leaf_only)",
+		Causes: []string{
+			"A node file allocates the node to a path that has children",
+			"A play targets a path that has children",
+		},
+		Remediation: []string{
+			"Move the allocation or play to the leaf paths below",
+			"Or annotate the path with aggregate: true in chassis.meta.yaml if targeting it is intended",
+		},
+		Check:   checkChassisLeafOnly,
+		Enabled: leafOnlyEnabled,
+	})
+}
+
+// leafOnlyEnabled runs the leaf-only rule by default once a severity is configured.
+func leafOnlyEnabled(ctx *Context) bool {
+	s := ctx.Config.Policy.LeafOnly.Severity
+	return s == chassis.PolicyWarning || s == chassis.PolicyError
+}
+
+func checkChassisLeafOnly(ctx *Context) []Finding {
+	severity := SeverityWarning
+	switch ctx.Config.Policy.LeafOnly.Severity {
+	case chassis.PolicyOff:
+		return nil
+	case chassis.PolicyError:
+		severity = SeverityError
+	}
+
+	intermediate := func(p string) bool {
+		return ctx.Chassis.Exists(p) && ctx.Chassis.HasChildren(p) && !ctx.Meta[p].Aggregate
+	}
+
+	// Report every intermediate target once, with everything targeting it
+	nodes := make(map[string][]string)
+	roles := make(map[string][]string)
+	for _, n := range ctx.Nodes {
+		for _, p := range ctx.Chassis.ExpandAllocations(n.Chassis) {
+			if intermediate(p) {
+				nodes[p] = append(nodes[p], n.Hostname+"@"+n.Platform)
+			}
+		}
+	}
+	for _, a := range ctx.Attachments {
+		if intermediate(a.Chassis) {
+			roles[a.Chassis] = append(roles[a.Chassis], a.Component)
+		}
+	}
+
+	targets := make(map[string]bool)
+	for p := range nodes {
+		targets[p] = true
+	}
+	for p := range roles {
+		targets[p] = true
+	}
+
+	var findings []Finding
+	for p := range targets {
+		var parts []string
+		if len(nodes[p]) > 0 {
+			sort.Strings(nodes[p])
+			parts = append(parts, fmt.Sprintf("%d node(s): %s", len(nodes[p]), strings.Join(nodes[p], ", ")))
+		}
+		if len(roles[p]) > 0 {
+			sort.Strings(roles[p])
+			parts = append(parts, fmt.Sprintf("%d role(s): %s", len(roles[p]), strings.Join(roles[p], ", ")))
+		}
+		findings = append(findings, Finding{
+			Severity: severity,
+			Chassis:  p,
+			Message:  "intermediate path targeted by " + strings.Join(parts, "; "),
+		})
+	}
+	return findings
+}
+
+// RuleChassisCapacity flags paths allocated more nodes than their max_nodes
+// annotation, or fewer than min_nodes.
+const RuleChassisCapacity = "chassis-capacity"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisCapacity,
+		Description: "Paths annotated max_nodes or min_nodes in chassis.meta.yaml get that many nodes per platform at most or at least (policy.capacity)",
+		Causes: []string{
+			"Node files allocate more nodes of a platform to the path or its descendants than max_nodes",
+			"A critical layer such as cluster.control has fewer nodes than min_nodes, e.g. after nodes were removed",
+			"The annotations were changed below or above the current allocations",
+		},
+		Remediation: []string{
+			"Remove allocations from, or add allocations to, the node files of the platform",
+			"Use plasmactl chassis:balance to allocate unallocated nodes",
+			"Or change the annotation in chassis.meta.yaml",
+		},
+		Check: checkChassisCapacity,
+	})
+}
+
+func checkChassisCapacity(ctx *Context) []Finding {
+	severity := SeverityError
+	switch ctx.Config.Policy.Capacity.Severity {
+	case chassis.PolicyOff:
+		return nil
+	case chassis.PolicyWarning:
+		severity = SeverityWarning
+	}
+
+	// Platforms without nodes at a path are under-provisioned too
+	platformSet := make(map[string]bool)
+	for _, n := range ctx.Nodes {
+		platformSet[n.Platform] = true
+	}
+	platforms := make([]string, 0, len(platformSet))
+	for platform := range platformSet {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	var findings []Finding
+	for _, p := range ctx.Meta.Paths() {
+		a := ctx.Meta[p]
+		if msg := capacityError(a); msg != "" {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Chassis:  p,
+				File:     pkgchassis.MetaFile,
+				Message:  msg,
+			})
+			continue
+		}
+		if (a.MaxNodes == 0 && a.MinNodes == 0) || !ctx.Chassis.Exists(p) {
+			continue
+		}
+
+		counts := chassis.NodeCounts(ctx.Chassis, ctx.Nodes, p)
+		for _, platform := range platforms {
+			n := counts[platform]
+			var msg string
+			switch {
+			case a.MaxNodes > 0 && n > a.MaxNodes:
+				msg = fmt.Sprintf("%d node(s) of platform %s allocated, max_nodes is %d", n, platform, a.MaxNodes)
+			case n < a.MinNodes:
+				msg = fmt.Sprintf("%d node(s) of platform %s allocated, min_nodes is %d", n, platform, a.MinNodes)
+			default:
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: severity,
+				Chassis:  p,
+				Message:  msg,
+			})
+		}
+	}
+	return findings
+}
+
+// capacityError describes invalid capacity annotations, or returns "".
+func capacityError(a pkgchassis.Annotations) string {
+	switch {
+	case a.MaxNodes < 0:
+		return fmt.Sprintf("max_nodes is %d, expected a positive number", a.MaxNodes)
+	case a.MinNodes < 0:
+		return fmt.Sprintf("min_nodes is %d, expected a positive number", a.MinNodes)
+	case a.MaxNodes > 0 && a.MinNodes > a.MaxNodes:
+		return fmt.Sprintf("min_nodes %d exceeds max_nodes %d", a.MinNodes, a.MaxNodes)
+	}
+	return ""
+}
+
+// RuleChassisEmptyLayer flags layers no node is allocated to and no play targets.
+const RuleChassisEmptyLayer = "chassis-empty-layer"
+
+func init() {
+	register(Rule{
+		Name:        RuleChassisEmptyLayer,
+		Description: "Every layer, e.g. platform.foundation, has nodes allocated to it or below, or roles attached to it or below",
+		Causes: []string{
+			"The layer was declared ahead of its nodes and playbook",
+			"Its nodes and plays were moved elsewhere, leaving the layer behind",
+		},
+		Remediation: []string{
+			"Allocate nodes or attach roles to the layer",
+			"Or remove it: plasmactl chassis:remove <layer>",
+		},
+		Check: checkChassisEmptyLayer,
+	})
+}
+
+func checkChassisEmptyLayer(ctx *Context) []Finding {
+	var findings []Finding
+	for _, layer := range ctx.Chassis.Flatten() {
+		if strings.Count(layer, ".") != 1 || layerUsed(ctx, layer) {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Chassis:  layer,
+			Message:  "no node is allocated to the layer or below, and no role is attached to it or below",
+		})
+	}
+	return findings
+}
+
+// layerUsed reports whether a node is allocated or a role attached to the
+// layer or one of its descendants. Allocations of the root, spread by
+// distribution, don't count.
+func layerUsed(ctx *Context, layer string) bool {
+	for _, a := range ctx.Attachments {
+		if a.Chassis == layer || pkgchassis.IsDescendantOf(a.Chassis, layer) {
+			return true
+		}
+	}
+	for _, n := range ctx.Nodes {
+		for _, p := range ctx.Chassis.ExpandAllocations(n.Chassis) {
+			if p == layer || pkgchassis.IsDescendantOf(p, layer) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package validate
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+)
+
+// RuleComponentLayerOwnership flags roles attached under a layer they don't belong to.
+const RuleComponentLayerOwnership = "component-layer-ownership"
+
+// RuleComponentDuplicatePlay flags roles attached to the same chassis path by
+// plays of several playbooks.
+const RuleComponentDuplicatePlay = "component-duplicate-play"
+
+func init() {
+	register(Rule{
+		Name:        RuleComponentLayerOwnership,
+		Description: "Roles must be attached under the chassis layer matching their name prefix (policy.layer_ownership)",
+		Causes: []string{
+			"A play attaches a role of one layer to a chassis path of another",
+			"The role was moved to another layer without updating its plays",
+		},
+		Remediation: []string{
+			"Attach the role under its own layer",
+			"Or declare the additional layer in policy.layer_ownership.layers",
+			"Or set policy.layer_ownership.severity: off to disable the check",
+		},
+		Check: checkComponentLayerOwnership,
+	})
+	register(Rule{
+		Name:        RuleComponentDuplicatePlay,
+		Description: "A role is attached to a chassis path by a single playbook, so it doesn't run twice",
+		Causes: []string{
+			"A play was copied to another layer playbook without removing the original",
+			"Two layers attach a shared role to the same chassis path",
+		},
+		Remediation: []string{
+			"Keep the role in the play of the owning layer and remove it from the others",
+		},
+		Check: checkComponentDuplicatePlay,
+	})
+}
+
+func checkComponentLayerOwnership(ctx *Context) []Finding {
+	policy := ctx.Config.Policy.LayerOwnership
+	severity := SeverityWarning
+	switch policy.Severity {
+	case chassis.PolicyOff:
+		return nil
+	case chassis.PolicyError:
+		severity = SeverityError
+	}
+
+	var findings []Finding
+	for _, a := range ctx.Attachments {
+		ok, allowed := policy.Owns(a.Component, a.Chassis)
+		if ok {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: severity,
+			Chassis:  a.Chassis,
+			File:     a.Playbook,
+			Message: fmt.Sprintf("role %s is attached outside its layer (expected under %s)",
+				a.Component, strings.Join(allowed, ", ")),
+		})
+	}
+	return findings
+}
+
+func checkComponentDuplicatePlay(ctx *Context) []Finding {
+	// chassis path → role → playbooks attaching it
+	playbooks := make(map[string]map[string][]string)
+	seen := make(map[[3]string]bool)
+	for _, a := range ctx.Attachments {
+		key := [3]string{a.Chassis, a.Component, a.Playbook}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		roles, ok := playbooks[a.Chassis]
+		if !ok {
+			roles = make(map[string][]string)
+			playbooks[a.Chassis] = roles
+		}
+		roles[a.Component] = append(roles[a.Component], a.Playbook)
+	}
+
+	var findings []Finding
+	for _, chassisPath := range slices.Sorted(maps.Keys(playbooks)) {
+		roles := playbooks[chassisPath]
+		// Report the overlapping roles once per set of playbooks
+		overlaps := make(map[string][]string)
+		for role, files := range roles {
+			if len(files) < 2 {
+				continue
+			}
+			sort.Strings(files)
+			key := strings.Join(files, ", ")
+			overlaps[key] = append(overlaps[key], role)
+		}
+		// Sets sharing their first playbook are only told apart by order
+		for _, key := range slices.Sorted(maps.Keys(overlaps)) {
+			overlap := overlaps[key]
+			sort.Strings(overlap)
+			files := strings.Split(key, ", ")
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Chassis:  chassisPath,
+				File:     files[0],
+				Message: fmt.Sprintf("plays in %s attach the same roles, which then run once per play: %s",
+					key, strings.Join(overlap, ", ")),
+			})
+		}
+	}
+	return findings
+}
+
+// RuleComponentUnknownChassis flags plays whose hosts name an undeclared
+// path below a declared chassis root.
+const RuleComponentUnknownChassis = "component-unknown-chassis"
+
+func init() {
+	register(Rule{
+		Name:        RuleComponentUnknownChassis,
+		Description: "Plays attaching roles target declared chassis paths; hosts below a chassis root that aren't declared match no inventory group",
+		Causes: []string{
+			"The path was removed or renamed in chassis.yaml by hand",
+			"The hosts value is misspelled",
+		},
+		Remediation: []string{
+			"Correct the hosts of the play",
+			"Or declare the path: plasmactl chassis:add <chassis>",
+		},
+		Check: checkComponentUnknownChassis,
+	})
+}
+
+func checkComponentUnknownChassis(ctx *Context) []Finding {
+	// Plays are reported once, with all their roles
+	roles := make(map[[2]string][]string)
+	for _, a := range ctx.Attachments {
+		// Other host patterns, such as all or groups of other inventories, are left alone
+		if strings.ContainsAny(a.Chassis, " ,:!&*[") || ctx.Chassis.Exists(a.Chassis) {
+			continue
+		}
+		root, _, _ := strings.Cut(a.Chassis, ".")
+		if root == a.Chassis || !ctx.Chassis.Exists(root) {
+			continue
+		}
+		key := [2]string{a.Playbook, a.Chassis}
+		if !slices.Contains(roles[key], a.Component) {
+			roles[key] = append(roles[key], a.Component)
+		}
+	}
+
+	var findings []Finding
+	for key, names := range roles {
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Chassis:  key[1],
+			File:     key[0],
+			Message:  fmt.Sprintf("hosts %q is not declared in chassis.yaml; roles: %s", key[1], strings.Join(names, ", ")),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,66 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+)
+
+// RuleLayoutMixedExtensions flags node files and playbooks deviating from
+// the extension used by most of them.
+const RuleLayoutMixedExtensions = "layout-mixed-extensions"
+
+func init() {
+	register(Rule{
+		Name:        RuleLayoutMixedExtensions,
+		Description: "Node files and playbooks use a single extension, either .yaml or .yml (layout.extensions)",
+		Causes: []string{
+			"Files were added by tools or people using another extension",
+			"Platforms or layers were copied in from another repository",
+		},
+		Remediation: []string{
+			"Rename the reported files to the extension used by the others",
+		},
+		Check: checkLayoutMixedExtensions,
+	})
+}
+
+func checkLayoutMixedExtensions(ctx *Context) []Finding {
+	files := make([]string, 0, len(ctx.Nodes))
+	for _, n := range ctx.Nodes {
+		files = append(files, n.File)
+	}
+	playbooks, _ := chassis.PlaybookFiles(ctx.Dir)
+	for _, p := range playbooks {
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, p)
+		}
+	}
+
+	// The most used extension wins, ties going to the preferred one
+	counts := make(map[string]int)
+	for _, f := range files {
+		counts[filepath.Ext(f)]++
+	}
+	exts := ctx.Config.Layout.FileExtensions()
+	major := exts[0]
+	for _, ext := range exts {
+		if counts[ext] > counts[major] {
+			major = ext
+		}
+	}
+
+	var findings []Finding
+	for _, f := range files {
+		if ext := filepath.Ext(f); ext != major {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				File:     f,
+				Message:  fmt.Sprintf("uses %s while %d other file(s) use %s", ext, counts[major], major),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+)
+
+// Fix is a safe automatic change resolving findings of a rule: the new
+// content of a single file.
+type Fix struct {
+	Rule        string `json:"rule"`
+	File        string `json:"file"` // relative to the repository
+	Description string `json:"description"`
+	Diff        string `json:"diff"` // unified diff of the change
+
+	path  string
+	after []byte
+}
+
+// newFix returns a fix replacing the content of a repository file.
+func newFix(ctx *Context, path, description string, before, after []byte) Fix {
+	rel, err := filepath.Rel(ctx.Dir, path)
+	if err != nil {
+		rel = path
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: "a/" + filepath.ToSlash(rel),
+		ToFile:   "b/" + filepath.ToSlash(rel),
+		Context:  3,
+	})
+	return Fix{
+		File:        rel,
+		Description: description,
+		Diff:        diff,
+		path:        path,
+		after:       after,
+	}
+}
+
+// Apply writes the fixed file.
+func (f Fix) Apply() error {
+	return chassis.ReplaceFile(f.path, f.after)
+}
+
+// Fixable reports whether the named rule offers automatic fixes.
+func Fixable(name string) bool {
+	r, err := Lookup(name)
+	return err == nil && r.Fix != nil
+}
+
+// Fixes collects the fixes of the named rules, or of every rule with fixes
+// that runs by default. Fixes of one rule are computed against the files as
+// they are, so when several rules change the same file only the fix of the
+// first is kept; the other rules are returned as deferred, to be fixed by
+// running again.
+func Fixes(ctx *Context, names ...string) (fixes []Fix, deferred []string, err error) {
+	var selected []Rule
+	if len(names) > 0 {
+		for _, name := range names {
+			r, err := Lookup(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			selected = append(selected, r)
+		}
+	} else {
+		for _, r := range rules {
+			if r.Enabled == nil || r.Enabled(ctx) {
+				selected = append(selected, r)
+			}
+		}
+	}
+
+	fixes = []Fix{}
+	files := make(map[string]bool)
+	for _, r := range selected {
+		if r.Fix == nil {
+			continue
+		}
+		ruleFixes, err := r.Fix(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		postponed := false
+		for _, f := range ruleFixes {
+			if files[f.path] {
+				postponed = true
+				continue
+			}
+			f.Rule = r.Name
+			fixes = append(fixes, f)
+		}
+		for _, f := range ruleFixes {
+			files[f.path] = true
+		}
+		if postponed {
+			deferred = append(deferred, r.Name)
+		}
+	}
+	return fixes, deferred, nil
+}
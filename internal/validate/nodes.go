@@ -0,0 +1,426 @@
+package validate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// RuleNodeUnallocated flags nodes that are not allocated to any chassis path.
+const RuleNodeUnallocated = "node-unallocated"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeUnallocated,
+		Description: "Every node must be allocated to at least one chassis path; unallocated hosts silently receive no components",
+		Causes: []string{
+			"A node file was added without a chassis key",
+			"All chassis paths of a node were removed or renamed by hand",
+		},
+		Remediation: []string{
+			"Allocate the node: plasmactl chassis:verify-nodes --fix --default <chassis>",
+			"Or add the chassis paths to the chassis list of the node file",
+		},
+		Check: checkNodeUnallocated,
+	})
+}
+
+// Unallocated returns the nodes whose chassis list is empty or missing.
+func Unallocated(nodes []chassis.Node) []chassis.Node {
+	var result []chassis.Node
+	for _, n := range nodes {
+		if len(n.Chassis) == 0 {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func checkNodeUnallocated(ctx *Context) []Finding {
+	var findings []Finding
+	for _, n := range Unallocated(ctx.Nodes) {
+		msg := "node has an empty chassis list"
+		if !n.ChassisDeclared {
+			msg = "node file has no chassis key"
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Node:     n.Hostname + "@" + n.Platform,
+			File:     n.File,
+			Message:  msg,
+		})
+	}
+	return findings
+}
+
+// RuleNodeUnreadable flags node files that can't be read or parsed, which
+// every action skips.
+const RuleNodeUnreadable = "node-unreadable"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeUnreadable,
+		Description: "Every node file must be readable YAML; unreadable node files are skipped and their host receives no components",
+		Causes: []string{
+			"A node file was edited by hand and has a YAML syntax error",
+			"A merge conflict left conflict markers in a node file",
+			"The file isn't readable by the current user",
+		},
+		Remediation: []string{
+			"Fix the reported error in the node file, e.g. with a YAML linter",
+			"Or remove the file if the node was decommissioned",
+		},
+		Check: checkNodeUnreadable,
+	})
+}
+
+func checkNodeUnreadable(ctx *Context) []Finding {
+	var findings []Finding
+	for _, e := range ctx.UnreadableNodes {
+		hostname, _ := pkgchassis.TrimExtension(filepath.Base(e.File), ctx.Config.Layout.FileExtensions())
+		var platform string
+		if rel, err := filepath.Rel(ctx.Dir, e.File); err == nil {
+			platform = ctx.Config.Layout.PlatformOf(rel)
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Node:     chassis.NodeName(hostname, platform),
+			File:     e.File,
+			Message:  e.Error,
+		})
+	}
+	return findings
+}
+
+// RuleNodeDuplicateHostname flags hostnames defined under more than one platform.
+const RuleNodeDuplicateHostname = "node-duplicate-hostname"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeDuplicateHostname,
+		Description: "A hostname must be defined under a single platform; show and query merge nodes by hostname",
+		Causes: []string{
+			"A node file was copied to another platform without renaming it",
+			"The same physical host is registered in several platforms",
+		},
+		Remediation: []string{
+			"Rename one of the node files, e.g. to <hostname>-<platform>.yaml",
+			"Or remove the node from the platforms it no longer belongs to",
+		},
+		Check: checkNodeDuplicateHostname,
+	})
+}
+
+func checkNodeDuplicateHostname(ctx *Context) []Finding {
+	byHostname := make(map[string][]chassis.Node)
+	var hostnames []string
+	for _, n := range ctx.Nodes {
+		if _, ok := byHostname[n.Hostname]; !ok {
+			hostnames = append(hostnames, n.Hostname)
+		}
+		byHostname[n.Hostname] = append(byHostname[n.Hostname], n)
+	}
+
+	var findings []Finding
+	for _, hostname := range hostnames {
+		nodes := byHostname[hostname]
+		if len(nodes) < 2 {
+			continue
+		}
+		files := make([]string, len(nodes))
+		for i, n := range nodes {
+			files[i] = n.File
+		}
+		for _, n := range nodes {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Node:     n.Hostname + "@" + n.Platform,
+				File:     n.File,
+				Message: fmt.Sprintf("hostname %q is defined in %d platforms (%s); rename it, e.g. to %s-%s",
+					hostname, len(nodes), strings.Join(files, ", "), n.Hostname, n.Platform),
+			})
+		}
+	}
+	return findings
+}
+
+// RuleNodeHostnameMismatch flags node files whose hostname field differs from the file name.
+const RuleNodeHostnameMismatch = "node-hostname-mismatch"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeHostnameMismatch,
+		Description: "The hostname field of a node file must match its file name",
+		Causes: []string{
+			"A node file was renamed without updating its hostname field",
+			"A node file was copied and only the hostname field was edited",
+		},
+		Remediation: []string{
+			"Rename the file or fix the hostname field so both agree",
+			"Set layout.hostname: yaml if the hostname field is authoritative",
+		},
+		Check: checkNodeHostnameMismatch,
+	})
+}
+
+func checkNodeHostnameMismatch(ctx *Context) []Finding {
+	// When the layout trusts the YAML field, a mismatch is intended but still worth a look
+	severity := SeverityError
+	used := "file name"
+	if ctx.Config.Layout.TrustsYAMLHostname() {
+		severity = SeverityWarning
+		used = "hostname field"
+	}
+
+	var findings []Finding
+	for _, n := range ctx.Nodes {
+		if n.DeclaredHostname == "" || n.DeclaredHostname == n.FileHostname {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: severity,
+			Node:     n.Hostname + "@" + n.Platform,
+			File:     n.File,
+			Message: fmt.Sprintf("hostname field %q does not match file name %q (using %s)",
+				n.DeclaredHostname, n.FileHostname, used),
+		})
+	}
+	return findings
+}
+
+// RuleNodeAllocationExpression flags allocation expressions matching no chassis path.
+const RuleNodeAllocationExpression = "node-allocation-expression"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeAllocationExpression,
+		Description: "Every term of an allocation expression must match a chassis path, and the expression must select at least one path",
+		Causes: []string{
+			"A wildcard or exclusion is misspelled",
+			"The paths an expression selected were removed or renamed",
+		},
+		Remediation: []string{
+			"Fix the expression in the chassis list of the node file",
+			"Check which paths a pattern selects with plasmactl chassis:list <prefix>",
+		},
+		Check: checkNodeAllocationExpression,
+	})
+}
+
+func checkNodeAllocationExpression(ctx *Context) []Finding {
+	paths := ctx.Chassis.Flatten()
+	matchesAny := func(pattern string) bool {
+		for _, p := range paths {
+			if pkgchassis.MatchPattern(pattern, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var findings []Finding
+	for _, n := range ctx.Nodes {
+		hasExpression := false
+		for _, entry := range n.Chassis {
+			if !pkgchassis.IsExpression(entry) {
+				continue
+			}
+			hasExpression = true
+			for _, t := range pkgchassis.Terms(entry) {
+				if matchesAny(strings.TrimPrefix(t, "!")) {
+					continue
+				}
+				findings = append(findings, Finding{
+					Severity: SeverityWarning,
+					Node:     n.Hostname + "@" + n.Platform,
+					File:     n.File,
+					Message:  fmt.Sprintf("term %q of %q matches no chassis path", t, entry),
+				})
+			}
+		}
+		if hasExpression && len(ctx.Chassis.ExpandAllocations(n.Chassis)) == 0 {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Node:     n.Hostname + "@" + n.Platform,
+				File:     n.File,
+				Message:  "allocation expressions select no chassis path",
+			})
+		}
+	}
+	return findings
+}
+
+// RuleNodeAllocationCase flags allocation entries naming a declared path
+// with different letter case or stray whitespace.
+const RuleNodeAllocationCase = "node-allocation-case"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeAllocationCase,
+		Description: "Allocation entries name declared chassis paths exactly, without different letter case or stray whitespace",
+		Causes: []string{
+			"A node file was edited by hand, e.g. Platform.Foundation instead of platform.foundation",
+		},
+		Remediation: []string{
+			"Run plasmactl chassis:lint --fix --rules node-allocation-case",
+			"Or correct the entry by hand",
+		},
+		Check: checkNodeAllocationCase,
+		Fix:   fixNodeAllocations(canonicalEntries, "use the declared spelling of chassis paths"),
+	})
+}
+
+func checkNodeAllocationCase(ctx *Context) []Finding {
+	var findings []Finding
+	for _, n := range ctx.Nodes {
+		for _, entry := range n.Chassis {
+			if canonical, ok := canonicalEntry(ctx.Chassis, entry); ok {
+				findings = append(findings, Finding{
+					Severity: SeverityWarning,
+					Node:     n.Hostname + "@" + n.Platform,
+					File:     n.File,
+					Message:  fmt.Sprintf("entry %q is declared as %q", entry, canonical),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// canonicalEntry returns the declared spelling of a plain allocation entry
+// differing from it, or false.
+func canonicalEntry(c *chassis.Chassis, entry string) (string, bool) {
+	if pkgchassis.IsExpression(strings.TrimSpace(entry)) || c.Exists(entry) {
+		return "", false
+	}
+	canonical, ok := c.Canonical(entry)
+	return canonical, ok && canonical != entry
+}
+
+// canonicalEntries replaces entries by their declared spelling.
+func canonicalEntries(c *chassis.Chassis, entries []string) []string {
+	for i, entry := range entries {
+		if canonical, ok := canonicalEntry(c, entry); ok {
+			entries[i] = canonical
+		}
+	}
+	return entries
+}
+
+// RuleNodeDuplicateAllocation flags node files listing an allocation entry twice.
+const RuleNodeDuplicateAllocation = "node-duplicate-allocation"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeDuplicateAllocation,
+		Description: "A node file lists each allocation entry once",
+		Causes: []string{
+			"Node files were merged or edited by hand",
+		},
+		Remediation: []string{
+			"Run plasmactl chassis:lint --fix --rules node-duplicate-allocation",
+			"Or remove the repeated entries by hand",
+		},
+		Check: checkNodeDuplicateAllocation,
+		Fix:   fixNodeAllocations(func(_ *chassis.Chassis, entries []string) []string { return uniqueEntries(entries) }, "remove repeated allocation entries"),
+	})
+}
+
+func checkNodeDuplicateAllocation(ctx *Context) []Finding {
+	var findings []Finding
+	for _, n := range ctx.Nodes {
+		seen := make(map[string]bool)
+		for _, entry := range n.Chassis {
+			key := strings.TrimSpace(entry)
+			if seen[key] {
+				findings = append(findings, Finding{
+					Severity: SeverityWarning,
+					Node:     n.Hostname + "@" + n.Platform,
+					File:     n.File,
+					Message:  fmt.Sprintf("entry %q is listed more than once", key),
+				})
+			}
+			seen[key] = true
+		}
+	}
+	return findings
+}
+
+// uniqueEntries drops repeated entries, keeping the first.
+func uniqueEntries(entries []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, entry := range entries {
+		if key := strings.TrimSpace(entry); !seen[key] {
+			seen[key] = true
+			unique = append(unique, entry)
+		}
+	}
+	return unique
+}
+
+// fixNodeAllocations returns a fix rewriting the chassis list of every node
+// file with edit.
+func fixNodeAllocations(edit func(c *chassis.Chassis, entries []string) []string, description string) func(ctx *Context) ([]Fix, error) {
+	return func(ctx *Context) ([]Fix, error) {
+		var fixes []Fix
+		for _, n := range ctx.Nodes {
+			before, after, err := chassis.EditAllocations(n.File, func(entries []string) []string {
+				return edit(ctx.Chassis, entries)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if after != nil {
+				fixes = append(fixes, newFix(ctx, n.File, description, before, after))
+			}
+		}
+		return fixes, nil
+	}
+}
+
+// RuleNodeUnknownChassis flags allocation entries naming no declared chassis path.
+const RuleNodeUnknownChassis = "node-unknown-chassis"
+
+func init() {
+	register(Rule{
+		Name:        RuleNodeUnknownChassis,
+		Description: "Every plain allocation entry of a node file names a declared chassis path",
+		Causes: []string{
+			"The path was removed or renamed in chassis.yaml by hand",
+			"The entry is misspelled",
+		},
+		Remediation: []string{
+			"Correct the entry in the chassis list of the node file",
+			"Or declare the path: plasmactl chassis:add <chassis>",
+		},
+		Check: checkNodeUnknownChassis,
+	})
+}
+
+func checkNodeUnknownChassis(ctx *Context) []Finding {
+	var findings []Finding
+	for _, n := range ctx.Nodes {
+		for _, entry := range n.Chassis {
+			entry = strings.TrimSpace(entry)
+			if entry == "" || pkgchassis.IsExpression(entry) || ctx.Chassis.Exists(entry) {
+				continue
+			}
+			// Spelling variants of declared paths are node-allocation-case findings
+			if _, ok := ctx.Chassis.Canonical(entry); ok {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Chassis:  entry,
+				Node:     n.Hostname + "@" + n.Platform,
+				File:     n.File,
+				Message:  fmt.Sprintf("entry %q is not declared in chassis.yaml", entry),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,174 @@
+// Package validate implements chassis consistency rules shared by validation actions
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	pkgchassis "github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Severity of a finding.
+type Severity string
+
+// Finding severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single rule violation.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Chassis  string   `json:"chassis,omitempty"`
+	Node     string   `json:"node,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Context holds the repository state rules are checked against.
+type Context struct {
+	Dir     string
+	Chassis *chassis.Chassis
+	Nodes   []chassis.Node // all nodes of all platforms, without Fields
+	// UnreadableNodes are the node files that couldn't be read or parsed.
+	UnreadableNodes []chassis.FileError
+	// Attachments are all component attachments of all layer playbooks.
+	Attachments []chassis.Attachment
+	// Meta holds the annotations of chassis.meta.yaml.
+	Meta   pkgchassis.Meta
+	Config chassis.Config
+}
+
+// Load reads the repository state needed by rules.
+func Load(dir string, cfg chassis.Config) (*Context, error) {
+	c, err := chassis.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules only need the chassis fields of nodes
+	var nodes []chassis.Node
+	err = chassis.ForEachNode(dir, "", func(n chassis.Node) error {
+		nodes = append(nodes, n)
+		return nil
+	})
+	skipped, err := chassis.SplitNodeFileErrors(err)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := chassis.LoadAttachments(dir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := pkgchassis.LoadMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Context{
+		Dir:             dir,
+		Chassis:         c,
+		Nodes:           nodes,
+		UnreadableNodes: chassis.FileErrors(skipped),
+		Attachments:     attachments,
+		Meta:            meta,
+		Config:          cfg,
+	}, nil
+}
+
+// Rule is a named consistency check.
+// Causes and Remediation are shown by chassis:explain.
+type Rule struct {
+	Name        string
+	Description string
+	Causes      []string
+	Remediation []string
+	Check       func(ctx *Context) []Finding
+	// Enabled reports whether the rule runs when no rules are selected by
+	// name. Nil means always; opt-in rules always run when selected.
+	Enabled func(ctx *Context) bool
+	// Fix returns safe automatic changes resolving the findings of the
+	// rule, see [Fixes]. Nil means the rule has no automatic fix.
+	Fix func(ctx *Context) ([]Fix, error)
+}
+
+// rules is the registry of all rules in evaluation order.
+var rules []Rule
+
+// register adds a rule to the registry.
+func register(r Rule) {
+	rules = append(rules, r)
+}
+
+// Rules returns all registered rules.
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// Lookup returns the named rule.
+func Lookup(name string) (Rule, error) {
+	for _, r := range rules {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return Rule{}, fmt.Errorf("unknown rule %q", name)
+}
+
+// Run checks the named rules, or all rules if none are given.
+// Findings are sorted by rule, then file, then chassis path.
+func Run(ctx *Context, names ...string) ([]Finding, error) {
+	var selected []Rule
+	for _, r := range rules {
+		if r.Enabled == nil || r.Enabled(ctx) {
+			selected = append(selected, r)
+		}
+	}
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			r, err := Lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, r)
+		}
+	}
+
+	findings := []Finding{}
+	for _, r := range selected {
+		for _, f := range r.Check(ctx) {
+			f.Rule = r.Name
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Rule != findings[j].Rule {
+			return findings[i].Rule < findings[j].Rule
+		}
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Chassis < findings[j].Chassis
+	})
+	return findings, nil
+}
+
+// Count returns the number of error and warning findings.
+func Count(findings []Finding) (errors, warnings int) {
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
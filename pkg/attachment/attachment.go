@@ -0,0 +1,359 @@
+// Package attachment reads and rewrites component attachments: the roles
+// layer playbooks (src/<layer>/<layer>.yaml) apply to chassis paths through
+// the hosts of their plays.
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+// Events reported to [Options.Trace], named like the --trace output of plasmactl-chassis.
+const (
+	EventRead    = "read"    // playbook was read and parsed
+	EventSkip    = "skip"    // playbook was ignored, detail explains why
+	EventMatch   = "match"   // playbook has plays targeting the requested chassis path
+	EventNoMatch = "nomatch" // playbook was parsed but doesn't target the path
+	EventWrite   = "write"   // playbook was rewritten
+)
+
+// Attachment is a component attached to a chassis path by a play.
+type Attachment struct {
+	Component string
+	Playbook  string
+	Chassis   string
+	// Version is the version constraint recorded with the role, if any
+	Version string
+}
+
+// Play is the attachment-relevant part of a playbook play.
+type Play struct {
+	Hosts string `json:"hosts"`
+	Roles []Role `json:"roles,omitempty"`
+}
+
+// Role is a role of a play with its optional version constraint.
+type Role struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// PlaybookError reports a playbook that couldn't be read, parsed or written.
+type PlaybookError struct {
+	Path string
+	Op   string // read, parse or write
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *PlaybookError) Error() string {
+	return fmt.Sprintf("%s playbook %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PlaybookError) Unwrap() error {
+	return e.Err
+}
+
+// detail describes the error for trace events, which name the playbook already.
+func (e *PlaybookError) detail() string {
+	if e.Op == "read" {
+		return e.Err.Error()
+	}
+	return e.Op + " error: " + e.Err.Error()
+}
+
+// Options configure [Load] and [Update].
+type Options struct {
+	// Chassis limits Load to plays targeting the path or its descendants.
+	// Empty loads all attachments.
+	Chassis string
+	// Playbooks limits the playbooks considered, e.g. to those an index
+	// lists as relevant. Nil considers all of [Playbooks] in PlaybooksDir.
+	Playbooks []string
+	// PlaybooksDir is the directory holding <layer>/<layer>.yaml, relative
+	// to the repository. Defaults to [chassis.DefaultPlaybooksDir].
+	PlaybooksDir string
+	// Parse parses a playbook for Load, e.g. through a cache, and reports
+	// its own read events. Defaults to [ParsePlaybook].
+	Parse func(path string) ([]Play, error)
+	// Write writes a playbook rewritten by Update. Defaults to writing the
+	// file with mode 0644.
+	Write func(path string, data []byte) error
+	// Trace receives an event for every playbook considered, see [EventRead].
+	Trace func(path, event, detail string)
+}
+
+func (o *Options) trace(path, event, detail string) {
+	if o.Trace != nil {
+		o.Trace(path, event, detail)
+	}
+}
+
+// playbooks returns the playbooks to consider.
+func (o *Options) playbooks(dir string) ([]string, error) {
+	if o.Playbooks != nil {
+		return o.Playbooks, nil
+	}
+	return Playbooks(dir, o.PlaybooksDir)
+}
+
+// Playbooks returns the playbook path of every layer directory under
+// playbooksDir, src/ if empty, whether or not the playbook exists:
+// <layer>/<layer>.yaml, or .yml if only that exists. Symlinked layer
+// directories are followed, see [chassis.Dirs]. A repository without the
+// directory has none.
+func Playbooks(dir, playbooksDir string) ([]string, error) {
+	if playbooksDir == "" {
+		playbooksDir = chassis.DefaultPlaybooksDir
+	}
+	srcDir := filepath.Join(dir, filepath.FromSlash(playbooksDir))
+	layers, err := chassis.Dirs(srcDir, nil)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var playbooks []string
+	for _, layer := range layers {
+		playbooks = append(playbooks, chassis.FindFile(filepath.Join(srcDir, layer, layer), chassis.DefaultExtensions))
+	}
+	return playbooks, nil
+}
+
+// Targets reports whether a play with the given hosts targets chassisPath or
+// one of its descendants. Every play targets the empty path.
+func Targets(hosts, chassisPath string) bool {
+	return chassisPath == "" || hosts == chassisPath || chassis.IsDescendantOf(hosts, chassisPath)
+}
+
+// ParsePlaybook reads a playbook and returns its plays.
+func ParsePlaybook(path string) ([]Play, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &PlaybookError{Path: path, Op: "read", Err: err}
+	}
+	plays, err := ParsePlays(data)
+	if err != nil {
+		return nil, &PlaybookError{Path: path, Op: "parse", Err: err}
+	}
+	return plays, nil
+}
+
+// ParsePlays returns the plays of playbook content. Roles are strings or
+// dicts with a role key and an optional version constraint:
+//
+//	# src/foundation/foundation.yaml
+//	- hosts: platform.foundation.cluster
+//	  roles:
+//	    - foundation.applications.os
+//	    - role: foundation.applications.cluster
+//	      version: "~1.4"
+func ParsePlays(data []byte) ([]Play, error) {
+	var raw []struct {
+		Hosts string        `yaml:"hosts"`
+		Roles []interface{} `yaml:"roles"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	plays := make([]Play, 0, len(raw))
+	for _, r := range raw {
+		p := Play{Hosts: r.Hosts}
+		for _, role := range r.Roles {
+			var parsed Role
+			switch role := role.(type) {
+			case string:
+				parsed.Name = role
+			case map[string]interface{}:
+				if name, ok := role["role"].(string); ok {
+					parsed.Name = name
+				}
+				if version, ok := role["version"]; ok && version != nil {
+					parsed.Version = fmt.Sprint(version)
+				}
+			}
+			if parsed.Name != "" {
+				p.Roles = append(p.Roles, parsed)
+			}
+		}
+		plays = append(plays, p)
+	}
+	return plays, nil
+}
+
+// Load returns the attachments of the playbooks in dir, see [Options].
+// Playbooks that can't be read or parsed are skipped; their
+// [PlaybookError] values are joined in the returned error alongside the
+// attachments of the others. Load stops early when ctx is done.
+func Load(ctx context.Context, dir string, opts Options) ([]Attachment, error) {
+	playbooks, err := opts.playbooks(dir)
+	if err != nil {
+		return nil, err
+	}
+	parse := opts.Parse
+	if parse == nil {
+		parse = func(path string) ([]Play, error) {
+			plays, err := ParsePlaybook(path)
+			if err == nil {
+				opts.trace(path, EventRead, "")
+			}
+			return plays, err
+		}
+	}
+
+	var attachments []Attachment
+	var errs []error
+	for _, path := range playbooks {
+		if err := ctx.Err(); err != nil {
+			return attachments, err
+		}
+		plays, err := parse(path)
+		if err != nil {
+			var pbErr *PlaybookError
+			if !errors.As(err, &pbErr) {
+				pbErr = &PlaybookError{Path: path, Op: "parse", Err: err}
+			}
+			opts.trace(path, EventSkip, pbErr.detail())
+			errs = append(errs, pbErr)
+			continue
+		}
+
+		matched := false
+		for _, play := range plays {
+			if play.Hosts == "" || !Targets(play.Hosts, opts.Chassis) {
+				continue
+			}
+			matched = true
+			for _, role := range play.Roles {
+				attachments = append(attachments, Attachment{
+					Component: role.Name,
+					Playbook:  path,
+					Chassis:   play.Hosts,
+					Version:   role.Version,
+				})
+			}
+		}
+
+		if matched {
+			opts.trace(path, EventMatch, "")
+		} else {
+			opts.trace(path, EventNoMatch, "no play targets "+opts.Chassis)
+		}
+	}
+	return attachments, errors.Join(errs...)
+}
+
+// Update renames oldPath and its descendants to newPath in the hosts of
+// every play, preserving the formatting of the playbooks, and returns the
+// playbooks rewritten. Playbooks that can't be read or parsed are left
+// alone. Playbooks that can't be written don't stop the update; their
+// [PlaybookError] values are joined in the returned error. Update stops
+// early when ctx is done.
+func Update(ctx context.Context, dir, oldPath, newPath string, opts Options) ([]string, error) {
+	playbooks, err := opts.playbooks(dir)
+	if err != nil {
+		return nil, err
+	}
+	write := opts.Write
+	if write == nil {
+		write = func(path string, data []byte) error {
+			return os.WriteFile(path, data, 0644)
+		}
+	}
+
+	var updated []string
+	var errs []error
+	for _, path := range playbooks {
+		if err := ctx.Err(); err != nil {
+			return updated, errors.Join(append(errs, err)...)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			opts.trace(path, EventSkip, err.Error())
+			continue
+		}
+
+		// Parse as yaml.Node to preserve formatting
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			opts.trace(path, EventSkip, "parse error: "+err.Error())
+			continue
+		}
+		if !renameHosts(&doc, oldPath, newPath) {
+			opts.trace(path, EventNoMatch, "no hosts reference "+oldPath)
+			continue
+		}
+		opts.trace(path, EventMatch, "")
+		clearMergeTags(&doc)
+		newData, err := yaml.Marshal(&doc)
+		if err != nil {
+			opts.trace(path, EventSkip, "marshal error: "+err.Error())
+			continue
+		}
+		if err := write(path, newData); err != nil {
+			opts.trace(path, EventSkip, "write error: "+err.Error())
+			errs = append(errs, &PlaybookError{Path: path, Op: "write", Err: err})
+			continue
+		}
+		opts.trace(path, EventWrite, "")
+		updated = append(updated, path)
+	}
+	return updated, errors.Join(errs...)
+}
+
+// renameHosts recursively renames hosts fields below node.
+func renameHosts(node *yaml.Node, oldPath, newPath string) bool {
+	updated := false
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if renameHosts(child, oldPath, newPath) {
+				updated = true
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			value := node.Content[i+1]
+
+			if key.Value == "hosts" && value.Kind == yaml.ScalarNode {
+				// Exact match or descendant
+				if value.Value == oldPath {
+					value.Value = newPath
+					updated = true
+				} else if strings.HasPrefix(value.Value, oldPath+".") {
+					value.Value = newPath + value.Value[len(oldPath):]
+					updated = true
+				}
+			} else if renameHosts(value, oldPath, newPath) {
+				updated = true
+			}
+		}
+	}
+
+	return updated
+}
+
+// clearMergeTags resets the tag of every merge key, which the encoder would
+// otherwise write out as "!!merge <<".
+func clearMergeTags(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Value == "<<" && node.ShortTag() == "!!merge" {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		clearMergeTags(child)
+	}
+}
@@ -0,0 +1,33 @@
+package chassis
+
+import "gopkg.in/yaml.v3"
+
+// Anchors and aliases in chassis.yaml are resolved on read: a path declared
+// through an alias exists wherever the alias appears, exactly as if the
+// anchored content was written out in place.
+
+// resolveAlias returns the node an alias refers to, or node itself.
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// HasAliases reports whether chassis.yaml uses YAML aliases.
+func (c *Chassis) HasAliases() bool {
+	return c.node != nil && hasAliases(c.node)
+}
+
+// hasAliases reports whether an alias node appears below node.
+func hasAliases(node *yaml.Node) bool {
+	if node.Kind == yaml.AliasNode {
+		return true
+	}
+	for _, child := range node.Content {
+		if hasAliases(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// aliasedChassis shares a section through an anchor, and a whole layer.
+const aliasedChassis = `platform:
+  foundation: &foundation
+    - cluster: &cluster
+      - control
+      - nodes
+    - storage
+  interaction:
+    - cluster: *cluster
+    - observability
+  staging: *foundation
+`
+
+// writeChassis writes chassis.yaml to a temporary directory and returns it.
+func writeChassis(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chassis.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFlattenAliases(t *testing.T) {
+	c, err := Load(writeChassis(t, aliasedChassis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.HasAliases() {
+		t.Error("HasAliases() = false, want true")
+	}
+
+	want := []string{
+		"platform",
+		"platform.foundation",
+		"platform.foundation.cluster",
+		"platform.foundation.cluster.control",
+		"platform.foundation.cluster.nodes",
+		"platform.foundation.storage",
+		"platform.interaction",
+		"platform.interaction.cluster",
+		"platform.interaction.cluster.control",
+		"platform.interaction.cluster.nodes",
+		"platform.interaction.observability",
+		"platform.staging",
+		"platform.staging.cluster",
+		"platform.staging.cluster.control",
+		"platform.staging.cluster.nodes",
+		"platform.staging.storage",
+	}
+	if got := c.Flatten(); !slices.Equal(got, want) {
+		t.Errorf("Flatten() =\n%v\nwant\n%v", got, want)
+	}
+	for _, p := range []string{"platform.interaction.cluster.nodes", "platform.staging.storage"} {
+		if !c.Exists(p) {
+			t.Errorf("Exists(%q) = false, want true", p)
+		}
+	}
+}
+
+func TestHasAliasesWithoutAliases(t *testing.T) {
+	c, err := Load(writeChassis(t, "platform:\n  foundation:\n    - cluster\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HasAliases() {
+		t.Error("HasAliases() = true, want false")
+	}
+}
@@ -0,0 +1,223 @@
+package chassis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Provenance tells why a node is effectively allocated to a chassis path.
+type Provenance string
+
+// Provenances of effective allocations.
+const (
+	// ProvenanceDirect is an allocation written in the node file, directly
+	// or through an allocation expression.
+	ProvenanceDirect Provenance = "direct"
+	// ProvenanceInherited is an allocation added by distribution, from a
+	// directly allocated ancestor or descendant.
+	ProvenanceInherited Provenance = "inherited"
+)
+
+// DeclaredNode is a node with the allocation entries of its node file.
+type DeclaredNode struct {
+	Hostname string
+	Platform string
+	// File is the node file declaring the allocations.
+	File string
+	// Chassis are the allocation entries, paths or expressions.
+	Chassis []string
+}
+
+// Allocation is an effective allocation of a node to a chassis path.
+type Allocation struct {
+	Path       string     `json:"path"`
+	Provenance Provenance `json:"provenance"`
+	// From is the directly allocated path an inherited allocation derives from.
+	From string `json:"from,omitempty"`
+}
+
+// NodeAllocations are the effective allocations of a node.
+type NodeAllocations struct {
+	Hostname    string       `json:"hostname"`
+	Platform    string       `json:"platform"`
+	File        string       `json:"file,omitempty"`
+	Allocations []Allocation `json:"allocations"`
+}
+
+// DisplayName returns the node formatted as "hostname@platform".
+func (n NodeAllocations) DisplayName() string {
+	return n.Hostname + "@" + n.Platform
+}
+
+// Paths returns the effective chassis paths, sorted.
+func (n NodeAllocations) Paths() []string {
+	paths := make([]string, 0, len(n.Allocations))
+	for _, a := range n.Allocations {
+		paths = append(paths, a.Path)
+	}
+	return paths
+}
+
+// AllocatedTo reports whether the node is effectively allocated to chassisPath or one of its descendants.
+func (n NodeAllocations) AllocatedTo(chassisPath string) bool {
+	for _, a := range n.Allocations {
+		if a.Path == chassisPath || IsDescendantOf(a.Path, chassisPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allocate computes the effective allocations of nodes with the given
+// distributor. Distribution is computed per platform, since nodes of
+// different platforms don't share paths. The result keeps the order of nodes.
+func Allocate(c *Chassis, d Distributor, nodes []DeclaredNode) []NodeAllocations {
+	// platform → hostname → allocation entries
+	direct := make(map[string]map[string][]string)
+	for _, n := range nodes {
+		if direct[n.Platform] == nil {
+			direct[n.Platform] = make(map[string][]string)
+		}
+		direct[n.Platform][n.Hostname] = append(direct[n.Platform][n.Hostname], n.Chassis...)
+	}
+	effective := make(map[string]map[string][]string, len(direct))
+	for platform, allocs := range direct {
+		effective[platform] = d.Distribute(c, allocs)
+	}
+
+	result := make([]NodeAllocations, 0, len(nodes))
+	for _, n := range nodes {
+		declared := c.ExpandAllocations(direct[n.Platform][n.Hostname])
+		result = append(result, NodeAllocations{
+			Hostname:    n.Hostname,
+			Platform:    n.Platform,
+			File:        n.File,
+			Allocations: provenance(effective[n.Platform][n.Hostname], declared),
+		})
+	}
+	return result
+}
+
+// provenance attributes each effective path to the declared paths. An
+// inherited path derives from the nearest declared descendant, as ancestors
+// are added upward, or else from the nearest declared ancestor.
+func provenance(paths, declared []string) []Allocation {
+	isDeclared := make(map[string]bool, len(declared))
+	for _, p := range declared {
+		isDeclared[p] = true
+	}
+	sorted := append([]string(nil), declared...)
+	sort.Strings(sorted)
+
+	allocations := make([]Allocation, 0, len(paths))
+	for _, p := range paths {
+		if isDeclared[p] {
+			allocations = append(allocations, Allocation{Path: p, Provenance: ProvenanceDirect})
+			continue
+		}
+		a := Allocation{Path: p, Provenance: ProvenanceInherited}
+		for _, d := range sorted {
+			if IsDescendantOf(d, p) && (a.From == "" || len(d) < len(a.From)) {
+				a.From = d
+			}
+		}
+		if a.From == "" {
+			for q := Parent(p); q != ""; q = Parent(q) {
+				if isDeclared[q] {
+					a.From = q
+					break
+				}
+			}
+		}
+		allocations = append(allocations, a)
+	}
+	return allocations
+}
+
+// EmptyAllocation explains why a node declaring allocation entries is
+// effectively allocated to no path of the chassis tree.
+type EmptyAllocation struct {
+	Hostname string   `json:"hostname"`
+	Platform string   `json:"platform"`
+	Entries  []string `json:"entries"`
+	// Reasons explain each included term of the entries.
+	Reasons []string `json:"reasons"`
+}
+
+// DisplayName returns the node formatted as "hostname@platform".
+func (e EmptyAllocation) DisplayName() string {
+	return e.Hostname + "@" + e.Platform
+}
+
+// EmptyAllocations returns the nodes declaring allocation entries whose
+// effective allocations, as computed by [Allocate] for nodes, hold no path
+// of the tree except ancestors of missing paths, e.g. because the entries
+// name removed paths, or patterns matching nothing or only excluded paths.
+// Nodes without entries aren't returned.
+func EmptyAllocations(c *Chassis, nodes []DeclaredNode, allocations []NodeAllocations) []EmptyAllocation {
+	var empty []EmptyAllocation
+	for i, n := range nodes {
+		if len(n.Chassis) == 0 || i >= len(allocations) {
+			continue
+		}
+		allocated := false
+		for _, a := range allocations[i].Allocations {
+			// Ancestors inherited from a missing path don't count
+			if c.Exists(a.Path) && (a.Provenance == ProvenanceDirect || c.Exists(a.From)) {
+				allocated = true
+				break
+			}
+		}
+		if !allocated {
+			empty = append(empty, EmptyAllocation{
+				Hostname: n.Hostname,
+				Platform: n.Platform,
+				Entries:  n.Chassis,
+				Reasons:  c.emptyReasons(n.Chassis),
+			})
+		}
+	}
+	return empty
+}
+
+// emptyReasons explains why each include term of allocation entries adds
+// no path of the tree.
+func (c *Chassis) emptyReasons(entries []string) []string {
+	include, exclude := splitTerms(entries)
+	if len(include) == 0 {
+		return []string{"no path is included, only excluded"}
+	}
+	var reasons []string
+	for _, t := range include {
+		if !strings.Contains(t, "*") {
+			switch x := excludedBy(exclude, t); {
+			case !c.Exists(t):
+				reasons = append(reasons, fmt.Sprintf("%s is not declared in chassis.yaml", t))
+			case x != "":
+				reasons = append(reasons, fmt.Sprintf("%s is excluded by !%s", t, x))
+			default:
+				reasons = append(reasons, fmt.Sprintf("%s yields no path after distribution", t))
+			}
+			continue
+		}
+		matched, kept := 0, 0
+		for _, p := range c.Flatten() {
+			if MatchPattern(t, p) {
+				matched++
+				if !excluded(exclude, p) {
+					kept++
+				}
+			}
+		}
+		switch {
+		case matched == 0:
+			reasons = append(reasons, fmt.Sprintf("%s matches no chassis path", t))
+		case kept == 0:
+			reasons = append(reasons, fmt.Sprintf("%s only matches excluded paths", t))
+		default:
+			reasons = append(reasons, fmt.Sprintf("%s yields no path after distribution", t))
+		}
+	}
+	return reasons
+}
@@ -16,6 +16,11 @@ import (
 type Chassis struct {
 	node *yaml.Node
 	data map[string]map[string][]interface{}
+
+	normalized []Normalization
+
+	header      Header
+	headerNodes []*yaml.Node // header key/value nodes, written before the tree
 }
 
 // YAMLNode returns the underlying YAML document node.
@@ -51,14 +56,24 @@ func Load(dir string) (*Chassis, error) {
 		return nil, fmt.Errorf("failed to parse chassis.yaml: %w", err)
 	}
 
+	header, headerNodes := splitHeader(&node)
+	if err := checkHeader(header); err != nil {
+		return nil, err
+	}
+
+	normalized := normalizeScalars(&node)
+
 	var parsed map[string]map[string][]interface{}
-	if err := yaml.Unmarshal(data, &parsed); err != nil {
+	if err := node.Decode(&parsed); err != nil {
 		return nil, fmt.Errorf("failed to parse chassis.yaml: %w", err)
 	}
 
 	return &Chassis{
-		node: &node,
-		data: parsed,
+		node:        &node,
+		data:        parsed,
+		normalized:  normalized,
+		header:      header,
+		headerNodes: headerNodes,
 	}, nil
 }
 
@@ -78,7 +93,7 @@ func (c *Chassis) Flatten() []string {
 	// Iterate root keys (e.g., "platform")
 	for i := 0; i < len(rootNode.Content); i += 2 {
 		rootKey := rootNode.Content[i].Value
-		rootValue := rootNode.Content[i+1]
+		rootValue := resolveAlias(rootNode.Content[i+1])
 		paths = append(paths, rootKey)
 
 		if rootValue.Kind != yaml.MappingNode {
@@ -88,7 +103,7 @@ func (c *Chassis) Flatten() []string {
 		// Iterate layers (e.g., "foundation", "interaction")
 		for j := 0; j < len(rootValue.Content); j += 2 {
 			layerKey := rootValue.Content[j].Value
-			layerValue := rootValue.Content[j+1]
+			layerValue := resolveAlias(rootValue.Content[j+1])
 			layerPrefix := rootKey + "." + layerKey
 			paths = append(paths, layerPrefix)
 
@@ -106,13 +121,14 @@ func flattenSequence(prefix string, node *yaml.Node) []string {
 	var paths []string
 
 	for _, item := range node.Content {
+		item = resolveAlias(item)
 		switch item.Kind {
 		case yaml.ScalarNode:
 			paths = append(paths, prefix+"."+item.Value)
 		case yaml.MappingNode:
 			for k := 0; k < len(item.Content); k += 2 {
 				key := item.Content[k].Value
-				value := item.Content[k+1]
+				value := resolveAlias(item.Content[k+1])
 				newPrefix := prefix + "." + key
 				paths = append(paths, newPrefix)
 				if value.Kind == yaml.SequenceNode {
@@ -135,6 +151,123 @@ func (c *Chassis) Exists(chassisPath string) bool {
 	return false
 }
 
+// NearMatch returns an existing path that differs from chassisPath, or from
+// one of its missing ancestors, only by letter case or surrounding whitespace
+// in a segment. It returns false if there is no such near-duplicate.
+func (c *Chassis) NearMatch(chassisPath string) (string, bool) {
+	byKey := make(map[string]string)
+	exact := make(map[string]bool)
+	for _, p := range c.Flatten() {
+		exact[p] = true
+		if _, ok := byKey[normalizeKey(p)]; !ok {
+			byKey[normalizeKey(p)] = p
+		}
+	}
+
+	parts := strings.Split(chassisPath, ".")
+	for i := range parts {
+		prefix := strings.Join(parts[:i+1], ".")
+		if exact[prefix] {
+			continue
+		}
+		if existing, ok := byKey[normalizeKey(prefix)]; ok {
+			return existing, true
+		}
+	}
+	return "", false
+}
+
+// Canonical returns the declared path equal to chassisPath up to letter case
+// and surrounding whitespace of its segments. It returns false if there is
+// no such path.
+func (c *Chassis) Canonical(chassisPath string) (string, bool) {
+	key := normalizeKey(chassisPath)
+	for _, p := range c.Flatten() {
+		if normalizeKey(p) == key {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// normalizeKey folds case and trims whitespace of each path segment.
+func normalizeKey(chassisPath string) string {
+	parts := strings.Split(chassisPath, ".")
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(p))
+	}
+	return strings.Join(parts, ".")
+}
+
+// HasChildren reports whether a chassis path has at least one child path.
+func (c *Chassis) HasChildren(chassisPath string) bool {
+	return len(c.Children(chassisPath)) > 0
+}
+
+// IsPlaceholder reports whether a chassis path is declared with an explicit
+// empty collection (e.g., "layer: []"), as opposed to a null value or a bare
+// leaf entry. Placeholders are paths reserved for future use.
+func (c *Chassis) IsPlaceholder(chassisPath string) bool {
+	value := c.lookup(chassisPath)
+	if value == nil {
+		return false
+	}
+	return (value.Kind == yaml.SequenceNode || value.Kind == yaml.MappingNode) && len(value.Content) == 0
+}
+
+// IsEmpty reports whether a chassis path is declared as a container without
+// children: an explicit empty collection or a null value ("layer:").
+// Bare scalar leaves are not containers and are never empty.
+func (c *Chassis) IsEmpty(chassisPath string) bool {
+	value := c.lookup(chassisPath)
+	if value == nil {
+		return false
+	}
+	if value.Kind == yaml.ScalarNode {
+		return value.Tag == "!!null"
+	}
+	return (value.Kind == yaml.SequenceNode || value.Kind == yaml.MappingNode) && len(value.Content) == 0
+}
+
+// lookup returns the YAML value node declared for a chassis path.
+// It returns nil if the path doesn't exist or is a bare scalar leaf.
+func (c *Chassis) lookup(chassisPath string) *yaml.Node {
+	if c.node == nil || len(c.node.Content) == 0 {
+		return nil
+	}
+	return lookupNode(c.node.Content[0], strings.Split(chassisPath, "."))
+}
+
+// lookupNode walks mapping and sequence nodes following path segments.
+func lookupNode(node *yaml.Node, parts []string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || len(parts) == 0 {
+		return node
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			if node.Content[i].Value == parts[0] {
+				return lookupNode(node.Content[i+1], parts[1:])
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind == yaml.MappingNode {
+				for j := 0; j < len(item.Content); j += 2 {
+					if item.Content[j].Value == parts[0] {
+						return lookupNode(item.Content[j+1], parts[1:])
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // Root returns the root chassis name (e.g., "platform").
 func (c *Chassis) Root() string {
 	paths := c.Flatten()
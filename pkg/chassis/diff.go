@@ -0,0 +1,268 @@
+package chassis
+
+import (
+	"sort"
+	"strings"
+)
+
+// PathDiff lists chassis paths that differ between two trees.
+type PathDiff struct {
+	Added   []string `json:"added"`   // paths only in the new tree
+	Removed []string `json:"removed"` // paths only in the old tree
+}
+
+// IsEmpty reports whether both trees declare the same paths.
+func (d PathDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Diff compares the paths of two trees. Added paths follow the order of
+// newer, removed paths follow the order of older.
+func Diff(older, newer *Chassis) PathDiff {
+	oldPaths := older.Flatten()
+	newPaths := newer.Flatten()
+
+	inOld := make(map[string]bool, len(oldPaths))
+	for _, p := range oldPaths {
+		inOld[p] = true
+	}
+	inNew := make(map[string]bool, len(newPaths))
+	for _, p := range newPaths {
+		inNew[p] = true
+	}
+
+	d := PathDiff{Added: []string{}, Removed: []string{}}
+	for _, p := range newPaths {
+		if !inOld[p] {
+			d.Added = append(d.Added, p)
+		}
+	}
+	for _, p := range oldPaths {
+		if !inNew[p] {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+	return d
+}
+
+// Topology is a chassis tree with the effective allocations of its nodes
+// and the attachments of its components, both as name → chassis paths.
+type Topology struct {
+	Chassis     *Chassis
+	Allocations map[string][]string
+	Attachments map[string][]string
+}
+
+// Rename is a chassis path renamed or moved along with its subtree.
+type Rename struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Delta lists the chassis paths a node or component gained and lost.
+type Delta struct {
+	Name    string   `json:"name"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// TopologyDiff lists the differences between two topologies, see
+// [DiffTopology]. Added and Removed leave out the renamed subtrees.
+type TopologyDiff struct {
+	PathDiff
+	Renamed     []Rename `json:"renamed"`
+	Allocations []Delta  `json:"allocations"`
+	Attachments []Delta  `json:"attachments"`
+}
+
+// IsEmpty reports whether both topologies are the same.
+func (d TopologyDiff) IsEmpty() bool {
+	return d.PathDiff.IsEmpty() && len(d.Renamed) == 0 && len(d.Allocations) == 0 && len(d.Attachments) == 0
+}
+
+// DiffTopology compares two topologies. A removed subtree is reported as
+// renamed to an added one if both have the same paths, nodes and
+// components relative to their root, or failing that the same paths, or
+// the same root name for leaves moved to another parent, and no other
+// subtree matches. Allocations and attachments to a renamed path don't
+// count as changes; a node or component that moved is reported with its
+// old paths as removed.
+func DiffTopology(older, newer Topology) TopologyDiff {
+	paths := Diff(older.Chassis, newer.Chassis)
+	d := TopologyDiff{
+		PathDiff: PathDiff{Added: []string{}, Removed: []string{}},
+		Renamed:  detectRenames(older, newer, paths),
+	}
+
+	translate := func(p string) string {
+		for _, r := range d.Renamed {
+			if p == r.Old || IsDescendantOf(p, r.Old) {
+				return r.New + p[len(r.Old):]
+			}
+		}
+		return p
+	}
+	renamedTo := make(map[string]bool)
+	for _, p := range paths.Removed {
+		if q := translate(p); q != p && newer.Chassis.Exists(q) {
+			renamedTo[q] = true
+			continue
+		}
+		d.Removed = append(d.Removed, p)
+	}
+	for _, p := range paths.Added {
+		if !renamedTo[p] {
+			d.Added = append(d.Added, p)
+		}
+	}
+
+	d.Allocations = deltas(older.Allocations, newer.Allocations, translate)
+	d.Attachments = deltas(older.Attachments, newer.Attachments, translate)
+	return d
+}
+
+// detectRenames pairs the topmost removed and added subtrees with the same
+// signature, see [DiffTopology]. Ambiguous signatures aren't paired.
+func detectRenames(older, newer Topology, paths PathDiff) []Rename {
+	removed := topmost(paths.Removed)
+	added := topmost(paths.Added)
+	paired := make(map[string]bool)
+	renames := []Rename{}
+
+	pair := func(signature func(t Topology, p string) string) {
+		bySignature := make(map[string][2][]string)
+		for _, p := range removed {
+			if sig := signature(older, p); sig != "" && !paired[p] {
+				e := bySignature[sig]
+				e[0] = append(e[0], p)
+				bySignature[sig] = e
+			}
+		}
+		for _, p := range added {
+			if sig := signature(newer, p); sig != "" && !paired[p] {
+				e := bySignature[sig]
+				e[1] = append(e[1], p)
+				bySignature[sig] = e
+			}
+		}
+		for _, e := range bySignature {
+			if len(e[0]) == 1 && len(e[1]) == 1 {
+				paired[e[0][0]], paired[e[1][0]] = true, true
+				renames = append(renames, Rename{Old: e[0][0], New: e[1][0]})
+			}
+		}
+	}
+	nonTrivial := func(sig string) string {
+		if !strings.Contains(sig, "\n") {
+			// A leaf without nodes or components could be anything
+			return ""
+		}
+		return sig
+	}
+	pair(func(t Topology, p string) string {
+		return nonTrivial(subtreeSignature(t, p, true))
+	})
+	pair(func(t Topology, p string) string {
+		return nonTrivial(subtreeSignature(t, p, false))
+	})
+	pair(func(t Topology, p string) string {
+		return p[strings.LastIndex(p, ".")+1:] + "\n" + subtreeSignature(t, p, false)
+	})
+
+	sort.Slice(renames, func(i, j int) bool { return renames[i].Old < renames[j].Old })
+	return renames
+}
+
+// subtreeSignature describes the subtree of p relative to p: its paths and,
+// with relations, the nodes and components allocated or attached to them.
+func subtreeSignature(t Topology, p string, relations bool) string {
+	relative := func(q string) (string, bool) {
+		if q == p {
+			return ".", true
+		}
+		if IsDescendantOf(q, p) {
+			return q[len(p):], true
+		}
+		return "", false
+	}
+	var lines []string
+	for _, q := range t.Chassis.FlattenWithPrefix(p) {
+		rel, _ := relative(q)
+		lines = append(lines, "path "+rel)
+	}
+	if relations {
+		for kind, m := range map[string]map[string][]string{"node": t.Allocations, "component": t.Attachments} {
+			for name, paths := range m {
+				for _, q := range paths {
+					if rel, ok := relative(q); ok {
+						lines = append(lines, kind+" "+name+" "+rel)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// topmost returns the paths whose parent isn't in paths.
+func topmost(paths []string) []string {
+	in := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		in[p] = true
+	}
+	var result []string
+	for _, p := range paths {
+		if !in[Parent(p)] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// deltas compares name → chassis paths maps, sorted by name. Older paths
+// are translated to their newer names before they are compared.
+func deltas(older, newer map[string][]string, translate func(string) string) []Delta {
+	names := make(map[string]bool)
+	for name := range older {
+		names[name] = true
+	}
+	for name := range newer {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	result := []Delta{}
+	for _, name := range sorted {
+		was := make(map[string]bool, len(older[name]))
+		for _, p := range older[name] {
+			was[translate(p)] = true
+		}
+		is := make(map[string]bool, len(newer[name]))
+		for _, p := range newer[name] {
+			is[p] = true
+		}
+
+		d := Delta{Name: name}
+		for _, p := range newer[name] {
+			if !was[p] {
+				d.Added = appendUnique(d.Added, p)
+			}
+		}
+		for _, p := range older[name] {
+			if !is[translate(p)] {
+				d.Removed = appendUnique(d.Removed, p)
+			}
+		}
+		if len(d.Added) > 0 || len(d.Removed) > 0 {
+			sort.Strings(d.Added)
+			sort.Strings(d.Removed)
+			result = append(result, d)
+		}
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dirs returns the names of the subdirectories of dir, such as the platform
+// directories under inst/ or the layer directories under src/. Symlinks are
+// followed, so mono-repos may link platforms or layers in from elsewhere.
+// Entries are skipped, and reported to skip if not nil, when they are broken
+// symlinks, point at dir itself or one of its ancestors, which would loop
+// when descended into, or resolve to a directory already listed.
+func Dirs(dir string, skip func(name, reason string)) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if skip == nil {
+		skip = func(string, string) {}
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Real directories first, so they win over symlinks to them
+	var names []string
+	seen := make(map[string]string) // resolved path → name
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+			seen[filepath.Join(realDir, entry.Name())] = entry.Name()
+		}
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := filepath.EvalSymlinks(filepath.Join(dir, name))
+		if err != nil {
+			skip(name, "broken symlink: "+err.Error())
+			continue
+		}
+		info, err := os.Stat(target)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if target == realDir || strings.HasPrefix(realDir, target+string(filepath.Separator)) {
+			skip(name, "symlink loop: points at "+target)
+			continue
+		}
+		if other, ok := seen[target]; ok {
+			skip(name, "same directory as "+other)
+			continue
+		}
+		seen[target] = name
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,123 @@
+package chassis
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// symlinkLayout creates inst/ with a real platform, linked platforms and
+// links that loop or are broken, and returns the path of inst/.
+func symlinkLayout(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	inst := filepath.Join(root, "inst")
+	for _, d := range []string{"inst/dev", "shared/prod", "shared/stage"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(inst, "README.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"prod":    "../shared/prod", // platform linked in from elsewhere
+		"stage":   filepath.Join(root, "shared", "stage"),
+		"self":    ".",  // loops into inst/
+		"up":      "..", // loops into an ancestor
+		"broken":  "../shared/missing",
+		"dev-2":   "dev", // same directory as dev
+		"readme":  "README.md",
+		"cycle-a": "cycle-b", // links pointing at each other
+		"cycle-b": "cycle-a",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(inst, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	return inst
+}
+
+// dirsWithin runs Dirs and fails the test if it doesn't return in time.
+func dirsWithin(t *testing.T, dir string) ([]string, map[string]string) {
+	t.Helper()
+	type result struct {
+		names   []string
+		skipped map[string]string
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		skipped := make(map[string]string)
+		names, err := Dirs(dir, func(name, reason string) { skipped[name] = reason })
+		done <- result{names, skipped, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		return r.names, r.skipped
+	case <-time.After(10 * time.Second):
+		t.Fatal("Dirs did not terminate")
+		return nil, nil
+	}
+}
+
+func TestDirsSymlinks(t *testing.T) {
+	inst := symlinkLayout(t)
+	names, skipped := dirsWithin(t, inst)
+
+	if want := []string{"dev", "prod", "stage"}; !slices.Equal(names, want) {
+		t.Errorf("Dirs() = %v, want %v", names, want)
+	}
+	wantSkipped := map[string]string{
+		"self":    "symlink loop",
+		"up":      "symlink loop",
+		"broken":  "broken symlink",
+		"cycle-a": "broken symlink",
+		"cycle-b": "broken symlink",
+		"dev-2":   "same directory as dev",
+	}
+	for name, reason := range wantSkipped {
+		if !strings.HasPrefix(skipped[name], reason) {
+			t.Errorf("%s: skip reason %q, want %q", name, skipped[name], reason)
+		}
+	}
+	for name := range skipped {
+		if _, ok := wantSkipped[name]; !ok {
+			t.Errorf("%s: skipped unexpectedly: %s", name, skipped[name])
+		}
+	}
+}
+
+func TestDirsSymlinkedDir(t *testing.T) {
+	inst := symlinkLayout(t)
+	link := filepath.Join(filepath.Dir(inst), "inst-link")
+	if err := os.Symlink(inst, link); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loops are detected against the resolved directory
+	names, skipped := dirsWithin(t, link)
+	if want := []string{"dev", "prod", "stage"}; !slices.Equal(names, want) {
+		t.Errorf("Dirs() = %v, want %v", names, want)
+	}
+	if !strings.HasPrefix(skipped["self"], "symlink loop") {
+		t.Errorf("self: skip reason %q, want a symlink loop", skipped["self"])
+	}
+}
+
+func TestDirsNilSkip(t *testing.T) {
+	names, err := Dirs(symlinkLayout(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dev", "prod", "stage"}; !slices.Equal(names, want) {
+		t.Errorf("Dirs() = %v, want %v", names, want)
+	}
+}
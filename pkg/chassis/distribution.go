@@ -0,0 +1,215 @@
+package chassis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Strategy names a distribution algorithm.
+type Strategy string
+
+// Supported distribution strategies.
+const (
+	// StrategyNearestLeaf propagates nodes down to child paths that have no
+	// direct allocation of their own, then up to all ancestors.
+	StrategyNearestLeaf Strategy = "nearest-leaf"
+	// StrategyAllDescendants propagates nodes to every descendant of a
+	// directly allocated path, then up to all ancestors.
+	StrategyAllDescendants Strategy = "all-descendants"
+	// StrategyExplicitOnly keeps direct allocations as they are.
+	StrategyExplicitOnly Strategy = "explicit-only"
+)
+
+// DefaultStrategy is used when no strategy is configured.
+const DefaultStrategy = StrategyNearestLeaf
+
+// Strategies returns all supported strategies.
+func Strategies() []Strategy {
+	return []Strategy{StrategyNearestLeaf, StrategyAllDescendants, StrategyExplicitOnly}
+}
+
+// Validate checks that s names a supported strategy. An empty strategy
+// selects [DefaultStrategy].
+func (s Strategy) Validate() error {
+	if s == "" || slices.Contains(Strategies(), s) {
+		return nil
+	}
+	return fmt.Errorf("unknown distribution strategy %q (supported: %s, %s, %s)",
+		s, StrategyNearestLeaf, StrategyAllDescendants, StrategyExplicitOnly)
+}
+
+// Distributor computes effective allocations from direct allocations.
+// The direct map is hostname → chassis paths as written in node files;
+// allocation expressions are evaluated by the distributors of [NewDistributor].
+// The returned map is hostname → effective chassis paths, sorted.
+type Distributor interface {
+	Distribute(c *Chassis, direct map[string][]string) map[string][]string
+}
+
+// DistributorFunc adapts a function to the [Distributor] interface.
+type DistributorFunc func(c *Chassis, direct map[string][]string) map[string][]string
+
+// Distribute implements [Distributor].
+func (f DistributorFunc) Distribute(c *Chassis, direct map[string][]string) map[string][]string {
+	return f(c, direct)
+}
+
+// NewDistributor returns the distributor for the given strategy.
+// An empty strategy selects [DefaultStrategy].
+func NewDistributor(s Strategy) (Distributor, error) {
+	switch s {
+	case "", StrategyNearestLeaf:
+		return expanding(distributeNearestLeaf), nil
+	case StrategyAllDescendants:
+		return expanding(distributeAllDescendants), nil
+	case StrategyExplicitOnly:
+		return expanding(distributeExplicitOnly), nil
+	default:
+		return nil, s.Validate()
+	}
+}
+
+// Distribute computes effective allocations using [DefaultStrategy].
+func (c *Chassis) Distribute(direct map[string][]string) map[string][]string {
+	return expanding(distributeNearestLeaf)(c, direct)
+}
+
+// expanding evaluates allocation expressions before distributing and
+// applies their exclusions to the result.
+func expanding(f DistributorFunc) DistributorFunc {
+	return func(c *Chassis, direct map[string][]string) map[string][]string {
+		return applyExclusions(direct, f(c, expandDirect(c, direct)))
+	}
+}
+
+// distributeNearestLeaf implements [StrategyNearestLeaf].
+//
+// Distribution rules (matching platform_nodes.py behavior):
+//  1. Nodes are directly allocated to paths via their chassis field
+//  2. Downward: nodes propagate from parent to empty child paths
+//     (a path is "empty" if no node is directly allocated to it)
+//  3. Upward: nodes propagate to all ancestor paths
+func distributeNearestLeaf(c *Chassis, direct map[string][]string) map[string][]string {
+	if c == nil || len(direct) == 0 {
+		return nil
+	}
+
+	allocs := copyAllocations(direct)
+	directlyOccupied := make(map[string]bool)
+	for _, paths := range direct {
+		for _, p := range paths {
+			directlyOccupied[p] = true
+		}
+	}
+
+	// Downward propagation in tree order so parents are resolved before children
+	childrenMap := c.ChildrenMap()
+	hostnames := sortedKeys(allocs)
+	for _, chassisPath := range c.Flatten() {
+		var inPath []string
+		for _, hostname := range hostnames {
+			if contains(allocs[hostname], chassisPath) {
+				inPath = append(inPath, hostname)
+			}
+		}
+		for _, child := range childrenMap[chassisPath] {
+			if directlyOccupied[child] {
+				continue
+			}
+			for _, hostname := range inPath {
+				allocs[hostname] = appendUnique(allocs[hostname], child)
+			}
+		}
+	}
+
+	return withAncestors(c, allocs)
+}
+
+// distributeAllDescendants implements [StrategyAllDescendants].
+func distributeAllDescendants(c *Chassis, direct map[string][]string) map[string][]string {
+	if c == nil || len(direct) == 0 {
+		return nil
+	}
+
+	allocs := copyAllocations(direct)
+	all := c.Flatten()
+	for hostname, paths := range direct {
+		for _, p := range paths {
+			for _, candidate := range all {
+				if IsDescendantOf(candidate, p) {
+					allocs[hostname] = appendUnique(allocs[hostname], candidate)
+				}
+			}
+		}
+	}
+
+	return withAncestors(c, allocs)
+}
+
+// distributeExplicitOnly implements [StrategyExplicitOnly].
+func distributeExplicitOnly(_ *Chassis, direct map[string][]string) map[string][]string {
+	if len(direct) == 0 {
+		return nil
+	}
+	allocs := copyAllocations(direct)
+	for hostname := range allocs {
+		sort.Strings(allocs[hostname])
+	}
+	return allocs
+}
+
+// withAncestors adds every ancestor of each allocated path and sorts the result.
+func withAncestors(c *Chassis, allocs map[string][]string) map[string][]string {
+	for hostname, paths := range allocs {
+		var toAdd []string
+		for _, p := range paths {
+			toAdd = append(toAdd, c.Ancestors(p)...)
+		}
+		for _, ancestor := range toAdd {
+			allocs[hostname] = appendUnique(allocs[hostname], ancestor)
+		}
+		sort.Strings(allocs[hostname])
+	}
+	return allocs
+}
+
+// copyAllocations returns a deep copy of an allocation map with duplicates removed.
+func copyAllocations(direct map[string][]string) map[string][]string {
+	allocs := make(map[string][]string, len(direct))
+	for hostname, paths := range direct {
+		allocs[hostname] = []string{}
+		for _, p := range paths {
+			allocs[hostname] = appendUnique(allocs[hostname], p)
+		}
+	}
+	return allocs
+}
+
+// sortedKeys returns the keys of an allocation map in sorted order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendUnique appends value to slice if not already present.
+func appendUnique(slice []string, value string) []string {
+	if contains(slice, value) {
+		return slice
+	}
+	return append(slice, value)
+}
+
+// contains checks if slice contains value.
+func contains(slice []string, value string) bool {
+	for _, v := range slice {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
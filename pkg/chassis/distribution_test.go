@@ -0,0 +1,178 @@
+// The tests are in an external package, since plasmactl-node, whose
+// allocations the default strategy must match, imports this package.
+package chassis_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/plasmash/plasmactl-node/pkg/node"
+
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+)
+
+const distributionChassis = `platform:
+  foundation:
+    - cluster:
+      - control
+      - nodes
+    - storage:
+      - kv
+  interaction:
+    - observability
+`
+
+func TestDistributors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chassis.yaml"), []byte(distributionChassis), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := chassis.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type allocations = map[string][]string
+	tests := []struct {
+		name   string
+		direct allocations
+		want   map[chassis.Strategy]allocations
+	}{
+		{
+			name:   "layer",
+			direct: allocations{"n1": {"platform.foundation"}},
+			want: map[chassis.Strategy]allocations{
+				chassis.StrategyNearestLeaf: {"n1": {
+					"platform", "platform.foundation",
+					"platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes",
+					"platform.foundation.storage", "platform.foundation.storage.kv",
+				}},
+				chassis.StrategyAllDescendants: {"n1": {
+					"platform", "platform.foundation",
+					"platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes",
+					"platform.foundation.storage", "platform.foundation.storage.kv",
+				}},
+				chassis.StrategyExplicitOnly: {"n1": {"platform.foundation"}},
+			},
+		},
+		{
+			name: "occupied child",
+			direct: allocations{
+				"n1": {"platform.foundation.cluster"},
+				"n2": {"platform.foundation.cluster.control"},
+			},
+			want: map[chassis.Strategy]allocations{
+				// control has a node of its own, so n1 only reaches nodes
+				chassis.StrategyNearestLeaf: {
+					"n1": {"platform", "platform.foundation", "platform.foundation.cluster", "platform.foundation.cluster.nodes"},
+					"n2": {"platform", "platform.foundation", "platform.foundation.cluster", "platform.foundation.cluster.control"},
+				},
+				chassis.StrategyAllDescendants: {
+					"n1": {"platform", "platform.foundation", "platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes"},
+					"n2": {"platform", "platform.foundation", "platform.foundation.cluster", "platform.foundation.cluster.control"},
+				},
+				chassis.StrategyExplicitOnly: {
+					"n1": {"platform.foundation.cluster"},
+					"n2": {"platform.foundation.cluster.control"},
+				},
+			},
+		},
+		{
+			name: "leaves",
+			direct: allocations{
+				"n1": {"platform.foundation.storage.kv", "platform.interaction.observability"},
+				"n2": {"platform.interaction.observability"},
+			},
+			want: map[chassis.Strategy]allocations{
+				chassis.StrategyNearestLeaf: {
+					"n1": {"platform", "platform.foundation", "platform.foundation.storage", "platform.foundation.storage.kv", "platform.interaction", "platform.interaction.observability"},
+					"n2": {"platform", "platform.interaction", "platform.interaction.observability"},
+				},
+				chassis.StrategyAllDescendants: {
+					"n1": {"platform", "platform.foundation", "platform.foundation.storage", "platform.foundation.storage.kv", "platform.interaction", "platform.interaction.observability"},
+					"n2": {"platform", "platform.interaction", "platform.interaction.observability"},
+				},
+				chassis.StrategyExplicitOnly: {
+					"n1": {"platform.foundation.storage.kv", "platform.interaction.observability"},
+					"n2": {"platform.interaction.observability"},
+				},
+			},
+		},
+		{
+			name: "root",
+			direct: allocations{
+				"n1": {"platform"},
+				"n2": {"platform.interaction"},
+			},
+			want: map[chassis.Strategy]allocations{
+				// interaction and its subtree belong to n2 alone
+				chassis.StrategyNearestLeaf: {
+					"n1": {
+						"platform", "platform.foundation",
+						"platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes",
+						"platform.foundation.storage", "platform.foundation.storage.kv",
+					},
+					"n2": {"platform", "platform.interaction", "platform.interaction.observability"},
+				},
+				chassis.StrategyAllDescendants: {
+					"n1": {
+						"platform", "platform.foundation",
+						"platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes",
+						"platform.foundation.storage", "platform.foundation.storage.kv",
+						"platform.interaction", "platform.interaction.observability",
+					},
+					"n2": {"platform", "platform.interaction", "platform.interaction.observability"},
+				},
+				chassis.StrategyExplicitOnly: {
+					"n1": {"platform"},
+					"n2": {"platform.interaction"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, strategy := range chassis.Strategies() {
+			t.Run(tt.name+"/"+string(strategy), func(t *testing.T) {
+				d, err := chassis.NewDistributor(strategy)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := d.Distribute(c, tt.direct)
+				if want := tt.want[strategy]; !maps.EqualFunc(got, want, slices.Equal) {
+					t.Errorf("Distribute() =\n%v\nwant\n%v", got, want)
+				}
+
+				if strategy != chassis.DefaultStrategy {
+					return
+				}
+				if got := c.Distribute(tt.direct); !maps.EqualFunc(got, tt.want[strategy], slices.Equal) {
+					t.Errorf("Chassis.Distribute() =\n%v\nwant\n%v", got, tt.want[strategy])
+				}
+				var nodes node.Nodes
+				for _, hostname := range slices.Sorted(maps.Keys(tt.direct)) {
+					nodes = append(nodes, node.Node{Hostname: hostname, Chassis: tt.direct[hostname]})
+				}
+				if fromNode := nodes.Allocations(c); !maps.EqualFunc(got, fromNode, slices.Equal) {
+					t.Errorf("Distribute() =\n%v\nplasmactl-node Allocations() =\n%v", got, fromNode)
+				}
+			})
+		}
+	}
+}
+
+func TestNewDistributorUnknown(t *testing.T) {
+	if _, err := chassis.NewDistributor("round-robin"); err == nil {
+		t.Error("NewDistributor() accepted an unknown strategy")
+	}
+	if err := chassis.Strategy("round-robin").Validate(); err == nil {
+		t.Error("Validate() accepted an unknown strategy")
+	}
+	for _, s := range append(chassis.Strategies(), "") {
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package chassis
+
+import "strings"
+
+// Allocation entries in node files are chassis paths or expressions:
+//
+//	platform.foundation.cluster           a single path
+//	platform.foundation.*                 every path below platform.foundation
+//	platform.*.control                    * matches any one segment elsewhere
+//	!platform.foundation.cluster.storage  excludes the path and its descendants
+//
+// An entry may hold several space-separated terms. The allocation of a node
+// is the union of its include terms minus the union of its exclude terms.
+
+// IsExpression reports whether an allocation entry is more than a plain path.
+func IsExpression(entry string) bool {
+	return strings.ContainsAny(entry, "*! \t")
+}
+
+// Terms splits an allocation entry into its terms.
+func Terms(entry string) []string {
+	return strings.Fields(entry)
+}
+
+// MatchPattern reports whether chassisPath matches a path pattern.
+// A * segment matches any one segment; as the last segment it matches
+// one or more remaining segments.
+func MatchPattern(pattern, chassisPath string) bool {
+	ps := strings.Split(pattern, ".")
+	cs := strings.Split(chassisPath, ".")
+	for i, seg := range ps {
+		if seg == "*" && i == len(ps)-1 {
+			return len(cs) > i
+		}
+		if i >= len(cs) || (seg != "*" && seg != cs[i]) {
+			return false
+		}
+	}
+	return len(cs) == len(ps)
+}
+
+// ExpandAllocations evaluates allocation entries against the chassis tree.
+// Plain paths are kept even if missing from the tree, so validation can
+// report them; patterns expand to the matching paths in tree order.
+func (c *Chassis) ExpandAllocations(entries []string) []string {
+	expression := false
+	for _, e := range entries {
+		if IsExpression(e) {
+			expression = true
+			break
+		}
+	}
+	if !expression {
+		return entries
+	}
+
+	include, exclude := splitTerms(entries)
+
+	var all []string
+	if c != nil {
+		all = c.Flatten()
+	}
+	result := []string{}
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if seen[p] || excluded(exclude, p) {
+			return
+		}
+		seen[p] = true
+		result = append(result, p)
+	}
+	for _, t := range include {
+		if !strings.Contains(t, "*") {
+			add(t)
+			continue
+		}
+		for _, p := range all {
+			if MatchPattern(t, p) {
+				add(p)
+			}
+		}
+	}
+	return result
+}
+
+// splitTerms returns the include and exclude terms of allocation entries.
+func splitTerms(entries []string) (include, exclude []string) {
+	for _, e := range entries {
+		for _, t := range Terms(e) {
+			if strings.HasPrefix(t, "!") {
+				exclude = append(exclude, t[1:])
+			} else {
+				include = append(include, t)
+			}
+		}
+	}
+	return include, exclude
+}
+
+// excluded reports whether chassisPath or one of its ancestors matches an exclude term.
+func excluded(exclude []string, chassisPath string) bool {
+	return excludedBy(exclude, chassisPath) != ""
+}
+
+// excludedBy returns the first exclude term matching chassisPath or one of
+// its ancestors, or "".
+func excludedBy(exclude []string, chassisPath string) string {
+	for _, x := range exclude {
+		for p := chassisPath; p != ""; p = Parent(p) {
+			if MatchPattern(x, p) {
+				return x
+			}
+		}
+	}
+	return ""
+}
+
+// ReferencesPath reports whether a term of an allocation entry names
+// chassisPath or one of its descendants, as included or excluded path.
+func ReferencesPath(entry, chassisPath string) bool {
+	for _, t := range Terms(entry) {
+		t = strings.TrimPrefix(t, "!")
+		if t == chassisPath || IsDescendantOf(t, chassisPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// RenameAllocation rewrites the terms of an allocation entry referencing
+// oldPath or its descendants to newPath. Wildcard segments inside the
+// renamed prefix can't be rewritten and are left as they are.
+func RenameAllocation(entry, oldPath, newPath string) (string, bool) {
+	if !IsExpression(entry) {
+		if entry == oldPath || IsDescendantOf(entry, oldPath) {
+			return newPath + entry[len(oldPath):], true
+		}
+		return entry, false
+	}
+
+	terms := Terms(entry)
+	changed := false
+	for i, t := range terms {
+		neg := ""
+		if strings.HasPrefix(t, "!") {
+			neg, t = "!", t[1:]
+		}
+		if t == oldPath || IsDescendantOf(t, oldPath) {
+			terms[i] = neg + newPath + t[len(oldPath):]
+			changed = true
+		}
+	}
+	if !changed {
+		return entry, false
+	}
+	return strings.Join(terms, " "), true
+}
+
+// expandDirect evaluates allocation expressions of every host.
+func expandDirect(c *Chassis, direct map[string][]string) map[string][]string {
+	expanded := make(map[string][]string, len(direct))
+	for hostname, entries := range direct {
+		expanded[hostname] = c.ExpandAllocations(entries)
+	}
+	return expanded
+}
+
+// applyExclusions removes excluded paths from effective allocations, so that
+// downward propagation doesn't bring a node back into an excluded subtree.
+func applyExclusions(direct, allocs map[string][]string) map[string][]string {
+	if allocs == nil {
+		return nil
+	}
+	for hostname, entries := range direct {
+		_, exclude := splitTerms(entries)
+		if len(exclude) == 0 {
+			continue
+		}
+		kept := allocs[hostname][:0]
+		for _, p := range allocs[hostname] {
+			if !excluded(exclude, p) {
+				kept = append(kept, p)
+			}
+		}
+		allocs[hostname] = kept
+	}
+	return allocs
+}
@@ -0,0 +1,128 @@
+package chassis
+
+import (
+	"slices"
+	"testing"
+)
+
+// expressionChassis is the tree allocation expressions are evaluated against.
+const expressionChassis = `platform:
+  foundation:
+    - cluster:
+      - control
+      - nodes
+    - storage:
+      - kv
+  interaction:
+    - observability
+`
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"platform.foundation.cluster", "platform.foundation.cluster", true},
+		{"platform.foundation.cluster", "platform.foundation.cluster.control", false},
+		// A trailing * matches one or more segments
+		{"platform.foundation.*", "platform.foundation.cluster", true},
+		{"platform.foundation.*", "platform.foundation.cluster.control", true},
+		{"platform.foundation.*", "platform.foundation", false},
+		{"platform.foundation.*", "platform.interaction.observability", false},
+		// A * elsewhere matches exactly one segment
+		{"platform.*.control", "platform.foundation.control", true},
+		{"platform.*.control", "platform.foundation.cluster.control", false},
+		{"platform.*.control", "platform.control", false},
+		{"*.foundation", "platform.foundation", true},
+	}
+	for _, tt := range tests {
+		if got := MatchPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExpandAllocations(t *testing.T) {
+	c, err := Load(writeChassis(t, expressionChassis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		entries []string
+		want    []string
+	}{
+		{"plain", []string{"platform.foundation.cluster", "platform.missing"},
+			[]string{"platform.foundation.cluster", "platform.missing"}},
+		{"trailing-wildcard", []string{"platform.foundation.*"}, []string{
+			"platform.foundation.cluster", "platform.foundation.cluster.control", "platform.foundation.cluster.nodes",
+			"platform.foundation.storage", "platform.foundation.storage.kv",
+		}},
+		{"inner-wildcard", []string{"platform.*.observability"}, []string{"platform.interaction.observability"}},
+		{"exclude-descendants", []string{"platform.foundation.* !platform.foundation.cluster"},
+			[]string{"platform.foundation.storage", "platform.foundation.storage.kv"}},
+		{"exclude-other-entry", []string{"platform.foundation.storage", "!platform.foundation.storage.kv platform.foundation.storage.*"},
+			[]string{"platform.foundation.storage"}},
+		{"exclude-pattern", []string{"platform.*.* !platform.*.cluster"}, []string{
+			"platform.foundation.storage", "platform.foundation.storage.kv", "platform.interaction.observability",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ExpandAllocations(tt.entries); !slices.Equal(got, tt.want) {
+				t.Errorf("ExpandAllocations(%q) = %v, want %v", tt.entries, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDistributeExclusions checks that excluded paths stay excluded once
+// distribution propagated allocations down to them.
+func TestDistributeExclusions(t *testing.T) {
+	c, err := Load(writeChassis(t, expressionChassis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct := map[string][]string{"node-1": {"platform.foundation !platform.foundation.cluster"}}
+	want := []string{"platform", "platform.foundation", "platform.foundation.storage", "platform.foundation.storage.kv"}
+	for _, s := range Strategies() {
+		if s == StrategyExplicitOnly {
+			continue // doesn't propagate down
+		}
+		d, err := NewDistributor(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := slices.Sorted(slices.Values(d.Distribute(c, direct)["node-1"]))
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: allocations = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestRenameAllocation(t *testing.T) {
+	const oldPath, newPath = "platform.foundation.cluster", "platform.foundation.k8s"
+	tests := []struct {
+		entry   string
+		want    string
+		changed bool
+	}{
+		{"platform.foundation.cluster", "platform.foundation.k8s", true},
+		{"platform.foundation.cluster.control", "platform.foundation.k8s.control", true},
+		{"platform.foundation.clusters", "platform.foundation.clusters", false},
+		{"platform.foundation", "platform.foundation", false},
+		{"platform.foundation.cluster.*", "platform.foundation.k8s.*", true},
+		{"!platform.foundation.cluster", "!platform.foundation.k8s", true},
+		{"platform.foundation.* !platform.foundation.cluster.nodes", "platform.foundation.* !platform.foundation.k8s.nodes", true},
+		{"platform.foundation.* !platform.foundation.storage", "platform.foundation.* !platform.foundation.storage", false},
+		// Wildcards inside the renamed prefix can't be rewritten
+		{"platform.*.cluster", "platform.*.cluster", false},
+	}
+	for _, tt := range tests {
+		got, changed := RenameAllocation(tt.entry, oldPath, newPath)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("RenameAllocation(%q) = %q, %v; want %q, %v", tt.entry, got, changed, tt.want, tt.changed)
+		}
+	}
+}
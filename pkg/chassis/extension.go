@@ -0,0 +1,32 @@
+package chassis
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultExtensions are the file extensions of node files and playbooks,
+// in order of preference.
+var DefaultExtensions = []string{".yaml", ".yml"}
+
+// TrimExtension returns name without the first of exts it ends with, and
+// whether it had one.
+func TrimExtension(name string, exts []string) (string, bool) {
+	for _, ext := range exts {
+		if stem, ok := strings.CutSuffix(name, ext); ok && stem != "" {
+			return stem, true
+		}
+	}
+	return name, false
+}
+
+// FindFile returns stem with the first of exts naming an existing file, or
+// with the first of exts if none does, e.g. the path a new file is written to.
+func FindFile(stem string, exts []string) string {
+	for _, ext := range exts {
+		if _, err := os.Stat(stem + ext); err == nil {
+			return stem + ext
+		}
+	}
+	return stem + exts[0]
+}
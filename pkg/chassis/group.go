@@ -0,0 +1,12 @@
+package chassis
+
+import "strings"
+
+// groupNameReplacer maps characters that are invalid in Ansible group names.
+var groupNameReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// GroupName returns the Ansible group name derived from a chassis path,
+// e.g. platform.foundation.cluster-a → platform_foundation_cluster_a.
+func GroupName(chassisPath string) string {
+	return groupNameReplacer.Replace(chassisPath)
+}
@@ -0,0 +1,144 @@
+package chassis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limits bounds the size of a chassis tree. A zero field selects the
+// default limit, a negative field disables the check.
+type Limits struct {
+	MaxDepth     int `yaml:"max_depth"`      // maximum number of segments in a path
+	MaxPaths     int `yaml:"max_paths"`      // maximum number of paths in the tree
+	MaxChildren  int `yaml:"max_children"`   // maximum number of direct children of a path
+	MaxGroupName int `yaml:"max_group_name"` // maximum length of a derived Ansible group name
+}
+
+// DefaultLimits are generous bounds that only pathological trees exceed.
+var DefaultLimits = Limits{
+	MaxDepth:     12,
+	MaxPaths:     10000,
+	MaxChildren:  500,
+	MaxGroupName: 128,
+}
+
+// withDefaults fills zero fields from [DefaultLimits].
+func (l Limits) withDefaults() Limits {
+	if l.MaxDepth == 0 {
+		l.MaxDepth = DefaultLimits.MaxDepth
+	}
+	if l.MaxPaths == 0 {
+		l.MaxPaths = DefaultLimits.MaxPaths
+	}
+	if l.MaxChildren == 0 {
+		l.MaxChildren = DefaultLimits.MaxChildren
+	}
+	if l.MaxGroupName == 0 {
+		l.MaxGroupName = DefaultLimits.MaxGroupName
+	}
+	return l
+}
+
+// CheckAdd verifies that adding chassisPath (and any missing ancestors)
+// keeps the tree within limits.
+func (l Limits) CheckAdd(c *Chassis, chassisPath string) error {
+	l = l.withDefaults()
+
+	depth := strings.Count(chassisPath, ".") + 1
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("chassis path %q has depth %d, exceeding the limit of %d", chassisPath, depth, l.MaxDepth)
+	}
+
+	existing := make(map[string]bool)
+	for _, p := range c.Flatten() {
+		existing[p] = true
+	}
+
+	// Collect paths that would be created, root first
+	var created []string
+	for p := chassisPath; p != ""; p = Parent(p) {
+		if existing[p] {
+			break
+		}
+		created = append([]string{p}, created...)
+	}
+
+	if l.MaxPaths > 0 && len(existing)+len(created) > l.MaxPaths {
+		return fmt.Errorf("adding %q would grow the chassis to %d paths, exceeding the limit of %d",
+			chassisPath, len(existing)+len(created), l.MaxPaths)
+	}
+
+	if l.MaxChildren > 0 && len(created) > 0 {
+		parent := Parent(created[0])
+		var siblings int
+		if parent == "" {
+			for p := range existing {
+				if Parent(p) == "" {
+					siblings++
+				}
+			}
+		} else {
+			siblings = len(c.Children(parent))
+		}
+		if siblings+1 > l.MaxChildren {
+			return fmt.Errorf("adding %q would give %q %d children, exceeding the limit of %d",
+				chassisPath, parentLabel(parent), siblings+1, l.MaxChildren)
+		}
+	}
+
+	return nil
+}
+
+// Check verifies that the whole tree is within limits.
+func (l Limits) Check(c *Chassis) error {
+	l = l.withDefaults()
+
+	paths := c.Flatten()
+	if l.MaxPaths > 0 && len(paths) > l.MaxPaths {
+		return fmt.Errorf("chassis has %d paths, exceeding the limit of %d", len(paths), l.MaxPaths)
+	}
+
+	// Parents are kept in tree order, so the first one over the limit is reported
+	children := make(map[string]int)
+	var parents []string
+	for _, p := range paths {
+		if depth := strings.Count(p, ".") + 1; l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("chassis path %q has depth %d, exceeding the limit of %d", p, depth, l.MaxDepth)
+		}
+		parent := Parent(p)
+		if _, seen := children[parent]; !seen {
+			parents = append(parents, parent)
+		}
+		children[parent]++
+	}
+
+	if l.MaxChildren > 0 {
+		for _, parent := range parents {
+			if n := children[parent]; n > l.MaxChildren {
+				return fmt.Errorf("%q has %d children, exceeding the limit of %d", parentLabel(parent), n, l.MaxChildren)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckGroupName verifies that the group name derived from chassisPath
+// fits the group name budget.
+func (l Limits) CheckGroupName(chassisPath string) error {
+	l = l.withDefaults()
+	name := GroupName(chassisPath)
+	if l.MaxGroupName > 0 && len(name) > l.MaxGroupName {
+		return fmt.Errorf("group name %q derived from %q has %d characters, exceeding the limit of %d",
+			name, chassisPath, len(name), l.MaxGroupName)
+	}
+	return nil
+}
+
+// parentLabel names a parent path in messages, including the document root.
+func parentLabel(parent string) string {
+	if parent == "" {
+		return "<root>"
+	}
+	return parent
+}
@@ -0,0 +1,27 @@
+package chassis
+
+import "testing"
+
+func TestLimitsCheckReportsFirstParent(t *testing.T) {
+	c, err := Load(writeChassis(t, `platform:
+  foundation:
+    - a
+    - b
+    - c
+  interaction:
+    - x
+    - y
+    - z
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both layers exceed the limit; the one first in the tree is reported every time
+	want := `"platform.foundation" has 3 children, exceeding the limit of 2`
+	for i := 0; i < 20; i++ {
+		err := Limits{MaxChildren: 2}.Check(c)
+		if err == nil || err.Error() != want {
+			t.Fatalf("Check() = %v, want %s", err, want)
+		}
+	}
+}
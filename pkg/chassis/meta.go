@@ -0,0 +1,80 @@
+package chassis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MetaFile is the sidecar file holding per-path annotations next to chassis.yaml.
+const MetaFile = "chassis.meta.yaml"
+
+// Annotations are metadata attached to a chassis path.
+type Annotations struct {
+	// Template is the name of the template the path was instantiated from.
+	Template string `yaml:"template,omitempty"`
+	// TemplatePaths are the template's relative paths at instantiation time.
+	TemplatePaths []string `yaml:"template_paths,omitempty"`
+	// TemplateParams are the parameters the template was rendered with.
+	TemplateParams map[string]string `yaml:"template_params,omitempty"`
+	// Aggregate marks an intermediate path as an intended target of
+	// allocations and attachments under the leaf_only policy.
+	Aggregate bool `yaml:"aggregate,omitempty"`
+	// MaxNodes caps the nodes of a platform allocated to the path or below.
+	// Zero means no limit.
+	MaxNodes int `yaml:"max_nodes,omitempty"`
+	// MinNodes is the number of nodes of a platform the path or its
+	// descendants need for redundancy. Zero means no minimum.
+	MinNodes int `yaml:"min_nodes,omitempty"`
+}
+
+// IsZero reports whether no annotation is set.
+func (a Annotations) IsZero() bool {
+	return a.Template == "" && len(a.TemplatePaths) == 0 && len(a.TemplateParams) == 0 && !a.Aggregate && a.MaxNodes == 0 && a.MinNodes == 0
+}
+
+// Meta maps chassis paths to their annotations.
+type Meta map[string]Annotations
+
+// LoadMeta reads chassis.meta.yaml from the given directory.
+// A missing file yields empty metadata.
+func LoadMeta(dir string) (Meta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MetaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Meta{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", MetaFile, err)
+	}
+
+	meta := Meta{}
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", MetaFile, err)
+	}
+	return meta, nil
+}
+
+// Paths returns the annotated chassis paths in sorted order.
+func (m Meta) Paths() []string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Subtree returns the annotated paths equal to or below chassisPath, sorted.
+func (m Meta) Subtree(chassisPath string) []string {
+	var paths []string
+	for _, p := range m.Paths() {
+		if p == chassisPath || strings.HasPrefix(p, chassisPath+".") {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,66 @@
+package chassis
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// strayChars are trimmed from path segments on load.
+const strayChars = " \t'\""
+
+// Normalization records a path segment that was trimmed on load,
+// e.g. "control " declared as '- "control "'.
+type Normalization struct {
+	Line       int    `json:"line"`
+	Original   string `json:"original"`
+	Normalized string `json:"normalized"`
+}
+
+// Normalized returns the path segments trimmed of stray whitespace and
+// quotes when the chassis was loaded. Saving the chassis writes them back
+// normalized.
+func (c *Chassis) Normalized() []Normalization {
+	return c.normalized
+}
+
+// normalizeScalars trims stray whitespace and quotes from mapping keys and
+// scalar sequence items, so operator input matches declared segments.
+func normalizeScalars(node *yaml.Node) []Normalization {
+	var result []Normalization
+
+	normalize := func(n *yaml.Node) {
+		if n.Kind != yaml.ScalarNode || n.Tag == "!!null" {
+			return
+		}
+		trimmed := strings.Trim(n.Value, strayChars)
+		if trimmed == n.Value || trimmed == "" {
+			return
+		}
+		result = append(result, Normalization{Line: n.Line, Original: n.Value, Normalized: trimmed})
+		n.Value = trimmed
+		n.Style = 0
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			result = append(result, normalizeScalars(child)...)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			if child.Kind == yaml.ScalarNode {
+				normalize(child)
+				continue
+			}
+			result = append(result, normalizeScalars(child)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			normalize(node.Content[i])
+			result = append(result, normalizeScalars(node.Content[i+1])...)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,21 @@
+package chassis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultReservedNames are inventory names Ansible defines implicitly:
+// a chassis segment named like them breaks exported inventories.
+var DefaultReservedNames = []string{"all", "ungrouped", "localhost"}
+
+// CheckReserved verifies that no segment of chassisPath is a reserved name.
+func CheckReserved(chassisPath string, reserved []string) error {
+	for _, seg := range strings.Split(chassisPath, ".") {
+		if slices.Contains(reserved, seg) {
+			return fmt.Errorf("chassis path %q uses reserved inventory name %q as a segment", chassisPath, seg)
+		}
+	}
+	return nil
+}
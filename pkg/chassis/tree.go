@@ -0,0 +1,52 @@
+package chassis
+
+import "strings"
+
+// TreeNode is a chassis path in the hierarchy. Children keep the order
+// of chassis.yaml.
+type TreeNode struct {
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	Placeholder bool        `json:"placeholder,omitempty"` // declared as an empty collection
+	Children    []*TreeNode `json:"children"`
+}
+
+// Tree returns the root paths of the chassis with their descendants,
+// in chassis.yaml order.
+func (c *Chassis) Tree() []*TreeNode {
+	return c.TreeOf(c.Flatten())
+}
+
+// TreeOf nests chassis paths in the order given. Ancestors missing from
+// paths are added as intermediate nodes.
+func (c *Chassis) TreeOf(paths []string) []*TreeNode {
+	root := &TreeNode{}
+	for _, path := range paths {
+		current := root
+		for _, part := range strings.Split(path, ".") {
+			current = current.child(part, c)
+		}
+	}
+	return root.Children
+}
+
+// child returns the child named name, appending it if missing.
+func (n *TreeNode) child(name string, c *Chassis) *TreeNode {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	path := name
+	if n.Path != "" {
+		path = n.Path + "." + name
+	}
+	child := &TreeNode{
+		Name:        name,
+		Path:        path,
+		Placeholder: c.IsPlaceholder(path),
+		Children:    []*TreeNode{},
+	}
+	n.Children = append(n.Children, child)
+	return child
+}
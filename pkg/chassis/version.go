@@ -0,0 +1,168 @@
+package chassis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// chassis.yaml may start with a header naming its format:
+//
+//	apiVersion: chassis/v1
+//	kind: Chassis
+//	platform:
+//	  foundation: ...
+//
+// Files without a header are the legacy format, read as before.
+
+// Header keys of chassis.yaml.
+const (
+	HeaderAPIVersion = "apiVersion"
+	HeaderKind       = "kind"
+)
+
+// APIVersion is the current format of chassis.yaml, written by chassis:migrate.
+const APIVersion = "chassis/v1"
+
+// Kind is the kind declared in the header of chassis.yaml.
+const Kind = "Chassis"
+
+// FormatVersion is the numeric version of [APIVersion].
+const FormatVersion = 1
+
+// Header is the format header of chassis.yaml. Both fields are empty for
+// the legacy format.
+type Header struct {
+	APIVersion string
+	Kind       string
+}
+
+// formatSupport describes how Load handles a format version.
+type formatSupport struct {
+	// migrateTo is the version chassis:migrate upgrades to, "" if current.
+	migrateTo string
+	// migrate rewrites the document to migrateTo.
+	migrate func(c *Chassis)
+}
+
+// compatibility lists the format versions Load reads. Versions missing here,
+// e.g. written by newer releases, are refused instead of misread.
+var compatibility = map[string]formatSupport{
+	"": {
+		// Legacy files only lack the header
+		migrateTo: APIVersion,
+		migrate:   func(c *Chassis) { c.SetHeader(Header{APIVersion: APIVersion, Kind: Kind}) },
+	},
+	APIVersion: {},
+}
+
+// SupportedVersions returns the format versions Load reads, sorted.
+// The legacy format without header is not listed.
+func SupportedVersions() []string {
+	var versions []string
+	for v := range compatibility {
+		if v != "" {
+			versions = append(versions, v)
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// checkHeader refuses headers of unknown formats.
+func checkHeader(h Header) error {
+	if h.Kind != "" && h.Kind != Kind {
+		return fmt.Errorf("chassis.yaml declares kind %q, expected %q", h.Kind, Kind)
+	}
+	if _, ok := compatibility[h.APIVersion]; !ok {
+		return fmt.Errorf("chassis.yaml uses apiVersion %q, which this version of plasmactl-chassis can't read (supported: %s); upgrade the plugin",
+			h.APIVersion, strings.Join(SupportedVersions(), ", "))
+	}
+	return nil
+}
+
+// splitHeader removes the header keys from the root mapping of a document
+// and returns them with their key/value nodes.
+func splitHeader(doc *yaml.Node) (Header, []*yaml.Node) {
+	var h Header
+	if doc == nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return h, nil
+	}
+	root := doc.Content[0]
+	var header, rest []*yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case HeaderAPIVersion:
+			h.APIVersion = value.Value
+		case HeaderKind:
+			h.Kind = value.Value
+		default:
+			rest = append(rest, key, value)
+			continue
+		}
+		header = append(header, key, value)
+	}
+	root.Content = rest
+	return h, header
+}
+
+// Header returns the format header of chassis.yaml.
+func (c *Chassis) Header() Header {
+	return c.header
+}
+
+// SetHeader replaces the format header written with the document.
+func (c *Chassis) SetHeader(h Header) {
+	c.header = h
+	c.headerNodes = nil
+	for _, kv := range [][2]string{{HeaderAPIVersion, h.APIVersion}, {HeaderKind, h.Kind}} {
+		if kv[1] == "" {
+			continue
+		}
+		c.headerNodes = append(c.headerNodes,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kv[0]},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kv[1]})
+	}
+}
+
+// Document returns the YAML document to write, with the header in front of
+// the tree. The tree nodes are shared with the chassis.
+func (c *Chassis) Document() *yaml.Node {
+	if len(c.headerNodes) == 0 {
+		return c.node
+	}
+	doc := yaml.Node{Kind: yaml.DocumentNode}
+	if c.node != nil {
+		doc = *c.node
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	root := *doc.Content[0]
+	root.Content = append(append([]*yaml.Node(nil), c.headerNodes...), root.Content...)
+	doc.Content = append([]*yaml.Node{&root}, doc.Content[1:]...)
+	return &doc
+}
+
+// NeedsMigration reports whether chassis.yaml uses an older format than [APIVersion].
+func (c *Chassis) NeedsMigration() bool {
+	return compatibility[c.header.APIVersion].migrateTo != ""
+}
+
+// Migrate upgrades the document to the current format and returns the
+// versions it passed through, starting with the original one. The legacy
+// format is reported as "".
+func (c *Chassis) Migrate() []string {
+	versions := []string{c.header.APIVersion}
+	for {
+		support := compatibility[c.header.APIVersion]
+		if support.migrateTo == "" {
+			return versions
+		}
+		support.migrate(c)
+		versions = append(versions, c.header.APIVersion)
+	}
+}
@@ -0,0 +1,252 @@
+package chassis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of repository file an event is about.
+type EventKind string
+
+// Event kinds.
+const (
+	EventChassis  EventKind = "chassis"  // chassis.yaml or chassis.meta.yaml
+	EventNode     EventKind = "node"     // inst/<platform>/nodes/<hostname>.yaml in the default layout
+	EventPlaybook EventKind = "playbook" // src/<layer>/<layer>.yaml in the default layout
+)
+
+// Directories of the default repository layout, relative to its root.
+const (
+	// DefaultNodesGlob matches the directory of node files of every platform.
+	DefaultNodesGlob = "inst/*/nodes"
+	// DefaultPlaybooksDir holds a directory per layer with its playbook.
+	DefaultPlaybooksDir = "src"
+)
+
+// WatchLayout locates the node files and playbooks a [Watcher] polls, for
+// repositories that don't use the default layout. Zero fields use the
+// defaults.
+type WatchLayout struct {
+	// Nodes is a slash-separated glob, relative to the watched directory,
+	// matching the directories of node files. Defaults to [DefaultNodesGlob].
+	Nodes string
+	// Playbooks is the directory holding <layer>/<layer>.yaml, relative to
+	// the watched directory. Defaults to [DefaultPlaybooksDir].
+	Playbooks string
+	// Extensions are the accepted extensions of node files and playbooks.
+	// Defaults to [DefaultExtensions].
+	Extensions []string
+}
+
+// withDefaults returns l with zero fields set to their defaults.
+func (l WatchLayout) withDefaults() WatchLayout {
+	if l.Nodes == "" {
+		l.Nodes = DefaultNodesGlob
+	}
+	if l.Playbooks == "" {
+		l.Playbooks = DefaultPlaybooksDir
+	}
+	if len(l.Extensions) == 0 {
+		l.Extensions = DefaultExtensions
+	}
+	return l
+}
+
+// EventOp is the modification an event reports.
+type EventOp string
+
+// Event operations.
+const (
+	OpCreated  EventOp = "created"
+	OpModified EventOp = "modified"
+	OpRemoved  EventOp = "removed"
+)
+
+// Event is a change of a chassis, node or playbook file.
+type Event struct {
+	Kind EventKind `json:"kind"`
+	Op   EventOp   `json:"op"`
+	Path string    `json:"path"` // relative to the watched directory
+	Time time.Time `json:"time"`
+}
+
+// fileState identifies a version of a watched file.
+type fileState struct {
+	kind    EventKind
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls a repository for changes of chassis, node and playbook files
+// and notifies subscribers, so long-running companions can react without
+// reloading the repository on their own schedule.
+//
+//	w := chassis.NewWatcher(dir, 2*time.Second, chassis.WatchLayout{})
+//	events, unsubscribe := w.Subscribe(16)
+//	defer unsubscribe()
+//	go w.Run(ctx)
+//	for e := range events { ... }
+type Watcher struct {
+	dir      string
+	interval time.Duration
+	layout   WatchLayout
+
+	mu     sync.Mutex
+	subs   map[int]chan Event
+	nextID int
+	closed bool
+}
+
+// NewWatcher returns a watcher polling the files of layout below dir every
+// interval.
+func NewWatcher(dir string, interval time.Duration, layout WatchLayout) *Watcher {
+	return &Watcher{dir: dir, interval: interval, layout: layout.withDefaults(), subs: make(map[int]chan Event)}
+}
+
+// Subscribe returns a channel receiving events, buffered to hold buffer
+// events, and a function ending the subscription. Events that don't fit a
+// full buffer are dropped for that subscriber, so a slow subscriber never
+// blocks others. The channel is closed when the subscription ends or the
+// watcher stops.
+func (w *Watcher) Subscribe(buffer int) (<-chan Event, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan Event, buffer)
+	if w.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if sub, ok := w.subs[id]; ok {
+				delete(w.subs, id)
+				close(sub)
+			}
+		})
+	}
+}
+
+// Run polls until ctx is done, then closes all subscriptions.
+// Files present at the start are the baseline and produce no events.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.close()
+
+	prev, err := scanWatched(w.dir, w.layout)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			cur, err := scanWatched(w.dir, w.layout)
+			if err != nil {
+				// Transient errors, e.g. a checkout in progress; retry on the next tick
+				continue
+			}
+			for _, e := range diffStates(prev, cur, now) {
+				w.publish(e)
+			}
+			prev = cur
+		}
+	}
+}
+
+// publish sends an event to every subscriber with buffer space left.
+func (w *Watcher) publish(e Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// close ends all subscriptions.
+func (w *Watcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	for id, ch := range w.subs {
+		delete(w.subs, id)
+		close(ch)
+	}
+}
+
+// scanWatched returns the state of every file of layout below dir,
+// keyed by the path relative to dir.
+func scanWatched(dir string, layout WatchLayout) (map[string]fileState, error) {
+	states := make(map[string]fileState)
+	add := func(rel string, kind EventKind) {
+		info, err := os.Stat(filepath.Join(dir, rel))
+		if err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		states[rel] = fileState{kind: kind, modTime: info.ModTime(), size: info.Size()}
+	}
+
+	add("chassis.yaml", EventChassis)
+	add("chassis.meta.yaml", EventChassis)
+
+	nodeFiles, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(layout.Nodes), "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range nodeFiles {
+		if _, ok := TrimExtension(filepath.Base(f), layout.Extensions); ok {
+			rel, _ := filepath.Rel(dir, f)
+			add(rel, EventNode)
+		}
+	}
+
+	playbooksDir := filepath.FromSlash(layout.Playbooks)
+	layers, err := Dirs(filepath.Join(dir, playbooksDir), nil)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, l := range layers {
+		for _, ext := range layout.Extensions {
+			add(filepath.Join(playbooksDir, l, l+ext), EventPlaybook)
+		}
+	}
+	return states, nil
+}
+
+// diffStates returns the events turning prev into cur, sorted by path.
+func diffStates(prev, cur map[string]fileState, now time.Time) []Event {
+	var events []Event
+	for rel, s := range cur {
+		p, ok := prev[rel]
+		switch {
+		case !ok:
+			events = append(events, Event{Kind: s.kind, Op: OpCreated, Path: rel, Time: now})
+		case !p.modTime.Equal(s.modTime) || p.size != s.size:
+			events = append(events, Event{Kind: s.kind, Op: OpModified, Path: rel, Time: now})
+		}
+	}
+	for rel, p := range prev {
+		if _, ok := cur[rel]; !ok {
+			events = append(events, Event{Kind: p.kind, Op: OpRemoved, Path: rel, Time: now})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
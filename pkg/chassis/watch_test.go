@@ -0,0 +1,64 @@
+package chassis
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestScanWatchedLayout(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"chassis.yaml",
+		"inst/prod/nodes/prod-1.yaml",
+		"src/foundation/foundation.yaml",
+		"environments/prod/hosts/prod-2.yml",
+		"environments/prod/hosts/notes.txt",
+		"layers/interaction/interaction.yml",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		layout WatchLayout
+		want   map[string]EventKind
+	}{
+		{"default", WatchLayout{}, map[string]EventKind{
+			"chassis.yaml":                   EventChassis,
+			"inst/prod/nodes/prod-1.yaml":    EventNode,
+			"src/foundation/foundation.yaml": EventPlaybook,
+		}},
+		{"custom", WatchLayout{Nodes: "environments/*/hosts", Playbooks: "layers"}, map[string]EventKind{
+			"chassis.yaml":                       EventChassis,
+			"environments/prod/hosts/prod-2.yml": EventNode,
+			"layers/interaction/interaction.yml": EventPlaybook,
+		}},
+		{"extensions", WatchLayout{Extensions: []string{".yml"}}, map[string]EventKind{
+			"chassis.yaml": EventChassis,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states, err := scanWatched(dir, tt.layout.withDefaults())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]EventKind, len(states))
+			for rel, s := range states {
+				got[filepath.ToSlash(rel)] = s.kind
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("scanWatched() = %v, want %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(tt.want)))
+			}
+		})
+	}
+}
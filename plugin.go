@@ -4,16 +4,62 @@ package plasmactlchassis
 import (
 	"context"
 	"embed"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"strings"
 
 	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
+	"gopkg.in/yaml.v3"
 
 	"github.com/plasmash/plasmactl-chassis/actions/add"
+	"github.com/plasmash/plasmactl-chassis/actions/adopt"
+	"github.com/plasmash/plasmactl-chassis/actions/allocate"
+	"github.com/plasmash/plasmactl-chassis/actions/attach"
+	"github.com/plasmash/plasmactl-chassis/actions/auditcompare"
+	"github.com/plasmash/plasmactl-chassis/actions/balance"
+	"github.com/plasmash/plasmactl-chassis/actions/bootstrap"
+	"github.com/plasmash/plasmactl-chassis/actions/capabilities"
+	"github.com/plasmash/plasmactl-chassis/actions/children"
+	"github.com/plasmash/plasmactl-chassis/actions/compare"
+	"github.com/plasmash/plasmactl-chassis/actions/components"
+	"github.com/plasmash/plasmactl-chassis/actions/deallocate"
+	"github.com/plasmash/plasmactl-chassis/actions/detach"
+	"github.com/plasmash/plasmactl-chassis/actions/diff"
+	"github.com/plasmash/plasmactl-chassis/actions/exists"
+	"github.com/plasmash/plasmactl-chassis/actions/explain"
+	"github.com/plasmash/plasmactl-chassis/actions/export"
+	"github.com/plasmash/plasmactl-chassis/actions/gc"
+	"github.com/plasmash/plasmactl-chassis/actions/grep"
+	"github.com/plasmash/plasmactl-chassis/actions/impact"
+	"github.com/plasmash/plasmactl-chassis/actions/importer"
+	"github.com/plasmash/plasmactl-chassis/actions/instantiate"
+	"github.com/plasmash/plasmactl-chassis/actions/lint"
 	"github.com/plasmash/plasmactl-chassis/actions/list"
+	"github.com/plasmash/plasmactl-chassis/actions/migrate"
+	"github.com/plasmash/plasmactl-chassis/actions/migratelayout"
+	"github.com/plasmash/plasmactl-chassis/actions/move"
+	"github.com/plasmash/plasmactl-chassis/actions/nodes"
+	"github.com/plasmash/plasmactl-chassis/actions/overview"
+	"github.com/plasmash/plasmactl-chassis/actions/parent"
+	"github.com/plasmash/plasmactl-chassis/actions/policy"
 	"github.com/plasmash/plasmactl-chassis/actions/query"
 	"github.com/plasmash/plasmactl-chassis/actions/remove"
 	"github.com/plasmash/plasmactl-chassis/actions/rename"
+	"github.com/plasmash/plasmactl-chassis/actions/reorder"
+	"github.com/plasmash/plasmactl-chassis/actions/restore"
 	"github.com/plasmash/plasmactl-chassis/actions/show"
+	"github.com/plasmash/plasmactl-chassis/actions/split"
+	"github.com/plasmash/plasmactl-chassis/actions/templateupgrade"
+	"github.com/plasmash/plasmactl-chassis/actions/validate"
+	"github.com/plasmash/plasmactl-chassis/actions/verifynodes"
+	"github.com/plasmash/plasmactl-chassis/actions/visualize"
+	"github.com/plasmash/plasmactl-chassis/internal/chassis"
+	"github.com/plasmash/plasmactl-chassis/internal/cli"
+	"github.com/plasmash/plasmactl-chassis/internal/message"
 )
 
 //go:embed actions/*/*.yaml
@@ -25,7 +71,8 @@ func init() {
 
 // Plugin is [launchr.Plugin] plugin providing chassis management functionality.
 type Plugin struct {
-	cfg launchr.Config
+	cfg      launchr.Config
+	settings chassis.Config
 }
 
 // PluginInfo implements [launchr.Plugin] interface.
@@ -38,6 +85,38 @@ func (p *Plugin) PluginInfo() launchr.PluginInfo {
 // OnAppInit implements [launchr.Plugin] interface.
 func (p *Plugin) OnAppInit(app launchr.App) error {
 	app.Services().Get(&p.cfg)
+	if err := p.cfg.Get(chassis.ConfigKey, &p.settings); err != nil {
+		return fmt.Errorf("failed to read %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Layout.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Policy.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Display.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Telemetry.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Facts.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	if err := p.settings.Distribution.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", chassis.ConfigKey, err)
+	}
+	chassis.SetLayout(p.settings.Layout)
+	chassis.SetLockConfig(p.settings.Lock)
+	chassis.SetReservedNames(p.settings.ReservedNames)
+	chassis.SetBackupConfig(p.settings.Backup)
+	chassis.SetDisplayConfig(p.settings.Display)
+	chassis.SetTelemetryConfig(p.settings.Telemetry)
+	chassis.SetRetryConfig(p.settings.Retry)
+	chassis.SetStrictByDefault(p.settings.Strict)
 	return nil
 }
 
@@ -49,21 +128,259 @@ type actionRunner interface {
 	Result() any
 }
 
+// dryRunAware is implemented by mutating actions supporting the global --dry-run option.
+type dryRunAware interface {
+	SetDryRun(bool)
+}
+
+// mutator is implemented by actions modifying the repository only on request,
+// e.g. with --apply or --fix. Actions supporting --dry-run mutate unless it is set.
+type mutator interface {
+	Mutates() bool
+}
+
+// messageAware is implemented by actions reporting catalog messages.
+type messageAware interface {
+	SetMessageTerm(*launchr.Terminal)
+	Messages() []message.Message
+}
+
+// messageLog is implemented by results embedding [message.Log].
+type messageLog interface {
+	SetMessages([]message.Message)
+}
+
+// strictAware is implemented by actions supporting the global --strict option.
+type strictAware interface {
+	SetStrict(bool)
+	Degraded() error
+}
+
+// traceAware is implemented by actions reporting phase timings for the global --trace option.
+type traceAware interface {
+	SetTracer(*cli.Tracer)
+}
+
+// Global options appended to action definitions at discovery time.
+const (
+	// optTrace is registered on every action.
+	optTrace = `
+name: trace
+title: Trace
+description: Log every file considered or skipped and timing per phase
+type: boolean
+default: false
+`
+	// optSummary is registered on every action.
+	optSummary = `
+name: summary
+title: Summary
+description: Print elapsed time, files read and written, and playbook cache hit rate when done
+type: boolean
+default: false
+`
+	// optStrict is registered on every action.
+	optStrict = `
+name: strict
+title: Strict
+description: Fail on soft failures, such as unreadable node or playbook files and partial updates, and on warnings
+type: boolean
+default: false
+`
+	// optDryRun is registered on every mutating action.
+	optDryRun = `
+name: dry-run
+title: Dry Run
+description: Show what would change without modifying files
+type: boolean
+default: false
+`
+	// optBackup is registered on every mutating action whose changes chassis:restore can revert.
+	optBackup = `
+name: backup
+title: Backup
+description: Copy every file to .plasmactl/backups/<timestamp> before modifying it, for chassis:restore
+type: boolean
+default: false
+`
+)
+
+// modulePath is the Go module path of the plugin.
+const modulePath = "github.com/plasmash/plasmactl-chassis"
+
+// definitions holds the final YAML of every created action, for chassis:capabilities.
+var definitions = make(map[string][]byte)
+
 // createAction builds a launchr action from YAML and a factory function.
-func createAction(yamlFile, name string, factory func(*action.Input) actionRunner) *action.Action {
+// Global option definitions are appended to the options declared in YAML.
+func createAction(yamlFile, name string, factory func(*action.Input) actionRunner, globals ...string) *action.Action {
 	data, _ := actionYamlFS.ReadFile(yamlFile)
+	data, err := withGlobalOptions(data, append([]string{optTrace, optSummary, optStrict}, globals...)...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid global options for %s: %s", name, err))
+	}
+	definitions[name] = data
 	act := action.NewFromYAML(name, data)
 	act.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
 		log, term := getLogger(a)
-		runner := factory(a.Input())
+		input := a.Input()
+		runner := factory(input)
 		runner.SetLogger(log)
 		runner.SetTerm(term)
+		msgs, reportsMessages := runner.(messageAware)
+		if reportsMessages {
+			msgs.SetMessageTerm(term)
+		}
+		strict, strictRunner := runner.(strictAware)
+		if strictRunner {
+			strict.SetStrict(optBool(input, "strict") || chassis.StrictByDefault())
+		}
+		mutates := false
+		if r, ok := runner.(dryRunAware); ok {
+			r.SetDryRun(optBool(input, "dry-run"))
+			mutates = !optBool(input, "dry-run")
+		}
+		if r, ok := runner.(mutator); ok {
+			mutates = r.Mutates()
+		}
+		// Mutations are serialized per repository
+		var runErr error
+		if mutates {
+			release, err := chassis.AcquireLock(optString(input, "dir"), name)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			// Only mutations write the index, once done and still locked
+			defer func() {
+				if runErr != nil {
+					return
+				}
+				if err := chassis.RefreshIndex(optString(input, "dir")); err != nil {
+					log.Debug("failed to refresh the index", "error", err)
+				}
+			}()
+		}
+		backingUp := mutates && slices.Contains(globals, optBackup) &&
+			(optBool(input, "backup") || chassis.BackupByDefault())
+		if backingUp {
+			if err := chassis.BeginBackup(optString(input, "dir"), name); err != nil {
+				runErr = err // nothing changed, so the index isn't refreshed
+				return nil, err
+			}
+		}
+		var tracers []chassis.Tracer
+		if optBool(input, "summary") {
+			summary := cli.NewSummary()
+			tracers = append(tracers, summary)
+			defer summary.Print(term)
+		}
+		if optBool(input, "trace") {
+			tracer := cli.NewTracer(term)
+			tracers = append(tracers, tracer)
+			defer tracer.Done()
+			if r, ok := runner.(traceAware); ok {
+				r.SetTracer(tracer)
+			}
+		}
+		// Telemetry is recorded once the tracer is removed, so scanning the
+		// repository scale isn't traced or counted
+		if telemetry := chassis.StartTelemetry(optString(input, "dir"), name, optionNames(input)); telemetry != nil {
+			tracers = append(tracers, telemetry)
+			defer func() {
+				if err := telemetry.Finish(runErr); err != nil {
+					log.Debug("failed to record telemetry", "error", err)
+				}
+			}()
+		}
+		chassis.SetTracer(chassis.MultiTracer(tracers...))
+		defer chassis.SetTracer(nil)
 		err := runner.Execute()
-		return runner.Result(), err
+		if err == nil && strictRunner {
+			err = strict.Degraded()
+		}
+		if backingUp {
+			set, backupErr := chassis.EndBackup()
+			switch {
+			case backupErr != nil:
+				err = errors.Join(err, backupErr)
+			case set != nil:
+				term.Info().Printfln("Backed up %d file(s) to %s/%s, revert with chassis:restore", len(set.Entries), chassis.BackupDir, set.Name)
+			}
+		}
+		result := runner.Result()
+		if l, ok := result.(messageLog); ok && reportsMessages && !reflect.ValueOf(result).IsNil() {
+			l.SetMessages(msgs.Messages())
+		}
+		cli.NormalizeResult(result)
+		runErr = err
+		return result, err
 	}))
 	return act
 }
 
+// withGlobalOptions appends option definitions to action.options of an action YAML,
+// skipping options the action already declares itself.
+func withGlobalOptions(data []byte, globals ...string) ([]byte, error) {
+	if len(globals) == 0 {
+		return data, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("action definition is not a mapping")
+	}
+
+	actionNode := mappingValue(doc.Content[0], "action")
+	if actionNode == nil || actionNode.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("action definition has no action section")
+	}
+
+	optionsNode := mappingValue(actionNode, "options")
+	if optionsNode == nil {
+		optionsNode = &yaml.Node{Kind: yaml.SequenceNode}
+		actionNode.Content = append(actionNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "options"}, optionsNode)
+	}
+
+	declared := make(map[string]bool)
+	for _, opt := range optionsNode.Content {
+		if n := mappingValue(opt, "name"); n != nil {
+			declared[n.Value] = true
+		}
+	}
+
+	for _, g := range globals {
+		var optDoc yaml.Node
+		if err := yaml.Unmarshal([]byte(g), &optDoc); err != nil {
+			return nil, err
+		}
+		opt := optDoc.Content[0]
+		if n := mappingValue(opt, "name"); n != nil && declared[n.Value] {
+			continue
+		}
+		optionsNode.Content = append(optionsNode.Content, opt)
+	}
+
+	return yaml.Marshal(&doc)
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // optString returns a string option value or empty string if nil.
 func optString(input *action.Input, name string) string {
 	if v := input.Opt(name); v != nil {
@@ -72,6 +389,27 @@ func optString(input *action.Input, name string) string {
 	return ""
 }
 
+// optList returns the non-empty items of a comma-separated string option.
+func optList(input *action.Input, name string) []string {
+	var items []string
+	for _, item := range strings.Split(optString(input, name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// optionNames returns the sorted names of the options set by the user.
+func optionNames(input *action.Input) []string {
+	names := make([]string, 0, len(input.OptsChanged()))
+	for name := range input.OptsChanged() {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 // optBool returns a bool option value or false if nil.
 func optBool(input *action.Input, name string) bool {
 	if v := input.Opt(name); v != nil {
@@ -80,6 +418,14 @@ func optBool(input *action.Input, name string) bool {
 	return false
 }
 
+// optInt returns an integer option value or 0 if nil.
+func optInt(input *action.Input, name string) int {
+	if v := input.Opt(name); v != nil {
+		return v.(int)
+	}
+	return 0
+}
+
 // argString returns a string argument value or empty string if nil.
 func argString(input *action.Input, name string) string {
 	if v := input.Arg(name); v != nil {
@@ -93,17 +439,63 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 	return []*action.Action{
 		createAction("actions/list/list.yaml", "chassis:list", func(input *action.Input) actionRunner {
 			return &list.List{
-				Dir:     optString(input, "dir"),
-				Chassis: argString(input, "chassis"),
-				Tree:    optBool(input, "tree"),
+				Dir:          optString(input, "dir"),
+				Chassis:      argString(input, "chassis"),
+				Tree:         optBool(input, "tree"),
+				Format:       optString(input, "format"),
+				ChangedSince: optString(input, "changed-since"),
+				Match:        optString(input, "match"),
+				Exclude:      optString(input, "exclude"),
+				Distribution: p.settings.Distribution,
 			}
 		}),
 		createAction("actions/show/show.yaml", "chassis:show", func(input *action.Input) actionRunner {
 			return &show.Show{
-				Dir:      optString(input, "dir"),
-				Chassis:  argString(input, "chassis"),
-				Platform: optString(input, "platform"),
-				Kind:     optString(input, "kind"),
+				Dir:          optString(input, "dir"),
+				Chassis:      argString(input, "chassis"),
+				Platform:     optString(input, "platform"),
+				Kind:         optString(input, "kind"),
+				Format:       optString(input, "format"),
+				Facts:        optBool(input, "facts"),
+				Match:        optString(input, "match"),
+				Exclude:      optString(input, "exclude"),
+				Distribution: p.settings.Distribution,
+				FactsSource:  p.settings.Facts,
+			}
+		}),
+		createAction("actions/exists/exists.yaml", "chassis:exists", func(input *action.Input) actionRunner {
+			return &exists.Exists{
+				Dir:     optString(input, "dir"),
+				Chassis: argString(input, "chassis"),
+			}
+		}),
+		createAction("actions/parent/parent.yaml", "chassis:parent", func(input *action.Input) actionRunner {
+			return &parent.Parent{
+				Dir:     optString(input, "dir"),
+				Chassis: argString(input, "chassis"),
+			}
+		}),
+		createAction("actions/children/children.yaml", "chassis:children", func(input *action.Input) actionRunner {
+			return &children.Children{
+				Dir:       optString(input, "dir"),
+				Chassis:   argString(input, "chassis"),
+				Recursive: optBool(input, "recursive"),
+			}
+		}),
+		createAction("actions/nodes/nodes.yaml", "chassis:nodes", func(input *action.Input) actionRunner {
+			return &nodes.Nodes{
+				Dir:          optString(input, "dir"),
+				Chassis:      argString(input, "chassis"),
+				Platform:     optString(input, "platform"),
+				Format:       optString(input, "format"),
+				Distribution: p.settings.Distribution,
+			}
+		}),
+		createAction("actions/components/components.yaml", "chassis:components", func(input *action.Input) actionRunner {
+			return &components.Components{
+				Dir:     optString(input, "dir"),
+				Chassis: argString(input, "chassis"),
+				Name:    optString(input, "name"),
 			}
 		}),
 		createAction("actions/add/add.yaml", "chassis:add", func(input *action.Input) actionRunner {
@@ -111,28 +503,292 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 				Dir:     optString(input, "dir"),
 				Chassis: input.Arg("chassis").(string),
 				Force:   optBool(input, "force"),
+				After:   optString(input, "after"),
+				Before:  optString(input, "before"),
+				Limits:  p.settings.Limits,
 			}
-		}),
+		}, optDryRun, optBackup),
 		createAction("actions/remove/remove.yaml", "chassis:remove", func(input *action.Input) actionRunner {
 			return &remove.Remove{
+				Dir:          optString(input, "dir"),
+				Chassis:      input.Arg("chassis").(string),
+				Distribution: p.settings.Distribution,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/reorder/reorder.yaml", "chassis:reorder", func(input *action.Input) actionRunner {
+			return &reorder.Reorder{
+				Dir:    optString(input, "dir"),
+				Parent: input.Arg("parent").(string),
+				Order:  optList(input, "order"),
+				Alpha:  optBool(input, "alpha"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/split/split.yaml", "chassis:split", func(input *action.Input) actionRunner {
+			return &split.Split{
 				Dir:     optString(input, "dir"),
 				Chassis: input.Arg("chassis").(string),
-				DryRun:  optBool(input, "dry-run"),
+				Into:    optList(input, "into"),
+				Assign:  optString(input, "assign"),
+				Limits:  p.settings.Limits,
 			}
-		}),
+		}, optDryRun, optBackup),
 		createAction("actions/rename/rename.yaml", "chassis:rename", func(input *action.Input) actionRunner {
 			return &rename.Rename{
-				Dir:    optString(input, "dir"),
-				Old:    input.Arg("old").(string),
-				New:    input.Arg("new").(string),
-				DryRun: optBool(input, "dry-run"),
+				Dir:         optString(input, "dir"),
+				Old:         input.Arg("old").(string),
+				New:         input.Arg("new").(string),
+				Deep:        optBool(input, "deep"),
+				Merge:       optBool(input, "merge"),
+				RenameFiles: optBool(input, "rename-files"),
+				GitMv:       optBool(input, "git-mv"),
+
+				Distribution: p.settings.Distribution,
 			}
-		}),
+		}, optDryRun, optBackup),
+		createAction("actions/move/move.yaml", "chassis:move", func(input *action.Input) actionRunner {
+			return &move.Move{
+				Dir:       optString(input, "dir"),
+				Chassis:   input.Arg("chassis").(string),
+				NewParent: input.Arg("new-parent").(string),
+
+				RenameFiles: optBool(input, "rename-files"),
+				GitMv:       optBool(input, "git-mv"),
+
+				Limits: p.settings.Limits,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/allocate/allocate.yaml", "chassis:allocate", func(input *action.Input) actionRunner {
+			return &allocate.Allocate{
+				Dir:     optString(input, "dir"),
+				Node:    input.Arg("node").(string),
+				Chassis: input.Arg("chassis").(string),
+				Policy:  p.settings.Policy,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/deallocate/deallocate.yaml", "chassis:deallocate", func(input *action.Input) actionRunner {
+			return &deallocate.Deallocate{
+				Dir:     optString(input, "dir"),
+				Node:    input.Arg("node").(string),
+				Chassis: input.Arg("chassis").(string),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/attach/attach.yaml", "chassis:attach", func(input *action.Input) actionRunner {
+			return &attach.Attach{
+				Dir:       optString(input, "dir"),
+				Component: input.Arg("component").(string),
+				Chassis:   input.Arg("chassis").(string),
+				Version:   optString(input, "constraint"),
+				Policy:    p.settings.Policy,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/detach/detach.yaml", "chassis:detach", func(input *action.Input) actionRunner {
+			return &detach.Detach{
+				Dir:         optString(input, "dir"),
+				Component:   input.Arg("component").(string),
+				Chassis:     input.Arg("chassis").(string),
+				RemoveEmpty: optBool(input, "remove-empty"),
+			}
+		}, optDryRun, optBackup),
 		createAction("actions/query/query.yaml", "chassis:query", func(input *action.Input) actionRunner {
 			return &query.Query{
+				Dir:          optString(input, "dir"),
+				Identifier:   input.Arg("identifier").(string),
+				Kind:         optString(input, "kind"),
+				All:          optBool(input, "all"),
+				Distribution: p.settings.Distribution,
+			}
+		}),
+		createAction("actions/grep/grep.yaml", "chassis:grep", func(input *action.Input) actionRunner {
+			return &grep.Grep{
+				Dir:     optString(input, "dir"),
+				Pattern: input.Arg("pattern").(string),
+			}
+		}),
+		createAction("actions/impact/impact.yaml", "chassis:impact", func(input *action.Input) actionRunner {
+			return &impact.Impact{
+				Dir:          optString(input, "dir"),
+				Target:       input.Arg("target").(string),
+				Kind:         optString(input, "kind"),
+				Distribution: p.settings.Distribution,
+			}
+		}),
+		createAction("actions/overview/overview.yaml", "chassis:overview", func(input *action.Input) actionRunner {
+			return &overview.Overview{
+				Dir:    optString(input, "dir"),
+				Config: p.settings,
+			}
+		}),
+		createAction("actions/compare/compare.yaml", "chassis:compare", func(input *action.Input) actionRunner {
+			return &compare.Compare{
+				Dir:   optString(input, "dir"),
+				Other: optString(input, "other"),
+			}
+		}),
+		createAction("actions/diff/diff.yaml", "chassis:diff", func(input *action.Input) actionRunner {
+			return &diff.Diff{
+				Dir:          optString(input, "dir"),
+				From:         input.Arg("from").(string),
+				To:           argString(input, "to"),
+				Distribution: p.settings.Distribution,
+			}
+		}),
+		createAction("actions/auditcompare/auditcompare.yaml", "chassis:audit-compare", func(input *action.Input) actionRunner {
+			return &auditcompare.AuditCompare{
+				Dir:          optString(input, "dir"),
+				File:         input.Arg("file").(string),
+				Format:       optString(input, "format"),
+				Platform:     optString(input, "platform"),
+				AllGroups:    optBool(input, "all-groups"),
+				Distribution: p.settings.Distribution,
+			}
+		}),
+		createAction("actions/instantiate/instantiate.yaml", "chassis:instantiate", func(input *action.Input) actionRunner {
+			return &instantiate.Instantiate{
+				Dir:      optString(input, "dir"),
+				Template: input.Arg("template").(string),
+				Chassis:  input.Arg("chassis").(string),
+				Params:   action.InputOptSlice[string](input, "param"),
+				Config:   p.settings,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/templateupgrade/templateupgrade.yaml", "chassis:template-upgrade", func(input *action.Input) actionRunner {
+			return &templateupgrade.TemplateUpgrade{
+				Dir:      optString(input, "dir"),
+				Template: input.Arg("template").(string),
+				Chassis:  optString(input, "chassis"),
+				Config:   p.settings,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/validate/validate.yaml", "chassis:validate", func(input *action.Input) actionRunner {
+			return &validate.Validate{
+				Dir:    optString(input, "dir"),
+				Rules:  action.InputOptSlice[string](input, "rule"),
+				Config: p.settings,
+			}
+		}),
+		createAction("actions/lint/lint.yaml", "chassis:lint", func(input *action.Input) actionRunner {
+			return &lint.Lint{
+				Dir:    optString(input, "dir"),
+				Rules:  optList(input, "rules"),
+				Fix:    optBool(input, "fix"),
+				Config: p.settings,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/policy/policy.yaml", "chassis:policy", func(input *action.Input) actionRunner {
+			return &policy.Policy{
+				Dir:     optString(input, "dir"),
+				Command: argString(input, "command"),
+				Key:     argString(input, "key"),
+				Value:   argString(input, "value"),
+				Config:  p.settings,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/verifynodes/verifynodes.yaml", "chassis:verify-nodes", func(input *action.Input) actionRunner {
+			return &verifynodes.VerifyNodes{
+				Dir:      optString(input, "dir"),
+				Platform: optString(input, "platform"),
+				Fix:      optBool(input, "fix"),
+				Default:  optString(input, "default"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/export/export.yaml", "chassis:export", func(input *action.Input) actionRunner {
+			return &export.Export{
+				Dir:      optString(input, "dir"),
+				Format:   optString(input, "format"),
+				Output:   optString(input, "output"),
+				Platform: optString(input, "platform"),
+				Checksum: optBool(input, "checksum"),
+				Sign:     optBool(input, "sign"),
+				Match:    optString(input, "match"),
+				Exclude:  optString(input, "exclude"),
+				Config:   p.settings,
+
+				ExcludeQuarantined: optBool(input, "exclude-quarantined"),
+				Obfuscate:          optBool(input, "obfuscate"),
+				ObfuscatePaths:     optBool(input, "obfuscate-paths"),
+			}
+		}),
+		createAction("actions/visualize/visualize.yaml", "chassis:visualize", func(input *action.Input) actionRunner {
+			return &visualize.Visualize{
+				Dir:      optString(input, "dir"),
+				Output:   optString(input, "output"),
+				Platform: optString(input, "platform"),
+				Title:    optString(input, "title"),
+				Config:   p.settings,
+			}
+		}),
+		createAction("actions/importer/importer.yaml", "chassis:import", func(input *action.Input) actionRunner {
+			return &importer.Import{
+				Dir:              optString(input, "dir"),
+				File:             input.Arg("file").(string),
+				Format:           optString(input, "format"),
+				RequireSignature: optBool(input, "require-signature"),
+				Config:           p.settings,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/gc/gc.yaml", "chassis:gc", func(input *action.Input) actionRunner {
+			return &gc.GC{
 				Dir:        optString(input, "dir"),
-				Identifier: input.Arg("identifier").(string),
-				Kind:       optString(input, "kind"),
+				Aggressive: optBool(input, "aggressive"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/restore/restore.yaml", "chassis:restore", func(input *action.Input) actionRunner {
+			return &restore.Restore{
+				Dir:  optString(input, "dir"),
+				Set:  argString(input, "set"),
+				List: optBool(input, "list"),
+			}
+		}, optDryRun),
+		createAction("actions/balance/balance.yaml", "chassis:balance", func(input *action.Input) actionRunner {
+			return &balance.Balance{
+				Dir:      optString(input, "dir"),
+				Chassis:  input.Arg("chassis").(string),
+				Platform: optString(input, "platform"),
+				PerChild: optInt(input, "per-child"),
+				Apply:    optBool(input, "apply"),
+
+				Distribution: p.settings.Distribution,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/bootstrap/bootstrap.yaml", "chassis:bootstrap", func(input *action.Input) actionRunner {
+			return &bootstrap.Bootstrap{
+				Dir:      optString(input, "dir"),
+				Platform: optString(input, "platform"),
+				Count:    optString(input, "count"),
+				Pattern:  optString(input, "pattern"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/adopt/adopt.yaml", "chassis:adopt", func(input *action.Input) actionRunner {
+			return &adopt.Adopt{
+				Dir:    optString(input, "dir"),
+				Apply:  optBool(input, "apply"),
+				Limits: p.settings.Limits,
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/explain/explain.yaml", "chassis:explain", func(input *action.Input) actionRunner {
+			return &explain.Explain{
+				Code: argString(input, "code"),
+			}
+		}),
+		createAction("actions/migrate/migrate.yaml", "chassis:migrate", func(input *action.Input) actionRunner {
+			return &migrate.Migrate{
+				Dir: optString(input, "dir"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/migratelayout/migratelayout.yaml", "chassis:migrate-layout", func(input *action.Input) actionRunner {
+			return &migratelayout.MigrateLayout{
+				Dir:       optString(input, "dir"),
+				Nodes:     optString(input, "nodes"),
+				Playbooks: optString(input, "playbooks"),
+				Hostname:  optString(input, "hostname"),
+				Extension: optString(input, "extension"),
+				GitMv:     optBool(input, "git-mv"),
+			}
+		}, optDryRun, optBackup),
+		createAction("actions/capabilities/capabilities.yaml", "chassis:capabilities", func(_ *action.Input) actionRunner {
+			return &capabilities.Capabilities{
+				Version:     moduleVersion(),
+				Definitions: definitions,
 			}
 		}),
 	}, nil
@@ -151,3 +807,24 @@ func getLogger(a *action.Action) (*launchr.Logger, *launchr.Terminal) {
 
 	return log, term
 }
+
+// moduleVersion returns the version of this module in the running binary,
+// or "(devel)" if it isn't built as a dependency.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}